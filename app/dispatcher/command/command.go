@@ -0,0 +1,96 @@
+package command
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+// sessionServer is an implementation of SessionService.
+type sessionServer struct {
+	dispatcher routing.Dispatcher
+}
+
+// NewSessionServer creates a session service with the dispatcher.
+func NewSessionServer(dispatcher routing.Dispatcher) SessionServiceServer {
+	return &sessionServer{dispatcher: dispatcher}
+}
+
+func (s *sessionServer) registry() (routing.SessionRegistry, error) {
+	registry, ok := s.dispatcher.(routing.SessionRegistry)
+	if !ok {
+		return nil, newError("unsupported dispatcher implementation")
+	}
+	return registry, nil
+}
+
+func (s *sessionServer) ListSessions(ctx context.Context, request *ListSessionsRequest) (*ListSessionsResponse, error) {
+	registry, err := s.registry()
+	if err != nil {
+		return nil, err
+	}
+	response := &ListSessionsResponse{}
+	for _, info := range registry.Sessions() {
+		if request.User != "" && !strings.EqualFold(info.User, request.User) {
+			continue
+		}
+		if request.InboundTag != "" && info.InboundTag != request.InboundTag {
+			continue
+		}
+		session := &Session{
+			Id:          info.ID,
+			InboundTag:  info.InboundTag,
+			User:        info.User,
+			OutboundTag: info.OutboundTag,
+			StartTime:   info.Start.Unix(),
+			Uplink:      info.Uplink,
+			Downlink:    info.Downlink,
+		}
+		if info.Source.IsValid() {
+			session.Source = info.Source.String()
+		}
+		if info.Target.IsValid() {
+			session.Target = info.Target.String()
+		}
+		response.Sessions = append(response.Sessions, session)
+	}
+	return response, nil
+}
+
+func (s *sessionServer) CloseSession(ctx context.Context, request *CloseSessionRequest) (*CloseSessionResponse, error) {
+	registry, err := s.registry()
+	if err != nil {
+		return nil, err
+	}
+	if !registry.CloseSession(request.Id) {
+		return nil, newError("session ", request.Id, " not found")
+	}
+	newError("closed session ", request.Id).AtInfo().WriteToLog()
+	return &CloseSessionResponse{}, nil
+}
+
+func (s *sessionServer) mustEmbedUnimplementedSessionServiceServer() {}
+
+type service struct {
+	v *core.Instance
+}
+
+func (s *service) Register(server *grpc.Server) {
+	common.Must(s.v.RequireFeatures(func(dispatcher routing.Dispatcher) {
+		RegisterSessionServiceServer(server, NewSessionServer(dispatcher))
+	}))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := core.MustFromContext(ctx)
+		return &service{v: s}, nil
+	}))
+}
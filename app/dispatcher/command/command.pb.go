@@ -0,0 +1,436 @@
+package command
+
+import (
+	_ "github.com/v2fly/v2ray-core/v5/common/protoext"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ListSessionsRequest queries the sessions being dispatched.
+// * User selects sessions of the user email. All users are selected if left
+// empty.
+// * InboundTag selects sessions of the inbound. All inbounds are selected if
+// left empty.
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	InboundTag    string                 `protobuf:"bytes,2,opt,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListSessionsRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ListSessionsRequest) GetInboundTag() string {
+	if x != nil {
+		return x.InboundTag
+	}
+	return ""
+}
+
+type Session struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	InboundTag string                 `protobuf:"bytes,2,opt,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	User       string                 `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	// Source and target addresses, e.g. "tcp:1.2.3.4:5678".
+	Source      string `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Target      string `protobuf:"bytes,5,opt,name=target,proto3" json:"target,omitempty"`
+	OutboundTag string `protobuf:"bytes,6,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
+	// Unix timestamp in seconds when the session starts.
+	StartTime int64 `protobuf:"varint,7,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// Bytes transferred so far.
+	Uplink        int64 `protobuf:"varint,8,opt,name=uplink,proto3" json:"uplink,omitempty"`
+	Downlink      int64 `protobuf:"varint,9,opt,name=downlink,proto3" json:"downlink,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Session) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Session) GetInboundTag() string {
+	if x != nil {
+		return x.InboundTag
+	}
+	return ""
+}
+
+func (x *Session) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Session) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Session) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *Session) GetOutboundTag() string {
+	if x != nil {
+		return x.OutboundTag
+	}
+	return ""
+}
+
+func (x *Session) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *Session) GetUplink() int64 {
+	if x != nil {
+		return x.Uplink
+	}
+	return 0
+}
+
+func (x *Session) GetDownlink() int64 {
+	if x != nil {
+		return x.Downlink
+	}
+	return 0
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type CloseSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseSessionRequest) Reset() {
+	*x = CloseSessionRequest{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseSessionRequest) ProtoMessage() {}
+
+func (x *CloseSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseSessionRequest.ProtoReflect.Descriptor instead.
+func (*CloseSessionRequest) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CloseSessionRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CloseSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseSessionResponse) Reset() {
+	*x = CloseSessionResponse{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseSessionResponse) ProtoMessage() {}
+
+func (x *CloseSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseSessionResponse.ProtoReflect.Descriptor instead.
+func (*CloseSessionResponse) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{4}
+}
+
+type Config struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{5}
+}
+
+var File_app_dispatcher_command_command_proto protoreflect.FileDescriptor
+
+const file_app_dispatcher_command_command_proto_rawDesc = "" +
+	"\n" +
+	"$app/dispatcher/command/command.proto\x12!v2ray.core.app.dispatcher.command\x1a common/protoext/extensions.proto\"J\n" +
+	"\x13ListSessionsRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x1f\n" +
+	"\vinbound_tag\x18\x02 \x01(\tR\n" +
+	"inboundTag\"\xf4\x01\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x1f\n" +
+	"\vinbound_tag\x18\x02 \x01(\tR\n" +
+	"inboundTag\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x16\n" +
+	"\x06target\x18\x05 \x01(\tR\x06target\x12!\n" +
+	"\foutbound_tag\x18\x06 \x01(\tR\voutboundTag\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\a \x01(\x03R\tstartTime\x12\x16\n" +
+	"\x06uplink\x18\b \x01(\x03R\x06uplink\x12\x1a\n" +
+	"\bdownlink\x18\t \x01(\x03R\bdownlink\"^\n" +
+	"\x14ListSessionsResponse\x12F\n" +
+	"\bsessions\x18\x01 \x03(\v2*.v2ray.core.app.dispatcher.command.SessionR\bsessions\"%\n" +
+	"\x13CloseSessionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\"\x16\n" +
+	"\x14CloseSessionResponse\"%\n" +
+	"\x06Config:\x1b\x82\xb5\x18\x17\n" +
+	"\vgrpcservice\x12\bsessions2\x98\x02\n" +
+	"\x0eSessionService\x12\x81\x01\n" +
+	"\fListSessions\x126.v2ray.core.app.dispatcher.command.ListSessionsRequest\x1a7.v2ray.core.app.dispatcher.command.ListSessionsResponse\"\x00\x12\x81\x01\n" +
+	"\fCloseSession\x126.v2ray.core.app.dispatcher.command.CloseSessionRequest\x1a7.v2ray.core.app.dispatcher.command.CloseSessionResponse\"\x00B\x84\x01\n" +
+	"%com.v2ray.core.app.dispatcher.commandP\x01Z5github.com/v2fly/v2ray-core/v5/app/dispatcher/command\xaa\x02!V2Ray.Core.App.Dispatcher.Commandb\x06proto3"
+
+var (
+	file_app_dispatcher_command_command_proto_rawDescOnce sync.Once
+	file_app_dispatcher_command_command_proto_rawDescData []byte
+)
+
+func file_app_dispatcher_command_command_proto_rawDescGZIP() []byte {
+	file_app_dispatcher_command_command_proto_rawDescOnce.Do(func() {
+		file_app_dispatcher_command_command_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_app_dispatcher_command_command_proto_rawDesc), len(file_app_dispatcher_command_command_proto_rawDesc)))
+	})
+	return file_app_dispatcher_command_command_proto_rawDescData
+}
+
+var file_app_dispatcher_command_command_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_app_dispatcher_command_command_proto_goTypes = []any{
+	(*ListSessionsRequest)(nil),  // 0: v2ray.core.app.dispatcher.command.ListSessionsRequest
+	(*Session)(nil),              // 1: v2ray.core.app.dispatcher.command.Session
+	(*ListSessionsResponse)(nil), // 2: v2ray.core.app.dispatcher.command.ListSessionsResponse
+	(*CloseSessionRequest)(nil),  // 3: v2ray.core.app.dispatcher.command.CloseSessionRequest
+	(*CloseSessionResponse)(nil), // 4: v2ray.core.app.dispatcher.command.CloseSessionResponse
+	(*Config)(nil),               // 5: v2ray.core.app.dispatcher.command.Config
+}
+var file_app_dispatcher_command_command_proto_depIdxs = []int32{
+	1, // 0: v2ray.core.app.dispatcher.command.ListSessionsResponse.sessions:type_name -> v2ray.core.app.dispatcher.command.Session
+	0, // 1: v2ray.core.app.dispatcher.command.SessionService.ListSessions:input_type -> v2ray.core.app.dispatcher.command.ListSessionsRequest
+	3, // 2: v2ray.core.app.dispatcher.command.SessionService.CloseSession:input_type -> v2ray.core.app.dispatcher.command.CloseSessionRequest
+	2, // 3: v2ray.core.app.dispatcher.command.SessionService.ListSessions:output_type -> v2ray.core.app.dispatcher.command.ListSessionsResponse
+	4, // 4: v2ray.core.app.dispatcher.command.SessionService.CloseSession:output_type -> v2ray.core.app.dispatcher.command.CloseSessionResponse
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_app_dispatcher_command_command_proto_init() }
+func file_app_dispatcher_command_command_proto_init() {
+	if File_app_dispatcher_command_command_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_dispatcher_command_command_proto_rawDesc), len(file_app_dispatcher_command_command_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_app_dispatcher_command_command_proto_goTypes,
+		DependencyIndexes: file_app_dispatcher_command_command_proto_depIdxs,
+		MessageInfos:      file_app_dispatcher_command_command_proto_msgTypes,
+	}.Build()
+	File_app_dispatcher_command_command_proto = out.File
+	file_app_dispatcher_command_command_proto_goTypes = nil
+	file_app_dispatcher_command_command_proto_depIdxs = nil
+}
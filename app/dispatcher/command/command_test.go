@@ -0,0 +1,88 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	. "github.com/v2fly/v2ray-core/v5/app/dispatcher/command"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+type sessionDispatcher struct {
+	routing.Dispatcher
+	sessions []*routing.SessionInfo
+}
+
+func (d *sessionDispatcher) Sessions() []*routing.SessionInfo {
+	return d.sessions
+}
+
+func (d *sessionDispatcher) CloseSession(id uint32) bool {
+	for i, s := range d.sessions {
+		if s.ID == id {
+			d.sessions = append(d.sessions[:i], d.sessions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func TestSessionService(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	dispatcher := &sessionDispatcher{
+		sessions: []*routing.SessionInfo{
+			{
+				ID:          1,
+				InboundTag:  "socks",
+				User:        "love@v2fly.org",
+				Source:      net.TCPDestination(net.ParseAddress("1.2.3.4"), 5678),
+				Target:      net.TCPDestination(net.ParseAddress("v2fly.org"), 443),
+				OutboundTag: "direct",
+				Start:       start,
+				Uplink:      5,
+				Downlink:    6,
+			},
+			{ID: 2, InboundTag: "http", Start: start},
+		},
+	}
+	server := NewSessionServer(dispatcher)
+
+	resp, err := server.ListSessions(context.Background(), &ListSessionsRequest{User: "LOVE@v2fly.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &ListSessionsResponse{
+		Sessions: []*Session{
+			{
+				Id: 1, InboundTag: "socks", User: "love@v2fly.org", Source: "tcp:1.2.3.4:5678",
+				Target: "tcp:v2fly.org:443", OutboundTag: "direct", StartTime: 1700000000, Uplink: 5, Downlink: 6,
+			},
+		},
+	}
+	if r := cmp.Diff(resp, expected, protocmp.Transform()); r != "" {
+		t.Error(r)
+	}
+
+	resp, err = server.ListSessions(context.Background(), &ListSessionsRequest{InboundTag: "http"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Sessions) != 1 || resp.Sessions[0].Id != 2 || resp.Sessions[0].Source != "" {
+		t.Error("unexpected sessions: ", resp.Sessions)
+	}
+
+	if _, err := server.CloseSession(context.Background(), &CloseSessionRequest{Id: 3}); err == nil {
+		t.Error("expect error closing unknown session")
+	}
+	if _, err := server.CloseSession(context.Background(), &CloseSessionRequest{Id: 1}); err != nil {
+		t.Error(err)
+	}
+	if len(dispatcher.sessions) != 1 {
+		t.Error("expect session closed")
+	}
+}
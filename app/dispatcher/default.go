@@ -93,10 +93,11 @@ func (r *cachedReader) Interrupt() {
 
 // DefaultDispatcher is a default implementation of Dispatcher.
 type DefaultDispatcher struct {
-	ohm    outbound.Manager
-	router routing.Router
-	policy policy.Manager
-	stats  stats.Manager
+	ohm      outbound.Manager
+	router   routing.Router
+	policy   policy.Manager
+	stats    stats.Manager
+	sessions *sessionRegistry
 }
 
 func init() {
@@ -117,6 +118,7 @@ func (d *DefaultDispatcher) Init(config *Config, om outbound.Manager, router rou
 	d.router = router
 	d.policy = pm
 	d.stats = sm
+	d.sessions = newSessionRegistry()
 	return nil
 }
 
@@ -125,6 +127,16 @@ func (*DefaultDispatcher) Type() interface{} {
 	return routing.DispatcherType()
 }
 
+// Sessions implements routing.SessionRegistry.
+func (d *DefaultDispatcher) Sessions() []*routing.SessionInfo {
+	return d.sessions.Sessions()
+}
+
+// CloseSession implements routing.SessionRegistry.
+func (d *DefaultDispatcher) CloseSession(id uint32) bool {
+	return d.sessions.CloseSession(id)
+}
+
 // Start implements common.Runnable.
 func (*DefaultDispatcher) Start() error {
 	return nil
@@ -212,6 +224,7 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 	ctx = session.ContextWithOutbound(ctx, ob)
 
 	inbound, outbound := d.getLink(ctx)
+	ctx = contextWithActiveSession(ctx, d.sessions.register(ctx, destination, inbound, outbound))
 	content := session.ContentFromContext(ctx)
 	if content == nil {
 		content = new(session.Content)
@@ -232,6 +245,16 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 			result, err := sniffer(ctx, cReader, sniffingRequest.MetadataOnly, destination.Network)
 			if err == nil {
 				content.Protocol = result.Protocol()
+				if domain := result.Domain(); dns_proto.IsDomainName(domain) {
+					if activeSession := activeSessionFromContext(ctx); activeSession != nil {
+						activeSession.setSniffedDomain(domain)
+					}
+				}
+				if resAttrs, ok := result.(SnifferResultWithAttributes); ok {
+					for key, value := range resAttrs.Attributes() {
+						content.SetAttribute(key, value)
+					}
+				}
 			}
 			if err == nil && shouldOverride(result, sniffingRequest.OverrideDestinationForProtocol) {
 				domain := result.Domain()
@@ -365,6 +388,15 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 		return
 	}
 
+	if activeSession := activeSessionFromContext(ctx); activeSession != nil {
+		activeSession.setOutbound(handler.Tag(), destination)
+		if d.policy.ForSystem().Stats.OutboundDestinations {
+			if recorder, ok := d.stats.(stats.DestinationRecorder); ok {
+				activeSession.recordDestination(recorder)
+			}
+		}
+	}
+
 	if accessMessage := log.AccessMessageFromContext(ctx); accessMessage != nil {
 		if tag := handler.Tag(); tag != "" {
 			accessMessage.Detour = tag
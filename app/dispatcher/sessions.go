@@ -0,0 +1,278 @@
+package dispatcher
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/buf"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
+	"github.com/v2fly/v2ray-core/v5/transport"
+)
+
+// sessionCounter counts the bytes transferred in a session.
+type sessionCounter struct {
+	value int64
+}
+
+func (c *sessionCounter) Value() int64 {
+	return atomic.LoadInt64(&c.value)
+}
+
+func (c *sessionCounter) Set(newValue int64) int64 {
+	return atomic.SwapInt64(&c.value, newValue)
+}
+
+func (c *sessionCounter) Add(delta int64) int64 {
+	return atomic.AddInt64(&c.value, delta)
+}
+
+// destinationFlushInterval is the minimal interval between two flushes of the traffic of a session to its
+// destination while the session is active.
+const destinationFlushInterval = time.Second
+
+// destinationTraffic accounts the traffic of a session to its destination.
+type destinationTraffic struct {
+	sync.Mutex
+	recorder    stats.DestinationRecorder
+	outboundTag string
+	user        string
+	domain      string
+	recorded    int64
+	// flushed is the time of the last flush in Unix nanoseconds.
+	flushed atomic.Int64
+}
+
+// activeSession is a session being dispatched.
+type activeSession struct {
+	sync.Mutex
+	info          routing.SessionInfo
+	sniffedDomain string
+	uplink        sessionCounter
+	downlink      sessionCounter
+	links         []common.Interruptible
+	done          sync.Once
+	stop          func() bool
+	registry      *sessionRegistry
+	destination   atomic.Pointer[destinationTraffic]
+}
+
+func (s *activeSession) setSniffedDomain(domain string) {
+	s.Lock()
+	s.sniffedDomain = domain
+	s.Unlock()
+}
+
+func (s *activeSession) setOutbound(tag string, target net.Destination) {
+	s.Lock()
+	s.info.OutboundTag = tag
+	s.info.Target = target
+	s.Unlock()
+}
+
+func (s *activeSession) snapshot() *routing.SessionInfo {
+	s.Lock()
+	info := s.info
+	s.Unlock()
+	info.Uplink = s.uplink.Value()
+	info.Downlink = s.downlink.Value()
+	return &info
+}
+
+// recordDestination starts accounting the traffic of the session to the recorder, by its outbound, user, and
+// target domain, or sniffed domain, or target address. It shall be called after setOutbound.
+func (s *activeSession) recordDestination(recorder stats.DestinationRecorder) {
+	s.Lock()
+	d := &destinationTraffic{
+		recorder:    recorder,
+		outboundTag: s.info.OutboundTag,
+		user:        s.info.User,
+	}
+	switch {
+	case s.info.Target.Address != nil && s.info.Target.Address.Family().IsDomain():
+		d.domain = s.info.Target.Address.Domain()
+	case s.sniffedDomain != "":
+		d.domain = s.sniffedDomain
+	case s.info.Target.Address != nil:
+		d.domain = s.info.Target.Address.String()
+	}
+	s.Unlock()
+
+	s.destination.Store(d)
+	s.recordTraffic(true)
+}
+
+// recordTraffic records the traffic of the session since last recorded, if its destination is accounted. Unless
+// forced, the traffic is recorded at most once per destinationFlushInterval, so that the recorder is not locked
+// on every write.
+func (s *activeSession) recordTraffic(force bool) {
+	d := s.destination.Load()
+	if d == nil {
+		return
+	}
+	now := time.Now().UnixNano()
+	if !force && now-d.flushed.Load() < int64(destinationFlushInterval) {
+		return
+	}
+	d.Lock()
+	defer d.Unlock()
+
+	d.flushed.Store(now)
+	total := s.uplink.Value() + s.downlink.Value()
+	if delta := total - d.recorded; delta > 0 {
+		d.recorder.RecordDestination(d.outboundTag, d.user, d.domain, delta)
+		d.recorded = total
+	}
+}
+
+// finish removes the session from the registry, recording the rest of its traffic.
+func (s *activeSession) finish() {
+	s.done.Do(func() {
+		s.recordTraffic(true)
+		s.registry.remove(s.info.ID)
+		s.Lock()
+		if s.stop != nil {
+			s.stop()
+		}
+		s.Unlock()
+	})
+}
+
+// interrupt breaks both directions of the session.
+func (s *activeSession) interrupt() {
+	for _, link := range s.links {
+		link.Interrupt()
+	}
+	s.finish()
+}
+
+// sessionWriter is the writer of a session link, which finishes the session when it is interrupted, or
+// closed if finishOnClose.
+type sessionWriter struct {
+	SizeStatWriter
+	session       *activeSession
+	finishOnClose bool
+}
+
+func (w *sessionWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	err := w.SizeStatWriter.WriteMultiBuffer(mb)
+	w.session.recordTraffic(false)
+	return err
+}
+
+func (w *sessionWriter) Close() error {
+	err := w.SizeStatWriter.Close()
+	if w.finishOnClose {
+		w.session.finish()
+	}
+	return err
+}
+
+func (w *sessionWriter) Interrupt() {
+	w.SizeStatWriter.Interrupt()
+	w.session.finish()
+}
+
+// sessionRegistry tracks the sessions being dispatched.
+type sessionRegistry struct {
+	sync.RWMutex
+	nextID   uint32
+	sessions map[uint32]*activeSession
+}
+
+func newSessionRegistry() *sessionRegistry {
+	return &sessionRegistry{sessions: make(map[uint32]*activeSession)}
+}
+
+// register tracks the session of the links, until the downlink is closed, either link is interrupted, or
+// the context is done.
+func (r *sessionRegistry) register(ctx context.Context, destination net.Destination, inbound, outbound *transport.Link) *activeSession {
+	s := &activeSession{
+		info: routing.SessionInfo{
+			ID:     atomic.AddUint32(&r.nextID, 1),
+			Target: destination,
+			Start:  time.Now(),
+		},
+		registry: r,
+	}
+	if sessionInbound := session.InboundFromContext(ctx); sessionInbound != nil {
+		s.info.InboundTag = sessionInbound.Tag
+		s.info.Source = sessionInbound.Source
+		if sessionInbound.User != nil {
+			s.info.User = sessionInbound.User.Email
+		}
+	}
+	for _, reader := range []buf.Reader{inbound.Reader, outbound.Reader} {
+		if link, ok := reader.(common.Interruptible); ok {
+			s.links = append(s.links, link)
+		}
+	}
+	inbound.Writer = &sessionWriter{
+		SizeStatWriter: SizeStatWriter{Counter: &s.uplink, Writer: inbound.Writer},
+		session:        s,
+	}
+	outbound.Writer = &sessionWriter{
+		SizeStatWriter: SizeStatWriter{Counter: &s.downlink, Writer: outbound.Writer},
+		session:        s,
+		finishOnClose:  true,
+	}
+
+	r.Lock()
+	r.sessions[s.info.ID] = s
+	r.Unlock()
+	s.Lock()
+	s.stop = context.AfterFunc(ctx, s.finish)
+	s.Unlock()
+	return s
+}
+
+func (r *sessionRegistry) remove(id uint32) {
+	r.Lock()
+	delete(r.sessions, id)
+	r.Unlock()
+}
+
+// Sessions returns the snapshots of the sessions, in the order they start.
+func (r *sessionRegistry) Sessions() []*routing.SessionInfo {
+	r.RLock()
+	sessions := make([]*routing.SessionInfo, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		sessions = append(sessions, s.snapshot())
+	}
+	r.RUnlock()
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].ID < sessions[j].ID
+	})
+	return sessions
+}
+
+// CloseSession interrupts the session of the ID.
+func (r *sessionRegistry) CloseSession(id uint32) bool {
+	r.RLock()
+	s, found := r.sessions[id]
+	r.RUnlock()
+	if !found {
+		return false
+	}
+	s.interrupt()
+	return true
+}
+
+type activeSessionKey struct{}
+
+func contextWithActiveSession(ctx context.Context, s *activeSession) context.Context {
+	return context.WithValue(ctx, activeSessionKey{}, s)
+}
+
+func activeSessionFromContext(ctx context.Context) *activeSession {
+	if s, ok := ctx.Value(activeSessionKey{}).(*activeSession); ok {
+		return s
+	}
+	return nil
+}
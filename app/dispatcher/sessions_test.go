@@ -0,0 +1,151 @@
+package dispatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/buf"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
+	"github.com/v2fly/v2ray-core/v5/transport"
+	"github.com/v2fly/v2ray-core/v5/transport/pipe"
+)
+
+func newSessionLinks() (*transport.Link, *transport.Link) {
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	return &transport.Link{Reader: downlinkReader, Writer: uplinkWriter},
+		&transport.Link{Reader: uplinkReader, Writer: downlinkWriter}
+}
+
+func waitSessions(t *testing.T, r *sessionRegistry, count int) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if len(r.Sessions()) == count {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expect ", count, " sessions, but got ", len(r.Sessions()))
+}
+
+func TestSessionRegistry(t *testing.T) {
+	r := newSessionRegistry()
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		Tag:    "socks",
+		Source: net.TCPDestination(net.ParseAddress("1.2.3.4"), 5678),
+		User:   &protocol.MemoryUser{Email: "love@v2fly.org"},
+	})
+	inbound, outbound := newSessionLinks()
+	s := r.register(ctx, net.TCPDestination(net.ParseAddress("v2fly.org"), 443), inbound, outbound)
+	s.setOutbound("direct", net.TCPDestination(net.ParseAddress("v2fly.org"), 443))
+
+	common.Must(inbound.Writer.WriteMultiBuffer(buf.MultiBuffer{buf.FromBytes([]byte("hello"))}))
+	common.Must(outbound.Writer.WriteMultiBuffer(buf.MultiBuffer{buf.FromBytes([]byte("world!"))}))
+
+	sessions := r.Sessions()
+	if len(sessions) != 1 {
+		t.Fatal("expect 1 session, but got ", len(sessions))
+	}
+	info := sessions[0]
+	if info.InboundTag != "socks" || info.User != "love@v2fly.org" || info.OutboundTag != "direct" ||
+		info.Source.String() != "tcp:1.2.3.4:5678" || info.Target.String() != "tcp:v2fly.org:443" {
+		t.Error("unexpected session: ", info)
+	}
+	if info.Uplink != 5 || info.Downlink != 6 {
+		t.Error("unexpected traffic: ", info.Uplink, " ", info.Downlink)
+	}
+
+	if r.CloseSession(info.ID + 1) {
+		t.Error("expect no session closed of unknown id")
+	}
+	if !r.CloseSession(info.ID) {
+		t.Error("expect session closed")
+	}
+	if len(r.Sessions()) != 0 {
+		t.Error("expect session removed after closed")
+	}
+	if _, err := inbound.Reader.ReadMultiBuffer(); err == nil {
+		t.Error("expect downlink interrupted")
+	}
+	if _, err := outbound.Reader.ReadMultiBuffer(); err == nil {
+		t.Error("expect uplink interrupted")
+	}
+}
+
+func TestSessionRegistryFinish(t *testing.T) {
+	r := newSessionRegistry()
+	destination := net.TCPDestination(net.ParseAddress("v2fly.org"), 443)
+
+	_, outbound := newSessionLinks()
+	r.register(context.Background(), destination, &transport.Link{Writer: buf.Discard}, outbound)
+	common.Must(common.Close(outbound.Writer))
+	waitSessions(t, r, 0)
+
+	inbound, _ := newSessionLinks()
+	r.register(context.Background(), destination, inbound, &transport.Link{Writer: buf.Discard})
+	common.Interrupt(inbound.Writer)
+	waitSessions(t, r, 0)
+
+	inbound, _ = newSessionLinks()
+	r.register(context.Background(), destination, inbound, &transport.Link{Writer: buf.Discard})
+	common.Must(common.Close(inbound.Writer))
+	waitSessions(t, r, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.register(ctx, destination, &transport.Link{Writer: buf.Discard}, &transport.Link{Writer: buf.Discard})
+	waitSessions(t, r, 2)
+	cancel()
+	waitSessions(t, r, 1)
+}
+
+type destinationRecorder struct {
+	bytes map[string]int64
+}
+
+func (r *destinationRecorder) RecordDestination(outboundTag, user, domain string, bytes int64) {
+	r.bytes[outboundTag+">>>"+user+">>>"+domain] += bytes
+}
+
+func (r *destinationRecorder) TopDestinations() []*stats.DestinationTraffic {
+	return nil
+}
+
+func TestSessionRecordDestination(t *testing.T) {
+	r := newSessionRegistry()
+	recorder := &destinationRecorder{bytes: make(map[string]int64)}
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		User: &protocol.MemoryUser{Email: "love@v2fly.org"},
+	})
+	destination := net.TCPDestination(net.ParseAddress("1.2.3.4"), 443)
+
+	inbound, outbound := newSessionLinks()
+	s := r.register(ctx, destination, inbound, outbound)
+	s.setSniffedDomain("v2fly.org")
+	common.Must(inbound.Writer.WriteMultiBuffer(buf.MultiBuffer{buf.FromBytes([]byte("hello"))}))
+	s.setOutbound("proxy", destination)
+	s.recordDestination(recorder)
+	common.Must(outbound.Writer.WriteMultiBuffer(buf.MultiBuffer{buf.FromBytes([]byte("world!"))}))
+	common.Must(common.Close(outbound.Writer))
+
+	inbound, outbound = newSessionLinks()
+	s = r.register(ctx, destination, inbound, outbound)
+	s.setOutbound("direct", destination)
+	s.recordDestination(recorder)
+	common.Must(inbound.Writer.WriteMultiBuffer(buf.MultiBuffer{buf.FromBytes([]byte("hello"))}))
+	if v := recorder.bytes["direct>>>love@v2fly.org>>>1.2.3.4"]; v != 0 {
+		t.Error("expect traffic not flushed until the session finishes, but got ", v)
+	}
+	s.finish()
+
+	if v := recorder.bytes["proxy>>>love@v2fly.org>>>v2fly.org"]; v != 11 {
+		t.Error("expect 11 bytes to sniffed domain, but got ", v)
+	}
+	if v := recorder.bytes["direct>>>love@v2fly.org>>>1.2.3.4"]; v != 5 {
+		t.Error("expect 5 bytes to address, but got ", v)
+	}
+}
@@ -6,20 +6,23 @@ import (
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/net"
 	"github.com/v2fly/v2ray-core/v5/common/protocol"
-	"github.com/v2fly/v2ray-core/v5/common/protocol/bittorrent"
-	"github.com/v2fly/v2ray-core/v5/common/protocol/dns"
-	"github.com/v2fly/v2ray-core/v5/common/protocol/http"
-	"github.com/v2fly/v2ray-core/v5/common/protocol/quic"
-	"github.com/v2fly/v2ray-core/v5/common/protocol/tls"
+	protocolsniffer "github.com/v2fly/v2ray-core/v5/common/protocol/sniffer"
 )
 
-type SniffResult interface {
-	Protocol() string
-	Domain() string
-}
+type SniffResult = protocolsniffer.Result
 
 type protocolSniffer func(context.Context, []byte) (SniffResult, error)
 
+// SnifferFunc sniffs the protocol of the first payload of a connection. See protocolsniffer.Func.
+type SnifferFunc = protocolsniffer.Func
+
+// RegisterSniffer registers a sniffer of the network, which can be referenced by its name in
+// destination override of sniffing settings. It is expected to be called in init() of the package
+// implementing the sniffer.
+func RegisterSniffer(name string, network net.Network, sniffer SnifferFunc) error {
+	return protocolsniffer.Register(name, network, sniffer)
+}
+
 type protocolSnifferWithMetadata struct {
 	protocolSniffer protocolSniffer
 	// A Metadata sniffer will be invoked on connection establishment only, with nil body,
@@ -29,22 +32,55 @@ type protocolSnifferWithMetadata struct {
 	network         net.Network
 }
 
+// namedSniffResult is a result of registered sniffers, which can be referenced by the name of the sniffer.
+type namedSniffResult struct {
+	SniffResult
+	name string
+}
+
+func (r namedSniffResult) IsProtoSubsetOf(protocolName string) bool {
+	if r.name == protocolName {
+		return true
+	}
+	if subset, ok := r.SniffResult.(SnifferIsProtoSubsetOf); ok {
+		return subset.IsProtoSubsetOf(protocolName)
+	}
+	return false
+}
+
+func (r namedSniffResult) Attributes() map[string]string {
+	if result, ok := r.SniffResult.(SnifferResultWithAttributes); ok {
+		return result.Attributes()
+	}
+	return nil
+}
+
+func namedSniffer(s protocolsniffer.Sniffer) protocolSniffer {
+	return func(ctx context.Context, payload []byte) (SniffResult, error) {
+		result, err := s.Sniff(ctx, payload)
+		if err != nil || result == nil {
+			return result, err
+		}
+		return namedSniffResult{SniffResult: result, name: s.Name}, nil
+	}
+}
+
 type Sniffer struct {
 	sniffer []protocolSnifferWithMetadata
 }
 
+func registeredSniffers() []protocolSnifferWithMetadata {
+	registered := protocolsniffer.Registered()
+	ret := make([]protocolSnifferWithMetadata, 0, len(registered))
+	for _, s := range registered {
+		ret = append(ret, protocolSnifferWithMetadata{namedSniffer(s), false, s.Network})
+	}
+	return ret
+}
+
 func NewSniffer(ctx context.Context) *Sniffer {
 	ret := &Sniffer{
-		sniffer: []protocolSnifferWithMetadata{
-			{func(c context.Context, b []byte) (SniffResult, error) { return http.SniffHTTP(b) }, false, net.Network_TCP},
-			{func(c context.Context, b []byte) (SniffResult, error) { return tls.SniffTLS(b) }, false, net.Network_TCP},
-			{func(c context.Context, b []byte) (SniffResult, error) { return quic.SniffQUIC(b) }, false, net.Network_UDP},
-			{func(c context.Context, b []byte) (SniffResult, error) { return bittorrent.SniffBittorrent(b) }, false, net.Network_TCP},
-			{func(c context.Context, b []byte) (SniffResult, error) { return bittorrent.SniffUTP(b) }, false, net.Network_UDP},
-			{func(c context.Context, b []byte) (SniffResult, error) { return bittorrent.SniffUDPTracker(b) }, false, net.Network_UDP},
-			{func(c context.Context, b []byte) (SniffResult, error) { return dns.SniffDNS(b) }, false, net.Network_UDP},
-			{func(c context.Context, b []byte) (SniffResult, error) { return dns.SniffTCPDNS(b) }, false, net.Network_TCP},
-		},
+		sniffer: registeredSniffers(),
 	}
 	if sniffer, err := newFakeDNSSniffer(ctx); err == nil {
 		others := ret.sniffer
@@ -146,6 +182,13 @@ func (c compositeResult) ProtocolForDomainResult() string {
 	return c.domainResult.Protocol()
 }
 
+func (c compositeResult) Attributes() map[string]string {
+	if result, ok := c.protocolResult.(SnifferResultWithAttributes); ok {
+		return result.Attributes()
+	}
+	return nil
+}
+
 type SnifferResultComposite interface {
 	ProtocolForDomainResult() string
 }
@@ -153,3 +196,9 @@ type SnifferResultComposite interface {
 type SnifferIsProtoSubsetOf interface {
 	IsProtoSubsetOf(protocolName string) bool
 }
+
+// SnifferResultWithAttributes is a SniffResult carrying details of the sniffed content,
+// which are set as attributes of the session content for routing.
+type SnifferResultWithAttributes interface {
+	Attributes() map[string]string
+}
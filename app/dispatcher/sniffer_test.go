@@ -0,0 +1,63 @@
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	protocolsniffer "github.com/v2fly/v2ray-core/v5/common/protocol/sniffer"
+)
+
+type sshSniffResult struct{}
+
+func (sshSniffResult) Protocol() string {
+	return "ssh"
+}
+
+func (sshSniffResult) Domain() string {
+	return "ssh.example.com"
+}
+
+func sniffSSH(ctx context.Context, b []byte) (SniffResult, error) {
+	banner := []byte("SSH-")
+	if len(b) < len(banner) {
+		return nil, common.ErrNoClue
+	}
+	if !bytes.HasPrefix(b, banner) {
+		return nil, errUnknownContent
+	}
+	return sshSniffResult{}, nil
+}
+
+func TestRegisterSniffer(t *testing.T) {
+	common.Must(RegisterSniffer("ssh-test", net.Network_TCP, sniffSSH))
+	t.Cleanup(func() { protocolsniffer.Unregister("ssh-test", net.Network_TCP) })
+	if err := RegisterSniffer("ssh-test", net.Network_TCP, sniffSSH); err == nil {
+		t.Error("expect error for duplicated sniffer")
+	}
+	if !protocolsniffer.IsRegistered("ssh-test") || !protocolsniffer.IsRegistered("tls") || protocolsniffer.IsRegistered("unknown") {
+		t.Error("unexpected registered sniffers")
+	}
+
+	sniffer := &Sniffer{sniffer: registeredSniffers()}
+	result, err := sniffer.Sniff(context.Background(), []byte("SSH-2.0-OpenSSH_9.6\r\n"), net.Network_TCP)
+	common.Must(err)
+	if result.Protocol() != "ssh" {
+		t.Error("expect ssh but got ", result.Protocol())
+	}
+	if !shouldOverride(result, []string{"ssh-test"}) {
+		t.Error("expect override by sniffer name")
+	}
+	if shouldOverride(result, []string{"http", "tls"}) {
+		t.Error("unexpected override")
+	}
+
+	sniffer = &Sniffer{sniffer: registeredSniffers()}
+	result, err = sniffer.Sniff(context.Background(), []byte("GET / HTTP/1.1\r\nHost: www.v2fly.org\r\n\r\n"), net.Network_TCP)
+	common.Must(err)
+	if result.Protocol() != "http1" || !shouldOverride(result, []string{"http"}) {
+		t.Error("unexpected result of builtin sniffer: ", result.Protocol())
+	}
+}
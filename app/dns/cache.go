@@ -0,0 +1,144 @@
+package dns
+
+import (
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+const (
+	// staleAnswerTTL is the TTL of stale answers recommended by RFC 8767.
+	staleAnswerTTL = 30 * time.Second
+	// maxStaleDuration is how long a record is kept after it expires.
+	maxStaleDuration = 24 * time.Hour
+	// refreshTimeout is the minimal interval between two background refreshes of a record.
+	refreshTimeout = 5 * time.Second
+	// prefetchMinHits is the number of hits for a record to be prefetched.
+	prefetchMinHits = 2
+)
+
+// cachePolicy is how a client serves the records in its scope of the cache, besides the fresh ones.
+type cachePolicy struct {
+	// serveStale serves the expired records for a while, refreshing them in background.
+	serveStale bool
+	// prefetch refreshes the popular records in background shortly before they expire.
+	prefetch bool
+	// refresh queries the domain bypassing the cache, so that its records in the cache are updated. The subnet is the
+	// EDNS client subnet of the records, or nil for the records of the name server itself.
+	refresh func(subnet *net.IPNet, domain string, option feature_dns.IPOption)
+}
+
+// newCachePolicy returns the cachePolicy for the strategy, or nil if neither serve-stale nor prefetch is enabled.
+func newCachePolicy(strategy CacheStrategy, refresh func(subnet *net.IPNet, domain string, option feature_dns.IPOption)) *cachePolicy {
+	p := &cachePolicy{refresh: refresh}
+	switch strategy {
+	case CacheStrategy_CacheServeStale:
+		p.serveStale = true
+	case CacheStrategy_CachePrefetch:
+		p.prefetch = true
+	case CacheStrategy_CacheServeStaleAndPrefetch:
+		p.serveStale = true
+		p.prefetch = true
+	default:
+		return nil
+	}
+	return p
+}
+
+// servesStale returns true if the expired records of the scope are kept to be served stale.
+func (s *recordCacheScope) servesStale() bool {
+	return s.policy != nil && s.policy.serveStale
+}
+
+// staleDeadline returns the time until which the record is kept in the cache of the scope.
+func (s *recordCacheScope) staleDeadline(rec *IPRecord) time.Time {
+	if s.servesStale() && rec.TTL > 0 && rec.RCode == dns.RcodeSuccess && len(rec.IP) > 0 {
+		return rec.Expire.Add(maxStaleDuration)
+	}
+	return rec.Expire
+}
+
+// lookupWith looks up the cache with the find function, counting the cache hits and misses. By the cache policy of
+// the scope, expired records are served stale and popular records are prefetched before they expire, while they
+// are refreshed in background.
+func (s *recordCacheScope) lookupWith(domain string, option feature_dns.IPOption, find func(string, feature_dns.IPOption) ([]net.IP, time.Time, error)) ([]net.IP, time.Time, error) {
+	ips, expireAt, err := find(domain, option)
+	if s.policy != nil {
+		stale := false
+		if err == errRecordNotFound && s.policy.serveStale {
+			if staleIPs := s.findStaleIPs(domain, option); len(staleIPs) > 0 {
+				ips, expireAt, err = staleIPs, time.Now().Add(staleAnswerTTL), nil
+				stale = true
+			}
+		}
+		if err != errRecordNotFound && s.cache.hit(s.key(domain), option, stale, s.policy.prefetch) {
+			newError(s.name, " refreshing ", domain, " in background").AtDebug().WriteToLog()
+			go s.policy.refresh(s.subnet, domain, option)
+		}
+	}
+	s.countLookup(err)
+	return ips, expireAt, err
+}
+
+// findStaleIPs returns the IPs of the expired records of the domain, which can still be served stale.
+func (s *recordCacheScope) findStaleIPs(domain string, option feature_dns.IPOption) []net.IP {
+	rec, found := s.get(domain)
+	if !found {
+		return nil
+	}
+	now := time.Now()
+	var addrs []net.Address
+	for _, r := range []struct {
+		enabled bool
+		record  *IPRecord
+	}{{option.IPv4Enable, rec.A}, {option.IPv6Enable, rec.AAAA}} {
+		if r.enabled && r.record != nil && now.Before(s.staleDeadline(r.record)) {
+			addrs = append(addrs, r.record.IP...)
+		}
+	}
+	ips, _ := toNetIP(addrs)
+	return ips
+}
+
+// hit counts a hit of the records of the key. It returns true if the records should be refreshed in background,
+// either because they are stale, or because they are popular and about to expire when prefetch is enabled.
+func (c *recordCache) hit(key cacheKey, option feature_dns.IPOption, stale bool, prefetch bool) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	element, found := c.elements[key]
+	if !found {
+		return false
+	}
+	e := element.Value.(*cacheElement)
+	e.hits++
+	now := time.Now()
+	if !stale && (!prefetch || e.hits < prefetchMinHits || !e.record.expiring(option, now)) {
+		return false
+	}
+	if now.Before(e.refreshUntil) {
+		return false
+	}
+	e.refreshUntil = now.Add(refreshTimeout)
+	return true
+}
+
+// expiring returns true if any of the IP records of the option is about to expire.
+func (r record) expiring(option feature_dns.IPOption, now time.Time) bool {
+	for _, rec := range []struct {
+		enabled bool
+		record  *IPRecord
+	}{{option.IPv4Enable, r.A}, {option.IPv6Enable, r.AAAA}} {
+		if !rec.enabled || rec.record == nil || rec.record.TTL == 0 {
+			continue
+		}
+		ttl := time.Duration(rec.record.TTL) * time.Second
+		if rec.record.Expire.Sub(now) < max(ttl/10, time.Second) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+package dns
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+// countingServer answers every query with a fixed IP and TTL, failing after being broken. The answers are cached
+// in the scope of the shared cache, like other name servers.
+type countingServer struct {
+	queries atomic.Int32
+	broken  atomic.Bool
+	ttl     time.Duration
+	cache   *recordCacheScope
+}
+
+func (*countingServer) Name() string     { return "counting" }
+func (*countingServer) NewReqID() uint16 { return 0 }
+
+func (s *countingServer) setRecordCache(scope *recordCacheScope) {
+	s.cache = scope
+}
+
+func (s *countingServer) QueryIP(ctx context.Context, domain string, clientIP net.IP, option feature_dns.IPOption, disableCache bool) ([]net.IP, error) {
+	ips, _, err := s.QueryIPWithTTL(ctx, domain, clientIP, option, disableCache)
+	return ips, err
+}
+
+func (s *countingServer) QueryIPWithTTL(ctx context.Context, domain string, _ net.IP, option feature_dns.IPOption, disableCache bool) ([]net.IP, time.Time, error) {
+	fqdn := Fqdn(domain)
+	if !disableCache && s.cache != nil {
+		ips, expireAt, err := s.cache.lookup(fqdn, option)
+		if err != errRecordNotFound {
+			markCacheHit(ctx)
+			return ips, expireAt, err
+		}
+	}
+	s.queries.Add(1)
+	if s.broken.Load() {
+		return nil, time.Time{}, feature_dns.RCodeError(2)
+	}
+	expireAt := time.Now().Add(s.ttl)
+	if s.cache != nil {
+		s.cache.update(fqdn, record{A: &IPRecord{
+			IP:     []net.Address{net.IPAddress([]byte{1, 2, 3, 4})},
+			Expire: expireAt,
+			TTL:    uint32(max(s.ttl/time.Second, 1)),
+		}})
+	}
+	return []net.IP{{1, 2, 3, 4}}, expireAt, nil
+}
+
+func (*countingServer) QueryRaw(context.Context, []byte) ([]byte, error) {
+	return nil, newError("not implemented")
+}
+
+func newCacheTestClient(server Server, strategy CacheStrategy) *Client {
+	client := &Client{
+		queryStrategy: feature_dns.IPOption{IPv4Enable: true, IPv6Enable: true},
+		cacheStrategy: strategy,
+	}
+	client.setServer(server)
+	client.setRecordCache(newRecordCache(0, 0, 0))
+	return client
+}
+
+func waitForQueries(t *testing.T, server *countingServer, expected int32) {
+	t.Helper()
+	for i := 0; i < 100 && server.queries.Load() < expected; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := server.queries.Load(); n != expected {
+		t.Fatal("expect ", expected, " queries, but got ", n)
+	}
+}
+
+func TestClientServeStale(t *testing.T) {
+	server := &countingServer{ttl: 100 * time.Millisecond}
+	client := newCacheTestClient(server, CacheStrategy_CacheServeStale)
+	option := feature_dns.IPOption{IPv4Enable: true}
+
+	if _, _, err := client.QueryIPWithTTL(context.Background(), "example.com", option); err != nil {
+		t.Fatal(err)
+	}
+	server.broken.Store(true)
+	time.Sleep(200 * time.Millisecond)
+
+	ips, expireAt, err := client.QueryIPWithTTL(context.Background(), "example.com", option)
+	if err != nil {
+		t.Fatal("expect stale answer, but got ", err)
+	}
+	if len(ips) != 1 || !ips[0].Equal(net.IP{1, 2, 3, 4}) {
+		t.Error("unexpected stale answer: ", ips)
+	}
+	if ttl := time.Until(expireAt); ttl <= 0 || ttl > staleAnswerTTL {
+		t.Error("unexpected stale TTL: ", ttl)
+	}
+	waitForQueries(t, server, 2)
+
+	// The failed refresh must not be retried immediately.
+	if _, _, err := client.QueryIPWithTTL(context.Background(), "example.com", option); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	waitForQueries(t, server, 2)
+}
+
+func TestClientPrefetch(t *testing.T) {
+	server := &countingServer{ttl: 2 * time.Second}
+	client := newCacheTestClient(server, CacheStrategy_CachePrefetch)
+	option := feature_dns.IPOption{IPv4Enable: true}
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := client.QueryIPWithTTL(context.Background(), "example.com", option); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitForQueries(t, server, 1)
+
+	time.Sleep(1100 * time.Millisecond)
+	if _, _, err := client.QueryIPWithTTL(context.Background(), "example.com", option); err != nil {
+		t.Fatal(err)
+	}
+	waitForQueries(t, server, 2)
+
+	_, expireAt, err := client.QueryIPWithTTL(context.Background(), "example.com", option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(expireAt) < time.Second {
+		t.Error("expect prefetched answer, but got TTL ", time.Until(expireAt))
+	}
+}
+
+func TestClientWithoutServeStale(t *testing.T) {
+	server := &countingServer{ttl: 50 * time.Millisecond}
+	client := newCacheTestClient(server, CacheStrategy_CacheEnabled)
+	option := feature_dns.IPOption{IPv4Enable: true}
+
+	if _, _, err := client.QueryIPWithTTL(context.Background(), "example.com", option); err != nil {
+		t.Fatal(err)
+	}
+	server.broken.Store(true)
+	time.Sleep(100 * time.Millisecond)
+	if _, _, err := client.QueryIPWithTTL(context.Background(), "example.com", option); err == nil {
+		t.Error("expect error without serve-stale")
+	}
+}
+
+func TestRecordCacheKeepsStaleRecords(t *testing.T) {
+	cache := newRecordCache(0, 0, 0)
+	stale := cache.newScope("stale")
+	stale.policy = newCachePolicy(CacheStrategy_CacheServeStale, func(*net.IPNet, string, feature_dns.IPOption) {})
+	fresh := cache.newScope("fresh")
+
+	expired := &IPRecord{
+		IP:     []net.Address{net.IPAddress([]byte{1, 2, 3, 4})},
+		Expire: time.Now().Add(-time.Minute),
+		TTL:    60,
+	}
+	stale.update("example.com.", record{A: expired})
+	fresh.update("example.com.", record{A: expired})
+	if err := cache.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found := fresh.get("example.com."); found {
+		t.Error("expect expired record removed")
+	}
+	ips, _, err := stale.lookup("example.com.", feature_dns.IPOption{IPv4Enable: true})
+	if err != nil || len(ips) != 1 {
+		t.Error("expect stale record served, but got ", ips, err)
+	}
+}
+
+func TestFlushStaleRecords(t *testing.T) {
+	server := &countingServer{ttl: 50 * time.Millisecond}
+	client := newCacheTestClient(server, CacheStrategy_CacheServeStaleAndPrefetch)
+	option := feature_dns.IPOption{IPv4Enable: true}
+
+	if _, _, err := client.QueryIPWithTTL(context.Background(), "example.com", option); err != nil {
+		t.Fatal(err)
+	}
+	server.broken.Store(true)
+	time.Sleep(100 * time.Millisecond)
+
+	entries := client.recordCache.Entries("", "example.com")
+	if len(entries) != 1 || !entries[0].Stale || entries[0].Server != "counting" {
+		t.Fatal("expect a stale entry, but got ", entries)
+	}
+	if n := client.recordCache.Flush("counting", "example.com"); n != 1 {
+		t.Error("expect 1 domain flushed, but got ", n)
+	}
+	if _, _, err := client.QueryIPWithTTL(context.Background(), "example.com", option); err == nil {
+		t.Error("expect error after the stale record is flushed")
+	}
+}
+
+func TestServeStaleForSubnet(t *testing.T) {
+	refreshed := make(chan *net.IPNet, 1)
+	scope := newRecordCache(0, 0, 0).newScope("test")
+	scope.policy = newCachePolicy(CacheStrategy_CacheServeStale, func(subnet *net.IPNet, _ string, _ feature_dns.IPOption) {
+		refreshed <- subnet
+	})
+	_, subnet, _ := net.ParseCIDR("1.2.3.0/24")
+	subnetScope := scope.forSubnet(subnet)
+	subnetScope.update("example.com.", record{A: &IPRecord{
+		IP:     []net.Address{net.IPAddress([]byte{1, 2, 3, 4})},
+		Expire: time.Now().Add(-time.Minute),
+		TTL:    60,
+	}})
+
+	ips, _, err := subnetScope.lookup("example.com.", feature_dns.IPOption{IPv4Enable: true})
+	if err != nil || len(ips) != 1 {
+		t.Error("expect stale record served, but got ", ips, err)
+	}
+	select {
+	case s := <-refreshed:
+		if s.String() != subnet.String() {
+			t.Error("expect refresh for subnet ", subnet, ", but got ", s)
+		}
+	case <-time.After(time.Second):
+		t.Error("expect stale record refreshed")
+	}
+}
+
+func TestSubnetRecordsShareCapacity(t *testing.T) {
+	cache := newRecordCache(2, 0, 0)
+	scope := cache.newScope("test")
+	for _, cidr := range []string{"1.2.3.0/24", "1.2.4.0/24", "1.2.5.0/24"} {
+		_, subnet, _ := net.ParseCIDR(cidr)
+		scope.forSubnet(subnet).update("example.com.", record{A: &IPRecord{
+			IP:     []net.Address{net.IPAddress(subnet.IP)},
+			Expire: time.Now().Add(time.Minute),
+			TTL:    60,
+		}})
+	}
+	if stats := cache.Stats(); stats.Size != 2 || stats.Evictions != 1 {
+		t.Error("expect records of subnets bounded by the capacity, but got ", stats)
+	}
+
+	_, subnet, _ := net.ParseCIDR("1.2.5.0/24")
+	ips, _, err := scope.forSubnet(subnet).lookup("example.com.", feature_dns.IPOption{IPv4Enable: true})
+	if err != nil || len(ips) != 1 || !ips[0].Equal(subnet.IP) {
+		t.Error("expect record of the subnet, but got ", ips, err)
+	}
+	if _, _, err := scope.lookup("example.com.", feature_dns.IPOption{IPv4Enable: true}); err == nil {
+		t.Error("expect records of subnets apart from the name server")
+	}
+}
@@ -0,0 +1,291 @@
+package dns
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/strmatcher"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+const (
+	defaultClientSubnetIPv4Prefix = 24
+	defaultClientSubnetIPv6Prefix = 56
+)
+
+// clientSubnetRule chooses the client IP for the queries of the domains on behalf of the users.
+type clientSubnetRule struct {
+	domains  []strmatcher.Matcher
+	emails   map[string]bool
+	clientIP net.IP
+}
+
+func (r *clientSubnetRule) match(domain string, email string) bool {
+	if len(r.emails) > 0 && !r.emails[strings.ToLower(email)] {
+		return false
+	}
+	if len(r.domains) == 0 {
+		return true
+	}
+	for _, matcher := range r.domains {
+		if matcher.Match(domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientSubnetSelector chooses the EDNS client subnet of a query by the domain and the client it is made on behalf of.
+type clientSubnetSelector struct {
+	fromSource bool
+	ipv4Prefix int
+	ipv6Prefix int
+	rules      []*clientSubnetRule
+}
+
+func newClientSubnetSelector(config *ClientSubnet) (*clientSubnetSelector, error) {
+	s := &clientSubnetSelector{
+		fromSource: config.FromSource,
+		ipv4Prefix: int(config.Ipv4Prefix),
+		ipv6Prefix: int(config.Ipv6Prefix),
+	}
+	if s.ipv4Prefix == 0 {
+		s.ipv4Prefix = defaultClientSubnetIPv4Prefix
+	}
+	if s.ipv6Prefix == 0 {
+		s.ipv6Prefix = defaultClientSubnetIPv6Prefix
+	}
+	if s.ipv4Prefix > 32 || s.ipv6Prefix > 128 {
+		return nil, newError("invalid client subnet prefix: ", s.ipv4Prefix, ", ", s.ipv6Prefix)
+	}
+	for _, r := range config.Rule {
+		rule := &clientSubnetRule{}
+		for _, domain := range r.Domain {
+			matcher, err := toStrMatcher(domain.Type, domain.Domain)
+			if err != nil {
+				return nil, newError("failed to create client subnet rule").Base(err)
+			}
+			rule.domains = append(rule.domains, matcher)
+		}
+		if len(r.UserEmail) > 0 {
+			rule.emails = make(map[string]bool, len(r.UserEmail))
+			for _, email := range r.UserEmail {
+				rule.emails[strings.ToLower(email)] = true
+			}
+		}
+		if len(r.ClientIp) > 0 {
+			switch ip := net.IP(r.ClientIp); {
+			case ip.To4() != nil:
+				rule.clientIP = ip.To4()
+			case len(ip) == net.IPv6len:
+				rule.clientIP = ip
+			default:
+				return nil, newError("invalid client IP of client subnet rule: ", r.ClientIp)
+			}
+		}
+		s.rules = append(s.rules, rule)
+	}
+	return s, nil
+}
+
+// toClientSubnet converts the simplified config with the client IPs in text. It returns nil if the config is nil.
+func (c *SimplifiedClientSubnet) toClientSubnet() (*ClientSubnet, error) {
+	if c == nil {
+		return nil, nil
+	}
+	config := &ClientSubnet{
+		FromSource: c.FromSource,
+		Ipv4Prefix: c.Ipv4Prefix,
+		Ipv6Prefix: c.Ipv6Prefix,
+	}
+	for _, r := range c.Rule {
+		rule := &ClientSubnet_Rule{UserEmail: r.UserEmail}
+		if r.ClientIp != "" {
+			if rule.ClientIp = net.ParseIP(r.ClientIp); rule.ClientIp == nil {
+				return nil, newError("invalid client IP of client subnet: ", r.ClientIp)
+			}
+		}
+		for _, domain := range r.Domain {
+			rule.Domain = append(rule.Domain, &NameServer_PriorityDomain{
+				Type:   domain.Type,
+				Domain: domain.Domain,
+			})
+		}
+		config.Rule = append(config.Rule, rule)
+	}
+	return config, nil
+}
+
+// subnet returns the client subnet for the query, or nil if the static client IP should be used.
+func (s *clientSubnetSelector) subnet(ctx context.Context, domain string) *net.IPNet {
+	var source net.IP
+	var email string
+	if inbound := queryInboundFromContext(ctx); inbound != nil {
+		if inbound.Source.IsValid() && inbound.Source.Address.Family().IsIP() {
+			source = inbound.Source.Address.IP()
+		}
+		if inbound.User != nil {
+			email = inbound.User.Email
+		}
+	}
+
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	for _, rule := range s.rules {
+		if !rule.match(domain, email) {
+			continue
+		}
+		if rule.clientIP != nil {
+			return s.truncate(rule.clientIP)
+		}
+		return s.truncateSource(source)
+	}
+	if s.fromSource {
+		return s.truncateSource(source)
+	}
+	return nil
+}
+
+// truncateSource returns the subnet of the source address, or nil if the address is not routable on the Internet.
+func (s *clientSubnetSelector) truncateSource(source net.IP) *net.IPNet {
+	if source == nil || !source.IsGlobalUnicast() || source.IsPrivate() {
+		return nil
+	}
+	return s.truncate(source)
+}
+
+func (s *clientSubnetSelector) truncate(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		mask := net.CIDRMask(s.ipv4Prefix, 8*net.IPv4len)
+		return &net.IPNet{IP: ip4.Mask(mask), Mask: mask}
+	}
+	mask := net.CIDRMask(s.ipv6Prefix, 8*net.IPv6len)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
+
+// setEDNS0Subnet sets the client subnet of the DNS message, replacing the one in the message if any. It returns true
+// if the message had no OPT record, which is added.
+func setEDNS0Subnet(msg *dns.Msg, subnet *net.IPNet) bool {
+	prefix, _ := subnet.Mask.Size()
+	ecs := newEDNS0Subnet(subnet.IP, uint8(prefix))
+	opt := msg.IsEdns0()
+	if opt == nil {
+		opt = &dns.OPT{Hdr: dns.RR_Header{Name: ".", Rrtype: dns.TypeOPT}}
+		opt.SetUDPSize(dns.MinMsgSize)
+		opt.Option = append(opt.Option, ecs)
+		msg.Extra = append(msg.Extra, opt)
+		return true
+	}
+	options := make([]dns.EDNS0, 0, len(opt.Option)+1)
+	for _, o := range opt.Option {
+		if o.Option() != dns.EDNS0SUBNET {
+			options = append(options, o)
+		}
+	}
+	opt.Option = append(options, ecs)
+	return false
+}
+
+// queryIPWithSubnet sends IP queries with the client subnet. The answers are cached apart from other subnets.
+func (c *Client) queryIPWithSubnet(ctx context.Context, domain string, subnet *net.IPNet, option feature_dns.IPOption, disableCache bool) ([]net.IP, time.Time, error) {
+	fqdn := Fqdn(domain)
+	var cache *recordCacheScope
+	if c.cache != nil {
+		cache = c.cache.forSubnet(subnet)
+	}
+	if !disableCache && cache != nil {
+		ips, expireAt, err := cache.lookup(fqdn, option)
+		if err != errRecordNotFound {
+			newError(c.Name(), " cache HIT ", domain, " for ", subnet, " -> ", ips).Base(err).AtDebug().WriteToLog()
+			markCacheHit(ctx)
+			if err != nil {
+				return ips, expireAt, err
+			}
+			ips, err = c.MatchExpectedIPs(domain, ips)
+			return ips, expireAt, err
+		}
+	}
+
+	prefix, _ := subnet.Mask.Size()
+	opt := newEDNS0Options(newEDNS0Subnet(subnet.IP, uint8(prefix)))
+	reqs := buildReqMsgs(fqdn, option, func() uint16 { return 0 }, opt)
+
+	type result struct {
+		reqType uint16
+		record  *IPRecord
+		err     error
+	}
+	results := make(chan result, len(reqs))
+	for _, req := range reqs {
+		// The requests share the OPT record, which is modified when packed.
+		request, err := req.msg.Pack()
+		if err != nil {
+			return nil, time.Time{}, newError("failed to pack dns query").Base(err)
+		}
+		go func(reqType uint16, request []byte) {
+			response, err := c.queryRaw(ctx, request, false)
+			if err != nil {
+				results <- result{reqType, nil, err}
+				return
+			}
+			rec, err := parseResponse(response)
+			results <- result{reqType, rec, err}
+		}(req.reqType, request)
+	}
+	// A failed query of one family does not fail the other, like the name servers querying both families.
+	var rec record
+	var queryErr error
+	for range reqs {
+		r := <-results
+		if r.err != nil {
+			newError(c.Name(), " failed to query ", dns.Type(r.reqType), " of ", domain, " for ", subnet).Base(r.err).AtDebug().WriteToLog()
+			queryErr = r.err
+			continue
+		}
+		switch r.reqType {
+		case dns.TypeA:
+			rec.A = r.record
+		case dns.TypeAAAA:
+			rec.AAAA = r.record
+		}
+	}
+	if rec.A == nil && rec.AAAA == nil {
+		return nil, time.Time{}, queryErr
+	}
+	if cache != nil {
+		cache.update(fqdn, rec)
+	}
+
+	var ips []net.Address
+	var expireAt time.Time
+	var lastErr error
+	for _, r := range []*IPRecord{rec.A, rec.AAAA} {
+		if r == nil {
+			continue
+		}
+		addrs, expire, err := r.getIPs()
+		if err != nil && err != errRecordNotFound {
+			lastErr = err
+		}
+		expireAt = expire
+		ips = append(ips, addrs...)
+	}
+	if len(ips) == 0 {
+		if lastErr == nil {
+			lastErr = queryErr
+		}
+		if lastErr != nil {
+			return nil, expireAt, lastErr
+		}
+		return nil, expireAt, feature_dns.ErrEmptyResponse
+	}
+	netIPs, err := toNetIP(ips)
+	if err != nil {
+		return nil, expireAt, err
+	}
+	netIPs, err = c.MatchExpectedIPs(domain, netIPs)
+	return netIPs, expireAt, err
+}
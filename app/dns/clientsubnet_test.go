@@ -0,0 +1,222 @@
+package dns
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/miekg/dns"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+// subnetEchoServer answers A queries with the address of their EDNS client subnet.
+type subnetEchoServer struct {
+	queries  int32
+	failAAAA bool
+}
+
+func (s *subnetEchoServer) Name() string { return "echo" }
+
+func (s *subnetEchoServer) QueryIP(ctx context.Context, domain string, clientIP net.IP, option feature_dns.IPOption, disableCache bool) ([]net.IP, error) {
+	ips, _, err := s.QueryIPWithTTL(ctx, domain, clientIP, option, disableCache)
+	return ips, err
+}
+
+func (s *subnetEchoServer) QueryIPWithTTL(context.Context, string, net.IP, feature_dns.IPOption, bool) ([]net.IP, time.Time, error) {
+	return []net.IP{{9, 9, 9, 9}}, time.Now().Add(time.Minute), nil
+}
+
+func (s *subnetEchoServer) NewReqID() uint16 { return 1 }
+
+func (s *subnetEchoServer) QueryRaw(_ context.Context, b []byte) ([]byte, error) {
+	atomic.AddInt32(&s.queries, 1)
+	request := new(dns.Msg)
+	common.Must(request.Unpack(b))
+	if s.failAAAA && request.Question[0].Qtype == dns.TypeAAAA {
+		return nil, context.DeadlineExceeded
+	}
+	response := new(dns.Msg)
+	response.SetReply(request)
+	if opt := request.IsEdns0(); opt != nil {
+		response.Extra = append(response.Extra, opt)
+	}
+	if opt := request.IsEdns0(); opt != nil && request.Question[0].Qtype == dns.TypeA {
+		for _, option := range opt.Option {
+			if subnet, ok := option.(*dns.EDNS0_SUBNET); ok {
+				response.Answer = append(response.Answer, &dns.A{
+					Hdr: dns.RR_Header{Name: request.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+					A:   subnet.Address,
+				})
+			}
+		}
+	}
+	return response.Pack()
+}
+
+func contextWithInbound(source string, email string) context.Context {
+	inbound := &session.Inbound{Source: net.UDPDestination(net.ParseAddress(source), 53)}
+	if email != "" {
+		inbound.User = &protocol.MemoryUser{Email: email}
+	}
+	return contextWithQueryInfo(context.Background(), &queryInfo{inbound: inbound})
+}
+
+func TestClientSubnetSelector(t *testing.T) {
+	selector, err := newClientSubnetSelector(&ClientSubnet{
+		FromSource: true,
+		Rule: []*ClientSubnet_Rule{
+			{
+				Domain:   []*NameServer_PriorityDomain{{Type: DomainMatchingType_Subdomain, Domain: "example.com"}},
+				ClientIp: []byte{8, 8, 8, 8},
+			},
+			{
+				UserEmail: []string{"Static@example.com"},
+				ClientIp:  net.ParseIP("2001:db8::1"),
+			},
+		},
+	})
+	common.Must(err)
+
+	for _, tc := range []struct {
+		ctx    context.Context
+		domain string
+		subnet string
+	}{
+		{contextWithInbound("1.2.3.4", ""), "v2fly.org", "1.2.3.0/24"},
+		{contextWithInbound("2001:db8:1234:5678::1", ""), "v2fly.org", "2001:db8:1234:5600::/56"},
+		{contextWithInbound("1.2.3.4", ""), "www.example.com.", "8.8.8.0/24"},
+		{contextWithInbound("1.2.3.4", "static@example.com"), "v2fly.org", "2001:db8::/56"},
+		{contextWithInbound("192.168.1.1", ""), "v2fly.org", "<nil>"},
+		{contextWithInbound("127.0.0.1", ""), "v2fly.org", "<nil>"},
+		{context.Background(), "v2fly.org", "<nil>"},
+	} {
+		if subnet := selector.subnet(tc.ctx, tc.domain); subnet.String() != tc.subnet {
+			t.Error("expect subnet ", tc.subnet, " for ", tc.domain, ", but got ", subnet)
+		}
+	}
+}
+
+func TestClientQueryWithSubnet(t *testing.T) {
+	server := new(subnetEchoServer)
+	selector, err := newClientSubnetSelector(&ClientSubnet{FromSource: true})
+	common.Must(err)
+	client := &Client{
+		server:        server,
+		clientSubnet:  selector,
+		queryStrategy: feature_dns.IPOption{IPv4Enable: true, IPv6Enable: true},
+	}
+	client.setRecordCache(newRecordCache(0, 0, 0))
+	option := feature_dns.IPOption{IPv4Enable: true}
+
+	for _, tc := range []struct {
+		source string
+		ip     net.IP
+	}{
+		{"1.2.3.4", net.IP{1, 2, 3, 0}},
+		{"5.6.7.8", net.IP{5, 6, 7, 0}},
+		{"1.2.3.5", net.IP{1, 2, 3, 0}},
+		{"10.0.0.1", net.IP{9, 9, 9, 9}},
+	} {
+		ips, err := client.QueryIP(contextWithInbound(tc.source, ""), "example.com", option)
+		common.Must(err)
+		if len(ips) != 1 || !ips[0].Equal(tc.ip) {
+			t.Error("expect ", tc.ip, " for source ", tc.source, ", but got ", ips)
+		}
+	}
+	if queries := atomic.LoadInt32(&server.queries); queries != 2 {
+		t.Error("expect 2 queries with distinct subnets, but got ", queries)
+	}
+}
+
+func TestClientQueryWithSubnetFamilyFailure(t *testing.T) {
+	server := &subnetEchoServer{failAAAA: true}
+	selector, err := newClientSubnetSelector(&ClientSubnet{FromSource: true})
+	common.Must(err)
+	client := &Client{
+		server:        server,
+		clientSubnet:  selector,
+		queryStrategy: feature_dns.IPOption{IPv4Enable: true, IPv6Enable: true},
+	}
+	client.setRecordCache(newRecordCache(0, 0, 0))
+
+	ips, err := client.QueryIP(contextWithInbound("1.2.3.4", ""), "example.com", feature_dns.IPOption{IPv4Enable: true, IPv6Enable: true})
+	common.Must(err)
+	if len(ips) != 1 || !ips[0].Equal(net.IP{1, 2, 3, 0}) {
+		t.Error("expect 1.2.3.0 despite the failed AAAA query, but got ", ips)
+	}
+	if _, err := client.QueryIP(contextWithInbound("1.2.3.4", ""), "example.com", feature_dns.IPOption{IPv6Enable: true}); err != context.DeadlineExceeded {
+		t.Error("expect the error of the failed AAAA query, but got ", err)
+	}
+}
+
+func TestClientQueryRawWithSubnet(t *testing.T) {
+	selector, err := newClientSubnetSelector(&ClientSubnet{FromSource: true})
+	common.Must(err)
+	client := &Client{server: new(subnetEchoServer), clientSubnet: selector}
+
+	for _, tc := range []struct {
+		name   string
+		subnet *dns.EDNS0_SUBNET
+	}{
+		{"without OPT", nil},
+		{"with client subnet", newEDNS0Subnet(net.IP{8, 8, 8, 0}, 24)},
+	} {
+		msg := new(dns.Msg)
+		msg.SetQuestion("example.com.", dns.TypeA)
+		if tc.subnet != nil {
+			msg.Extra = append(msg.Extra, newEDNS0Options(tc.subnet))
+		}
+		request, err := msg.Pack()
+		common.Must(err)
+
+		response, err := client.QueryRaw(contextWithInbound("1.2.3.4", ""), request, false)
+		common.Must(err)
+		common.Must(msg.Unpack(response))
+		if len(msg.Answer) != 1 || !msg.Answer[0].(*dns.A).A.Equal(net.IP{1, 2, 3, 0}) {
+			t.Error(tc.name, ": expect answer of subnet 1.2.3.0/24, but got ", msg.Answer)
+		}
+		if opt := msg.IsEdns0(); (opt == nil) != (tc.subnet == nil) {
+			t.Error(tc.name, ": unexpected OPT record in response: ", opt)
+		}
+	}
+}
+
+func TestSimplifiedClientSubnet(t *testing.T) {
+	config, err := (&SimplifiedClientSubnet{
+		FromSource: true,
+		Ipv4Prefix: 16,
+		Rule: []*SimplifiedClientSubnet_Rule{{
+			Domain:    []*SimplifiedNameServer_PriorityDomain{{Type: DomainMatchingType_Subdomain, Domain: "example.com"}},
+			UserEmail: []string{"love@v2fly.org"},
+			ClientIp:  "1.2.3.4",
+		}},
+	}).toClientSubnet()
+	common.Must(err)
+	expected := &ClientSubnet{
+		FromSource: true,
+		Ipv4Prefix: 16,
+		Rule: []*ClientSubnet_Rule{{
+			Domain:    []*NameServer_PriorityDomain{{Type: DomainMatchingType_Subdomain, Domain: "example.com"}},
+			UserEmail: []string{"love@v2fly.org"},
+			ClientIp:  net.ParseIP("1.2.3.4"),
+		}},
+	}
+	if r := cmp.Diff(config, expected, protocmp.Transform()); r != "" {
+		t.Error(r)
+	}
+
+	if config, err := (*SimplifiedClientSubnet)(nil).toClientSubnet(); config != nil || err != nil {
+		t.Error("expect nil for nil config, but got ", config, err)
+	}
+	if _, err := (&SimplifiedClientSubnet{Rule: []*SimplifiedClientSubnet_Rule{{ClientIp: "invalid"}}}).toClientSubnet(); err == nil {
+		t.Error("expect error for invalid client IP")
+	}
+}
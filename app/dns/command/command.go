@@ -0,0 +1,93 @@
+package command
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+import (
+	"context"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/common"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+// dnsServer is an implementation of DnsService.
+type dnsServer struct {
+	client feature_dns.Client
+}
+
+// NewDNSServer creates a DNS service with the DNS client.
+func NewDNSServer(client feature_dns.Client) DnsServiceServer {
+	return &dnsServer{client: client}
+}
+
+func (s *dnsServer) cacheManager() (feature_dns.CacheManager, error) {
+	cm, ok := s.client.(feature_dns.CacheManager)
+	if !ok {
+		return nil, newError("unsupported DNS client implementation")
+	}
+	return cm, nil
+}
+
+func (s *dnsServer) GetCache(ctx context.Context, request *GetCacheRequest) (*GetCacheResponse, error) {
+	cm, err := s.cacheManager()
+	if err != nil {
+		return nil, err
+	}
+	response := &GetCacheResponse{}
+	for _, entry := range cm.GetCacheEntries(request.Server, request.Domain) {
+		msg := &CacheEntry{
+			Server:     entry.Server,
+			Domain:     entry.Domain,
+			Type:       dns.Type(entry.Type).String(),
+			Rcode:      dns.RcodeToString[entry.RCode],
+			ExpireTime: entry.Expire.Unix(),
+			Stale:      entry.Stale,
+			Hits:       entry.Hits,
+		}
+		for _, ip := range entry.IPs {
+			msg.Ip = append(msg.Ip, ip.String())
+		}
+		response.Entries = append(response.Entries, msg)
+	}
+	stats := cm.GetCacheStats()
+	response.Stats = &CacheStats{
+		Size:      int64(stats.Size),
+		Capacity:  int64(stats.Capacity),
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		Evictions: stats.Evictions,
+	}
+	return response, nil
+}
+
+func (s *dnsServer) FlushCache(ctx context.Context, request *FlushCacheRequest) (*FlushCacheResponse, error) {
+	cm, err := s.cacheManager()
+	if err != nil {
+		return nil, err
+	}
+	flushed := cm.FlushCache(request.Server, request.Domain)
+	newError("flushed ", flushed, " domains from DNS cache").AtInfo().WriteToLog()
+	return &FlushCacheResponse{Flushed: int64(flushed)}, nil
+}
+
+func (s *dnsServer) mustEmbedUnimplementedDnsServiceServer() {}
+
+type service struct {
+	v *core.Instance
+}
+
+func (s *service) Register(server *grpc.Server) {
+	common.Must(s.v.RequireFeatures(func(client feature_dns.Client) {
+		RegisterDnsServiceServer(server, NewDNSServer(client))
+	}))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := core.MustFromContext(ctx)
+		return &service{v: s}, nil
+	}))
+}
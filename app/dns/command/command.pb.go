@@ -0,0 +1,546 @@
+package command
+
+import (
+	_ "github.com/v2fly/v2ray-core/v5/common/protoext"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// GetCacheRequest queries the DNS cache.
+// * Server selects records of the name server, e.g. "UDP//8.8.8.8:53". All
+// name servers are selected if left empty.
+// * Domain selects records of the domain. All domains are selected if left
+// empty.
+type GetCacheRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCacheRequest) Reset() {
+	*x = GetCacheRequest{}
+	mi := &file_app_dns_command_command_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCacheRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCacheRequest) ProtoMessage() {}
+
+func (x *GetCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_command_command_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCacheRequest.ProtoReflect.Descriptor instead.
+func (*GetCacheRequest) Descriptor() ([]byte, []int) {
+	return file_app_dns_command_command_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetCacheRequest) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *GetCacheRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type CacheEntry struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Server string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Domain string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Query type of the record, either "A" or "AAAA".
+	Type string   `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Ip   []string `protobuf:"bytes,4,rep,name=ip,proto3" json:"ip,omitempty"`
+	// Response code of the record, e.g. "NOERROR" or "NXDOMAIN".
+	Rcode string `protobuf:"bytes,5,opt,name=rcode,proto3" json:"rcode,omitempty"`
+	// Unix timestamp in seconds when the record expires.
+	ExpireTime int64 `protobuf:"varint,6,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	// Whether the record has expired, and is kept only to be served stale.
+	Stale bool `protobuf:"varint,7,opt,name=stale,proto3" json:"stale,omitempty"`
+	// Number of lookups of the domain since its records are updated.
+	Hits          uint32 `protobuf:"varint,8,opt,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheEntry) Reset() {
+	*x = CacheEntry{}
+	mi := &file_app_dns_command_command_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheEntry) ProtoMessage() {}
+
+func (x *CacheEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_command_command_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheEntry.ProtoReflect.Descriptor instead.
+func (*CacheEntry) Descriptor() ([]byte, []int) {
+	return file_app_dns_command_command_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CacheEntry) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *CacheEntry) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *CacheEntry) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CacheEntry) GetIp() []string {
+	if x != nil {
+		return x.Ip
+	}
+	return nil
+}
+
+func (x *CacheEntry) GetRcode() string {
+	if x != nil {
+		return x.Rcode
+	}
+	return ""
+}
+
+func (x *CacheEntry) GetExpireTime() int64 {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return 0
+}
+
+func (x *CacheEntry) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *CacheEntry) GetHits() uint32 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+type CacheStats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of cached domains.
+	Size          int64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Capacity      int64 `protobuf:"varint,2,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Hits          int64 `protobuf:"varint,3,opt,name=hits,proto3" json:"hits,omitempty"`
+	Misses        int64 `protobuf:"varint,4,opt,name=misses,proto3" json:"misses,omitempty"`
+	Evictions     int64 `protobuf:"varint,5,opt,name=evictions,proto3" json:"evictions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheStats) Reset() {
+	*x = CacheStats{}
+	mi := &file_app_dns_command_command_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheStats) ProtoMessage() {}
+
+func (x *CacheStats) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_command_command_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheStats.ProtoReflect.Descriptor instead.
+func (*CacheStats) Descriptor() ([]byte, []int) {
+	return file_app_dns_command_command_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CacheStats) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *CacheStats) GetCapacity() int64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *CacheStats) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *CacheStats) GetMisses() int64 {
+	if x != nil {
+		return x.Misses
+	}
+	return 0
+}
+
+func (x *CacheStats) GetEvictions() int64 {
+	if x != nil {
+		return x.Evictions
+	}
+	return 0
+}
+
+type GetCacheResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*CacheEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Stats         *CacheStats            `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCacheResponse) Reset() {
+	*x = GetCacheResponse{}
+	mi := &file_app_dns_command_command_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCacheResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCacheResponse) ProtoMessage() {}
+
+func (x *GetCacheResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_command_command_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCacheResponse.ProtoReflect.Descriptor instead.
+func (*GetCacheResponse) Descriptor() ([]byte, []int) {
+	return file_app_dns_command_command_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetCacheResponse) GetEntries() []*CacheEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetCacheResponse) GetStats() *CacheStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// FlushCacheRequest removes records from the DNS cache. Server and domain
+// select records as in GetCacheRequest.
+type FlushCacheRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlushCacheRequest) Reset() {
+	*x = FlushCacheRequest{}
+	mi := &file_app_dns_command_command_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlushCacheRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlushCacheRequest) ProtoMessage() {}
+
+func (x *FlushCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_command_command_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlushCacheRequest.ProtoReflect.Descriptor instead.
+func (*FlushCacheRequest) Descriptor() ([]byte, []int) {
+	return file_app_dns_command_command_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FlushCacheRequest) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *FlushCacheRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type FlushCacheResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of removed domains.
+	Flushed       int64 `protobuf:"varint,1,opt,name=flushed,proto3" json:"flushed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlushCacheResponse) Reset() {
+	*x = FlushCacheResponse{}
+	mi := &file_app_dns_command_command_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlushCacheResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlushCacheResponse) ProtoMessage() {}
+
+func (x *FlushCacheResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_command_command_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlushCacheResponse.ProtoReflect.Descriptor instead.
+func (*FlushCacheResponse) Descriptor() ([]byte, []int) {
+	return file_app_dns_command_command_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FlushCacheResponse) GetFlushed() int64 {
+	if x != nil {
+		return x.Flushed
+	}
+	return 0
+}
+
+type Config struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_app_dns_command_command_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_command_command_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_app_dns_command_command_proto_rawDescGZIP(), []int{6}
+}
+
+var File_app_dns_command_command_proto protoreflect.FileDescriptor
+
+const file_app_dns_command_command_proto_rawDesc = "" +
+	"\n" +
+	"\x1dapp/dns/command/command.proto\x12\x1av2ray.core.app.dns.command\x1a common/protoext/extensions.proto\"A\n" +
+	"\x0fGetCacheRequest\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\"\xc1\x01\n" +
+	"\n" +
+	"CacheEntry\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02ip\x18\x04 \x03(\tR\x02ip\x12\x14\n" +
+	"\x05rcode\x18\x05 \x01(\tR\x05rcode\x12\x1f\n" +
+	"\vexpire_time\x18\x06 \x01(\x03R\n" +
+	"expireTime\x12\x14\n" +
+	"\x05stale\x18\a \x01(\bR\x05stale\x12\x12\n" +
+	"\x04hits\x18\b \x01(\rR\x04hits\"\x86\x01\n" +
+	"\n" +
+	"CacheStats\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x03R\x04size\x12\x1a\n" +
+	"\bcapacity\x18\x02 \x01(\x03R\bcapacity\x12\x12\n" +
+	"\x04hits\x18\x03 \x01(\x03R\x04hits\x12\x16\n" +
+	"\x06misses\x18\x04 \x01(\x03R\x06misses\x12\x1c\n" +
+	"\tevictions\x18\x05 \x01(\x03R\tevictions\"\x92\x01\n" +
+	"\x10GetCacheResponse\x12@\n" +
+	"\aentries\x18\x01 \x03(\v2&.v2ray.core.app.dns.command.CacheEntryR\aentries\x12<\n" +
+	"\x05stats\x18\x02 \x01(\v2&.v2ray.core.app.dns.command.CacheStatsR\x05stats\"C\n" +
+	"\x11FlushCacheRequest\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\".\n" +
+	"\x12FlushCacheResponse\x12\x18\n" +
+	"\aflushed\x18\x01 \x01(\x03R\aflushed\" \n" +
+	"\x06Config:\x16\x82\xb5\x18\x12\n" +
+	"\vgrpcservice\x12\x03dns2\xe4\x01\n" +
+	"\n" +
+	"DnsService\x12g\n" +
+	"\bGetCache\x12+.v2ray.core.app.dns.command.GetCacheRequest\x1a,.v2ray.core.app.dns.command.GetCacheResponse\"\x00\x12m\n" +
+	"\n" +
+	"FlushCache\x12-.v2ray.core.app.dns.command.FlushCacheRequest\x1a..v2ray.core.app.dns.command.FlushCacheResponse\"\x00Bo\n" +
+	"\x1ecom.v2ray.core.app.dns.commandP\x01Z.github.com/v2fly/v2ray-core/v5/app/dns/command\xaa\x02\x1aV2Ray.Core.App.Dns.Commandb\x06proto3"
+
+var (
+	file_app_dns_command_command_proto_rawDescOnce sync.Once
+	file_app_dns_command_command_proto_rawDescData []byte
+)
+
+func file_app_dns_command_command_proto_rawDescGZIP() []byte {
+	file_app_dns_command_command_proto_rawDescOnce.Do(func() {
+		file_app_dns_command_command_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_app_dns_command_command_proto_rawDesc), len(file_app_dns_command_command_proto_rawDesc)))
+	})
+	return file_app_dns_command_command_proto_rawDescData
+}
+
+var file_app_dns_command_command_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_app_dns_command_command_proto_goTypes = []any{
+	(*GetCacheRequest)(nil),    // 0: v2ray.core.app.dns.command.GetCacheRequest
+	(*CacheEntry)(nil),         // 1: v2ray.core.app.dns.command.CacheEntry
+	(*CacheStats)(nil),         // 2: v2ray.core.app.dns.command.CacheStats
+	(*GetCacheResponse)(nil),   // 3: v2ray.core.app.dns.command.GetCacheResponse
+	(*FlushCacheRequest)(nil),  // 4: v2ray.core.app.dns.command.FlushCacheRequest
+	(*FlushCacheResponse)(nil), // 5: v2ray.core.app.dns.command.FlushCacheResponse
+	(*Config)(nil),             // 6: v2ray.core.app.dns.command.Config
+}
+var file_app_dns_command_command_proto_depIdxs = []int32{
+	1, // 0: v2ray.core.app.dns.command.GetCacheResponse.entries:type_name -> v2ray.core.app.dns.command.CacheEntry
+	2, // 1: v2ray.core.app.dns.command.GetCacheResponse.stats:type_name -> v2ray.core.app.dns.command.CacheStats
+	0, // 2: v2ray.core.app.dns.command.DnsService.GetCache:input_type -> v2ray.core.app.dns.command.GetCacheRequest
+	4, // 3: v2ray.core.app.dns.command.DnsService.FlushCache:input_type -> v2ray.core.app.dns.command.FlushCacheRequest
+	3, // 4: v2ray.core.app.dns.command.DnsService.GetCache:output_type -> v2ray.core.app.dns.command.GetCacheResponse
+	5, // 5: v2ray.core.app.dns.command.DnsService.FlushCache:output_type -> v2ray.core.app.dns.command.FlushCacheResponse
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_app_dns_command_command_proto_init() }
+func file_app_dns_command_command_proto_init() {
+	if File_app_dns_command_command_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_dns_command_command_proto_rawDesc), len(file_app_dns_command_command_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_app_dns_command_command_proto_goTypes,
+		DependencyIndexes: file_app_dns_command_command_proto_depIdxs,
+		MessageInfos:      file_app_dns_command_command_proto_msgTypes,
+	}.Build()
+	File_app_dns_command_command_proto = out.File
+	file_app_dns_command_command_proto_goTypes = nil
+	file_app_dns_command_command_proto_depIdxs = nil
+}
@@ -0,0 +1,78 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/miekg/dns"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	. "github.com/v2fly/v2ray-core/v5/app/dns/command"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+type cacheClient struct {
+	feature_dns.Client
+	entries []*feature_dns.CacheEntry
+}
+
+func (c *cacheClient) GetCacheEntries(server, domain string) []*feature_dns.CacheEntry {
+	var entries []*feature_dns.CacheEntry
+	for _, entry := range c.entries {
+		if (server == "" || entry.Server == server) && (domain == "" || entry.Domain == domain) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func (c *cacheClient) FlushCache(server, domain string) int {
+	n := len(c.entries)
+	c.entries = c.entries[:0]
+	return n
+}
+
+func (c *cacheClient) GetCacheStats() feature_dns.CacheStats {
+	return feature_dns.CacheStats{Size: len(c.entries), Capacity: 4096, Hits: 3, Misses: 1}
+}
+
+func TestServiceCache(t *testing.T) {
+	expire := time.Unix(1700000000, 0)
+	client := &cacheClient{entries: []*feature_dns.CacheEntry{
+		{Server: "UDP//8.8.8.8:53", Domain: "example.com.", Type: dns.TypeA, IPs: []net.IP{{1, 2, 3, 4}}, Expire: expire},
+		{Server: "UDP//1.1.1.1:53", Domain: "example.org.", Type: dns.TypeAAAA, RCode: dns.RcodeNameError, Expire: expire},
+	}}
+	s := NewDNSServer(client)
+
+	resp, err := s.GetCache(context.Background(), &GetCacheRequest{Server: "UDP//8.8.8.8:53"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &GetCacheResponse{
+		Entries: []*CacheEntry{
+			{Server: "UDP//8.8.8.8:53", Domain: "example.com.", Type: "A", Ip: []string{"1.2.3.4"}, Rcode: "NOERROR", ExpireTime: 1700000000},
+		},
+		Stats: &CacheStats{Size: 2, Capacity: 4096, Hits: 3, Misses: 1},
+	}
+	if r := cmp.Diff(expected, resp, protocmp.Transform()); r != "" {
+		t.Error(r)
+	}
+
+	flushResp, err := s.FlushCache(context.Background(), &FlushCacheRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flushResp.Flushed != 2 {
+		t.Error("expect 2 domains flushed, but got ", flushResp.Flushed)
+	}
+}
+
+func TestServiceCacheUnsupported(t *testing.T) {
+	s := NewDNSServer(struct{ feature_dns.Client }{})
+	if _, err := s.GetCache(context.Background(), &GetCacheRequest{}); err == nil {
+		t.Error("expect error for client without cache")
+	}
+}
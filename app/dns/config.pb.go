@@ -125,6 +125,12 @@ type CacheStrategy int32
 const (
 	CacheStrategy_CacheEnabled  CacheStrategy = 0
 	CacheStrategy_CacheDisabled CacheStrategy = 1
+	// Answer with expired records while refreshing them in background (RFC
+	// 8767).
+	CacheStrategy_CacheServeStale CacheStrategy = 2
+	// Refresh popular records in background shortly before they expire.
+	CacheStrategy_CachePrefetch              CacheStrategy = 3
+	CacheStrategy_CacheServeStaleAndPrefetch CacheStrategy = 4
 )
 
 // Enum value maps for CacheStrategy.
@@ -132,10 +138,16 @@ var (
 	CacheStrategy_name = map[int32]string{
 		0: "CacheEnabled",
 		1: "CacheDisabled",
+		2: "CacheServeStale",
+		3: "CachePrefetch",
+		4: "CacheServeStaleAndPrefetch",
 	}
 	CacheStrategy_value = map[string]int32{
-		"CacheEnabled":  0,
-		"CacheDisabled": 1,
+		"CacheEnabled":               0,
+		"CacheDisabled":              1,
+		"CacheServeStale":            2,
+		"CachePrefetch":              3,
+		"CacheServeStaleAndPrefetch": 4,
 	}
 )
 
@@ -215,6 +227,157 @@ func (FallbackStrategy) EnumDescriptor() ([]byte, []int) {
 	return file_app_dns_config_proto_rawDescGZIP(), []int{3}
 }
 
+type ParallelStrategy int32
+
+const (
+	// Query the name servers one by one, falling back on failure.
+	ParallelStrategy_Sequential ParallelStrategy = 0
+	// Query the name servers concurrently, and answer with the first valid
+	// answer.
+	ParallelStrategy_Race ParallelStrategy = 1
+	// Query the name servers concurrently and wait for all of them. Answers
+	// matching the expected IPs of their name servers are preferred.
+	ParallelStrategy_PreferExpectedIPs ParallelStrategy = 2
+)
+
+// Enum value maps for ParallelStrategy.
+var (
+	ParallelStrategy_name = map[int32]string{
+		0: "Sequential",
+		1: "Race",
+		2: "PreferExpectedIPs",
+	}
+	ParallelStrategy_value = map[string]int32{
+		"Sequential":        0,
+		"Race":              1,
+		"PreferExpectedIPs": 2,
+	}
+)
+
+func (x ParallelStrategy) Enum() *ParallelStrategy {
+	p := new(ParallelStrategy)
+	*p = x
+	return p
+}
+
+func (x ParallelStrategy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ParallelStrategy) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_dns_config_proto_enumTypes[4].Descriptor()
+}
+
+func (ParallelStrategy) Type() protoreflect.EnumType {
+	return &file_app_dns_config_proto_enumTypes[4]
+}
+
+func (x ParallelStrategy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ParallelStrategy.Descriptor instead.
+func (ParallelStrategy) EnumDescriptor() ([]byte, []int) {
+	return file_app_dns_config_proto_rawDescGZIP(), []int{4}
+}
+
+type DnssecMode int32
+
+const (
+	DnssecMode_DnssecDisabled DnssecMode = 0
+	// Validate the answers from the chain of trust, and answer SERVFAIL for
+	// bogus ones.
+	DnssecMode_DnssecValidate DnssecMode = 1
+)
+
+// Enum value maps for DnssecMode.
+var (
+	DnssecMode_name = map[int32]string{
+		0: "DnssecDisabled",
+		1: "DnssecValidate",
+	}
+	DnssecMode_value = map[string]int32{
+		"DnssecDisabled": 0,
+		"DnssecValidate": 1,
+	}
+)
+
+func (x DnssecMode) Enum() *DnssecMode {
+	p := new(DnssecMode)
+	*p = x
+	return p
+}
+
+func (x DnssecMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DnssecMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_dns_config_proto_enumTypes[5].Descriptor()
+}
+
+func (DnssecMode) Type() protoreflect.EnumType {
+	return &file_app_dns_config_proto_enumTypes[5]
+}
+
+func (x DnssecMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DnssecMode.Descriptor instead.
+func (DnssecMode) EnumDescriptor() ([]byte, []int) {
+	return file_app_dns_config_proto_rawDescGZIP(), []int{5}
+}
+
+type HostsFile_Format int32
+
+const (
+	// Lines of IP address followed by domains, as in /etc/hosts.
+	HostsFile_Hosts HostsFile_Format = 0
+	// Adblock-style rules of "||domain^". The domains and their subdomains
+	// are mapped to unspecified addresses.
+	HostsFile_Adblock HostsFile_Format = 1
+)
+
+// Enum value maps for HostsFile_Format.
+var (
+	HostsFile_Format_name = map[int32]string{
+		0: "Hosts",
+		1: "Adblock",
+	}
+	HostsFile_Format_value = map[string]int32{
+		"Hosts":   0,
+		"Adblock": 1,
+	}
+)
+
+func (x HostsFile_Format) Enum() *HostsFile_Format {
+	p := new(HostsFile_Format)
+	*p = x
+	return p
+}
+
+func (x HostsFile_Format) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HostsFile_Format) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_dns_config_proto_enumTypes[6].Descriptor()
+}
+
+func (HostsFile_Format) Type() protoreflect.EnumType {
+	return &file_app_dns_config_proto_enumTypes[6]
+}
+
+func (x HostsFile_Format) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HostsFile_Format.Descriptor instead.
+func (HostsFile_Format) EnumDescriptor() ([]byte, []int) {
+	return file_app_dns_config_proto_rawDescGZIP(), []int{3, 0}
+}
+
 type NameServer struct {
 	state             protoimpl.MessageState       `protogen:"open.v1"`
 	Address           *net.Endpoint                `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
@@ -231,8 +394,15 @@ type NameServer struct {
 	QueryStrategy    *QueryStrategy    `protobuf:"varint,8,opt,name=query_strategy,json=queryStrategy,proto3,enum=v2ray.core.app.dns.QueryStrategy,oneof" json:"query_strategy,omitempty"`
 	CacheStrategy    *CacheStrategy    `protobuf:"varint,9,opt,name=cache_strategy,json=cacheStrategy,proto3,enum=v2ray.core.app.dns.CacheStrategy,oneof" json:"cache_strategy,omitempty"`
 	FallbackStrategy *FallbackStrategy `protobuf:"varint,10,opt,name=fallback_strategy,json=fallbackStrategy,proto3,enum=v2ray.core.app.dns.FallbackStrategy,oneof" json:"fallback_strategy,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	Dnssec           DnssecMode        `protobuf:"varint,12,opt,name=dnssec,proto3,enum=v2ray.core.app.dns.DnssecMode" json:"dnssec,omitempty"`
+	// DS records of the trust anchors in presentation format. The root zone KSKs
+	// are used if empty.
+	DnssecTrustAnchor []string `protobuf:"bytes,13,rep,name=dnssec_trust_anchor,json=dnssecTrustAnchor,proto3" json:"dnssec_trust_anchor,omitempty"`
+	// EDNS client subnet chosen per query. It overrides the one of the Config
+	// if set.
+	ClientSubnet  *ClientSubnet `protobuf:"bytes,14,opt,name=client_subnet,json=clientSubnet,proto3" json:"client_subnet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *NameServer) Reset() {
@@ -343,6 +513,102 @@ func (x *NameServer) GetFallbackStrategy() FallbackStrategy {
 	return FallbackStrategy_Enabled
 }
 
+func (x *NameServer) GetDnssec() DnssecMode {
+	if x != nil {
+		return x.Dnssec
+	}
+	return DnssecMode_DnssecDisabled
+}
+
+func (x *NameServer) GetDnssecTrustAnchor() []string {
+	if x != nil {
+		return x.DnssecTrustAnchor
+	}
+	return nil
+}
+
+func (x *NameServer) GetClientSubnet() *ClientSubnet {
+	if x != nil {
+		return x.ClientSubnet
+	}
+	return nil
+}
+
+// ClientSubnet chooses the EDNS client subnet of a query by the queried domain
+// and the client it is made on behalf of. The client_ip of the name server is
+// used if no subnet is chosen.
+type ClientSubnet struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Send the source address of the client, unless a rule is matched.
+	FromSource bool `protobuf:"varint,1,opt,name=from_source,json=fromSource,proto3" json:"from_source,omitempty"`
+	// Prefix lengths the addresses are truncated to. 0 means the default of 24
+	// for IPv4 and 56 for IPv6.
+	Ipv4Prefix uint32 `protobuf:"varint,2,opt,name=ipv4_prefix,json=ipv4Prefix,proto3" json:"ipv4_prefix,omitempty"`
+	Ipv6Prefix uint32 `protobuf:"varint,3,opt,name=ipv6_prefix,json=ipv6Prefix,proto3" json:"ipv6_prefix,omitempty"`
+	// Rules matched in order.
+	Rule          []*ClientSubnet_Rule `protobuf:"bytes,4,rep,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientSubnet) Reset() {
+	*x = ClientSubnet{}
+	mi := &file_app_dns_config_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientSubnet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientSubnet) ProtoMessage() {}
+
+func (x *ClientSubnet) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_config_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientSubnet.ProtoReflect.Descriptor instead.
+func (*ClientSubnet) Descriptor() ([]byte, []int) {
+	return file_app_dns_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ClientSubnet) GetFromSource() bool {
+	if x != nil {
+		return x.FromSource
+	}
+	return false
+}
+
+func (x *ClientSubnet) GetIpv4Prefix() uint32 {
+	if x != nil {
+		return x.Ipv4Prefix
+	}
+	return 0
+}
+
+func (x *ClientSubnet) GetIpv6Prefix() uint32 {
+	if x != nil {
+		return x.Ipv6Prefix
+	}
+	return 0
+}
+
+func (x *ClientSubnet) GetRule() []*ClientSubnet_Rule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
 type HostMapping struct {
 	state  protoimpl.MessageState `protogen:"open.v1"`
 	Type   DomainMatchingType     `protobuf:"varint,1,opt,name=type,proto3,enum=v2ray.core.app.dns.DomainMatchingType" json:"type,omitempty"`
@@ -357,7 +623,7 @@ type HostMapping struct {
 
 func (x *HostMapping) Reset() {
 	*x = HostMapping{}
-	mi := &file_app_dns_config_proto_msgTypes[1]
+	mi := &file_app_dns_config_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -369,7 +635,7 @@ func (x *HostMapping) String() string {
 func (*HostMapping) ProtoMessage() {}
 
 func (x *HostMapping) ProtoReflect() protoreflect.Message {
-	mi := &file_app_dns_config_proto_msgTypes[1]
+	mi := &file_app_dns_config_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -382,7 +648,7 @@ func (x *HostMapping) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HostMapping.ProtoReflect.Descriptor instead.
 func (*HostMapping) Descriptor() ([]byte, []int) {
-	return file_app_dns_config_proto_rawDescGZIP(), []int{1}
+	return file_app_dns_config_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *HostMapping) GetType() DomainMatchingType {
@@ -413,6 +679,77 @@ func (x *HostMapping) GetProxiedDomain() string {
 	return ""
 }
 
+type HostsFile struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Format HostsFile_Format       `protobuf:"varint,1,opt,name=format,proto3,enum=v2ray.core.app.dns.HostsFile_Format" json:"format,omitempty"`
+	// Path of a local file, or an HTTP(S) URL of a remote file.
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// Tag of the outbound to fetch the remote file through.
+	ImportUsingTag string `protobuf:"bytes,3,opt,name=import_using_tag,json=importUsingTag,proto3" json:"import_using_tag,omitempty"`
+	// Interval in seconds to reload the file. 0 means never reloading.
+	ReloadInterval uint32 `protobuf:"varint,4,opt,name=reload_interval,json=reloadInterval,proto3" json:"reload_interval,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HostsFile) Reset() {
+	*x = HostsFile{}
+	mi := &file_app_dns_config_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostsFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostsFile) ProtoMessage() {}
+
+func (x *HostsFile) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_config_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostsFile.ProtoReflect.Descriptor instead.
+func (*HostsFile) Descriptor() ([]byte, []int) {
+	return file_app_dns_config_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *HostsFile) GetFormat() HostsFile_Format {
+	if x != nil {
+		return x.Format
+	}
+	return HostsFile_Hosts
+}
+
+func (x *HostsFile) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *HostsFile) GetImportUsingTag() string {
+	if x != nil {
+		return x.ImportUsingTag
+	}
+	return ""
+}
+
+func (x *HostsFile) GetReloadInterval() uint32 {
+	if x != nil {
+		return x.ReloadInterval
+	}
+	return 0
+}
+
 type Config struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Nameservers used by this DNS. Only traditional UDP servers are support at
@@ -458,13 +795,29 @@ type Config struct {
 	CacheStrategy CacheStrategy `protobuf:"varint,12,opt,name=cache_strategy,json=cacheStrategy,proto3,enum=v2ray.core.app.dns.CacheStrategy" json:"cache_strategy,omitempty"`
 	// Default fallback strategy for each name server.
 	FallbackStrategy FallbackStrategy `protobuf:"varint,13,opt,name=fallback_strategy,json=fallbackStrategy,proto3,enum=v2ray.core.app.dns.FallbackStrategy" json:"fallback_strategy,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// Maximum number of domains in the cache shared by all name servers. The
+	// least recently used domains are evicted. 0 means the default of 4096.
+	CacheSize uint32 `protobuf:"varint,17,opt,name=cache_size,json=cacheSize,proto3" json:"cache_size,omitempty"`
+	// Minimum and maximum TTL in seconds of the cached records. 0 means no
+	// limit.
+	CacheMinTtl uint32 `protobuf:"varint,18,opt,name=cache_min_ttl,json=cacheMinTtl,proto3" json:"cache_min_ttl,omitempty"`
+	CacheMaxTtl uint32 `protobuf:"varint,19,opt,name=cache_max_ttl,json=cacheMaxTtl,proto3" json:"cache_max_ttl,omitempty"`
+	// Strategy for querying the matched name servers.
+	ParallelStrategy ParallelStrategy `protobuf:"varint,20,opt,name=parallel_strategy,json=parallelStrategy,proto3,enum=v2ray.core.app.dns.ParallelStrategy" json:"parallel_strategy,omitempty"`
+	// Number of name servers to query concurrently, in the order they are
+	// matched. The next ones are queried if all of them fail. 0 means all.
+	ParallelCount uint32 `protobuf:"varint,21,opt,name=parallel_count,json=parallelCount,proto3" json:"parallel_count,omitempty"`
+	// Files of host mappings, which are looked up after static_hosts.
+	HostsFile []*HostsFile `protobuf:"bytes,22,rep,name=hosts_file,json=hostsFile,proto3" json:"hosts_file,omitempty"`
+	// Default EDNS client subnet chosen per query for each name server.
+	ClientSubnet  *ClientSubnet `protobuf:"bytes,23,opt,name=client_subnet,json=clientSubnet,proto3" json:"client_subnet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Config) Reset() {
 	*x = Config{}
-	mi := &file_app_dns_config_proto_msgTypes[2]
+	mi := &file_app_dns_config_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -476,7 +829,7 @@ func (x *Config) String() string {
 func (*Config) ProtoMessage() {}
 
 func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_app_dns_config_proto_msgTypes[2]
+	mi := &file_app_dns_config_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -489,7 +842,7 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Config.ProtoReflect.Descriptor instead.
 func (*Config) Descriptor() ([]byte, []int) {
-	return file_app_dns_config_proto_rawDescGZIP(), []int{2}
+	return file_app_dns_config_proto_rawDescGZIP(), []int{4}
 }
 
 // Deprecated: Marked as deprecated in app/dns/config.proto.
@@ -595,6 +948,55 @@ func (x *Config) GetFallbackStrategy() FallbackStrategy {
 	return FallbackStrategy_Enabled
 }
 
+func (x *Config) GetCacheSize() uint32 {
+	if x != nil {
+		return x.CacheSize
+	}
+	return 0
+}
+
+func (x *Config) GetCacheMinTtl() uint32 {
+	if x != nil {
+		return x.CacheMinTtl
+	}
+	return 0
+}
+
+func (x *Config) GetCacheMaxTtl() uint32 {
+	if x != nil {
+		return x.CacheMaxTtl
+	}
+	return 0
+}
+
+func (x *Config) GetParallelStrategy() ParallelStrategy {
+	if x != nil {
+		return x.ParallelStrategy
+	}
+	return ParallelStrategy_Sequential
+}
+
+func (x *Config) GetParallelCount() uint32 {
+	if x != nil {
+		return x.ParallelCount
+	}
+	return 0
+}
+
+func (x *Config) GetHostsFile() []*HostsFile {
+	if x != nil {
+		return x.HostsFile
+	}
+	return nil
+}
+
+func (x *Config) GetClientSubnet() *ClientSubnet {
+	if x != nil {
+		return x.ClientSubnet
+	}
+	return nil
+}
+
 type SimplifiedConfig struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// NameServer list used by this DNS client.
@@ -629,13 +1031,29 @@ type SimplifiedConfig struct {
 	CacheStrategy CacheStrategy `protobuf:"varint,12,opt,name=cache_strategy,json=cacheStrategy,proto3,enum=v2ray.core.app.dns.CacheStrategy" json:"cache_strategy,omitempty"`
 	// Default fallback strategy for each name server.
 	FallbackStrategy FallbackStrategy `protobuf:"varint,13,opt,name=fallback_strategy,json=fallbackStrategy,proto3,enum=v2ray.core.app.dns.FallbackStrategy" json:"fallback_strategy,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// Maximum number of domains in the cache shared by all name servers. The
+	// least recently used domains are evicted. 0 means the default of 4096.
+	CacheSize uint32 `protobuf:"varint,17,opt,name=cache_size,json=cacheSize,proto3" json:"cache_size,omitempty"`
+	// Minimum and maximum TTL in seconds of the cached records. 0 means no
+	// limit.
+	CacheMinTtl uint32 `protobuf:"varint,18,opt,name=cache_min_ttl,json=cacheMinTtl,proto3" json:"cache_min_ttl,omitempty"`
+	CacheMaxTtl uint32 `protobuf:"varint,19,opt,name=cache_max_ttl,json=cacheMaxTtl,proto3" json:"cache_max_ttl,omitempty"`
+	// Strategy for querying the matched name servers.
+	ParallelStrategy ParallelStrategy `protobuf:"varint,20,opt,name=parallel_strategy,json=parallelStrategy,proto3,enum=v2ray.core.app.dns.ParallelStrategy" json:"parallel_strategy,omitempty"`
+	// Number of name servers to query concurrently, in the order they are
+	// matched. The next ones are queried if all of them fail. 0 means all.
+	ParallelCount uint32 `protobuf:"varint,21,opt,name=parallel_count,json=parallelCount,proto3" json:"parallel_count,omitempty"`
+	// Files of host mappings, which are looked up after static_hosts.
+	HostsFile []*HostsFile `protobuf:"bytes,22,rep,name=hosts_file,json=hostsFile,proto3" json:"hosts_file,omitempty"`
+	// Default EDNS client subnet chosen per query for each name server.
+	ClientSubnet  *SimplifiedClientSubnet `protobuf:"bytes,23,opt,name=client_subnet,json=clientSubnet,proto3" json:"client_subnet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SimplifiedConfig) Reset() {
 	*x = SimplifiedConfig{}
-	mi := &file_app_dns_config_proto_msgTypes[3]
+	mi := &file_app_dns_config_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -647,7 +1065,7 @@ func (x *SimplifiedConfig) String() string {
 func (*SimplifiedConfig) ProtoMessage() {}
 
 func (x *SimplifiedConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_app_dns_config_proto_msgTypes[3]
+	mi := &file_app_dns_config_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -660,7 +1078,7 @@ func (x *SimplifiedConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SimplifiedConfig.ProtoReflect.Descriptor instead.
 func (*SimplifiedConfig) Descriptor() ([]byte, []int) {
-	return file_app_dns_config_proto_rawDescGZIP(), []int{3}
+	return file_app_dns_config_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *SimplifiedConfig) GetNameServer() []*SimplifiedNameServer {
@@ -750,6 +1168,55 @@ func (x *SimplifiedConfig) GetFallbackStrategy() FallbackStrategy {
 	return FallbackStrategy_Enabled
 }
 
+func (x *SimplifiedConfig) GetCacheSize() uint32 {
+	if x != nil {
+		return x.CacheSize
+	}
+	return 0
+}
+
+func (x *SimplifiedConfig) GetCacheMinTtl() uint32 {
+	if x != nil {
+		return x.CacheMinTtl
+	}
+	return 0
+}
+
+func (x *SimplifiedConfig) GetCacheMaxTtl() uint32 {
+	if x != nil {
+		return x.CacheMaxTtl
+	}
+	return 0
+}
+
+func (x *SimplifiedConfig) GetParallelStrategy() ParallelStrategy {
+	if x != nil {
+		return x.ParallelStrategy
+	}
+	return ParallelStrategy_Sequential
+}
+
+func (x *SimplifiedConfig) GetParallelCount() uint32 {
+	if x != nil {
+		return x.ParallelCount
+	}
+	return 0
+}
+
+func (x *SimplifiedConfig) GetHostsFile() []*HostsFile {
+	if x != nil {
+		return x.HostsFile
+	}
+	return nil
+}
+
+func (x *SimplifiedConfig) GetClientSubnet() *SimplifiedClientSubnet {
+	if x != nil {
+		return x.ClientSubnet
+	}
+	return nil
+}
+
 type SimplifiedHostMapping struct {
 	state  protoimpl.MessageState `protogen:"open.v1"`
 	Type   DomainMatchingType     `protobuf:"varint,1,opt,name=type,proto3,enum=v2ray.core.app.dns.DomainMatchingType" json:"type,omitempty"`
@@ -764,7 +1231,7 @@ type SimplifiedHostMapping struct {
 
 func (x *SimplifiedHostMapping) Reset() {
 	*x = SimplifiedHostMapping{}
-	mi := &file_app_dns_config_proto_msgTypes[4]
+	mi := &file_app_dns_config_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -776,7 +1243,7 @@ func (x *SimplifiedHostMapping) String() string {
 func (*SimplifiedHostMapping) ProtoMessage() {}
 
 func (x *SimplifiedHostMapping) ProtoReflect() protoreflect.Message {
-	mi := &file_app_dns_config_proto_msgTypes[4]
+	mi := &file_app_dns_config_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -789,7 +1256,7 @@ func (x *SimplifiedHostMapping) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SimplifiedHostMapping.ProtoReflect.Descriptor instead.
 func (*SimplifiedHostMapping) Descriptor() ([]byte, []int) {
-	return file_app_dns_config_proto_rawDescGZIP(), []int{4}
+	return file_app_dns_config_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *SimplifiedHostMapping) GetType() DomainMatchingType {
@@ -832,18 +1299,23 @@ type SimplifiedNameServer struct {
 	// Deprecated. Use fallback_strategy.
 	//
 	// Deprecated: Marked as deprecated in app/dns/config.proto.
-	SkipFallback     bool                    `protobuf:"varint,6,opt,name=skipFallback,proto3" json:"skipFallback,omitempty"`
-	QueryStrategy    *QueryStrategy          `protobuf:"varint,8,opt,name=query_strategy,json=queryStrategy,proto3,enum=v2ray.core.app.dns.QueryStrategy,oneof" json:"query_strategy,omitempty"`
-	CacheStrategy    *CacheStrategy          `protobuf:"varint,9,opt,name=cache_strategy,json=cacheStrategy,proto3,enum=v2ray.core.app.dns.CacheStrategy,oneof" json:"cache_strategy,omitempty"`
-	FallbackStrategy *FallbackStrategy       `protobuf:"varint,10,opt,name=fallback_strategy,json=fallbackStrategy,proto3,enum=v2ray.core.app.dns.FallbackStrategy,oneof" json:"fallback_strategy,omitempty"`
-	GeoDomain        []*routercommon.GeoSite `protobuf:"bytes,68001,rep,name=geo_domain,json=geoDomain,proto3" json:"geo_domain,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	SkipFallback      bool              `protobuf:"varint,6,opt,name=skipFallback,proto3" json:"skipFallback,omitempty"`
+	QueryStrategy     *QueryStrategy    `protobuf:"varint,8,opt,name=query_strategy,json=queryStrategy,proto3,enum=v2ray.core.app.dns.QueryStrategy,oneof" json:"query_strategy,omitempty"`
+	CacheStrategy     *CacheStrategy    `protobuf:"varint,9,opt,name=cache_strategy,json=cacheStrategy,proto3,enum=v2ray.core.app.dns.CacheStrategy,oneof" json:"cache_strategy,omitempty"`
+	FallbackStrategy  *FallbackStrategy `protobuf:"varint,10,opt,name=fallback_strategy,json=fallbackStrategy,proto3,enum=v2ray.core.app.dns.FallbackStrategy,oneof" json:"fallback_strategy,omitempty"`
+	Dnssec            DnssecMode        `protobuf:"varint,12,opt,name=dnssec,proto3,enum=v2ray.core.app.dns.DnssecMode" json:"dnssec,omitempty"`
+	DnssecTrustAnchor []string          `protobuf:"bytes,13,rep,name=dnssec_trust_anchor,json=dnssecTrustAnchor,proto3" json:"dnssec_trust_anchor,omitempty"`
+	// EDNS client subnet chosen per query. It overrides the one of the
+	// SimplifiedConfig if set.
+	ClientSubnet  *SimplifiedClientSubnet `protobuf:"bytes,14,opt,name=client_subnet,json=clientSubnet,proto3" json:"client_subnet,omitempty"`
+	GeoDomain     []*routercommon.GeoSite `protobuf:"bytes,68001,rep,name=geo_domain,json=geoDomain,proto3" json:"geo_domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SimplifiedNameServer) Reset() {
 	*x = SimplifiedNameServer{}
-	mi := &file_app_dns_config_proto_msgTypes[5]
+	mi := &file_app_dns_config_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -855,7 +1327,7 @@ func (x *SimplifiedNameServer) String() string {
 func (*SimplifiedNameServer) ProtoMessage() {}
 
 func (x *SimplifiedNameServer) ProtoReflect() protoreflect.Message {
-	mi := &file_app_dns_config_proto_msgTypes[5]
+	mi := &file_app_dns_config_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -868,7 +1340,7 @@ func (x *SimplifiedNameServer) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SimplifiedNameServer.ProtoReflect.Descriptor instead.
 func (*SimplifiedNameServer) Descriptor() ([]byte, []int) {
-	return file_app_dns_config_proto_rawDescGZIP(), []int{5}
+	return file_app_dns_config_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *SimplifiedNameServer) GetAddress() *net.Endpoint {
@@ -949,6 +1421,27 @@ func (x *SimplifiedNameServer) GetFallbackStrategy() FallbackStrategy {
 	return FallbackStrategy_Enabled
 }
 
+func (x *SimplifiedNameServer) GetDnssec() DnssecMode {
+	if x != nil {
+		return x.Dnssec
+	}
+	return DnssecMode_DnssecDisabled
+}
+
+func (x *SimplifiedNameServer) GetDnssecTrustAnchor() []string {
+	if x != nil {
+		return x.DnssecTrustAnchor
+	}
+	return nil
+}
+
+func (x *SimplifiedNameServer) GetClientSubnet() *SimplifiedClientSubnet {
+	if x != nil {
+		return x.ClientSubnet
+	}
+	return nil
+}
+
 func (x *SimplifiedNameServer) GetGeoDomain() []*routercommon.GeoSite {
 	if x != nil {
 		return x.GeoDomain
@@ -956,6 +1449,75 @@ func (x *SimplifiedNameServer) GetGeoDomain() []*routercommon.GeoSite {
 	return nil
 }
 
+// SimplifiedClientSubnet is ClientSubnet with the client IP in text.
+type SimplifiedClientSubnet struct {
+	state         protoimpl.MessageState         `protogen:"open.v1"`
+	FromSource    bool                           `protobuf:"varint,1,opt,name=from_source,json=fromSource,proto3" json:"from_source,omitempty"`
+	Ipv4Prefix    uint32                         `protobuf:"varint,2,opt,name=ipv4_prefix,json=ipv4Prefix,proto3" json:"ipv4_prefix,omitempty"`
+	Ipv6Prefix    uint32                         `protobuf:"varint,3,opt,name=ipv6_prefix,json=ipv6Prefix,proto3" json:"ipv6_prefix,omitempty"`
+	Rule          []*SimplifiedClientSubnet_Rule `protobuf:"bytes,4,rep,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimplifiedClientSubnet) Reset() {
+	*x = SimplifiedClientSubnet{}
+	mi := &file_app_dns_config_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimplifiedClientSubnet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimplifiedClientSubnet) ProtoMessage() {}
+
+func (x *SimplifiedClientSubnet) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_config_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimplifiedClientSubnet.ProtoReflect.Descriptor instead.
+func (*SimplifiedClientSubnet) Descriptor() ([]byte, []int) {
+	return file_app_dns_config_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SimplifiedClientSubnet) GetFromSource() bool {
+	if x != nil {
+		return x.FromSource
+	}
+	return false
+}
+
+func (x *SimplifiedClientSubnet) GetIpv4Prefix() uint32 {
+	if x != nil {
+		return x.Ipv4Prefix
+	}
+	return 0
+}
+
+func (x *SimplifiedClientSubnet) GetIpv6Prefix() uint32 {
+	if x != nil {
+		return x.Ipv6Prefix
+	}
+	return 0
+}
+
+func (x *SimplifiedClientSubnet) GetRule() []*SimplifiedClientSubnet_Rule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
 type NameServer_PriorityDomain struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Type          DomainMatchingType     `protobuf:"varint,1,opt,name=type,proto3,enum=v2ray.core.app.dns.DomainMatchingType" json:"type,omitempty"`
@@ -966,7 +1528,7 @@ type NameServer_PriorityDomain struct {
 
 func (x *NameServer_PriorityDomain) Reset() {
 	*x = NameServer_PriorityDomain{}
-	mi := &file_app_dns_config_proto_msgTypes[6]
+	mi := &file_app_dns_config_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -978,7 +1540,7 @@ func (x *NameServer_PriorityDomain) String() string {
 func (*NameServer_PriorityDomain) ProtoMessage() {}
 
 func (x *NameServer_PriorityDomain) ProtoReflect() protoreflect.Message {
-	mi := &file_app_dns_config_proto_msgTypes[6]
+	mi := &file_app_dns_config_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1018,7 +1580,7 @@ type NameServer_OriginalRule struct {
 
 func (x *NameServer_OriginalRule) Reset() {
 	*x = NameServer_OriginalRule{}
-	mi := &file_app_dns_config_proto_msgTypes[7]
+	mi := &file_app_dns_config_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1030,7 +1592,7 @@ func (x *NameServer_OriginalRule) String() string {
 func (*NameServer_OriginalRule) ProtoMessage() {}
 
 func (x *NameServer_OriginalRule) ProtoReflect() protoreflect.Message {
-	mi := &file_app_dns_config_proto_msgTypes[7]
+	mi := &file_app_dns_config_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1060,6 +1622,70 @@ func (x *NameServer_OriginalRule) GetSize() uint32 {
 	return 0
 }
 
+type ClientSubnet_Rule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Domains the rule applies to. All domains if empty.
+	Domain []*NameServer_PriorityDomain `protobuf:"bytes,1,rep,name=domain,proto3" json:"domain,omitempty"`
+	// Emails of the inbound users the rule applies to. All users if empty.
+	UserEmail []string `protobuf:"bytes,2,rep,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
+	// Client IP sent for the matched queries. The source address of the
+	// client is used if empty.
+	ClientIp      []byte `protobuf:"bytes,3,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientSubnet_Rule) Reset() {
+	*x = ClientSubnet_Rule{}
+	mi := &file_app_dns_config_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientSubnet_Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientSubnet_Rule) ProtoMessage() {}
+
+func (x *ClientSubnet_Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_config_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientSubnet_Rule.ProtoReflect.Descriptor instead.
+func (*ClientSubnet_Rule) Descriptor() ([]byte, []int) {
+	return file_app_dns_config_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *ClientSubnet_Rule) GetDomain() []*NameServer_PriorityDomain {
+	if x != nil {
+		return x.Domain
+	}
+	return nil
+}
+
+func (x *ClientSubnet_Rule) GetUserEmail() []string {
+	if x != nil {
+		return x.UserEmail
+	}
+	return nil
+}
+
+func (x *ClientSubnet_Rule) GetClientIp() []byte {
+	if x != nil {
+		return x.ClientIp
+	}
+	return nil
+}
+
 type SimplifiedNameServer_PriorityDomain struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Type          DomainMatchingType     `protobuf:"varint,1,opt,name=type,proto3,enum=v2ray.core.app.dns.DomainMatchingType" json:"type,omitempty"`
@@ -1070,7 +1696,7 @@ type SimplifiedNameServer_PriorityDomain struct {
 
 func (x *SimplifiedNameServer_PriorityDomain) Reset() {
 	*x = SimplifiedNameServer_PriorityDomain{}
-	mi := &file_app_dns_config_proto_msgTypes[9]
+	mi := &file_app_dns_config_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1082,7 +1708,7 @@ func (x *SimplifiedNameServer_PriorityDomain) String() string {
 func (*SimplifiedNameServer_PriorityDomain) ProtoMessage() {}
 
 func (x *SimplifiedNameServer_PriorityDomain) ProtoReflect() protoreflect.Message {
-	mi := &file_app_dns_config_proto_msgTypes[9]
+	mi := &file_app_dns_config_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1095,7 +1721,7 @@ func (x *SimplifiedNameServer_PriorityDomain) ProtoReflect() protoreflect.Messag
 
 // Deprecated: Use SimplifiedNameServer_PriorityDomain.ProtoReflect.Descriptor instead.
 func (*SimplifiedNameServer_PriorityDomain) Descriptor() ([]byte, []int) {
-	return file_app_dns_config_proto_rawDescGZIP(), []int{5, 0}
+	return file_app_dns_config_proto_rawDescGZIP(), []int{7, 0}
 }
 
 func (x *SimplifiedNameServer_PriorityDomain) GetType() DomainMatchingType {
@@ -1122,7 +1748,7 @@ type SimplifiedNameServer_OriginalRule struct {
 
 func (x *SimplifiedNameServer_OriginalRule) Reset() {
 	*x = SimplifiedNameServer_OriginalRule{}
-	mi := &file_app_dns_config_proto_msgTypes[10]
+	mi := &file_app_dns_config_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1134,7 +1760,7 @@ func (x *SimplifiedNameServer_OriginalRule) String() string {
 func (*SimplifiedNameServer_OriginalRule) ProtoMessage() {}
 
 func (x *SimplifiedNameServer_OriginalRule) ProtoReflect() protoreflect.Message {
-	mi := &file_app_dns_config_proto_msgTypes[10]
+	mi := &file_app_dns_config_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1147,7 +1773,7 @@ func (x *SimplifiedNameServer_OriginalRule) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use SimplifiedNameServer_OriginalRule.ProtoReflect.Descriptor instead.
 func (*SimplifiedNameServer_OriginalRule) Descriptor() ([]byte, []int) {
-	return file_app_dns_config_proto_rawDescGZIP(), []int{5, 1}
+	return file_app_dns_config_proto_rawDescGZIP(), []int{7, 1}
 }
 
 func (x *SimplifiedNameServer_OriginalRule) GetRule() string {
@@ -1164,11 +1790,71 @@ func (x *SimplifiedNameServer_OriginalRule) GetSize() uint32 {
 	return 0
 }
 
+type SimplifiedClientSubnet_Rule struct {
+	state         protoimpl.MessageState                 `protogen:"open.v1"`
+	Domain        []*SimplifiedNameServer_PriorityDomain `protobuf:"bytes,1,rep,name=domain,proto3" json:"domain,omitempty"`
+	UserEmail     []string                               `protobuf:"bytes,2,rep,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
+	ClientIp      string                                 `protobuf:"bytes,3,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimplifiedClientSubnet_Rule) Reset() {
+	*x = SimplifiedClientSubnet_Rule{}
+	mi := &file_app_dns_config_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimplifiedClientSubnet_Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimplifiedClientSubnet_Rule) ProtoMessage() {}
+
+func (x *SimplifiedClientSubnet_Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_config_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimplifiedClientSubnet_Rule.ProtoReflect.Descriptor instead.
+func (*SimplifiedClientSubnet_Rule) Descriptor() ([]byte, []int) {
+	return file_app_dns_config_proto_rawDescGZIP(), []int{8, 0}
+}
+
+func (x *SimplifiedClientSubnet_Rule) GetDomain() []*SimplifiedNameServer_PriorityDomain {
+	if x != nil {
+		return x.Domain
+	}
+	return nil
+}
+
+func (x *SimplifiedClientSubnet_Rule) GetUserEmail() []string {
+	if x != nil {
+		return x.UserEmail
+	}
+	return nil
+}
+
+func (x *SimplifiedClientSubnet_Rule) GetClientIp() string {
+	if x != nil {
+		return x.ClientIp
+	}
+	return ""
+}
+
 var File_app_dns_config_proto protoreflect.FileDescriptor
 
 const file_app_dns_config_proto_rawDesc = "" +
 	"\n" +
-	"\x14app/dns/config.proto\x12\x12v2ray.core.app.dns\x1a\x18common/net/address.proto\x1a\x1ccommon/net/destination.proto\x1a$app/router/routercommon/common.proto\x1a\x1dapp/dns/fakedns/fakedns.proto\x1a common/protoext/extensions.proto\"\xaa\a\n" +
+	"\x14app/dns/config.proto\x12\x12v2ray.core.app.dns\x1a\x18common/net/address.proto\x1a\x1ccommon/net/destination.proto\x1a$app/router/routercommon/common.proto\x1a\x1dapp/dns/fakedns/fakedns.proto\x1a common/protoext/extensions.proto\"\xd9\b\n" +
 	"\n" +
 	"NameServer\x129\n" +
 	"\aaddress\x18\x01 \x01(\v2\x1f.v2ray.core.common.net.EndpointR\aaddress\x12\x1b\n" +
@@ -1182,7 +1868,10 @@ const file_app_dns_config_proto_rawDesc = "" +
 	"\x0equery_strategy\x18\b \x01(\x0e2!.v2ray.core.app.dns.QueryStrategyH\x00R\rqueryStrategy\x88\x01\x01\x12M\n" +
 	"\x0ecache_strategy\x18\t \x01(\x0e2!.v2ray.core.app.dns.CacheStrategyH\x01R\rcacheStrategy\x88\x01\x01\x12V\n" +
 	"\x11fallback_strategy\x18\n" +
-	" \x01(\x0e2$.v2ray.core.app.dns.FallbackStrategyH\x02R\x10fallbackStrategy\x88\x01\x01\x1ad\n" +
+	" \x01(\x0e2$.v2ray.core.app.dns.FallbackStrategyH\x02R\x10fallbackStrategy\x88\x01\x01\x126\n" +
+	"\x06dnssec\x18\f \x01(\x0e2\x1e.v2ray.core.app.dns.DnssecModeR\x06dnssec\x12.\n" +
+	"\x13dnssec_trust_anchor\x18\r \x03(\tR\x11dnssecTrustAnchor\x12E\n" +
+	"\rclient_subnet\x18\x0e \x01(\v2 .v2ray.core.app.dns.ClientSubnetR\fclientSubnet\x1ad\n" +
 	"\x0ePriorityDomain\x12:\n" +
 	"\x04type\x18\x01 \x01(\x0e2&.v2ray.core.app.dns.DomainMatchingTypeR\x04type\x12\x16\n" +
 	"\x06domain\x18\x02 \x01(\tR\x06domain\x1a6\n" +
@@ -1191,12 +1880,33 @@ const file_app_dns_config_proto_rawDesc = "" +
 	"\x04size\x18\x02 \x01(\rR\x04sizeB\x11\n" +
 	"\x0f_query_strategyB\x11\n" +
 	"\x0f_cache_strategyB\x14\n" +
-	"\x12_fallback_strategy\"\x98\x01\n" +
+	"\x12_fallback_strategy\"\xb8\x02\n" +
+	"\fClientSubnet\x12\x1f\n" +
+	"\vfrom_source\x18\x01 \x01(\bR\n" +
+	"fromSource\x12\x1f\n" +
+	"\vipv4_prefix\x18\x02 \x01(\rR\n" +
+	"ipv4Prefix\x12\x1f\n" +
+	"\vipv6_prefix\x18\x03 \x01(\rR\n" +
+	"ipv6Prefix\x129\n" +
+	"\x04rule\x18\x04 \x03(\v2%.v2ray.core.app.dns.ClientSubnet.RuleR\x04rule\x1a\x89\x01\n" +
+	"\x04Rule\x12E\n" +
+	"\x06domain\x18\x01 \x03(\v2-.v2ray.core.app.dns.NameServer.PriorityDomainR\x06domain\x12\x1d\n" +
+	"\n" +
+	"user_email\x18\x02 \x03(\tR\tuserEmail\x12\x1b\n" +
+	"\tclient_ip\x18\x03 \x01(\fR\bclientIp\"\x98\x01\n" +
 	"\vHostMapping\x12:\n" +
 	"\x04type\x18\x01 \x01(\x0e2&.v2ray.core.app.dns.DomainMatchingTypeR\x04type\x12\x16\n" +
 	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x0e\n" +
 	"\x02ip\x18\x03 \x03(\fR\x02ip\x12%\n" +
-	"\x0eproxied_domain\x18\x04 \x01(\tR\rproxiedDomain\"\x90\a\n" +
+	"\x0eproxied_domain\x18\x04 \x01(\tR\rproxiedDomain\"\xd6\x01\n" +
+	"\tHostsFile\x12<\n" +
+	"\x06format\x18\x01 \x01(\x0e2$.v2ray.core.app.dns.HostsFile.FormatR\x06format\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12(\n" +
+	"\x10import_using_tag\x18\x03 \x01(\tR\x0eimportUsingTag\x12'\n" +
+	"\x0freload_interval\x18\x04 \x01(\rR\x0ereloadInterval\" \n" +
+	"\x06Format\x12\t\n" +
+	"\x05Hosts\x10\x00\x12\v\n" +
+	"\aAdblock\x10\x01\"\xf6\t\n" +
 	"\x06Config\x12E\n" +
 	"\vNameServers\x18\x01 \x03(\v2\x1f.v2ray.core.common.net.EndpointB\x02\x18\x01R\vNameServers\x12?\n" +
 	"\vname_server\x18\x05 \x03(\v2\x1e.v2ray.core.app.dns.NameServerR\n" +
@@ -1213,11 +1923,20 @@ const file_app_dns_config_proto_rawDesc = "" +
 	"\x16disableFallbackIfMatch\x18\v \x01(\bB\x02\x18\x01R\x16disableFallbackIfMatch\x12H\n" +
 	"\x0equery_strategy\x18\t \x01(\x0e2!.v2ray.core.app.dns.QueryStrategyR\rqueryStrategy\x12H\n" +
 	"\x0ecache_strategy\x18\f \x01(\x0e2!.v2ray.core.app.dns.CacheStrategyR\rcacheStrategy\x12Q\n" +
-	"\x11fallback_strategy\x18\r \x01(\x0e2$.v2ray.core.app.dns.FallbackStrategyR\x10fallbackStrategy\x1a[\n" +
+	"\x11fallback_strategy\x18\r \x01(\x0e2$.v2ray.core.app.dns.FallbackStrategyR\x10fallbackStrategy\x12\x1d\n" +
+	"\n" +
+	"cache_size\x18\x11 \x01(\rR\tcacheSize\x12\"\n" +
+	"\rcache_min_ttl\x18\x12 \x01(\rR\vcacheMinTtl\x12\"\n" +
+	"\rcache_max_ttl\x18\x13 \x01(\rR\vcacheMaxTtl\x12Q\n" +
+	"\x11parallel_strategy\x18\x14 \x01(\x0e2$.v2ray.core.app.dns.ParallelStrategyR\x10parallelStrategy\x12%\n" +
+	"\x0eparallel_count\x18\x15 \x01(\rR\rparallelCount\x12<\n" +
+	"\n" +
+	"hosts_file\x18\x16 \x03(\v2\x1d.v2ray.core.app.dns.HostsFileR\thostsFile\x12E\n" +
+	"\rclient_subnet\x18\x17 \x01(\v2 .v2ray.core.app.dns.ClientSubnetR\fclientSubnet\x1a[\n" +
 	"\n" +
 	"HostsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x127\n" +
-	"\x05value\x18\x02 \x01(\v2!.v2ray.core.common.net.IPOrDomainR\x05value:\x028\x01J\x04\b\a\x10\b\"\xe9\x05\n" +
+	"\x05value\x18\x02 \x01(\v2!.v2ray.core.common.net.IPOrDomainR\x05value:\x028\x01J\x04\b\a\x10\b\"\xd9\b\n" +
 	"\x10SimplifiedConfig\x12I\n" +
 	"\vname_server\x18\x05 \x03(\v2(.v2ray.core.app.dns.SimplifiedNameServerR\n" +
 	"nameServer\x12\x1b\n" +
@@ -1232,13 +1951,22 @@ const file_app_dns_config_proto_rawDesc = "" +
 	"\x16disableFallbackIfMatch\x18\v \x01(\bB\x02\x18\x01R\x16disableFallbackIfMatch\x12H\n" +
 	"\x0equery_strategy\x18\t \x01(\x0e2!.v2ray.core.app.dns.QueryStrategyR\rqueryStrategy\x12H\n" +
 	"\x0ecache_strategy\x18\f \x01(\x0e2!.v2ray.core.app.dns.CacheStrategyR\rcacheStrategy\x12Q\n" +
-	"\x11fallback_strategy\x18\r \x01(\x0e2$.v2ray.core.app.dns.FallbackStrategyR\x10fallbackStrategy:\x12\x82\xb5\x18\x0e\n" +
+	"\x11fallback_strategy\x18\r \x01(\x0e2$.v2ray.core.app.dns.FallbackStrategyR\x10fallbackStrategy\x12\x1d\n" +
+	"\n" +
+	"cache_size\x18\x11 \x01(\rR\tcacheSize\x12\"\n" +
+	"\rcache_min_ttl\x18\x12 \x01(\rR\vcacheMinTtl\x12\"\n" +
+	"\rcache_max_ttl\x18\x13 \x01(\rR\vcacheMaxTtl\x12Q\n" +
+	"\x11parallel_strategy\x18\x14 \x01(\x0e2$.v2ray.core.app.dns.ParallelStrategyR\x10parallelStrategy\x12%\n" +
+	"\x0eparallel_count\x18\x15 \x01(\rR\rparallelCount\x12<\n" +
+	"\n" +
+	"hosts_file\x18\x16 \x03(\v2\x1d.v2ray.core.app.dns.HostsFileR\thostsFile\x12O\n" +
+	"\rclient_subnet\x18\x17 \x01(\v2*.v2ray.core.app.dns.SimplifiedClientSubnetR\fclientSubnet:\x12\x82\xb5\x18\x0e\n" +
 	"\aservice\x12\x03dnsJ\x04\b\x01\x10\x02J\x04\b\x02\x10\x03J\x04\b\a\x10\b\"\xa2\x01\n" +
 	"\x15SimplifiedHostMapping\x12:\n" +
 	"\x04type\x18\x01 \x01(\x0e2&.v2ray.core.app.dns.DomainMatchingTypeR\x04type\x12\x16\n" +
 	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x0e\n" +
 	"\x02ip\x18\x03 \x03(\tR\x02ip\x12%\n" +
-	"\x0eproxied_domain\x18\x04 \x01(\tR\rproxiedDomain\"\x96\b\n" +
+	"\x0eproxied_domain\x18\x04 \x01(\tR\rproxiedDomain\"\xcf\t\n" +
 	"\x14SimplifiedNameServer\x129\n" +
 	"\aaddress\x18\x01 \x01(\v2\x1f.v2ray.core.common.net.EndpointR\aaddress\x12\x1b\n" +
 	"\tclient_ip\x18\x05 \x01(\tR\bclientIp\x12\x10\n" +
@@ -1251,7 +1979,10 @@ const file_app_dns_config_proto_rawDesc = "" +
 	"\x0equery_strategy\x18\b \x01(\x0e2!.v2ray.core.app.dns.QueryStrategyH\x00R\rqueryStrategy\x88\x01\x01\x12M\n" +
 	"\x0ecache_strategy\x18\t \x01(\x0e2!.v2ray.core.app.dns.CacheStrategyH\x01R\rcacheStrategy\x88\x01\x01\x12V\n" +
 	"\x11fallback_strategy\x18\n" +
-	" \x01(\x0e2$.v2ray.core.app.dns.FallbackStrategyH\x02R\x10fallbackStrategy\x88\x01\x01\x12L\n" +
+	" \x01(\x0e2$.v2ray.core.app.dns.FallbackStrategyH\x02R\x10fallbackStrategy\x88\x01\x01\x126\n" +
+	"\x06dnssec\x18\f \x01(\x0e2\x1e.v2ray.core.app.dns.DnssecModeR\x06dnssec\x12.\n" +
+	"\x13dnssec_trust_anchor\x18\r \x03(\tR\x11dnssecTrustAnchor\x12O\n" +
+	"\rclient_subnet\x18\x0e \x01(\v2*.v2ray.core.app.dns.SimplifiedClientSubnetR\fclientSubnet\x12L\n" +
 	"\n" +
 	"geo_domain\x18\xa1\x93\x04 \x03(\v2+.v2ray.core.app.router.routercommon.GeoSiteR\tgeoDomain\x1ad\n" +
 	"\x0ePriorityDomain\x12:\n" +
@@ -1262,7 +1993,20 @@ const file_app_dns_config_proto_rawDesc = "" +
 	"\x04size\x18\x02 \x01(\rR\x04sizeB\x11\n" +
 	"\x0f_query_strategyB\x11\n" +
 	"\x0f_cache_strategyB\x14\n" +
-	"\x12_fallback_strategy*E\n" +
+	"\x12_fallback_strategy\"\xd6\x02\n" +
+	"\x16SimplifiedClientSubnet\x12\x1f\n" +
+	"\vfrom_source\x18\x01 \x01(\bR\n" +
+	"fromSource\x12\x1f\n" +
+	"\vipv4_prefix\x18\x02 \x01(\rR\n" +
+	"ipv4Prefix\x12\x1f\n" +
+	"\vipv6_prefix\x18\x03 \x01(\rR\n" +
+	"ipv6Prefix\x12C\n" +
+	"\x04rule\x18\x04 \x03(\v2/.v2ray.core.app.dns.SimplifiedClientSubnet.RuleR\x04rule\x1a\x93\x01\n" +
+	"\x04Rule\x12O\n" +
+	"\x06domain\x18\x01 \x03(\v27.v2ray.core.app.dns.SimplifiedNameServer.PriorityDomainR\x06domain\x12\x1d\n" +
+	"\n" +
+	"user_email\x18\x02 \x03(\tR\tuserEmail\x12\x1b\n" +
+	"\tclient_ip\x18\x03 \x01(\tR\bclientIp*E\n" +
 	"\x12DomainMatchingType\x12\b\n" +
 	"\x04Full\x10\x00\x12\r\n" +
 	"\tSubdomain\x10\x01\x12\v\n" +
@@ -1272,14 +2016,26 @@ const file_app_dns_config_proto_rawDesc = "" +
 	"\n" +
 	"\x06USE_IP\x10\x00\x12\v\n" +
 	"\aUSE_IP4\x10\x01\x12\v\n" +
-	"\aUSE_IP6\x10\x02*4\n" +
+	"\aUSE_IP6\x10\x02*|\n" +
 	"\rCacheStrategy\x12\x10\n" +
 	"\fCacheEnabled\x10\x00\x12\x11\n" +
-	"\rCacheDisabled\x10\x01*E\n" +
+	"\rCacheDisabled\x10\x01\x12\x13\n" +
+	"\x0fCacheServeStale\x10\x02\x12\x11\n" +
+	"\rCachePrefetch\x10\x03\x12\x1e\n" +
+	"\x1aCacheServeStaleAndPrefetch\x10\x04*E\n" +
 	"\x10FallbackStrategy\x12\v\n" +
 	"\aEnabled\x10\x00\x12\f\n" +
 	"\bDisabled\x10\x01\x12\x16\n" +
-	"\x12DisabledIfAnyMatch\x10\x02BW\n" +
+	"\x12DisabledIfAnyMatch\x10\x02*C\n" +
+	"\x10ParallelStrategy\x12\x0e\n" +
+	"\n" +
+	"Sequential\x10\x00\x12\b\n" +
+	"\x04Race\x10\x01\x12\x15\n" +
+	"\x11PreferExpectedIPs\x10\x02*4\n" +
+	"\n" +
+	"DnssecMode\x12\x12\n" +
+	"\x0eDnssecDisabled\x10\x00\x12\x12\n" +
+	"\x0eDnssecValidate\x10\x01BW\n" +
 	"\x16com.v2ray.core.app.dnsP\x01Z&github.com/v2fly/v2ray-core/v5/app/dns\xaa\x02\x12V2Ray.Core.App.Dnsb\x06proto3"
 
 var (
@@ -1294,72 +2050,95 @@ func file_app_dns_config_proto_rawDescGZIP() []byte {
 	return file_app_dns_config_proto_rawDescData
 }
 
-var file_app_dns_config_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_app_dns_config_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_app_dns_config_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_app_dns_config_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_app_dns_config_proto_goTypes = []any{
 	(DomainMatchingType)(0),                     // 0: v2ray.core.app.dns.DomainMatchingType
 	(QueryStrategy)(0),                          // 1: v2ray.core.app.dns.QueryStrategy
 	(CacheStrategy)(0),                          // 2: v2ray.core.app.dns.CacheStrategy
 	(FallbackStrategy)(0),                       // 3: v2ray.core.app.dns.FallbackStrategy
-	(*NameServer)(nil),                          // 4: v2ray.core.app.dns.NameServer
-	(*HostMapping)(nil),                         // 5: v2ray.core.app.dns.HostMapping
-	(*Config)(nil),                              // 6: v2ray.core.app.dns.Config
-	(*SimplifiedConfig)(nil),                    // 7: v2ray.core.app.dns.SimplifiedConfig
-	(*SimplifiedHostMapping)(nil),               // 8: v2ray.core.app.dns.SimplifiedHostMapping
-	(*SimplifiedNameServer)(nil),                // 9: v2ray.core.app.dns.SimplifiedNameServer
-	(*NameServer_PriorityDomain)(nil),           // 10: v2ray.core.app.dns.NameServer.PriorityDomain
-	(*NameServer_OriginalRule)(nil),             // 11: v2ray.core.app.dns.NameServer.OriginalRule
-	nil,                                         // 12: v2ray.core.app.dns.Config.HostsEntry
-	(*SimplifiedNameServer_PriorityDomain)(nil), // 13: v2ray.core.app.dns.SimplifiedNameServer.PriorityDomain
-	(*SimplifiedNameServer_OriginalRule)(nil),   // 14: v2ray.core.app.dns.SimplifiedNameServer.OriginalRule
-	(*net.Endpoint)(nil),                        // 15: v2ray.core.common.net.Endpoint
-	(*routercommon.GeoIP)(nil),                  // 16: v2ray.core.app.router.routercommon.GeoIP
-	(*fakedns.FakeDnsPoolMulti)(nil),            // 17: v2ray.core.app.dns.fakedns.FakeDnsPoolMulti
-	(*routercommon.GeoSite)(nil),                // 18: v2ray.core.app.router.routercommon.GeoSite
-	(*net.IPOrDomain)(nil),                      // 19: v2ray.core.common.net.IPOrDomain
+	(ParallelStrategy)(0),                       // 4: v2ray.core.app.dns.ParallelStrategy
+	(DnssecMode)(0),                             // 5: v2ray.core.app.dns.DnssecMode
+	(HostsFile_Format)(0),                       // 6: v2ray.core.app.dns.HostsFile.Format
+	(*NameServer)(nil),                          // 7: v2ray.core.app.dns.NameServer
+	(*ClientSubnet)(nil),                        // 8: v2ray.core.app.dns.ClientSubnet
+	(*HostMapping)(nil),                         // 9: v2ray.core.app.dns.HostMapping
+	(*HostsFile)(nil),                           // 10: v2ray.core.app.dns.HostsFile
+	(*Config)(nil),                              // 11: v2ray.core.app.dns.Config
+	(*SimplifiedConfig)(nil),                    // 12: v2ray.core.app.dns.SimplifiedConfig
+	(*SimplifiedHostMapping)(nil),               // 13: v2ray.core.app.dns.SimplifiedHostMapping
+	(*SimplifiedNameServer)(nil),                // 14: v2ray.core.app.dns.SimplifiedNameServer
+	(*SimplifiedClientSubnet)(nil),              // 15: v2ray.core.app.dns.SimplifiedClientSubnet
+	(*NameServer_PriorityDomain)(nil),           // 16: v2ray.core.app.dns.NameServer.PriorityDomain
+	(*NameServer_OriginalRule)(nil),             // 17: v2ray.core.app.dns.NameServer.OriginalRule
+	(*ClientSubnet_Rule)(nil),                   // 18: v2ray.core.app.dns.ClientSubnet.Rule
+	nil,                                         // 19: v2ray.core.app.dns.Config.HostsEntry
+	(*SimplifiedNameServer_PriorityDomain)(nil), // 20: v2ray.core.app.dns.SimplifiedNameServer.PriorityDomain
+	(*SimplifiedNameServer_OriginalRule)(nil),   // 21: v2ray.core.app.dns.SimplifiedNameServer.OriginalRule
+	(*SimplifiedClientSubnet_Rule)(nil),         // 22: v2ray.core.app.dns.SimplifiedClientSubnet.Rule
+	(*net.Endpoint)(nil),                        // 23: v2ray.core.common.net.Endpoint
+	(*routercommon.GeoIP)(nil),                  // 24: v2ray.core.app.router.routercommon.GeoIP
+	(*fakedns.FakeDnsPoolMulti)(nil),            // 25: v2ray.core.app.dns.fakedns.FakeDnsPoolMulti
+	(*routercommon.GeoSite)(nil),                // 26: v2ray.core.app.router.routercommon.GeoSite
+	(*net.IPOrDomain)(nil),                      // 27: v2ray.core.common.net.IPOrDomain
 }
 var file_app_dns_config_proto_depIdxs = []int32{
-	15, // 0: v2ray.core.app.dns.NameServer.address:type_name -> v2ray.core.common.net.Endpoint
-	10, // 1: v2ray.core.app.dns.NameServer.prioritized_domain:type_name -> v2ray.core.app.dns.NameServer.PriorityDomain
-	16, // 2: v2ray.core.app.dns.NameServer.geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
-	11, // 3: v2ray.core.app.dns.NameServer.original_rules:type_name -> v2ray.core.app.dns.NameServer.OriginalRule
-	17, // 4: v2ray.core.app.dns.NameServer.fake_dns:type_name -> v2ray.core.app.dns.fakedns.FakeDnsPoolMulti
+	23, // 0: v2ray.core.app.dns.NameServer.address:type_name -> v2ray.core.common.net.Endpoint
+	16, // 1: v2ray.core.app.dns.NameServer.prioritized_domain:type_name -> v2ray.core.app.dns.NameServer.PriorityDomain
+	24, // 2: v2ray.core.app.dns.NameServer.geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
+	17, // 3: v2ray.core.app.dns.NameServer.original_rules:type_name -> v2ray.core.app.dns.NameServer.OriginalRule
+	25, // 4: v2ray.core.app.dns.NameServer.fake_dns:type_name -> v2ray.core.app.dns.fakedns.FakeDnsPoolMulti
 	1,  // 5: v2ray.core.app.dns.NameServer.query_strategy:type_name -> v2ray.core.app.dns.QueryStrategy
 	2,  // 6: v2ray.core.app.dns.NameServer.cache_strategy:type_name -> v2ray.core.app.dns.CacheStrategy
 	3,  // 7: v2ray.core.app.dns.NameServer.fallback_strategy:type_name -> v2ray.core.app.dns.FallbackStrategy
-	0,  // 8: v2ray.core.app.dns.HostMapping.type:type_name -> v2ray.core.app.dns.DomainMatchingType
-	15, // 9: v2ray.core.app.dns.Config.NameServers:type_name -> v2ray.core.common.net.Endpoint
-	4,  // 10: v2ray.core.app.dns.Config.name_server:type_name -> v2ray.core.app.dns.NameServer
-	12, // 11: v2ray.core.app.dns.Config.Hosts:type_name -> v2ray.core.app.dns.Config.HostsEntry
-	5,  // 12: v2ray.core.app.dns.Config.static_hosts:type_name -> v2ray.core.app.dns.HostMapping
-	17, // 13: v2ray.core.app.dns.Config.fake_dns:type_name -> v2ray.core.app.dns.fakedns.FakeDnsPoolMulti
-	1,  // 14: v2ray.core.app.dns.Config.query_strategy:type_name -> v2ray.core.app.dns.QueryStrategy
-	2,  // 15: v2ray.core.app.dns.Config.cache_strategy:type_name -> v2ray.core.app.dns.CacheStrategy
-	3,  // 16: v2ray.core.app.dns.Config.fallback_strategy:type_name -> v2ray.core.app.dns.FallbackStrategy
-	9,  // 17: v2ray.core.app.dns.SimplifiedConfig.name_server:type_name -> v2ray.core.app.dns.SimplifiedNameServer
-	8,  // 18: v2ray.core.app.dns.SimplifiedConfig.static_hosts:type_name -> v2ray.core.app.dns.SimplifiedHostMapping
-	17, // 19: v2ray.core.app.dns.SimplifiedConfig.fake_dns:type_name -> v2ray.core.app.dns.fakedns.FakeDnsPoolMulti
-	1,  // 20: v2ray.core.app.dns.SimplifiedConfig.query_strategy:type_name -> v2ray.core.app.dns.QueryStrategy
-	2,  // 21: v2ray.core.app.dns.SimplifiedConfig.cache_strategy:type_name -> v2ray.core.app.dns.CacheStrategy
-	3,  // 22: v2ray.core.app.dns.SimplifiedConfig.fallback_strategy:type_name -> v2ray.core.app.dns.FallbackStrategy
-	0,  // 23: v2ray.core.app.dns.SimplifiedHostMapping.type:type_name -> v2ray.core.app.dns.DomainMatchingType
-	15, // 24: v2ray.core.app.dns.SimplifiedNameServer.address:type_name -> v2ray.core.common.net.Endpoint
-	13, // 25: v2ray.core.app.dns.SimplifiedNameServer.prioritized_domain:type_name -> v2ray.core.app.dns.SimplifiedNameServer.PriorityDomain
-	16, // 26: v2ray.core.app.dns.SimplifiedNameServer.geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
-	14, // 27: v2ray.core.app.dns.SimplifiedNameServer.original_rules:type_name -> v2ray.core.app.dns.SimplifiedNameServer.OriginalRule
-	17, // 28: v2ray.core.app.dns.SimplifiedNameServer.fake_dns:type_name -> v2ray.core.app.dns.fakedns.FakeDnsPoolMulti
-	1,  // 29: v2ray.core.app.dns.SimplifiedNameServer.query_strategy:type_name -> v2ray.core.app.dns.QueryStrategy
-	2,  // 30: v2ray.core.app.dns.SimplifiedNameServer.cache_strategy:type_name -> v2ray.core.app.dns.CacheStrategy
-	3,  // 31: v2ray.core.app.dns.SimplifiedNameServer.fallback_strategy:type_name -> v2ray.core.app.dns.FallbackStrategy
-	18, // 32: v2ray.core.app.dns.SimplifiedNameServer.geo_domain:type_name -> v2ray.core.app.router.routercommon.GeoSite
-	0,  // 33: v2ray.core.app.dns.NameServer.PriorityDomain.type:type_name -> v2ray.core.app.dns.DomainMatchingType
-	19, // 34: v2ray.core.app.dns.Config.HostsEntry.value:type_name -> v2ray.core.common.net.IPOrDomain
-	0,  // 35: v2ray.core.app.dns.SimplifiedNameServer.PriorityDomain.type:type_name -> v2ray.core.app.dns.DomainMatchingType
-	36, // [36:36] is the sub-list for method output_type
-	36, // [36:36] is the sub-list for method input_type
-	36, // [36:36] is the sub-list for extension type_name
-	36, // [36:36] is the sub-list for extension extendee
-	0,  // [0:36] is the sub-list for field type_name
+	5,  // 8: v2ray.core.app.dns.NameServer.dnssec:type_name -> v2ray.core.app.dns.DnssecMode
+	8,  // 9: v2ray.core.app.dns.NameServer.client_subnet:type_name -> v2ray.core.app.dns.ClientSubnet
+	18, // 10: v2ray.core.app.dns.ClientSubnet.rule:type_name -> v2ray.core.app.dns.ClientSubnet.Rule
+	0,  // 11: v2ray.core.app.dns.HostMapping.type:type_name -> v2ray.core.app.dns.DomainMatchingType
+	6,  // 12: v2ray.core.app.dns.HostsFile.format:type_name -> v2ray.core.app.dns.HostsFile.Format
+	23, // 13: v2ray.core.app.dns.Config.NameServers:type_name -> v2ray.core.common.net.Endpoint
+	7,  // 14: v2ray.core.app.dns.Config.name_server:type_name -> v2ray.core.app.dns.NameServer
+	19, // 15: v2ray.core.app.dns.Config.Hosts:type_name -> v2ray.core.app.dns.Config.HostsEntry
+	9,  // 16: v2ray.core.app.dns.Config.static_hosts:type_name -> v2ray.core.app.dns.HostMapping
+	25, // 17: v2ray.core.app.dns.Config.fake_dns:type_name -> v2ray.core.app.dns.fakedns.FakeDnsPoolMulti
+	1,  // 18: v2ray.core.app.dns.Config.query_strategy:type_name -> v2ray.core.app.dns.QueryStrategy
+	2,  // 19: v2ray.core.app.dns.Config.cache_strategy:type_name -> v2ray.core.app.dns.CacheStrategy
+	3,  // 20: v2ray.core.app.dns.Config.fallback_strategy:type_name -> v2ray.core.app.dns.FallbackStrategy
+	4,  // 21: v2ray.core.app.dns.Config.parallel_strategy:type_name -> v2ray.core.app.dns.ParallelStrategy
+	10, // 22: v2ray.core.app.dns.Config.hosts_file:type_name -> v2ray.core.app.dns.HostsFile
+	8,  // 23: v2ray.core.app.dns.Config.client_subnet:type_name -> v2ray.core.app.dns.ClientSubnet
+	14, // 24: v2ray.core.app.dns.SimplifiedConfig.name_server:type_name -> v2ray.core.app.dns.SimplifiedNameServer
+	13, // 25: v2ray.core.app.dns.SimplifiedConfig.static_hosts:type_name -> v2ray.core.app.dns.SimplifiedHostMapping
+	25, // 26: v2ray.core.app.dns.SimplifiedConfig.fake_dns:type_name -> v2ray.core.app.dns.fakedns.FakeDnsPoolMulti
+	1,  // 27: v2ray.core.app.dns.SimplifiedConfig.query_strategy:type_name -> v2ray.core.app.dns.QueryStrategy
+	2,  // 28: v2ray.core.app.dns.SimplifiedConfig.cache_strategy:type_name -> v2ray.core.app.dns.CacheStrategy
+	3,  // 29: v2ray.core.app.dns.SimplifiedConfig.fallback_strategy:type_name -> v2ray.core.app.dns.FallbackStrategy
+	4,  // 30: v2ray.core.app.dns.SimplifiedConfig.parallel_strategy:type_name -> v2ray.core.app.dns.ParallelStrategy
+	10, // 31: v2ray.core.app.dns.SimplifiedConfig.hosts_file:type_name -> v2ray.core.app.dns.HostsFile
+	15, // 32: v2ray.core.app.dns.SimplifiedConfig.client_subnet:type_name -> v2ray.core.app.dns.SimplifiedClientSubnet
+	0,  // 33: v2ray.core.app.dns.SimplifiedHostMapping.type:type_name -> v2ray.core.app.dns.DomainMatchingType
+	23, // 34: v2ray.core.app.dns.SimplifiedNameServer.address:type_name -> v2ray.core.common.net.Endpoint
+	20, // 35: v2ray.core.app.dns.SimplifiedNameServer.prioritized_domain:type_name -> v2ray.core.app.dns.SimplifiedNameServer.PriorityDomain
+	24, // 36: v2ray.core.app.dns.SimplifiedNameServer.geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
+	21, // 37: v2ray.core.app.dns.SimplifiedNameServer.original_rules:type_name -> v2ray.core.app.dns.SimplifiedNameServer.OriginalRule
+	25, // 38: v2ray.core.app.dns.SimplifiedNameServer.fake_dns:type_name -> v2ray.core.app.dns.fakedns.FakeDnsPoolMulti
+	1,  // 39: v2ray.core.app.dns.SimplifiedNameServer.query_strategy:type_name -> v2ray.core.app.dns.QueryStrategy
+	2,  // 40: v2ray.core.app.dns.SimplifiedNameServer.cache_strategy:type_name -> v2ray.core.app.dns.CacheStrategy
+	3,  // 41: v2ray.core.app.dns.SimplifiedNameServer.fallback_strategy:type_name -> v2ray.core.app.dns.FallbackStrategy
+	5,  // 42: v2ray.core.app.dns.SimplifiedNameServer.dnssec:type_name -> v2ray.core.app.dns.DnssecMode
+	15, // 43: v2ray.core.app.dns.SimplifiedNameServer.client_subnet:type_name -> v2ray.core.app.dns.SimplifiedClientSubnet
+	26, // 44: v2ray.core.app.dns.SimplifiedNameServer.geo_domain:type_name -> v2ray.core.app.router.routercommon.GeoSite
+	22, // 45: v2ray.core.app.dns.SimplifiedClientSubnet.rule:type_name -> v2ray.core.app.dns.SimplifiedClientSubnet.Rule
+	0,  // 46: v2ray.core.app.dns.NameServer.PriorityDomain.type:type_name -> v2ray.core.app.dns.DomainMatchingType
+	16, // 47: v2ray.core.app.dns.ClientSubnet.Rule.domain:type_name -> v2ray.core.app.dns.NameServer.PriorityDomain
+	27, // 48: v2ray.core.app.dns.Config.HostsEntry.value:type_name -> v2ray.core.common.net.IPOrDomain
+	0,  // 49: v2ray.core.app.dns.SimplifiedNameServer.PriorityDomain.type:type_name -> v2ray.core.app.dns.DomainMatchingType
+	20, // 50: v2ray.core.app.dns.SimplifiedClientSubnet.Rule.domain:type_name -> v2ray.core.app.dns.SimplifiedNameServer.PriorityDomain
+	51, // [51:51] is the sub-list for method output_type
+	51, // [51:51] is the sub-list for method input_type
+	51, // [51:51] is the sub-list for extension type_name
+	51, // [51:51] is the sub-list for extension extendee
+	0,  // [0:51] is the sub-list for field type_name
 }
 
 func init() { file_app_dns_config_proto_init() }
@@ -1368,14 +2147,14 @@ func file_app_dns_config_proto_init() {
 		return
 	}
 	file_app_dns_config_proto_msgTypes[0].OneofWrappers = []any{}
-	file_app_dns_config_proto_msgTypes[5].OneofWrappers = []any{}
+	file_app_dns_config_proto_msgTypes[7].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_dns_config_proto_rawDesc), len(file_app_dns_config_proto_rawDesc)),
-			NumEnums:      4,
-			NumMessages:   11,
+			NumEnums:      7,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -24,6 +24,7 @@ import (
 	"github.com/v2fly/v2ray-core/v5/common/strmatcher"
 	"github.com/v2fly/v2ray-core/v5/features"
 	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/geodata"
 )
@@ -32,10 +33,14 @@ import (
 type DNS struct {
 	sync.Mutex
 	hosts         *StaticHosts
+	hostsFiles    *hostsFiles
 	clients       []*Client
 	ctx           context.Context
 	clientTags    map[string]bool
 	fakeDNSEngine *FakeDNSEngine
+	cache         *recordCache
+	parallel      ParallelStrategy
+	parallelCount int
 	domainMatcher strmatcher.IndexMatcher
 	matcherInfos  []DomainMatcherInfo
 }
@@ -53,6 +58,13 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 	if err != nil {
 		return nil, newError("failed to create hosts").Base(err)
 	}
+	var files *hostsFiles
+	if len(config.HostsFile) > 0 {
+		files, err = newHostsFiles(ctx, config.HostsFile)
+		if err != nil {
+			return nil, newError("failed to load hosts files").Base(err)
+		}
+	}
 
 	// Create name servers from legacy configs
 	clients := []*Client{}
@@ -81,10 +93,26 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 		clients = append(clients, NewLocalDNSClient())
 	}
 
+	// Share the record cache among name servers
+	cache := newRecordCache(int(config.CacheSize), config.CacheMinTtl, config.CacheMaxTtl)
+	for _, client := range clients {
+		client.setRecordCache(cache)
+	}
+	if err := core.RequireFeatures(ctx, func(sm stats.Manager) {
+		cache.registerCounters(sm)
+	}); err != nil {
+		return nil, err
+	}
+
 	s := &DNS{
-		hosts:   hosts,
-		clients: clients,
-		ctx:     ctx,
+		hosts:      hosts,
+		hostsFiles: files,
+		clients:    clients,
+		ctx:        ctx,
+		cache:      cache,
+
+		parallel:      config.ParallelStrategy,
+		parallelCount: int(config.ParallelCount),
 	}
 
 	// Establish members related to global DNS state
@@ -182,7 +210,7 @@ func establishExpectedIPs(s *DNS, config *Config, nsClientMap map[int]int) error
 }
 
 func establishFakeDNS(s *DNS, config *Config, nsClientMap map[int]int) error {
-	fakeHolders := &fakedns.HolderMulti{}
+	fakeHolders := fakedns.NewHolderMultiWithContext(s.ctx)
 	fakeDefault := (*fakedns.HolderMulti)(nil)
 	if config.FakeDns != nil {
 		defaultEngine, err := fakeHolders.AddPoolMulti(config.FakeDns)
@@ -228,14 +256,28 @@ func (*DNS) Type() interface{} {
 
 // Start implements common.Runnable.
 func (s *DNS) Start() error {
+	if s.hostsFiles != nil {
+		return s.hostsFiles.Start()
+	}
 	return nil
 }
 
 // Close implements common.Closable.
 func (s *DNS) Close() error {
+	if s.hostsFiles != nil {
+		return s.hostsFiles.Close()
+	}
 	return nil
 }
 
+// lookupHosts looks up the static hosts, and then the hosts files.
+func (s *DNS) lookupHosts(domain string, option feature_dns.IPOption) []net.Address {
+	if addrs := s.hosts.Lookup(domain, option); addrs != nil || s.hostsFiles == nil {
+		return addrs
+	}
+	return s.hostsFiles.Lookup(domain, option)
+}
+
 // IsOwnLink implements proxy.dns.ownLinkVerifier
 func (s *DNS) IsOwnLink(ctx context.Context) bool {
 	inbound := session.InboundFromContext(ctx)
@@ -252,6 +294,21 @@ func (s *DNS) AsFakeDNSEngine() feature_dns.FakeDNSEngine {
 	return s.fakeDNSEngine
 }
 
+// GetCacheEntries implements dns.CacheManager.
+func (s *DNS) GetCacheEntries(server, domain string) []*feature_dns.CacheEntry {
+	return s.cache.Entries(server, domain)
+}
+
+// FlushCache implements dns.CacheManager.
+func (s *DNS) FlushCache(server, domain string) int {
+	return s.cache.Flush(server, domain)
+}
+
+// GetCacheStats implements dns.CacheManager.
+func (s *DNS) GetCacheStats() feature_dns.CacheStats {
+	return s.cache.Stats()
+}
+
 // LookupIP implements dns.Client.
 func (s *DNS) LookupIP(domain string) ([]net.IP, error) {
 	return s.lookupIPInternal(domain, feature_dns.IPOption{IPv4Enable: true, IPv6Enable: true, FakeEnable: false})
@@ -278,10 +335,10 @@ func (s *DNS) LookupIPv6WithTTL(domain string) ([]net.IP, time.Time, error) {
 }
 
 func (s *DNS) QueryRaw(request []byte) ([]byte, error) {
-	return s.queryRaw(request, false)
+	return s.queryRaw(context.Background(), request, false)
 }
 
-func (s *DNS) queryRaw(request []byte, fakeEnabled bool) ([]byte, error) {
+func (s *DNS) queryRawInternal(request []byte, fakeEnabled bool, info *queryInfo) ([]byte, error) {
 	requestMsg := new(dns.Msg)
 	if err := requestMsg.Unpack(request); err != nil {
 		return nil, newError("failed to parse dns request").Base(err)
@@ -304,7 +361,7 @@ func (s *DNS) queryRaw(request []byte, fakeEnabled bool) ([]byte, error) {
 
 	if len(requestMsg.Question) == 1 && (qType == dns.TypeA || qType == dns.TypeAAAA) && qClass == dns.ClassINET {
 		// Static host lookup
-		switch addrs := s.hosts.Lookup(domain, feature_dns.IPOption{
+		switch addrs := s.lookupHosts(domain, feature_dns.IPOption{
 			IPv4Enable: qType == dns.TypeA,
 			IPv6Enable: qType == dns.TypeAAAA,
 			FakeEnable: fakeEnabled,
@@ -313,6 +370,7 @@ func (s *DNS) queryRaw(request []byte, fakeEnabled bool) ([]byte, error) {
 		case len(addrs) == 1 && addrs[0].Family().IsDomain(): // Domain replacement, unsupported
 		default: // Successfully found ip records in static host
 			newError("returning ", len(addrs), " IP(s) for domain ", domain, " -> ", addrs).WriteToLog()
+			info.server = "hosts"
 			ips, err := toNetIP(addrs)
 			if err != nil {
 				return nil, err
@@ -366,7 +424,8 @@ func (s *DNS) queryRaw(request []byte, fakeEnabled bool) ([]byte, error) {
 	}
 	errs := []error{}
 	for _, client := range clients {
-		response, err := client.QueryRaw(s.ctx, request,
+		info.server = client.Name()
+		response, err := client.QueryRaw(contextWithQueryInfo(s.ctx, info), request,
 			fakeEnabled && (qType == dns.TypeA || qType == dns.TypeAAAA),
 		)
 		if err == nil {
@@ -386,6 +445,10 @@ func (s *DNS) lookupIPInternal(domain string, option feature_dns.IPOption) ([]ne
 }
 
 func (s *DNS) lookupIPInternalWithTTL(domain string, option feature_dns.IPOption) ([]net.IP, time.Time, error) {
+	return s.lookupIPWithContext(context.Background(), domain, option)
+}
+
+func (s *DNS) lookupIP(domain string, option feature_dns.IPOption, info *queryInfo) ([]net.IP, time.Time, error) {
 	if domain == "" {
 		return nil, time.Time{}, newError("empty domain name")
 	}
@@ -394,24 +457,31 @@ func (s *DNS) lookupIPInternalWithTTL(domain string, option feature_dns.IPOption
 	domain = strings.TrimSuffix(domain, ".")
 
 	// Static host lookup
-	switch addrs := s.hosts.Lookup(domain, option); {
+	switch addrs := s.lookupHosts(domain, option); {
 	case addrs == nil: // Domain not recorded in static host
 		break
 	case len(addrs) == 0: // Domain recorded, but no valid IP returned (e.g. IPv4 address with only IPv6 enabled)
+		info.server = "hosts"
 		return nil, time.Now().Add(time.Duration(600) * time.Second), feature_dns.ErrEmptyResponse
 	case len(addrs) == 1 && addrs[0].Family().IsDomain(): // Domain replacement
 		newError("domain replaced: ", domain, " -> ", addrs[0].Domain()).WriteToLog()
 		domain = addrs[0].Domain()
 	default: // Successfully found ip records in static host
 		newError("returning ", len(addrs), " IP(s) for domain ", domain, " -> ", addrs).WriteToLog()
+		info.server = "hosts"
 		ips, err := toNetIP(addrs)
 		return ips, time.Now().Add(time.Duration(600) * time.Second), err
 	}
 
 	// Name servers lookup
+	clients := s.sortClients(domain, option)
+	if s.parallel != ParallelStrategy_Sequential && len(clients) > 1 {
+		return s.parallelLookup(clients, domain, option, info)
+	}
 	errs := []error{}
-	for _, client := range s.sortClients(domain, option) {
-		ips, expireAt, err := client.QueryIPWithTTL(s.ctx, domain, option)
+	for _, client := range clients {
+		info.server, info.cached = client.Name(), false
+		ips, expireAt, err := client.QueryIPWithTTL(contextWithQueryInfo(s.ctx, info), domain, option)
 		if len(ips) > 0 {
 			return ips, expireAt, nil
 		}
@@ -584,6 +654,13 @@ func init() {
 				FallbackStrategy: v.FallbackStrategy,
 				SkipFallback:     v.SkipFallback,
 				Geoip:            v.Geoip,
+
+				Dnssec:            v.Dnssec,
+				DnssecTrustAnchor: v.DnssecTrustAnchor,
+			}
+			var err error
+			if nameserver.ClientSubnet, err = v.ClientSubnet.toClientSubnet(); err != nil {
+				return nil, err
 			}
 			for _, prioritizedDomain := range v.PrioritizedDomain {
 				nameserver.PrioritizedDomain = append(nameserver.PrioritizedDomain, &NameServer_PriorityDomain{
@@ -617,11 +694,21 @@ func init() {
 			QueryStrategy:    simplifiedConfig.QueryStrategy,
 			CacheStrategy:    simplifiedConfig.CacheStrategy,
 			FallbackStrategy: simplifiedConfig.FallbackStrategy,
+			CacheSize:        simplifiedConfig.CacheSize,
+			CacheMinTtl:      simplifiedConfig.CacheMinTtl,
+			CacheMaxTtl:      simplifiedConfig.CacheMaxTtl,
+			ParallelStrategy: simplifiedConfig.ParallelStrategy,
+			ParallelCount:    simplifiedConfig.ParallelCount,
+			HostsFile:        simplifiedConfig.HostsFile,
 			// Deprecated flags
 			DisableCache:           simplifiedConfig.DisableCache,
 			DisableFallback:        simplifiedConfig.DisableFallback,
 			DisableFallbackIfMatch: simplifiedConfig.DisableFallbackIfMatch,
 		}
+		var err error
+		if fullConfig.ClientSubnet, err = simplifiedConfig.ClientSubnet.toClientSubnet(); err != nil {
+			return nil, err
+		}
 		return common.CreateObject(ctx, fullConfig)
 	}))
 }
@@ -22,8 +22,14 @@ func Fqdn(domain string) string {
 }
 
 type record struct {
-	A    *IPRecord
-	AAAA *IPRecord
+	A     *IPRecord
+	AAAA  *IPRecord
+	HTTPS *svcbRecord
+	SVCB  *svcbRecord
+}
+
+func (r record) isEmpty() bool {
+	return r.A == nil && r.AAAA == nil && r.HTTPS == nil && r.SVCB == nil
 }
 
 // IPRecord is a cacheable item for a resolved domain
@@ -69,28 +75,38 @@ func genEDNS0Subnet(clientIP net.IP) dns.EDNS0 {
 	if len(clientIP) == 0 {
 		return nil
 	}
-	var netmask uint8
-	var family uint16
 	if len(clientIP) == 4 {
+		return newEDNS0Subnet(clientIP, 24) // 24 for IPV4, 96 for IPv6
+	}
+	return newEDNS0Subnet(clientIP, 96)
+}
+
+// newEDNS0Subnet returns the EDNS client subnet option of the address with the prefix length.
+func newEDNS0Subnet(ip net.IP, prefix uint8) *dns.EDNS0_SUBNET {
+	var family uint16
+	if len(ip) == 4 {
 		family = 1
-		netmask = 24 // 24 for IPV4, 96 for IPv6
 	} else {
 		family = 2
-		netmask = 96
 	}
 	return &dns.EDNS0_SUBNET{
 		Code:          dns.EDNS0SUBNET,
 		Family:        family,
-		Address:       clientIP,
-		SourceNetmask: netmask,
+		Address:       ip,
+		SourceNetmask: prefix,
 		SourceScope:   0,
 	}
 }
 
 func genEDNS0Options(clientIP net.IP) dns.RR {
-	if len(clientIP) == 0 {
+	subnet := genEDNS0Subnet(clientIP)
+	if subnet == nil {
 		return nil
 	}
+	return newEDNS0Options(subnet)
+}
+
+func newEDNS0Options(subnet dns.EDNS0) *dns.OPT {
 	opt := &dns.OPT{
 		Hdr: dns.RR_Header{
 			Name:   ".",
@@ -101,7 +117,7 @@ func genEDNS0Options(clientIP net.IP) dns.RR {
 	opt.SetUDPSize(1350)
 	opt.SetExtendedRcode(0xfe00)
 	opt.SetDo(true)
-	opt.Option = append(opt.Option, genEDNS0Subnet(clientIP))
+	opt.Option = append(opt.Option, subnet)
 	return opt
 }
 
@@ -0,0 +1,888 @@
+package dns
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/v2fly/v2ray-core/v5/common/errors"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	dns_feature "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+// rootTrustAnchors are the DS records of the root zone KSKs published by IANA.
+var rootTrustAnchors = []string{
+	". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D",
+	". IN DS 38696 8 2 683D2D0ACB8C9B712A1948B27F741219298D0A450D612C483AF444A4C0FB2B16",
+}
+
+const maxDelegationTTL = time.Hour
+
+var errBogus = errors.New("DNSSEC validation failed")
+
+// delegation is the DNSSEC status of a name, found by walking the chain of trust from a trust anchor.
+type delegation struct {
+	// zone is the closest enclosing zone of the name known to be signed.
+	zone string
+	// keys are the validated DNSKEYs of the zone, or nil if the name is under an insecure delegation.
+	keys   []*dns.DNSKEY
+	expire time.Time
+}
+
+func (d *delegation) insecure() bool {
+	return d.keys == nil
+}
+
+// rrsetKey identifies an RRset in a DNS message section.
+type rrsetKey struct {
+	name   string
+	rrtype uint16
+}
+
+// splitRRSets groups the records by RRset, with the signatures covering them.
+func splitRRSets(rrs []dns.RR) (map[rrsetKey][]dns.RR, map[rrsetKey][]*dns.RRSIG) {
+	rrsets := make(map[rrsetKey][]dns.RR)
+	sigs := make(map[rrsetKey][]*dns.RRSIG)
+	for _, rr := range rrs {
+		header := rr.Header()
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			key := rrsetKey{dns.CanonicalName(header.Name), sig.TypeCovered}
+			sigs[key] = append(sigs[key], sig)
+			continue
+		}
+		if header.Rrtype == dns.TypeOPT {
+			continue
+		}
+		key := rrsetKey{dns.CanonicalName(header.Name), header.Rrtype}
+		rrsets[key] = append(rrsets[key], rr)
+	}
+	return rrsets, sigs
+}
+
+// verifyRRSet verifies the RRset with one of the signatures made by the keys of the zone.
+func verifyRRSet(rrset []dns.RR, sigs []*dns.RRSIG, zone string, keys []*dns.DNSKEY) error {
+	now := time.Now()
+	for _, sig := range sigs {
+		if dns.CanonicalName(sig.SignerName) != zone || !sig.ValidityPeriod(now) {
+			continue
+		}
+		for _, key := range keys {
+			if key.KeyTag() != sig.KeyTag || key.Algorithm != sig.Algorithm {
+				continue
+			}
+			if sig.Verify(key, rrset) == nil {
+				return nil
+			}
+		}
+	}
+	return newError("no valid signature for ", rrset[0].Header().Name, " ", dns.Type(rrset[0].Header().Rrtype), " from ", zone).Base(errBogus)
+}
+
+// rrsetTTL returns the TTL for caching the validated RRset.
+func rrsetTTL(rrset []dns.RR) time.Duration {
+	ttl := maxDelegationTTL
+	for _, rr := range rrset {
+		ttl = min(ttl, time.Duration(rr.Header().Ttl)*time.Second)
+	}
+	return ttl
+}
+
+// canonicalCompare compares two domain names in the canonical DNS name order defined in RFC 4034.
+func canonicalCompare(a, b string) int {
+	labelsA := dns.SplitDomainName(dns.CanonicalName(a))
+	labelsB := dns.SplitDomainName(dns.CanonicalName(b))
+	for i, j := len(labelsA)-1, len(labelsB)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if c := strings.Compare(labelsA[i], labelsB[j]); c != 0 {
+			return c
+		}
+	}
+	return len(labelsA) - len(labelsB)
+}
+
+// nsecCovers returns true if the name falls between the owner and the next name of the NSEC record.
+func nsecCovers(nsec *dns.NSEC, name string) bool {
+	owner, next := nsec.Header().Name, nsec.NextDomain
+	if canonicalCompare(owner, next) < 0 {
+		return canonicalCompare(owner, name) < 0 && canonicalCompare(name, next) < 0
+	}
+	// The last NSEC record of the zone.
+	return canonicalCompare(owner, name) < 0 || canonicalCompare(name, next) < 0
+}
+
+func hasType(bitmap []uint16, rrtype uint16) bool {
+	for _, t := range bitmap {
+		if t == rrtype {
+			return true
+		}
+	}
+	return false
+}
+
+// denialProof is what the NSEC or NSEC3 records prove about a name.
+type denialProof int
+
+const (
+	proofNone denialProof = iota
+	// proofNoData proves the name exists without the queried type.
+	proofNoData
+	// proofNoName proves the name does not exist.
+	proofNoName
+	// proofInsecureDelegation proves the name is a delegation without DS.
+	proofInsecureDelegation
+)
+
+// proveDenial checks the validated NSEC or NSEC3 records of the zone for the name and type, as
+// described in RFC 4035 section 5.4 and RFC 5155 section 8.
+func proveDenial(records []dns.RR, zone string, name string, rrtype uint16) denialProof {
+	zone, name = dns.CanonicalName(zone), dns.CanonicalName(name)
+	var nsecs []*dns.NSEC
+	var nsec3s []*dns.NSEC3
+	for _, rr := range records {
+		switch rr := rr.(type) {
+		case *dns.NSEC:
+			nsecs = append(nsecs, rr)
+		case *dns.NSEC3:
+			nsec3s = append(nsec3s, rr)
+		}
+	}
+	if len(nsec3s) > 0 {
+		return proveNSEC3Denial(nsec3s, zone, name, rrtype)
+	}
+	return proveNSECDenial(nsecs, zone, name, rrtype)
+}
+
+// proveNoData checks the type bitmap of the record matching the name. A bitmap listing the type or
+// CNAME proves nothing but that the denial is forged.
+func proveNoData(bitmap []uint16, rrtype uint16) denialProof {
+	if hasType(bitmap, rrtype) || hasType(bitmap, dns.TypeCNAME) {
+		return proofNone
+	}
+	delegation := hasType(bitmap, dns.TypeNS) && !hasType(bitmap, dns.TypeSOA)
+	switch {
+	case rrtype == dns.TypeDS && delegation:
+		return proofInsecureDelegation
+	case delegation:
+		// The record at the parent side of a zone cut proves nothing about the child zone.
+		return proofNone
+	default:
+		return proofNoData
+	}
+}
+
+// ancestorWithLabels returns the ancestor of the name with the number of labels.
+func ancestorWithLabels(name string, labels int) string {
+	indexes := dns.Split(name)
+	switch {
+	case labels <= 0:
+		return "."
+	case labels >= len(indexes):
+		return name
+	default:
+		return name[indexes[len(indexes)-labels]:]
+	}
+}
+
+func wildcardOf(name string) string {
+	if name == "." {
+		return "*."
+	}
+	return "*." + name
+}
+
+func proveNSECDenial(nsecs []*dns.NSEC, zone string, name string, rrtype uint16) denialProof {
+	var cover *dns.NSEC
+	for _, nsec := range nsecs {
+		if dns.CanonicalName(nsec.Header().Name) == name {
+			return proveNoData(nsec.TypeBitMap, rrtype)
+		}
+		if cover == nil && nsecCovers(nsec, name) {
+			cover = nsec
+		}
+	}
+	if cover == nil {
+		return proofNone
+	}
+	next := dns.CanonicalName(cover.NextDomain)
+	if dns.IsSubDomain(name, next) {
+		// An empty non-terminal, which exists without any record.
+		return proofNoData
+	}
+
+	// The name does not exist, and neither does the wildcard at its closest encloser.
+	labels := max(dns.CompareDomainName(name, cover.Header().Name), dns.CompareDomainName(name, next))
+	encloser := ancestorWithLabels(name, labels)
+	if !dns.IsSubDomain(zone, encloser) {
+		return proofNone
+	}
+	wildcard := wildcardOf(encloser)
+	for _, nsec := range nsecs {
+		if dns.CanonicalName(nsec.Header().Name) == wildcard {
+			// The answer would have been synthesized from the wildcard if it had the type.
+			if proveNoData(nsec.TypeBitMap, rrtype) == proofNoData {
+				return proofNoData
+			}
+			return proofNone
+		}
+	}
+	for _, nsec := range nsecs {
+		if nsecCovers(nsec, wildcard) {
+			return proofNoName
+		}
+	}
+	return proofNone
+}
+
+func proveNSEC3Denial(nsec3s []*dns.NSEC3, zone string, name string, rrtype uint16) denialProof {
+	matching := func(name string) *dns.NSEC3 {
+		for _, nsec3 := range nsec3s {
+			if nsec3.Match(name) {
+				return nsec3
+			}
+		}
+		return nil
+	}
+	covering := func(name string) *dns.NSEC3 {
+		for _, nsec3 := range nsec3s {
+			if nsec3.Cover(name) {
+				return nsec3
+			}
+		}
+		return nil
+	}
+
+	if nsec3 := matching(name); nsec3 != nil {
+		return proveNoData(nsec3.TypeBitMap, rrtype)
+	}
+
+	// The closest encloser proof: the closest existing ancestor matches an NSEC3 record, and the next
+	// closer name, one label longer, is covered by another.
+	labels := dns.CountLabel(name)
+	for n := labels - 1; n >= dns.CountLabel(zone); n-- {
+		encloser := ancestorWithLabels(name, n)
+		match := matching(encloser)
+		if match == nil {
+			continue
+		}
+		if hasType(match.TypeBitMap, dns.TypeDNAME) || (hasType(match.TypeBitMap, dns.TypeNS) && !hasType(match.TypeBitMap, dns.TypeSOA)) {
+			// The names below a zone cut or DNAME are not in the zone.
+			return proofNone
+		}
+		nextCloser := covering(ancestorWithLabels(name, n+1))
+		if nextCloser == nil {
+			return proofNone
+		}
+		if rrtype == dns.TypeDS && nextCloser.Flags&0x01 != 0 {
+			// Opt-out span may contain unsigned delegations.
+			return proofInsecureDelegation
+		}
+		wildcard := wildcardOf(encloser)
+		if nsec3 := matching(wildcard); nsec3 != nil {
+			// The answer would have been synthesized from the wildcard if it had the type.
+			if proveNoData(nsec3.TypeBitMap, rrtype) == proofNoData {
+				return proofNoData
+			}
+			return proofNone
+		}
+		if covering(wildcard) != nil {
+			return proofNoName
+		}
+		return proofNone
+	}
+	return proofNone
+}
+
+// dnssecValidator validates responses by walking the chain of trust with queries to a name server.
+type dnssecValidator struct {
+	server  ServerRaw
+	anchors map[string][]*dns.DS
+
+	access      sync.Mutex
+	delegations map[string]*delegation
+}
+
+func newDNSSECValidator(server ServerRaw, trustAnchors []string) (*dnssecValidator, error) {
+	if len(trustAnchors) == 0 {
+		trustAnchors = rootTrustAnchors
+	}
+	v := &dnssecValidator{
+		server:      server,
+		anchors:     make(map[string][]*dns.DS),
+		delegations: make(map[string]*delegation),
+	}
+	for _, anchor := range trustAnchors {
+		rr, err := dns.NewRR(anchor)
+		if err != nil {
+			return nil, newError("invalid trust anchor: ", anchor).Base(err)
+		}
+		ds, ok := rr.(*dns.DS)
+		if !ok {
+			return nil, newError("trust anchor is not a DS record: ", anchor)
+		}
+		zone := dns.CanonicalName(ds.Header().Name)
+		v.anchors[zone] = append(v.anchors[zone], ds)
+	}
+	return v, nil
+}
+
+// exchange sends a query with DNSSEC records requested and checking disabled.
+func (v *dnssecValidator) exchange(ctx context.Context, name string, rrtype uint16) (*dns.Msg, error) {
+	request := new(dns.Msg)
+	request.SetQuestion(name, rrtype)
+	request.Id = v.server.NewReqID()
+	request.CheckingDisabled = true
+	request.SetEdns0(1232, true)
+	b, err := request.Pack()
+	if err != nil {
+		return nil, err
+	}
+	b, err = v.server.QueryRaw(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	response := new(dns.Msg)
+	if err := response.Unpack(b); err != nil {
+		return nil, newError("failed to parse DNS response").Base(err)
+	}
+	return response, nil
+}
+
+// fetchKeys queries and validates the DNSKEYs of the zone with its DS records.
+func (v *dnssecValidator) fetchKeys(ctx context.Context, zone string, dsSet []*dns.DS) ([]*dns.DNSKEY, time.Duration, error) {
+	response, err := v.exchange(ctx, zone, dns.TypeDNSKEY)
+	if err != nil {
+		return nil, 0, newError("failed to query DNSKEY of ", zone).Base(err)
+	}
+	rrsets, sigs := splitRRSets(response.Answer)
+	key := rrsetKey{zone, dns.TypeDNSKEY}
+	rrset := rrsets[key]
+	var keys, trustedKeys []*dns.DNSKEY
+	for _, rr := range rrset {
+		dnskey := rr.(*dns.DNSKEY)
+		keys = append(keys, dnskey)
+		for _, ds := range dsSet {
+			if dnskey.KeyTag() != ds.KeyTag || dnskey.Algorithm != ds.Algorithm {
+				continue
+			}
+			if digest := dnskey.ToDS(ds.DigestType); digest != nil && strings.EqualFold(digest.Digest, ds.Digest) {
+				trustedKeys = append(trustedKeys, dnskey)
+			}
+		}
+	}
+	if len(trustedKeys) == 0 {
+		return nil, 0, newError("no DNSKEY of ", zone, " matches DS").Base(errBogus)
+	}
+	if err := verifyRRSet(rrset, sigs[key], zone, trustedKeys); err != nil {
+		return nil, 0, err
+	}
+	return keys, rrsetTTL(rrset), nil
+}
+
+// anchorFor returns the closest trust anchor enclosing the name.
+func (v *dnssecValidator) anchorFor(name string) (string, []*dns.DS) {
+	for off, end := 0, false; !end; off, end = dns.NextLabel(name, off) {
+		zone := name[off:]
+		if ds, found := v.anchors[zone]; found {
+			return zone, ds
+		}
+	}
+	if ds, found := v.anchors["."]; found {
+		return ".", ds
+	}
+	return "", nil
+}
+
+func (v *dnssecValidator) cachedDelegation(name string) *delegation {
+	v.access.Lock()
+	defer v.access.Unlock()
+	d, found := v.delegations[name]
+	if !found {
+		return nil
+	}
+	if d.expire.Before(time.Now()) {
+		delete(v.delegations, name)
+		return nil
+	}
+	return d
+}
+
+func (v *dnssecValidator) cacheDelegation(name string, d *delegation) {
+	v.access.Lock()
+	defer v.access.Unlock()
+	v.delegations[name] = d
+}
+
+// delegationFor walks the chain of trust down to the name, label by label, from the closest trust
+// anchor or cached ancestor.
+func (v *dnssecValidator) delegationFor(ctx context.Context, name string) (*delegation, error) {
+	name = dns.CanonicalName(name)
+	if d := v.cachedDelegation(name); d != nil {
+		return d, nil
+	}
+
+	anchor, dsSet := v.anchorFor(name)
+	if anchor == "" {
+		// No trust anchor for this name.
+		return &delegation{}, nil
+	}
+
+	// Names from the anchor (exclusive) down to the name (inclusive).
+	var names []string
+	var current *delegation
+	for off, end := 0, false; !end; off, end = dns.NextLabel(name, off) {
+		ancestor := name[off:]
+		if ancestor == anchor {
+			break
+		}
+		if d := v.cachedDelegation(ancestor); d != nil {
+			current = d
+			break
+		}
+		names = append([]string{ancestor}, names...)
+	}
+	if current == nil {
+		if current = v.cachedDelegation(anchor); current == nil {
+			keys, ttl, err := v.fetchKeys(ctx, anchor, dsSet)
+			if err != nil {
+				return nil, err
+			}
+			current = &delegation{zone: anchor, keys: keys, expire: time.Now().Add(ttl)}
+			v.cacheDelegation(anchor, current)
+		}
+	}
+
+	for _, child := range names {
+		if current.insecure() {
+			v.cacheDelegation(child, current)
+			continue
+		}
+		next, err := v.walk(ctx, current, child)
+		if err != nil {
+			return nil, err
+		}
+		v.cacheDelegation(child, next)
+		current = next
+	}
+	return current, nil
+}
+
+// walk finds the delegation status of the child name in the signed zone of the parent.
+func (v *dnssecValidator) walk(ctx context.Context, parent *delegation, child string) (*delegation, error) {
+	response, err := v.exchange(ctx, child, dns.TypeDS)
+	if err != nil {
+		return nil, newError("failed to query DS of ", child).Base(err)
+	}
+	if response.Rcode != dns.RcodeSuccess && response.Rcode != dns.RcodeNameError {
+		return nil, newError("failed to query DS of ", child, ": ", dns.RcodeToString[response.Rcode])
+	}
+
+	rrsets, sigs := splitRRSets(response.Answer)
+	key := rrsetKey{child, dns.TypeDS}
+	if rrset, found := rrsets[key]; found {
+		if err := verifyRRSet(rrset, sigs[key], parent.zone, parent.keys); err != nil {
+			return nil, err
+		}
+		var dsSet []*dns.DS
+		for _, rr := range rrset {
+			dsSet = append(dsSet, rr.(*dns.DS))
+		}
+		keys, ttl, err := v.fetchKeys(ctx, child, dsSet)
+		if err != nil {
+			return nil, err
+		}
+		return &delegation{zone: child, keys: keys, expire: time.Now().Add(min(ttl, rrsetTTL(rrset)))}, nil
+	}
+
+	key = rrsetKey{child, dns.TypeCNAME}
+	if rrset, found := rrsets[key]; found {
+		// An alias is not a zone cut.
+		if err := verifyRRSet(rrset, sigs[key], parent.zone, parent.keys); err != nil {
+			return nil, err
+		}
+		return &delegation{zone: parent.zone, keys: parent.keys, expire: time.Now().Add(rrsetTTL(rrset))}, nil
+	}
+
+	// No DS for the child, which must be proven by the signed zone of the parent.
+	records, ttl, err := v.verifiedDenial(response, parent)
+	if err != nil {
+		return nil, err
+	}
+	expire := time.Now().Add(ttl)
+	switch proveDenial(records, parent.zone, child, dns.TypeDS) {
+	case proofInsecureDelegation:
+		newError("DNSSEC: insecure delegation ", child).AtDebug().WriteToLog()
+		return &delegation{zone: child, expire: expire}, nil
+	case proofNoData, proofNoName:
+		// Not a zone cut.
+		return &delegation{zone: parent.zone, keys: parent.keys, expire: expire}, nil
+	default:
+		return nil, newError("no proof of missing DS for ", child).Base(errBogus)
+	}
+}
+
+// verifiedDenial returns the NSEC and NSEC3 records in the authority section, after verifying all
+// signatures in the section with the keys of the zone.
+func (v *dnssecValidator) verifiedDenial(response *dns.Msg, d *delegation) ([]dns.RR, time.Duration, error) {
+	rrsets, sigs := splitRRSets(response.Ns)
+	ttl := maxDelegationTTL
+	var records []dns.RR
+	for key, rrset := range rrsets {
+		switch key.rrtype {
+		case dns.TypeNSEC, dns.TypeNSEC3, dns.TypeSOA:
+		default:
+			continue
+		}
+		if err := verifyRRSet(rrset, sigs[key], d.zone, d.keys); err != nil {
+			return nil, 0, err
+		}
+		ttl = min(ttl, rrsetTTL(rrset))
+		if key.rrtype != dns.TypeSOA {
+			records = append(records, rrset...)
+		}
+	}
+	return records, ttl, nil
+}
+
+// Validate validates the response to the question. It returns true if the response is secure, or
+// false if it is insecure. An error wrapping errBogus is returned if the response is bogus.
+func (v *dnssecValidator) Validate(ctx context.Context, question dns.Question, response *dns.Msg) (bool, error) {
+	if response.Rcode != dns.RcodeSuccess && response.Rcode != dns.RcodeNameError {
+		return false, nil
+	}
+
+	secure := true
+	name := dns.CanonicalName(question.Name)
+	rrsets, sigs := splitRRSets(response.Answer)
+	found := false
+	// Follow the CNAME chain to the final name.
+	for i := 0; i < len(rrsets) && question.Qtype != dns.TypeCNAME; i++ {
+		cname, ok := rrsets[rrsetKey{name, dns.TypeCNAME}]
+		if !ok {
+			break
+		}
+		name = dns.CanonicalName(cname[0].(*dns.CNAME).Target)
+	}
+	for key, rrset := range rrsets {
+		d, err := v.delegationFor(ctx, key.name)
+		if err != nil {
+			return false, err
+		}
+		if key.name == name && (key.rrtype == question.Qtype || question.Qtype == dns.TypeANY) {
+			found = true
+		}
+		if d.insecure() {
+			secure = false
+			continue
+		}
+		if err := verifyRRSet(rrset, sigs[key], d.zone, d.keys); err != nil {
+			return false, err
+		}
+	}
+	if found {
+		return secure, nil
+	}
+
+	// Denial of existence
+	d, err := v.delegationFor(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	if d.insecure() {
+		return false, nil
+	}
+	records, _, err := v.verifiedDenial(response, d)
+	if err != nil {
+		return false, err
+	}
+	switch proof := proveDenial(records, d.zone, name, question.Qtype); {
+	case proof == proofNoName && response.Rcode == dns.RcodeNameError:
+		return secure, nil
+	case proof == proofNoData && response.Rcode == dns.RcodeSuccess:
+		return secure, nil
+	}
+	return false, newError("no proof of nonexistence for ", name, " ", dns.Type(question.Qtype)).Base(errBogus)
+}
+
+// DNSSECServer validates the responses of the name server with DNSSEC.
+type DNSSECServer struct {
+	server    ServerRaw
+	validator *dnssecValidator
+
+	cache *recordCacheScope
+}
+
+// NewDNSSECServer creates a DNSSECServer validating the responses of the server from the trust
+// anchors. The root zone KSKs are used if no trust anchor is given.
+func NewDNSSECServer(server ServerRaw, trustAnchors []string) (*DNSSECServer, error) {
+	validator, err := newDNSSECValidator(server, trustAnchors)
+	if err != nil {
+		return nil, err
+	}
+	return &DNSSECServer{
+		server:    server,
+		validator: validator,
+		cache:     newRecordCache(0, 0, 0).newScope(server.Name()),
+	}, nil
+}
+
+// setRecordCache implements recordCacheUser.
+func (s *DNSSECServer) setRecordCache(scope *recordCacheScope) {
+	s.cache = scope
+	if server, ok := s.server.(recordCacheUser); ok {
+		// The records of the server are not validated, so they never mix with the validated ones.
+		server.setRecordCache(scope.cache.newScope(s.server.Name()))
+	}
+}
+
+// Name implements Server.
+func (s *DNSSECServer) Name() string {
+	return s.server.Name()
+}
+
+// NewReqID implements ServerRaw.
+func (s *DNSSECServer) NewReqID() uint16 {
+	return s.server.NewReqID()
+}
+
+// servFail returns a SERVFAIL response to the request.
+func servFail(request *dns.Msg) *dns.Msg {
+	response := new(dns.Msg)
+	response.SetRcode(request, dns.RcodeServerFailure)
+	response.RecursionAvailable = true
+	return response
+}
+
+// stripDNSSEC removes DNSSEC records not requested by the client.
+func stripDNSSEC(rrs []dns.RR, qtype uint16) []dns.RR {
+	stripped := rrs[:0]
+	for _, rr := range rrs {
+		switch t := rr.Header().Rrtype; t {
+		case dns.TypeRRSIG, dns.TypeNSEC, dns.TypeNSEC3:
+			if t != qtype {
+				continue
+			}
+		}
+		stripped = append(stripped, rr)
+	}
+	return stripped
+}
+
+// QueryRaw implements ServerRaw.
+func (s *DNSSECServer) QueryRaw(ctx context.Context, request []byte) ([]byte, error) {
+	msg := new(dns.Msg)
+	if err := msg.Unpack(request); err != nil {
+		return nil, newError("failed to parse dns request").Base(err)
+	}
+	if len(msg.Question) != 1 {
+		return s.server.QueryRaw(ctx, request)
+	}
+	checkingDisabled := msg.CheckingDisabled
+	opt := msg.IsEdns0()
+	dnssecOK := opt != nil && opt.Do()
+	if opt == nil {
+		msg.SetEdns0(1232, true)
+	} else {
+		opt.SetDo(true)
+	}
+	msg.CheckingDisabled = true
+	b, err := msg.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err = s.server.QueryRaw(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	response := new(dns.Msg)
+	if err := response.Unpack(b); err != nil {
+		return nil, newError("failed to parse dns response").Base(err)
+	}
+
+	response.AuthenticatedData = false
+	if !checkingDisabled {
+		secure, err := s.validator.Validate(ctx, msg.Question[0], response)
+		if err != nil {
+			newError(s.Name(), " DNSSEC bogus answer for ", msg.Question[0].Name).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+			return servFail(msg).Pack()
+		}
+		response.AuthenticatedData = secure
+	}
+	response.CheckingDisabled = checkingDisabled
+	if !dnssecOK {
+		qtype := msg.Question[0].Qtype
+		response.Answer = stripDNSSEC(response.Answer, qtype)
+		response.Ns = stripDNSSEC(response.Ns, qtype)
+		response.Extra = stripDNSSEC(response.Extra, qtype)
+		if opt == nil {
+			response.Extra = filterOPT(response.Extra)
+		} else if responseOpt := response.IsEdns0(); responseOpt != nil {
+			responseOpt.SetDo(false)
+		}
+	}
+	return response.Pack()
+}
+
+func filterOPT(rrs []dns.RR) []dns.RR {
+	filtered := rrs[:0]
+	for _, rr := range rrs {
+		if rr.Header().Rrtype != dns.TypeOPT {
+			filtered = append(filtered, rr)
+		}
+	}
+	return filtered
+}
+
+// queryRecord sends the request and validates its response.
+func (s *DNSSECServer) queryRecord(ctx context.Context, req *dnsRequest) (*IPRecord, error) {
+	req.msg.CheckingDisabled = true
+	b, err := req.msg.Pack()
+	if err != nil {
+		return nil, err
+	}
+	b, err = s.server.QueryRaw(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	response := new(dns.Msg)
+	if err := response.Unpack(b); err != nil {
+		return nil, newError("failed to parse dns response").Base(err)
+	}
+	if _, err := s.validator.Validate(ctx, req.msg.Question[0], response); err != nil {
+		newError(s.Name(), " DNSSEC bogus answer for ", req.domain).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+		return &IPRecord{
+			RCode:  dns.RcodeServerFailure,
+			Expire: time.Now(),
+		}, nil
+	}
+	return parseResponse(b)
+}
+
+func (s *DNSSECServer) findIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, time.Time, error) {
+	record, found := s.cache.get(domain)
+	if !found {
+		return nil, time.Time{}, errRecordNotFound
+	}
+
+	var ips []net.Address
+	var expireAt time.Time
+	var lastErr error
+	for _, rec := range []struct {
+		enabled bool
+		record  *IPRecord
+	}{{option.IPv4Enable, record.A}, {option.IPv6Enable, record.AAAA}} {
+		if !rec.enabled {
+			continue
+		}
+		addrs, expire, err := rec.record.getIPs()
+		if err == errRecordNotFound {
+			return nil, time.Time{}, err
+		}
+		if err != nil {
+			lastErr = err
+		}
+		expireAt = expire
+		ips = append(ips, addrs...)
+	}
+	if len(ips) > 0 {
+		netIPs, err := toNetIP(ips)
+		return netIPs, expireAt, err
+	}
+	if lastErr != nil {
+		return nil, expireAt, lastErr
+	}
+	return nil, expireAt, dns_feature.ErrEmptyResponse
+}
+
+// updateIP caches the records with TTL.
+func (s *DNSSECServer) updateIP(domain string, newRec record) {
+	if newRec.A != nil && newRec.A.TTL == 0 {
+		newRec.A = nil
+	}
+	if newRec.AAAA != nil && newRec.AAAA.TTL == 0 {
+		newRec.AAAA = nil
+	}
+	s.cache.update(domain, newRec)
+}
+
+// QueryIPWithTTL implements ServerWithTTL.
+func (s *DNSSECServer) QueryIPWithTTL(ctx context.Context, domain string, clientIP net.IP, option dns_feature.IPOption, disableCache bool) ([]net.IP, time.Time, error) {
+	fqdn := Fqdn(domain)
+	if !disableCache {
+		ips, expireAt, err := s.cache.lookupWith(fqdn, option, s.findIPsForDomain)
+		if err != errRecordNotFound {
+			newError(s.Name(), " cache HIT ", domain, " -> ", ips).Base(err).AtDebug().WriteToLog()
+			markCacheHit(ctx)
+			return ips, expireAt, err
+		}
+	}
+
+	opt := new(dns.OPT)
+	opt.Hdr = dns.RR_Header{Name: ".", Rrtype: dns.TypeOPT}
+	opt.SetUDPSize(1232)
+	opt.SetDo(true)
+	if subnet := genEDNS0Subnet(clientIP); subnet != nil {
+		opt.Option = append(opt.Option, subnet)
+	}
+	reqs := buildReqMsgs(fqdn, option, s.NewReqID, opt)
+
+	type result struct {
+		reqType uint16
+		record  *IPRecord
+		err     error
+	}
+	results := make(chan result, len(reqs))
+	for _, req := range reqs {
+		go func(req *dnsRequest) {
+			rec, err := s.queryRecord(ctx, req)
+			results <- result{req.reqType, rec, err}
+		}(req)
+	}
+	var rec record
+	for range reqs {
+		r := <-results
+		if r.err != nil {
+			return nil, time.Time{}, r.err
+		}
+		switch r.reqType {
+		case dns.TypeA:
+			rec.A = r.record
+		case dns.TypeAAAA:
+			rec.AAAA = r.record
+		}
+	}
+
+	s.updateIP(fqdn, rec)
+
+	var ips []net.Address
+	var expireAt time.Time
+	var lastErr error
+	for _, r := range []*IPRecord{rec.A, rec.AAAA} {
+		if r == nil {
+			continue
+		}
+		addrs, expire, err := r.getIPs()
+		if err != nil && err != errRecordNotFound {
+			lastErr = err
+		}
+		expireAt = expire
+		ips = append(ips, addrs...)
+	}
+	if len(ips) > 0 {
+		netIPs, err := toNetIP(ips)
+		return netIPs, expireAt, err
+	}
+	if lastErr != nil {
+		return nil, expireAt, lastErr
+	}
+	return nil, expireAt, dns_feature.ErrEmptyResponse
+}
+
+// QueryIP implements Server.
+func (s *DNSSECServer) QueryIP(ctx context.Context, domain string, clientIP net.IP, option dns_feature.IPOption, disableCache bool) ([]net.IP, error) {
+	ips, _, err := s.QueryIPWithTTL(ctx, domain, clientIP, option, disableCache)
+	return ips, err
+}
@@ -0,0 +1,326 @@
+package dns
+
+import (
+	"context"
+	"crypto"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	dns_feature "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+type testZone struct {
+	name   string
+	key    *dns.DNSKEY
+	signer crypto.Signer
+}
+
+func newTestZone(name string) *testZone {
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: name, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	privateKey, err := key.Generate(256)
+	common.Must(err)
+	return &testZone{name: name, key: key, signer: privateKey.(crypto.Signer)}
+}
+
+func (z *testZone) sign(rrset ...dns.RR) []dns.RR {
+	now := time.Now()
+	sig := &dns.RRSIG{
+		Hdr:        dns.RR_Header{Name: rrset[0].Header().Name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 3600},
+		Algorithm:  z.key.Algorithm,
+		Inception:  uint32(now.Add(-time.Hour).Unix()),
+		Expiration: uint32(now.Add(time.Hour).Unix()),
+		KeyTag:     z.key.KeyTag(),
+		SignerName: z.name,
+	}
+	common.Must(sig.Sign(z.signer, rrset))
+	return append(rrset, sig)
+}
+
+func (z *testZone) nsec(name string, types ...uint16) []dns.RR {
+	return z.sign(&dns.NSEC{
+		Hdr:        dns.RR_Header{Name: name, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 3600},
+		NextDomain: "zzz." + z.name,
+		TypeBitMap: typeBitmap(append(types, dns.TypeRRSIG, dns.TypeNSEC)...),
+	})
+}
+
+func typeBitmap(types ...uint16) []uint16 {
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+func mustNewRR(s string) dns.RR {
+	rr, err := dns.NewRR(s)
+	common.Must(err)
+	return rr
+}
+
+// testAuthority answers queries from the signed records and denials.
+type testAuthority struct {
+	answers map[rrsetKey][]dns.RR
+	denials map[string][]dns.RR
+}
+
+func (*testAuthority) Name() string     { return "test" }
+func (*testAuthority) NewReqID() uint16 { return 0 }
+
+func (a *testAuthority) QueryIP(context.Context, string, net.IP, dns_feature.IPOption, bool) ([]net.IP, error) {
+	return nil, nil
+}
+
+func (a *testAuthority) QueryIPWithTTL(context.Context, string, net.IP, dns_feature.IPOption, bool) ([]net.IP, time.Time, error) {
+	return nil, time.Time{}, nil
+}
+
+func (a *testAuthority) QueryRaw(_ context.Context, b []byte) ([]byte, error) {
+	request := new(dns.Msg)
+	common.Must(request.Unpack(b))
+	response := new(dns.Msg)
+	response.SetReply(request)
+	response.SetEdns0(1232, true)
+	question := request.Question[0]
+	if answer, found := a.answers[rrsetKey{dns.CanonicalName(question.Name), question.Qtype}]; found {
+		response.Answer = answer
+	} else if alias, found := a.answers[rrsetKey{dns.CanonicalName(question.Name), dns.TypeCNAME}]; found {
+		response.Answer = alias
+	} else {
+		response.Ns = a.denials[dns.CanonicalName(question.Name)]
+	}
+	return response.Pack()
+}
+
+func newTestAuthority() (*testAuthority, string) {
+	root := newTestZone(".")
+	com := newTestZone("com.")
+	example := newTestZone("example.com.")
+
+	tampered := example.sign(mustNewRR("tampered.example.com. 300 IN A 1.1.1.1"))
+	tampered[0] = mustNewRR("tampered.example.com. 300 IN A 6.6.6.6")
+	alias := example.sign(mustNewRR("alias.example.com. 300 IN CNAME www.example.com."))
+	forged := example.sign(mustNewRR("forged.example.com. 300 IN CNAME www.example.com."))
+
+	authority := &testAuthority{
+		answers: map[rrsetKey][]dns.RR{
+			{".", dns.TypeDNSKEY}:                  root.sign(root.key),
+			{"com.", dns.TypeDS}:                   root.sign(com.key.ToDS(dns.SHA256)),
+			{"com.", dns.TypeDNSKEY}:               com.sign(com.key),
+			{"example.com.", dns.TypeDS}:           com.sign(example.key.ToDS(dns.SHA256)),
+			{"example.com.", dns.TypeDNSKEY}:       example.sign(example.key),
+			{"www.example.com.", dns.TypeA}:        example.sign(mustNewRR("www.example.com. 300 IN A 1.2.3.4")),
+			{"unsigned.example.com.", dns.TypeA}:   {mustNewRR("unsigned.example.com. 300 IN A 9.9.9.9")},
+			{"tampered.example.com.", dns.TypeA}:   tampered,
+			{"host.insecure.com.", dns.TypeA}:      {mustNewRR("host.insecure.com. 300 IN A 5.6.7.8")},
+			{"alias.example.com.", dns.TypeCNAME}:  alias,
+			{"alias.example.com.", dns.TypeA}:      append(alias, example.sign(mustNewRR("www.example.com. 300 IN A 1.2.3.4"))...),
+			{"forged.example.com.", dns.TypeCNAME}: forged,
+			{"forged.example.com.", dns.TypeA}:     append(forged, mustNewRR("www.example.com. 300 IN A 6.6.6.6")),
+		},
+		denials: map[string][]dns.RR{
+			"insecure.com.":         com.nsec("insecure.com.", dns.TypeNS),
+			"www.example.com.":      example.nsec("www.example.com.", dns.TypeA),
+			"unsigned.example.com.": example.nsec("unsigned.example.com.", dns.TypeA),
+			"tampered.example.com.": example.nsec("tampered.example.com.", dns.TypeA),
+			// A forged denial of the type listed by the NSEC record, with the NSEC record of the apex replayed.
+			"replayed.example.com.": append(example.nsec("replayed.example.com.", dns.TypeA), example.nsec("example.com.", dns.TypeSOA, dns.TypeNS, dns.TypeDNSKEY)...),
+		},
+	}
+	return authority, root.key.ToDS(dns.SHA256).String()
+}
+
+func TestDNSSECServerQueryIP(t *testing.T) {
+	authority, anchor := newTestAuthority()
+	server, err := NewDNSSECServer(authority, []string{anchor})
+	common.Must(err)
+
+	testCases := []struct {
+		domain string
+		option dns_feature.IPOption
+		ips    []net.IP
+		err    error
+	}{
+		{"www.example.com", dns_feature.IPOption{IPv4Enable: true}, []net.IP{{1, 2, 3, 4}}, nil},
+		{"www.example.com", dns_feature.IPOption{IPv6Enable: true}, nil, dns_feature.ErrEmptyResponse},
+		{"alias.example.com", dns_feature.IPOption{IPv4Enable: true}, []net.IP{{1, 2, 3, 4}}, nil},
+		{"host.insecure.com", dns_feature.IPOption{IPv4Enable: true}, []net.IP{{5, 6, 7, 8}}, nil},
+		{"unsigned.example.com", dns_feature.IPOption{IPv4Enable: true}, nil, dns_feature.RCodeError(dns.RcodeServerFailure)},
+		{"tampered.example.com", dns_feature.IPOption{IPv4Enable: true}, nil, dns_feature.RCodeError(dns.RcodeServerFailure)},
+		{"forged.example.com", dns_feature.IPOption{IPv4Enable: true}, nil, dns_feature.RCodeError(dns.RcodeServerFailure)},
+		{"replayed.example.com", dns_feature.IPOption{IPv4Enable: true}, nil, dns_feature.RCodeError(dns.RcodeServerFailure)},
+		{"replayed.example.com", dns_feature.IPOption{IPv6Enable: true}, nil, dns_feature.ErrEmptyResponse},
+	}
+	for _, tc := range testCases {
+		ips, err := server.QueryIP(context.Background(), tc.domain, nil, tc.option, true)
+		if err != tc.err {
+			t.Error(tc.domain, ": expect error ", tc.err, " but got ", err)
+			continue
+		}
+		if len(ips) != len(tc.ips) || (len(ips) > 0 && !ips[0].Equal(tc.ips[0])) {
+			t.Error(tc.domain, ": expect ", tc.ips, " but got ", ips)
+		}
+	}
+}
+
+func TestDNSSECServerQueryRaw(t *testing.T) {
+	authority, anchor := newTestAuthority()
+	server, err := NewDNSSECServer(authority, []string{anchor})
+	common.Must(err)
+
+	query := func(name string, dnssecOK bool) *dns.Msg {
+		request := new(dns.Msg)
+		request.SetQuestion(name, dns.TypeA)
+		if dnssecOK {
+			request.SetEdns0(1232, true)
+		}
+		b, err := request.Pack()
+		common.Must(err)
+		b, err = server.QueryRaw(context.Background(), b)
+		common.Must(err)
+		response := new(dns.Msg)
+		common.Must(response.Unpack(b))
+		return response
+	}
+
+	response := query("www.example.com.", true)
+	if !response.AuthenticatedData || len(response.Answer) != 2 {
+		t.Error("expect authenticated answer with signature: ", response)
+	}
+	response = query("www.example.com.", false)
+	if !response.AuthenticatedData || len(response.Answer) != 1 || response.IsEdns0() != nil {
+		t.Error("expect authenticated answer without DNSSEC records: ", response)
+	}
+	response = query("host.insecure.com.", true)
+	if response.AuthenticatedData || len(response.Answer) != 1 {
+		t.Error("expect insecure answer: ", response)
+	}
+	response = query("tampered.example.com.", true)
+	if response.Rcode != dns.RcodeServerFailure {
+		t.Error("expect SERVFAIL: ", response)
+	}
+}
+
+// nsecChain creates the unsigned NSEC records of the zone with the names and their types.
+func nsecChain(names map[string][]uint16) []dns.RR {
+	owners := make([]string, 0, len(names))
+	for name := range names {
+		owners = append(owners, name)
+	}
+	sort.Slice(owners, func(i, j int) bool { return canonicalCompare(owners[i], owners[j]) < 0 })
+	records := make([]dns.RR, 0, len(owners))
+	for i, owner := range owners {
+		records = append(records, &dns.NSEC{
+			Hdr:        dns.RR_Header{Name: owner, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 3600},
+			NextDomain: owners[(i+1)%len(owners)],
+			TypeBitMap: typeBitmap(append(names[owner], dns.TypeRRSIG, dns.TypeNSEC)...),
+		})
+	}
+	return records
+}
+
+// nsec3Chain creates the unsigned NSEC3 records of the zone with the names and their types.
+func nsec3Chain(zone string, names map[string][]uint16, flags uint8) []dns.RR {
+	hashes := make([]string, 0, len(names))
+	types := make(map[string][]uint16)
+	for name, t := range names {
+		hash := dns.HashName(name, dns.SHA1, 0, "")
+		hashes = append(hashes, hash)
+		types[hash] = t
+	}
+	sort.Strings(hashes)
+	records := make([]dns.RR, 0, len(hashes))
+	for i, hash := range hashes {
+		records = append(records, &dns.NSEC3{
+			Hdr:        dns.RR_Header{Name: hash + "." + zone, Rrtype: dns.TypeNSEC3, Class: dns.ClassINET, Ttl: 3600},
+			Hash:       dns.SHA1,
+			Flags:      flags,
+			NextDomain: hashes[(i+1)%len(hashes)],
+			HashLength: 20,
+			TypeBitMap: typeBitmap(append(types[hash], dns.TypeRRSIG)...),
+		})
+	}
+	return records
+}
+
+// selectNSEC returns the records matching or covering any of the names.
+func selectNSEC(records []dns.RR, names ...string) []dns.RR {
+	var selected []dns.RR
+	for _, rr := range records {
+		for _, name := range names {
+			var found bool
+			switch rr := rr.(type) {
+			case *dns.NSEC:
+				found = dns.CanonicalName(rr.Header().Name) == name || nsecCovers(rr, name)
+			case *dns.NSEC3:
+				found = rr.Match(name) || rr.Cover(name)
+			}
+			if found {
+				selected = append(selected, rr)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func TestProveDenial(t *testing.T) {
+	names := map[string][]uint16{
+		"example.com.":        {dns.TypeSOA, dns.TypeNS, dns.TypeDNSKEY},
+		"a.example.com.":      {dns.TypeA},
+		"www.example.com.":    {dns.TypeA},
+		"x.y.example.com.":    {dns.TypeA},
+		"*.wild.example.com.": {dns.TypeTXT},
+		"sub.example.com.":    {dns.TypeNS},
+	}
+	nsecs := nsecChain(names)
+	names["y.example.com."] = nil
+	names["wild.example.com."] = nil
+	nsec3s := nsec3Chain("example.com.", names, 0)
+	optOut := nsec3Chain("example.com.", names, 1)
+
+	testCases := []struct {
+		description string
+		records     []dns.RR
+		name        string
+		rrtype      uint16
+		proof       denialProof
+	}{
+		{"NSEC no data", nsecs, "www.example.com.", dns.TypeAAAA, proofNoData},
+		{"NSEC listing the type", nsecs, "www.example.com.", dns.TypeA, proofNone},
+		{"NSEC listing the type with replayed apex", selectNSEC(nsecs, "www.example.com.", "example.com."), "www.example.com.", dns.TypeA, proofNone},
+		{"replayed apex NSEC", selectNSEC(nsecs, "example.com."), "b.example.com.", dns.TypeAAAA, proofNone},
+		{"NSEC no name", selectNSEC(nsecs, "nx.example.com.", "*.example.com."), "nx.example.com.", dns.TypeA, proofNoName},
+		{"NSEC no name without wildcard denial", selectNSEC(nsecs, "nx.example.com."), "nx.example.com.", dns.TypeA, proofNone},
+		{"NSEC empty non-terminal", selectNSEC(nsecs, "y.example.com."), "y.example.com.", dns.TypeA, proofNoData},
+		{"NSEC wildcard no data", selectNSEC(nsecs, "foo.wild.example.com.", "*.wild.example.com."), "foo.wild.example.com.", dns.TypeAAAA, proofNoData},
+		{"NSEC wildcard listing the type", selectNSEC(nsecs, "foo.wild.example.com.", "*.wild.example.com."), "foo.wild.example.com.", dns.TypeTXT, proofNone},
+		{"NSEC insecure delegation", nsecs, "sub.example.com.", dns.TypeDS, proofInsecureDelegation},
+		{"NSEC of delegation", nsecs, "sub.example.com.", dns.TypeA, proofNone},
+		{"NSEC3 no data", nsec3s, "www.example.com.", dns.TypeAAAA, proofNoData},
+		{"NSEC3 listing the type", nsec3s, "www.example.com.", dns.TypeA, proofNone},
+		{"NSEC3 empty non-terminal", nsec3s, "y.example.com.", dns.TypeA, proofNoData},
+		{"NSEC3 no name", selectNSEC(nsec3s, "example.com.", "nx.example.com.", "*.example.com."), "nx.example.com.", dns.TypeA, proofNoName},
+		{"NSEC3 no name without closest encloser", selectNSEC(nsec3s, "nx.example.com."), "nx.example.com.", dns.TypeA, proofNone},
+		{"NSEC3 no name without next closer", selectNSEC(nsec3s, "example.com.", "*.example.com."), "nx.x.example.com.", dns.TypeA, proofNone},
+		{"NSEC3 wildcard no data", selectNSEC(nsec3s, "wild.example.com.", "foo.wild.example.com.", "*.wild.example.com."), "foo.wild.example.com.", dns.TypeAAAA, proofNoData},
+		{"NSEC3 wildcard listing the type", nsec3s, "foo.wild.example.com.", dns.TypeTXT, proofNone},
+		{"NSEC3 below delegation", nsec3s, "www.sub.example.com.", dns.TypeA, proofNone},
+		{"NSEC3 opt-out", selectNSEC(optOut, "example.com.", "nx.example.com."), "nx.example.com.", dns.TypeDS, proofInsecureDelegation},
+	}
+	for _, tc := range testCases {
+		if len(tc.records) == 0 {
+			t.Fatal(tc.description, ": no records")
+		}
+		if proof := proveDenial(tc.records, "example.com.", tc.name, tc.rrtype); proof != tc.proof {
+			t.Error(tc.description, ": expect proof ", tc.proof, " but got ", proof)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package dns
 
 import (
+	"context"
 	"time"
 
 	fakedns "github.com/v2fly/v2ray-core/v5/app/dns/fakedns"
@@ -44,7 +45,18 @@ func (s *FakeDNSClient) NewReqID() uint16 {
 }
 
 func (s *FakeDNSClient) QueryRaw(request []byte) ([]byte, error) {
-	return s.queryRaw(request, true)
+	return s.queryRaw(context.Background(), request, true)
+}
+
+// LookupIPWithContext implements dns.ContextQuery.
+func (s *FakeDNSClient) LookupIPWithContext(ctx context.Context, domain string, option dns.IPOption) ([]net.IP, time.Time, error) {
+	option.FakeEnable = true
+	return s.lookupIPWithContext(ctx, domain, option)
+}
+
+// QueryRawWithContext implements dns.ContextQuery.
+func (s *FakeDNSClient) QueryRawWithContext(ctx context.Context, request []byte) ([]byte, error) {
+	return s.queryRaw(ctx, request, true)
 }
 
 // FakeDNSEngine is an implementation of dns.FakeDNSEngine based on a fully functional DNS.
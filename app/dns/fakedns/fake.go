@@ -7,13 +7,20 @@ import (
 	gonet "net"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/v2fly/v2ray-core/v5/app/persistentstorage/protostorage"
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/cache"
+	"github.com/v2fly/v2ray-core/v5/common/environment"
+	"github.com/v2fly/v2ray-core/v5/common/environment/envctx"
 	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/task"
 	"github.com/v2fly/v2ray-core/v5/features/dns"
 )
 
+const defaultSnapshotInterval = time.Minute
+
 type Holder struct {
 	domainToIP cache.Lru
 	nextIP     *big.Int
@@ -22,6 +29,11 @@ type Holder struct {
 	ipRange *gonet.IPNet
 
 	config *FakeDnsPool
+
+	ctx          context.Context
+	storage      protostorage.ProtoPersistentStorage
+	snapshotTask *task.Periodic
+	dirty        bool
 }
 
 func (fkdns *Holder) IsIPInIPPool(ip net.Address) bool {
@@ -51,6 +63,10 @@ func (fkdns *Holder) Start() error {
 }
 
 func (fkdns *Holder) Close() error {
+	if fkdns.snapshotTask != nil {
+		fkdns.snapshotTask.Close()
+		fkdns.snapshot()
+	}
 	fkdns.mu.Lock()
 	fkdns.domainToIP = nil
 	fkdns.mu.Unlock()
@@ -72,7 +88,32 @@ func NewFakeDNSHolder() (*Holder, error) {
 }
 
 func NewFakeDNSHolderConfigOnly(conf *FakeDnsPool) (*Holder, error) {
-	return &Holder{nil, nil, nil, nil, conf}, nil
+	return &Holder{config: conf, ctx: context.Background()}, nil
+}
+
+// enablePersistence sets up the persistent storage for the mapping, if it is enabled in config.
+// It must be called when loading config, so that the storage of the app environment can be
+// narrowed before it is ready.
+func (fkdns *Holder) enablePersistence(ctx context.Context) error {
+	if fkdns.config == nil || !fkdns.config.Persistent {
+		return nil
+	}
+	appEnvironment, ok := envctx.EnvironmentFromContext(ctx).(environment.AppEnvironment)
+	if !ok {
+		return newError("persistent storage is not available for fake dns pool ", fkdns.config.IpPool)
+	}
+	poolStorage, err := appEnvironment.PersistentStorage().NarrowScope(ctx, []byte("fakedns"))
+	if err != nil {
+		return newError("failed to get persistent storage for fake dns").Base(err)
+	}
+	fkdns.ctx = ctx
+	fkdns.storage = protostorage.NewProtoStorage(poolStorage, false)
+	return nil
+}
+
+// storageKey returns the key of the snapshot in the persistent storage, which is unique for an IP pool.
+func (fkdns *Holder) storageKey() string {
+	return strings.NewReplacer("/", "_", ":", "-").Replace(fkdns.ipRange.String())
 }
 
 func (fkdns *Holder) initializeFromConfig() error {
@@ -103,9 +144,68 @@ func (fkdns *Holder) initialize(ipPoolCidr string, lruSize int) error {
 	fkdns.ipRange = ipRange
 	fkdns.nextIP = currentIP
 	fkdns.mu = new(sync.Mutex)
+
+	if fkdns.storage != nil {
+		fkdns.restore()
+		interval := defaultSnapshotInterval
+		if fkdns.config.SnapshotInterval > 0 {
+			interval = time.Duration(fkdns.config.SnapshotInterval) * time.Second
+		}
+		fkdns.snapshotTask = &task.Periodic{
+			Interval: interval,
+			Execute: func() error {
+				fkdns.snapshot()
+				return nil
+			},
+		}
+		return fkdns.snapshotTask.Start()
+	}
 	return nil
 }
 
+// restore loads the mapping from the last snapshot in the persistent storage.
+func (fkdns *Holder) restore() {
+	snapshot := &FakeDnsPoolSnapshot{}
+	if err := fkdns.storage.GetProto(fkdns.ctx, fkdns.storageKey(), snapshot); err != nil {
+		newError("failed to load fake dns snapshot of ", fkdns.ipRange).Base(err).AtInfo().WriteToLog()
+		return
+	}
+	for _, mapping := range snapshot.Mappings {
+		ip := net.IPAddress(mapping.Ip)
+		if ip.Family().IsDomain() || !fkdns.ipRange.Contains(ip.IP()) {
+			continue
+		}
+		fkdns.domainToIP.Put(mapping.Domain, ip)
+	}
+	if nextIP := net.IPAddress(snapshot.NextIp); nextIP.Family().IsIP() && fkdns.ipRange.Contains(nextIP.IP()) {
+		fkdns.nextIP = big.NewInt(0).SetBytes(snapshot.NextIp)
+	}
+	newError("restored ", len(snapshot.Mappings), " fake dns mappings of ", fkdns.ipRange).AtInfo().WriteToLog()
+}
+
+// snapshot saves the mapping to the persistent storage if it has been changed.
+func (fkdns *Holder) snapshot() {
+	fkdns.mu.Lock()
+	if !fkdns.dirty || fkdns.domainToIP == nil {
+		fkdns.mu.Unlock()
+		return
+	}
+	snapshot := &FakeDnsPoolSnapshot{NextIp: fkdns.nextIP.Bytes()}
+	fkdns.domainToIP.Range(func(key, value interface{}) bool {
+		snapshot.Mappings = append(snapshot.Mappings, &FakeDnsMapping{
+			Domain: key.(string),
+			Ip:     value.(net.Address).IP(),
+		})
+		return true
+	})
+	fkdns.dirty = false
+	fkdns.mu.Unlock()
+
+	if err := fkdns.storage.PutProto(fkdns.ctx, fkdns.storageKey(), snapshot); err != nil {
+		newError("failed to persist fake dns snapshot of ", fkdns.ipRange).Base(err).WriteToLog()
+	}
+}
+
 // GetFakeIPForDomain checks and generate a fake IP for a domain name
 func (fkdns *Holder) GetFakeIPForDomain(domain string) []net.Address {
 	fkdns.mu.Lock()
@@ -128,6 +228,7 @@ func (fkdns *Holder) GetFakeIPForDomain(domain string) []net.Address {
 		}
 	}
 	fkdns.domainToIP.Put(strings.ToLower(domain), ip)
+	fkdns.dirty = true
 	return []net.Address{ip}
 }
 
@@ -146,6 +247,8 @@ func (fkdns *Holder) GetDomainFromFakeDNS(ip net.Address) string {
 
 type HolderMulti struct {
 	holders []*Holder
+
+	ctx context.Context
 }
 
 func (h *HolderMulti) IsIPInIPPool(ip net.Address) bool {
@@ -217,6 +320,11 @@ func (h *HolderMulti) AddPool(poolConfig *FakeDnsPool) (*Holder, error) {
 	if err != nil {
 		return nil, err
 	}
+	if h.ctx != nil {
+		if err := holder.enablePersistence(h.ctx); err != nil {
+			return nil, err
+		}
+	}
 	if running {
 		if err := holder.Start(); err != nil {
 			return nil, err
@@ -271,7 +379,17 @@ func (h *HolderMulti) createHolderGroups(conf *FakeDnsPoolMulti) error {
 }
 
 func NewFakeDNSHolderMulti(conf *FakeDnsPoolMulti) (*HolderMulti, error) {
-	holderMulti := &HolderMulti{}
+	return newFakeDNSHolderMulti(context.Background(), conf)
+}
+
+// NewHolderMultiWithContext creates an empty HolderMulti, whose pools persist their mappings with the
+// persistent storage of the app environment in ctx.
+func NewHolderMultiWithContext(ctx context.Context) *HolderMulti {
+	return &HolderMulti{ctx: ctx}
+}
+
+func newFakeDNSHolderMulti(ctx context.Context, conf *FakeDnsPoolMulti) (*HolderMulti, error) {
+	holderMulti := &HolderMulti{ctx: ctx}
 	if err := holderMulti.createHolderGroups(conf); err != nil {
 		return nil, err
 	}
@@ -285,13 +403,16 @@ func init() {
 		if f, err = NewFakeDNSHolderConfigOnly(config.(*FakeDnsPool)); err != nil {
 			return nil, err
 		}
+		if err = f.enablePersistence(ctx); err != nil {
+			return nil, err
+		}
 		return f, nil
 	}))
 
 	common.Must(common.RegisterConfig((*FakeDnsPoolMulti)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		var f *HolderMulti
 		var err error
-		if f, err = NewFakeDNSHolderMulti(config.(*FakeDnsPoolMulti)); err != nil {
+		if f, err = newFakeDNSHolderMulti(ctx, config.(*FakeDnsPoolMulti)); err != nil {
 			return nil, err
 		}
 		return f, nil
@@ -17,11 +17,13 @@ const (
 )
 
 type FakeDnsPool struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IpPool        string                 `protobuf:"bytes,1,opt,name=ip_pool,json=ipPool,proto3" json:"ip_pool,omitempty"` //CIDR of IP pool used as fake DNS IP
-	LruSize       int64                  `protobuf:"varint,2,opt,name=lruSize,proto3" json:"lruSize,omitempty"`            //Size of Pool for remembering relationship between domain name and IP address
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	IpPool           string                 `protobuf:"bytes,1,opt,name=ip_pool,json=ipPool,proto3" json:"ip_pool,omitempty"`                                //CIDR of IP pool used as fake DNS IP
+	LruSize          int64                  `protobuf:"varint,2,opt,name=lruSize,proto3" json:"lruSize,omitempty"`                                           //Size of Pool for remembering relationship between domain name and IP address
+	Persistent       bool                   `protobuf:"varint,3,opt,name=persistent,proto3" json:"persistent,omitempty"`                                     //Persist the relationship with the persistent storage so that it survives restarts
+	SnapshotInterval int64                  `protobuf:"varint,4,opt,name=snapshot_interval,json=snapshotInterval,proto3" json:"snapshot_interval,omitempty"` //Seconds between two snapshots of the persisted relationship, 60 if not set
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *FakeDnsPool) Reset() {
@@ -68,6 +70,20 @@ func (x *FakeDnsPool) GetLruSize() int64 {
 	return 0
 }
 
+func (x *FakeDnsPool) GetPersistent() bool {
+	if x != nil {
+		return x.Persistent
+	}
+	return false
+}
+
+func (x *FakeDnsPool) GetSnapshotInterval() int64 {
+	if x != nil {
+		return x.SnapshotInterval
+	}
+	return 0
+}
+
 type FakeDnsPoolMulti struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Pools         []*FakeDnsPool         `protobuf:"bytes,1,rep,name=pools,proto3" json:"pools,omitempty"`
@@ -112,18 +128,132 @@ func (x *FakeDnsPoolMulti) GetPools() []*FakeDnsPool {
 	return nil
 }
 
+type FakeDnsMapping struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Ip            []byte                 `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FakeDnsMapping) Reset() {
+	*x = FakeDnsMapping{}
+	mi := &file_app_dns_fakedns_fakedns_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FakeDnsMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FakeDnsMapping) ProtoMessage() {}
+
+func (x *FakeDnsMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_fakedns_fakedns_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FakeDnsMapping.ProtoReflect.Descriptor instead.
+func (*FakeDnsMapping) Descriptor() ([]byte, []int) {
+	return file_app_dns_fakedns_fakedns_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FakeDnsMapping) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *FakeDnsMapping) GetIp() []byte {
+	if x != nil {
+		return x.Ip
+	}
+	return nil
+}
+
+type FakeDnsPoolSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mappings      []*FakeDnsMapping      `protobuf:"bytes,1,rep,name=mappings,proto3" json:"mappings,omitempty"` //Ordered from the least recently used
+	NextIp        []byte                 `protobuf:"bytes,2,opt,name=next_ip,json=nextIp,proto3" json:"next_ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FakeDnsPoolSnapshot) Reset() {
+	*x = FakeDnsPoolSnapshot{}
+	mi := &file_app_dns_fakedns_fakedns_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FakeDnsPoolSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FakeDnsPoolSnapshot) ProtoMessage() {}
+
+func (x *FakeDnsPoolSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_fakedns_fakedns_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FakeDnsPoolSnapshot.ProtoReflect.Descriptor instead.
+func (*FakeDnsPoolSnapshot) Descriptor() ([]byte, []int) {
+	return file_app_dns_fakedns_fakedns_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FakeDnsPoolSnapshot) GetMappings() []*FakeDnsMapping {
+	if x != nil {
+		return x.Mappings
+	}
+	return nil
+}
+
+func (x *FakeDnsPoolSnapshot) GetNextIp() []byte {
+	if x != nil {
+		return x.NextIp
+	}
+	return nil
+}
+
 var File_app_dns_fakedns_fakedns_proto protoreflect.FileDescriptor
 
 const file_app_dns_fakedns_fakedns_proto_rawDesc = "" +
 	"\n" +
-	"\x1dapp/dns/fakedns/fakedns.proto\x12\x1av2ray.core.app.dns.fakedns\x1a common/protoext/extensions.proto\"X\n" +
+	"\x1dapp/dns/fakedns/fakedns.proto\x12\x1av2ray.core.app.dns.fakedns\x1a common/protoext/extensions.proto\"\xa5\x01\n" +
 	"\vFakeDnsPool\x12\x17\n" +
 	"\aip_pool\x18\x01 \x01(\tR\x06ipPool\x12\x18\n" +
-	"\alruSize\x18\x02 \x01(\x03R\alruSize:\x16\x82\xb5\x18\x12\n" +
+	"\alruSize\x18\x02 \x01(\x03R\alruSize\x12\x1e\n" +
+	"\n" +
+	"persistent\x18\x03 \x01(\bR\n" +
+	"persistent\x12+\n" +
+	"\x11snapshot_interval\x18\x04 \x01(\x03R\x10snapshotInterval:\x16\x82\xb5\x18\x12\n" +
 	"\aservice\x12\afakeDns\"n\n" +
 	"\x10FakeDnsPoolMulti\x12=\n" +
 	"\x05pools\x18\x01 \x03(\v2'.v2ray.core.app.dns.fakedns.FakeDnsPoolR\x05pools:\x1b\x82\xb5\x18\x17\n" +
-	"\aservice\x12\ffakeDnsMultiBo\n" +
+	"\aservice\x12\ffakeDnsMulti\"8\n" +
+	"\x0eFakeDnsMapping\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x0e\n" +
+	"\x02ip\x18\x02 \x01(\fR\x02ip\"v\n" +
+	"\x13FakeDnsPoolSnapshot\x12F\n" +
+	"\bmappings\x18\x01 \x03(\v2*.v2ray.core.app.dns.fakedns.FakeDnsMappingR\bmappings\x12\x17\n" +
+	"\anext_ip\x18\x02 \x01(\fR\x06nextIpBo\n" +
 	"\x1ecom.v2ray.core.app.dns.fakednsP\x01Z.github.com/v2fly/v2ray-core/v5/app/dns/fakedns\xaa\x02\x1aV2Ray.Core.App.Dns.Fakednsb\x06proto3"
 
 var (
@@ -138,18 +268,21 @@ func file_app_dns_fakedns_fakedns_proto_rawDescGZIP() []byte {
 	return file_app_dns_fakedns_fakedns_proto_rawDescData
 }
 
-var file_app_dns_fakedns_fakedns_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_app_dns_fakedns_fakedns_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_app_dns_fakedns_fakedns_proto_goTypes = []any{
-	(*FakeDnsPool)(nil),      // 0: v2ray.core.app.dns.fakedns.FakeDnsPool
-	(*FakeDnsPoolMulti)(nil), // 1: v2ray.core.app.dns.fakedns.FakeDnsPoolMulti
+	(*FakeDnsPool)(nil),         // 0: v2ray.core.app.dns.fakedns.FakeDnsPool
+	(*FakeDnsPoolMulti)(nil),    // 1: v2ray.core.app.dns.fakedns.FakeDnsPoolMulti
+	(*FakeDnsMapping)(nil),      // 2: v2ray.core.app.dns.fakedns.FakeDnsMapping
+	(*FakeDnsPoolSnapshot)(nil), // 3: v2ray.core.app.dns.fakedns.FakeDnsPoolSnapshot
 }
 var file_app_dns_fakedns_fakedns_proto_depIdxs = []int32{
 	0, // 0: v2ray.core.app.dns.fakedns.FakeDnsPoolMulti.pools:type_name -> v2ray.core.app.dns.fakedns.FakeDnsPool
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2, // 1: v2ray.core.app.dns.fakedns.FakeDnsPoolSnapshot.mappings:type_name -> v2ray.core.app.dns.fakedns.FakeDnsMapping
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_app_dns_fakedns_fakedns_proto_init() }
@@ -163,7 +296,7 @@ func file_app_dns_fakedns_fakedns_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_dns_fakedns_fakedns_proto_rawDesc), len(file_app_dns_fakedns_fakedns_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
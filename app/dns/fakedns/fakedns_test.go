@@ -1,12 +1,14 @@
 package fakedns
 
 import (
+	"context"
 	gonet "net"
 	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/net"
@@ -313,3 +315,54 @@ func TestFakeDNSMultiAddPool(t *testing.T) {
 		runTest(false)
 	})
 }
+
+type memoryProtoStorage map[string][]byte
+
+func (m memoryProtoStorage) PutProto(_ context.Context, key string, pb proto.Message) error {
+	data, err := proto.Marshal(pb)
+	if err != nil {
+		return err
+	}
+	m[key] = data
+	return nil
+}
+
+func (m memoryProtoStorage) GetProto(_ context.Context, key string, pb proto.Message) error {
+	data, found := m[key]
+	if !found {
+		return newError("not found")
+	}
+	return proto.Unmarshal(data, pb)
+}
+
+func TestFakeDnsHolderPersistent(t *testing.T) {
+	storage := memoryProtoStorage{}
+	newHolder := func() *Holder {
+		fkdns, err := NewFakeDNSHolderConfigOnly(&FakeDnsPool{
+			IpPool:     "240.0.0.0/12",
+			LruSize:    2,
+			Persistent: true,
+		})
+		common.Must(err)
+		fkdns.storage = storage
+		common.Must(fkdns.Start())
+		return fkdns
+	}
+
+	fkdns := newHolder()
+	fkdns.GetFakeIPForDomain("fakednstest.v2fly.org")
+	fkdns.GetFakeIPForDomain("fakednstest2.v2fly.org")
+	fkdns.GetFakeIPForDomain("fakednstest3.v2fly.org")
+	common.Must(fkdns.Close())
+	if _, found := storage["240.0.0.0_12"]; !found {
+		t.Fatal("snapshot not persisted")
+	}
+
+	fkdns = newHolder()
+	defer fkdns.Close()
+	assert.Equal(t, "", fkdns.GetDomainFromFakeDNS(net.ParseAddress("240.0.0.0")))
+	assert.Equal(t, "fakednstest2.v2fly.org", fkdns.GetDomainFromFakeDNS(net.ParseAddress("240.0.0.1")))
+	assert.Equal(t, "fakednstest3.v2fly.org", fkdns.GetDomainFromFakeDNS(net.ParseAddress("240.0.0.2")))
+	assert.Equal(t, "240.0.0.2", fkdns.GetFakeIPForDomain("fakednstest3.v2fly.org")[0].IP().String())
+	assert.Equal(t, "240.0.0.3", fkdns.GetFakeIPForDomain("fakednstest4.v2fly.org")[0].IP().String())
+}
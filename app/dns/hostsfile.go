@@ -0,0 +1,218 @@
+package dns
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/app/subscription"
+	"github.com/v2fly/v2ray-core/v5/app/subscription/documentfetcher"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/platform/filesystem"
+	"github.com/v2fly/v2ray-core/v5/common/task"
+	"github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+// hostsFiles is the host mappings loaded from hosts files and domain lists, which are reloaded on schedule.
+type hostsFiles struct {
+	sync.Mutex
+	ctx      context.Context
+	files    []*HostsFile
+	mappings [][]*HostMapping
+	hosts    atomic.Pointer[StaticHosts]
+	reloads  []*task.Periodic
+}
+
+// newHostsFiles creates hostsFiles and loads the local files. Remote files are loaded on Start.
+func newHostsFiles(ctx context.Context, files []*HostsFile) (*hostsFiles, error) {
+	h := &hostsFiles{
+		ctx:      ctx,
+		files:    files,
+		mappings: make([][]*HostMapping, len(files)),
+	}
+	for idx, file := range files {
+		if isRemoteHostsFile(file) {
+			continue
+		}
+		if err := h.load(idx); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+func isRemoteHostsFile(file *HostsFile) bool {
+	source := strings.ToLower(file.Source)
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// Start implements common.Runnable.
+func (h *hostsFiles) Start() error {
+	for idx, file := range h.files {
+		idx, file := idx, file
+		load := func() error {
+			if err := h.load(idx); err != nil {
+				newError("failed to load hosts file ", file.Source).Base(err).AtWarning().WriteToLog()
+			}
+			return nil
+		}
+		switch {
+		case file.ReloadInterval > 0:
+			// Local files are already loaded by newHostsFiles, so their first reload is skipped.
+			loaded := !isRemoteHostsFile(file)
+			reload := &task.Periodic{
+				Interval: time.Duration(file.ReloadInterval) * time.Second,
+				Execute: func() error {
+					if loaded {
+						loaded = false
+						return nil
+					}
+					return load()
+				},
+			}
+			h.reloads = append(h.reloads, reload)
+			go reload.Start()
+		case isRemoteHostsFile(file):
+			go load()
+		}
+	}
+	return nil
+}
+
+// Close implements common.Closable.
+func (h *hostsFiles) Close() error {
+	for _, reload := range h.reloads {
+		common.Close(reload)
+	}
+	return nil
+}
+
+func (h *hostsFiles) fetch(file *HostsFile) ([]byte, error) {
+	if !isRemoteHostsFile(file) {
+		return filesystem.ReadFile(file.Source)
+	}
+	fetcher, err := documentfetcher.GetFetcher("http")
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.DownloadDocument(h.ctx, &subscription.ImportSource{
+		Url:            file.Source,
+		ImportUsingTag: file.ImportUsingTag,
+	})
+}
+
+// load reads the file at idx, and rebuilds the host mappings of all files.
+func (h *hostsFiles) load(idx int) error {
+	file := h.files[idx]
+	content, err := h.fetch(file)
+	if err != nil {
+		return newError("failed to read hosts file ", file.Source).Base(err)
+	}
+	var mappings []*HostMapping
+	switch file.Format {
+	case HostsFile_Adblock:
+		mappings, err = parseAdblockList(content)
+	default:
+		mappings, err = parseHostsFile(content)
+	}
+	if err != nil {
+		return newError("failed to parse hosts file ", file.Source).Base(err)
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	h.mappings[idx] = mappings
+	var all []*HostMapping
+	for _, m := range h.mappings {
+		all = append(all, m...)
+	}
+	hosts, err := NewStaticHosts(all, nil)
+	if err != nil {
+		return err
+	}
+	h.hosts.Store(hosts)
+	newError("loaded ", len(mappings), " host mappings from ", file.Source).AtInfo().WriteToLog()
+	return nil
+}
+
+// Lookup returns IP addresses for the given domain, if exists in the loaded files.
+func (h *hostsFiles) Lookup(domain string, option dns.IPOption) []net.Address {
+	hosts := h.hosts.Load()
+	if hosts == nil {
+		return nil
+	}
+	return hosts.Lookup(domain, option)
+}
+
+// parseHostsFile parses the lines of IP address followed by domains, as in /etc/hosts. Invalid lines are ignored.
+func parseHostsFile(content []byte) ([]*HostMapping, error) {
+	var mappings []*HostMapping
+	domainIdx := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		ip := net.ParseIP(fields[0])
+		if ip == nil {
+			continue
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		for _, domain := range fields[1:] {
+			domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+			if idx, found := domainIdx[domain]; found {
+				mappings[idx].Ip = append(mappings[idx].Ip, ip)
+				continue
+			}
+			domainIdx[domain] = len(mappings)
+			mappings = append(mappings, &HostMapping{
+				Type:   DomainMatchingType_Full,
+				Domain: domain,
+				Ip:     [][]byte{ip},
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+// parseAdblockList parses the rules of "||domain^" into mappings of the domains and their subdomains to
+// unspecified addresses. Other rules are ignored.
+func parseAdblockList(content []byte) ([]*HostMapping, error) {
+	var mappings []*HostMapping
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "||") || !strings.HasSuffix(line, "^") {
+			continue
+		}
+		domain := strings.ToLower(line[2 : len(line)-1])
+		if domain == "" || strings.ContainsAny(domain, "/*|^$") {
+			continue
+		}
+		mappings = append(mappings, &HostMapping{
+			Type:   DomainMatchingType_Subdomain,
+			Domain: domain,
+			Ip:     [][]byte{net.AnyIP.IP(), net.AnyIPv6.IP()},
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
@@ -0,0 +1,102 @@
+package dns
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+func TestParseHostsFile(t *testing.T) {
+	mappings, err := parseHostsFile([]byte(`# comment
+127.0.0.1 localhost
+::1       localhost ip6-localhost # trailing comment
+10.0.0.1  Example.COM. www.example.com
+invalid   example.org
+10.0.0.2
+`))
+	common.Must(err)
+	expected := []*HostMapping{
+		{Type: DomainMatchingType_Full, Domain: "localhost", Ip: [][]byte{{127, 0, 0, 1}, net.ParseIP("::1")}},
+		{Type: DomainMatchingType_Full, Domain: "ip6-localhost", Ip: [][]byte{net.ParseIP("::1")}},
+		{Type: DomainMatchingType_Full, Domain: "example.com", Ip: [][]byte{{10, 0, 0, 1}}},
+		{Type: DomainMatchingType_Full, Domain: "www.example.com", Ip: [][]byte{{10, 0, 0, 1}}},
+	}
+	if r := cmp.Diff(mappings, expected, cmp.Comparer(func(a, b *HostMapping) bool {
+		return a.Type == b.Type && a.Domain == b.Domain && cmp.Equal(a.Ip, b.Ip)
+	})); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestParseAdblockList(t *testing.T) {
+	mappings, err := parseAdblockList([]byte(`! Title: test list
+||ads.example.com^
+||Tracker.Example.ORG^
+@@||allowed.example.com^
+||example.net^$third-party
+example.info##.banner
+`))
+	common.Must(err)
+	var domains []string
+	for _, mapping := range mappings {
+		if mapping.Type != DomainMatchingType_Subdomain {
+			t.Error("unexpected matching type: ", mapping.Type)
+		}
+		domains = append(domains, mapping.Domain)
+	}
+	if r := cmp.Diff(domains, []string{"ads.example.com", "tracker.example.org"}); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestHostsFilesReload(t *testing.T) {
+	dir := t.TempDir()
+	hostsPath := filepath.Join(dir, "hosts")
+	listPath := filepath.Join(dir, "list.txt")
+	common.Must(os.WriteFile(hostsPath, []byte("10.0.0.1 example.com\n"), 0o600))
+	common.Must(os.WriteFile(listPath, []byte("||ads.example.com^\n"), 0o600))
+
+	files, err := newHostsFiles(context.Background(), []*HostsFile{
+		{Source: hostsPath},
+		{Source: listPath, Format: HostsFile_Adblock},
+	})
+	common.Must(err)
+	option := feature_dns.IPOption{IPv4Enable: true, IPv6Enable: true}
+
+	if r := cmp.Diff(files.Lookup("example.com", option), []net.Address{net.ParseAddress("10.0.0.1")}); r != "" {
+		t.Error(r)
+	}
+	if r := cmp.Diff(files.Lookup("cdn.ads.example.com", option), []net.Address{net.AnyIP, net.AnyIPv6}); r != "" {
+		t.Error(r)
+	}
+
+	common.Must(os.WriteFile(hostsPath, []byte("10.0.0.2 example.com\n"), 0o600))
+	common.Must(files.load(0))
+	if r := cmp.Diff(files.Lookup("example.com", option), []net.Address{net.ParseAddress("10.0.0.2")}); r != "" {
+		t.Error(r)
+	}
+	if addrs := files.Lookup("ads.example.com", option); len(addrs) != 2 {
+		t.Error("expect mappings of other files kept, but got ", addrs)
+	}
+
+	// A line over the limit of the scanner fails the reload, and the previous mappings are kept.
+	common.Must(os.WriteFile(hostsPath, []byte("10.0.0.3 example.com\n10.0.0.3 "+strings.Repeat("a", 70000)+"\n"), 0o600))
+	if err := files.load(0); err == nil {
+		t.Error("expect error for too long line")
+	}
+	if r := cmp.Diff(files.Lookup("example.com", option), []net.Address{net.ParseAddress("10.0.0.2")}); r != "" {
+		t.Error(r)
+	}
+
+	if _, err := newHostsFiles(context.Background(), []*HostsFile{{Source: filepath.Join(dir, "missing")}}); err == nil {
+		t.Error("expect error for missing hosts file")
+	}
+}
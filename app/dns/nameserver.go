@@ -43,13 +43,16 @@ type ServerRaw interface {
 
 // Client is the interface for DNS client.
 type Client struct {
-	server   Server
-	clientIP net.IP
-	tag      string
+	server       Server
+	clientIP     net.IP
+	clientSubnet *clientSubnetSelector
+	tag          string
 
 	queryStrategy    feature_dns.IPOption
 	cacheStrategy    CacheStrategy
 	fallbackStrategy FallbackStrategy
+	recordCache      *recordCache
+	cache            *recordCacheScope
 
 	domains   []string
 	expectIPs []*router.GeoIPMatcher
@@ -119,7 +122,20 @@ func NewClient(ctx context.Context, ns *NameServer, dns *Config) (*Client, error
 
 	// Create DNS server instance
 	err := NewServer(ctx, ns.Address.AsDestination(), func(server Server) error {
-		client.server = server
+		// The server may be created later than the client, when its dependent features are registered.
+		if ns.Dnssec == DnssecMode_DnssecValidate {
+			serverRaw, ok := server.(ServerRaw)
+			if !ok {
+				return newError("DNSSEC validation is not supported by ", server.Name())
+			}
+			validator, err := NewDNSSECServer(serverRaw, ns.DnssecTrustAnchor)
+			if err != nil {
+				return newError("failed to create DNSSEC validating server").Base(err)
+			}
+			server = validator
+			newError("DNS: client ", ns.Address.Address.AsAddress(), " validates DNSSEC").AtInfo().WriteToLog()
+		}
+		client.setServer(server)
 		return nil
 	})
 	if err != nil {
@@ -136,6 +152,9 @@ func NewClient(ctx context.Context, ns *NameServer, dns *Config) (*Client, error
 	if len(ns.ClientIp) == 0 {
 		ns.ClientIp = dns.ClientIp
 	}
+	if ns.ClientSubnet == nil {
+		ns.ClientSubnet = dns.ClientSubnet
+	}
 	if ns.QueryStrategy == nil {
 		ns.QueryStrategy = &dns.QueryStrategy
 	}
@@ -197,6 +216,13 @@ func NewClient(ctx context.Context, ns *NameServer, dns *Config) (*Client, error
 		newError("DNS: client ", ns.Address.Address.AsAddress(), " uses clientIP ", net.IP(ns.ClientIp).String()).AtInfo().WriteToLog()
 	}
 
+	if ns.ClientSubnet != nil {
+		client.clientSubnet, err = newClientSubnetSelector(ns.ClientSubnet)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	client.clientIP = ns.ClientIp
 	client.tag = ns.Tag
 	client.queryStrategy = toIPOption(*ns.QueryStrategy)
@@ -205,6 +231,31 @@ func NewClient(ctx context.Context, ns *NameServer, dns *Config) (*Client, error
 	return client, nil
 }
 
+// setRecordCache makes the name server cache its records in the shared cache.
+// The name server may be created later than the cache, when its dependent features are registered.
+func (c *Client) setRecordCache(cache *recordCache) {
+	c.recordCache = cache
+	if c.server != nil {
+		c.useRecordCache()
+	}
+}
+
+// setServer sets the name server the client manages.
+func (c *Client) setServer(server Server) {
+	c.server = server
+	if c.recordCache != nil {
+		c.useRecordCache()
+	}
+}
+
+func (c *Client) useRecordCache() {
+	c.cache = c.recordCache.newScope(c.Name())
+	c.cache.policy = newCachePolicy(c.cacheStrategy, c.refresh)
+	if server, ok := c.server.(recordCacheUser); ok {
+		server.setRecordCache(c.cache)
+	}
+}
+
 // Name returns the server name the client manages.
 func (c *Client) Name() string {
 	return c.server.Name()
@@ -229,27 +280,90 @@ func (c *Client) QueryIPWithTTL(ctx context.Context, domain string, option featu
 	}
 	disableCache := c.cacheStrategy == CacheStrategy_CacheDisabled
 
+	if !queryOption.FakeEnable && c.clientSubnet != nil {
+		if _, ok := server.(ServerRaw); ok {
+			if subnet := c.clientSubnet.subnet(ctx, domain); subnet != nil {
+				return c.queryIPWithSubnet(ctx, domain, subnet, queryOption, disableCache)
+			}
+		}
+	}
+
+	return c.queryServer(ctx, server, domain, queryOption, disableCache)
+}
+
+// refresh queries the domain bypassing the cache, so that the records of the domain in the cache are updated.
+func (c *Client) refresh(subnet *net.IPNet, domain string, option feature_dns.IPOption) {
+	ctx := contextWithQueryInfo(context.Background(), nil)
+	var err error
+	if subnet != nil {
+		_, _, err = c.queryIPWithSubnet(ctx, domain, subnet, option, true)
+	} else {
+		_, _, err = c.queryServer(ctx, c.server, domain, option, true)
+	}
+	if err != nil {
+		newError(c.server.Name(), " failed to refresh ", domain).Base(err).AtDebug().WriteToLog()
+	}
+}
+
+func (c *Client) queryServer(ctx context.Context, server Server, domain string, option feature_dns.IPOption, disableCache bool) ([]net.IP, time.Time, error) {
 	ctx = session.ContextWithInbound(ctx, &session.Inbound{Tag: c.tag})
 	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
 	var ips []net.IP
 	var expireAt time.Time
 	var err error
 	if serverWithTTL, ok := server.(ServerWithTTL); ok {
-		ips, expireAt, err = serverWithTTL.QueryIPWithTTL(ctx, domain, c.clientIP, queryOption, disableCache)
+		ips, expireAt, err = serverWithTTL.QueryIPWithTTL(ctx, domain, c.clientIP, option, disableCache)
 	} else {
-		ips, err = server.QueryIP(ctx, domain, c.clientIP, queryOption, disableCache)
+		ips, err = server.QueryIP(ctx, domain, c.clientIP, option, disableCache)
 		expireAt = time.Now().Add(time.Duration(600) * time.Second)
 	}
 	cancel()
 
-	if err != nil || queryOption.FakeEnable {
+	if err != nil || option.FakeEnable {
 		return ips, expireAt, err
 	}
 	ips, err = c.MatchExpectedIPs(domain, ips)
 	return ips, expireAt, err
 }
 
+// QueryRaw sends the raw DNS request to the name server, with the client subnet chosen for the client the request is
+// made on behalf of, if any.
 func (c *Client) QueryRaw(ctx context.Context, request []byte, fakeEnabled bool) ([]byte, error) {
+	if c.clientSubnet == nil || (c.fakeDNS != nil && fakeEnabled) {
+		return c.queryRaw(ctx, request, fakeEnabled)
+	}
+	msg := new(dns.Msg)
+	if err := msg.Unpack(request); err != nil || len(msg.Question) != 1 {
+		return c.queryRaw(ctx, request, fakeEnabled)
+	}
+	subnet := c.clientSubnet.subnet(ctx, msg.Question[0].Name)
+	if subnet == nil {
+		return c.queryRaw(ctx, request, fakeEnabled)
+	}
+	addedOPT := setEDNS0Subnet(msg, subnet)
+	request, err := msg.Pack()
+	if err != nil {
+		return nil, newError("failed to pack dns query").Base(err)
+	}
+	response, err := c.queryRaw(ctx, request, fakeEnabled)
+	if err != nil || !addedOPT {
+		return response, err
+	}
+	// The OPT record is not expected by the client, which sent none.
+	if err := msg.Unpack(response); err != nil {
+		return nil, newError("failed to parse dns response").Base(err)
+	}
+	extra := msg.Extra[:0]
+	for _, rr := range msg.Extra {
+		if rr.Header().Rrtype != dns.TypeOPT {
+			extra = append(extra, rr)
+		}
+	}
+	msg.Extra = extra
+	return msg.Pack()
+}
+
+func (c *Client) queryRaw(ctx context.Context, request []byte, fakeEnabled bool) ([]byte, error) {
 	server := c.server
 	if c.fakeDNS != nil && fakeEnabled {
 		server = c.fakeDNS
@@ -7,18 +7,15 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"sync"
 	"time"
 
 	"github.com/miekg/dns"
 
-	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/net"
 	"github.com/v2fly/v2ray-core/v5/common/net/cnc"
 	protocol_dns "github.com/v2fly/v2ray-core/v5/common/protocol/dns"
 	"github.com/v2fly/v2ray-core/v5/common/session"
 	"github.com/v2fly/v2ray-core/v5/common/signal/pubsub"
-	"github.com/v2fly/v2ray-core/v5/common/task"
 	dns_feature "github.com/v2fly/v2ray-core/v5/features/dns"
 	"github.com/v2fly/v2ray-core/v5/features/routing"
 	"github.com/v2fly/v2ray-core/v5/transport/internet"
@@ -28,10 +25,8 @@ import (
 // which is compatible with traditional dns over udp(RFC1035),
 // thus most of the DOH implementation is copied from udpns.go
 type DoHNameServer struct {
-	sync.RWMutex
-	ips        map[string]record
+	cache      *recordCacheScope
 	pub        *pubsub.Service
-	cleanup    *task.Periodic
 	httpClient *http.Client
 	dohURL     string
 	name       string
@@ -103,16 +98,12 @@ func NewDoHLocalNameServer(url *url.URL) *DoHNameServer {
 
 func baseDOHNameServer(url *url.URL, prefix, protocol string) *DoHNameServer {
 	s := &DoHNameServer{
-		ips:      make(map[string]record),
 		pub:      pubsub.NewService(),
 		name:     prefix + "//" + url.Host,
 		dohURL:   url.String(),
 		protocol: protocol,
 	}
-	s.cleanup = &task.Periodic{
-		Interval: time.Minute,
-		Execute:  s.Cleanup,
-	}
+	s.cache = newRecordCache(0, 0, 0).newScope(s.name)
 	return s
 }
 
@@ -121,52 +112,13 @@ func (s *DoHNameServer) Name() string {
 	return s.name
 }
 
-// Cleanup clears expired items from cache
-func (s *DoHNameServer) Cleanup() error {
-	now := time.Now()
-	s.Lock()
-	defer s.Unlock()
-
-	if len(s.ips) == 0 {
-		return newError("nothing to do. stopping...")
-	}
-
-	for domain, record := range s.ips {
-		if record.A != nil && record.A.Expire.Before(now) {
-			record.A = nil
-		}
-		if record.AAAA != nil && record.AAAA.Expire.Before(now) {
-			record.AAAA = nil
-		}
-
-		if record.A == nil && record.AAAA == nil {
-			newError(s.name, " cleanup ", domain).AtDebug().WriteToLog()
-			delete(s.ips, domain)
-		} else {
-			s.ips[domain] = record
-		}
-	}
-
-	if len(s.ips) == 0 {
-		s.ips = make(map[string]record)
-	}
-
-	return nil
-}
-
 func (s *DoHNameServer) updateIP(req *dnsRequest, ipRec *IPRecord) {
 	elapsed := time.Since(req.start)
 
-	s.Lock()
-	rec := s.ips[req.domain]
-	updated := false
-
+	var rec record
 	switch req.reqType {
 	case dns.TypeA:
-		if isNewer(rec.A, ipRec) {
-			rec.A = ipRec
-			updated = true
-		}
+		rec.A = ipRec
 	case dns.TypeAAAA:
 		addr := make([]net.Address, 0)
 		for _, ip := range ipRec.IP {
@@ -175,24 +127,22 @@ func (s *DoHNameServer) updateIP(req *dnsRequest, ipRec *IPRecord) {
 			}
 		}
 		ipRec.IP = addr
-		if isNewer(rec.AAAA, ipRec) {
-			rec.AAAA = ipRec
-			updated = true
-		}
+		rec.AAAA = ipRec
 	}
 	newError(s.name, " got answer: ", req.domain, " Type", dns.Type(req.reqType), " -> ", ipRec.IP, " ", elapsed).AtInfo().WriteToLog()
 
-	if updated {
-		s.ips[req.domain] = rec
-	}
+	s.cache.update(req.domain, rec)
 	switch req.reqType {
 	case dns.TypeA:
 		s.pub.Publish(req.domain+"4", nil)
 	case dns.TypeAAAA:
 		s.pub.Publish(req.domain+"6", nil)
 	}
-	s.Unlock()
-	common.Must(s.cleanup.Start())
+}
+
+// setRecordCache implements recordCacheUser.
+func (s *DoHNameServer) setRecordCache(scope *recordCacheScope) {
+	s.cache = scope
 }
 
 func (s *DoHNameServer) NewReqID() uint16 {
@@ -309,61 +259,6 @@ func (s *DoHNameServer) dohHTTPSContext(ctx context.Context, b []byte) ([]byte,
 	return io.ReadAll(resp.Body)
 }
 
-func (s *DoHNameServer) findIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, time.Time, error) {
-	s.RLock()
-	record, found := s.ips[domain]
-	s.RUnlock()
-
-	if !found {
-		return nil, time.Time{}, errRecordNotFound
-	}
-
-	var ips, a, aaaa []net.Address
-	var expireAt time.Time
-	var err, lastErr error
-	updated := false
-	if option.IPv4Enable {
-		a, expireAt, err = record.A.getIPs()
-		if record.A != nil && record.A.TTL == 0 {
-			record.A = nil
-			updated = true
-		}
-		if err != nil {
-			lastErr = err
-		}
-		ips = append(ips, a...)
-	}
-
-	if option.IPv6Enable {
-		aaaa, expireAt, err = record.AAAA.getIPs()
-		if record.AAAA != nil && record.AAAA.TTL == 0 {
-			record.AAAA = nil
-			updated = true
-		}
-		if err != nil {
-			lastErr = err
-		}
-		ips = append(ips, aaaa...)
-	}
-
-	if updated {
-		s.Lock()
-		s.ips[domain] = record
-		s.Unlock()
-	}
-
-	if len(ips) > 0 {
-		ips, err := toNetIP(ips)
-		return ips, expireAt, err
-	}
-
-	if lastErr != nil {
-		return nil, expireAt, lastErr
-	}
-
-	return nil, expireAt, dns_feature.ErrEmptyResponse
-}
-
 // QueryIPWithTTL implements ServerWithTTL.
 func (s *DoHNameServer) QueryIPWithTTL(ctx context.Context, domain string, clientIP net.IP, option dns_feature.IPOption, disableCache bool) ([]net.IP, time.Time, error) { // nolint: dupl
 	fqdn := Fqdn(domain)
@@ -371,9 +266,10 @@ func (s *DoHNameServer) QueryIPWithTTL(ctx context.Context, domain string, clien
 	if disableCache {
 		newError("DNS cache is disabled. Querying IP for ", domain, " at ", s.name).AtDebug().WriteToLog()
 	} else {
-		ips, expireAt, err := s.findIPsForDomain(fqdn, option)
+		ips, expireAt, err := s.cache.lookup(fqdn, option)
 		if err != errRecordNotFound {
 			newError(s.name, " cache HIT ", domain, " -> ", ips).Base(err).AtDebug().WriteToLog()
+			markCacheHit(ctx)
 			return ips, expireAt, err
 		}
 	}
@@ -407,7 +303,7 @@ func (s *DoHNameServer) QueryIPWithTTL(ctx context.Context, domain string, clien
 	s.sendQuery(ctx, fqdn, clientIP, option)
 
 	for {
-		ips, expireAt, err := s.findIPsForDomain(fqdn, option)
+		ips, expireAt, err := s.cache.findIPs(fqdn, option)
 		if err != errRecordNotFound {
 			return ips, expireAt, err
 		}
@@ -5,20 +5,17 @@ import (
 	"encoding/binary"
 	"io"
 	"net/url"
-	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/miekg/dns"
 
-	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/buf"
 	"github.com/v2fly/v2ray-core/v5/common/net"
 	"github.com/v2fly/v2ray-core/v5/common/net/cnc"
 	protocol_dns "github.com/v2fly/v2ray-core/v5/common/protocol/dns"
 	"github.com/v2fly/v2ray-core/v5/common/session"
 	"github.com/v2fly/v2ray-core/v5/common/signal/pubsub"
-	"github.com/v2fly/v2ray-core/v5/common/task"
 	dns_feature "github.com/v2fly/v2ray-core/v5/features/dns"
 	"github.com/v2fly/v2ray-core/v5/features/routing"
 	"github.com/v2fly/v2ray-core/v5/transport/internet"
@@ -26,12 +23,10 @@ import (
 
 // TCPNameServer implemented DNS over TCP (RFC7766).
 type TCPNameServer struct {
-	sync.RWMutex
 	name        string
 	destination net.Destination
-	ips         map[string]record
+	cache       *recordCacheScope
 	pub         *pubsub.Service
-	cleanup     *task.Periodic
 	reqID       uint32
 	dial        func(context.Context) (net.Conn, error)
 	protocol    string
@@ -85,15 +80,11 @@ func baseTCPNameServer(url *url.URL, prefix string, port net.Port, protocol stri
 
 	s := &TCPNameServer{
 		destination: dest,
-		ips:         make(map[string]record),
 		pub:         pubsub.NewService(),
 		name:        prefix + "//" + dest.NetAddr(),
 		protocol:    protocol,
 	}
-	s.cleanup = &task.Periodic{
-		Interval: time.Minute,
-		Execute:  s.Cleanup,
-	}
+	s.cache = newRecordCache(0, 0, 0).newScope(s.name)
 
 	return s, nil
 }
@@ -103,52 +94,13 @@ func (s *TCPNameServer) Name() string {
 	return s.name
 }
 
-// Cleanup clears expired items from cache
-func (s *TCPNameServer) Cleanup() error {
-	now := time.Now()
-	s.Lock()
-	defer s.Unlock()
-
-	if len(s.ips) == 0 {
-		return newError("nothing to do. stopping...")
-	}
-
-	for domain, record := range s.ips {
-		if record.A != nil && record.A.Expire.Before(now) {
-			record.A = nil
-		}
-		if record.AAAA != nil && record.AAAA.Expire.Before(now) {
-			record.AAAA = nil
-		}
-
-		if record.A == nil && record.AAAA == nil {
-			newError(s.name, " cleanup ", domain).AtDebug().WriteToLog()
-			delete(s.ips, domain)
-		} else {
-			s.ips[domain] = record
-		}
-	}
-
-	if len(s.ips) == 0 {
-		s.ips = make(map[string]record)
-	}
-
-	return nil
-}
-
 func (s *TCPNameServer) updateIP(req *dnsRequest, ipRec *IPRecord) {
 	elapsed := time.Since(req.start)
 
-	s.Lock()
-	rec := s.ips[req.domain]
-	updated := false
-
+	var rec record
 	switch req.reqType {
 	case dns.TypeA:
-		if isNewer(rec.A, ipRec) {
-			rec.A = ipRec
-			updated = true
-		}
+		rec.A = ipRec
 	case dns.TypeAAAA:
 		addr := make([]net.Address, 0)
 		for _, ip := range ipRec.IP {
@@ -157,24 +109,22 @@ func (s *TCPNameServer) updateIP(req *dnsRequest, ipRec *IPRecord) {
 			}
 		}
 		ipRec.IP = addr
-		if isNewer(rec.AAAA, ipRec) {
-			rec.AAAA = ipRec
-			updated = true
-		}
+		rec.AAAA = ipRec
 	}
 	newError(s.name, " got answer: ", req.domain, " Type", dns.Type(req.reqType), " -> ", ipRec.IP, " ", elapsed).AtInfo().WriteToLog()
 
-	if updated {
-		s.ips[req.domain] = rec
-	}
+	s.cache.update(req.domain, rec)
 	switch req.reqType {
 	case dns.TypeA:
 		s.pub.Publish(req.domain+"4", nil)
 	case dns.TypeAAAA:
 		s.pub.Publish(req.domain+"6", nil)
 	}
-	s.Unlock()
-	common.Must(s.cleanup.Start())
+}
+
+// setRecordCache implements recordCacheUser.
+func (s *TCPNameServer) setRecordCache(scope *recordCacheScope) {
+	s.cache = scope
 }
 
 func (s *TCPNameServer) NewReqID() uint16 {
@@ -313,61 +263,6 @@ func (s *TCPNameServer) QueryRaw(ctx context.Context, request []byte) ([]byte, e
 	return response, nil
 }
 
-func (s *TCPNameServer) findIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, time.Time, error) {
-	s.RLock()
-	record, found := s.ips[domain]
-	s.RUnlock()
-
-	if !found {
-		return nil, time.Time{}, errRecordNotFound
-	}
-
-	var ips, a, aaaa []net.Address
-	var expireAt time.Time
-	var err, lastErr error
-	updated := false
-	if option.IPv4Enable {
-		a, expireAt, err = record.A.getIPs()
-		if record.A != nil && record.A.TTL == 0 {
-			record.A = nil
-			updated = true
-		}
-		if err != nil {
-			lastErr = err
-		}
-		ips = append(ips, a...)
-	}
-
-	if option.IPv6Enable {
-		aaaa, expireAt, err = record.AAAA.getIPs()
-		if record.AAAA != nil && record.AAAA.TTL == 0 {
-			record.AAAA = nil
-			updated = true
-		}
-		if err != nil {
-			lastErr = err
-		}
-		ips = append(ips, aaaa...)
-	}
-
-	if updated {
-		s.Lock()
-		s.ips[domain] = record
-		s.Unlock()
-	}
-
-	if len(ips) > 0 {
-		ips, err := toNetIP(ips)
-		return ips, expireAt, err
-	}
-
-	if lastErr != nil {
-		return nil, expireAt, lastErr
-	}
-
-	return nil, expireAt, dns_feature.ErrEmptyResponse
-}
-
 // QueryIPWithTTL implements ServerWithTTL.
 func (s *TCPNameServer) QueryIPWithTTL(ctx context.Context, domain string, clientIP net.IP, option dns_feature.IPOption, disableCache bool) ([]net.IP, time.Time, error) {
 	fqdn := Fqdn(domain)
@@ -375,9 +270,10 @@ func (s *TCPNameServer) QueryIPWithTTL(ctx context.Context, domain string, clien
 	if disableCache {
 		newError("DNS cache is disabled. Querying IP for ", domain, " at ", s.name).AtDebug().WriteToLog()
 	} else {
-		ips, expireAt, err := s.findIPsForDomain(fqdn, option)
+		ips, expireAt, err := s.cache.lookup(fqdn, option)
 		if err != errRecordNotFound {
 			newError(s.name, " cache HIT ", domain, " -> ", ips).Base(err).AtDebug().WriteToLog()
+			markCacheHit(ctx)
 			return ips, expireAt, err
 		}
 	}
@@ -411,7 +307,7 @@ func (s *TCPNameServer) QueryIPWithTTL(ctx context.Context, domain string, clien
 	s.sendQuery(ctx, fqdn, clientIP, option)
 
 	for {
-		ips, expireAt, err := s.findIPsForDomain(fqdn, option)
+		ips, expireAt, err := s.cache.findIPs(fqdn, option)
 		if err != errRecordNotFound {
 			return ips, expireAt, err
 		}
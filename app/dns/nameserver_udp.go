@@ -14,7 +14,6 @@ import (
 
 	core "github.com/v2fly/v2ray-core/v5"
 	"github.com/v2fly/v2ray-core/v5/app/dispatcher"
-	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/buf"
 	"github.com/v2fly/v2ray-core/v5/common/net"
 	protocol_dns "github.com/v2fly/v2ray-core/v5/common/protocol/dns"
@@ -32,7 +31,7 @@ type ClassicNameServer struct {
 	sync.RWMutex
 	name      string
 	address   net.Destination
-	ips       map[string]record
+	cache     *recordCacheScope
 	requests  map[uint16]dnsRequest
 	pub       *pubsub.Service
 	udpServer udp.DispatcherI
@@ -101,13 +100,13 @@ func NewClassicNameServer(address net.Destination, dispatcher routing.Dispatcher
 func newClassicNameServer(address net.Destination, name string, dispatcher routing.Dispatcher) *ClassicNameServer {
 	s := &ClassicNameServer{
 		address:  address,
-		ips:      make(map[string]record),
 		requests: make(map[uint16]dnsRequest),
 		pub:      pubsub.NewService(),
 		name:     name,
 
 		channel: make(map[uint16]chan []byte),
 	}
+	s.cache = newRecordCache(0, 0, 0).newScope(name)
 	s.cleanup = &task.Periodic{
 		Interval: time.Minute,
 		Execute:  s.Cleanup,
@@ -121,35 +120,16 @@ func (s *ClassicNameServer) Name() string {
 	return s.name
 }
 
-// Cleanup clears expired items from cache
+// Cleanup clears expired pending requests
 func (s *ClassicNameServer) Cleanup() error {
 	now := time.Now()
 	s.Lock()
 	defer s.Unlock()
 
-	if len(s.ips) == 0 && len(s.requests) == 0 {
+	if len(s.requests) == 0 {
 		return newError(s.name, " nothing to do. stopping...")
 	}
 
-	for domain, record := range s.ips {
-		if record.A != nil && record.A.Expire.Before(now) {
-			record.A = nil
-		}
-		if record.AAAA != nil && record.AAAA.Expire.Before(now) {
-			record.AAAA = nil
-		}
-
-		if record.A == nil && record.AAAA == nil {
-			delete(s.ips, domain)
-		} else {
-			s.ips[domain] = record
-		}
-	}
-
-	if len(s.ips) == 0 {
-		s.ips = make(map[string]record)
-	}
-
 	for id, req := range s.requests {
 		if req.expire.Before(now) {
 			delete(s.requests, id)
@@ -235,32 +215,19 @@ func (s *ClassicNameServer) HandleResponse(ctx context.Context, packet *udp_prot
 }
 
 func (s *ClassicNameServer) updateIP(domain string, newRec record) {
-	s.Lock()
-
 	newError(s.name, " updating IP records for domain:", domain).AtDebug().WriteToLog()
-	rec := s.ips[domain]
-
-	updated := false
-	if isNewer(rec.A, newRec.A) {
-		rec.A = newRec.A
-		updated = true
-	}
-	if isNewer(rec.AAAA, newRec.AAAA) {
-		rec.AAAA = newRec.AAAA
-		updated = true
-	}
-
-	if updated {
-		s.ips[domain] = rec
-	}
+	s.cache.update(domain, newRec)
 	if newRec.A != nil {
 		s.pub.Publish(domain+"4", nil)
 	}
 	if newRec.AAAA != nil {
 		s.pub.Publish(domain+"6", nil)
 	}
-	s.Unlock()
-	common.Must(s.cleanup.Start())
+}
+
+// setRecordCache implements recordCacheUser.
+func (s *ClassicNameServer) setRecordCache(scope *recordCacheScope) {
+	s.cache = scope
 }
 
 func (s *ClassicNameServer) NewReqID() uint16 {
@@ -269,11 +236,12 @@ func (s *ClassicNameServer) NewReqID() uint16 {
 
 func (s *ClassicNameServer) addPendingRequest(req *dnsRequest) {
 	s.Lock()
-	defer s.Unlock()
-
 	id := req.msg.Id
 	req.expire = time.Now().Add(time.Second * 8)
 	s.requests[id] = *req
+	s.Unlock()
+	// The request may have been answered already, so that there is nothing to clean up.
+	s.cleanup.Start()
 }
 
 func (s *ClassicNameServer) sendQuery(ctx context.Context, domain string, clientIP net.IP, option dns_feature.IPOption) {
@@ -358,70 +326,16 @@ func (s *ClassicNameServer) QueryRaw(ctx context.Context, request []byte) ([]byt
 	}
 }
 
-func (s *ClassicNameServer) findIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, time.Time, error) {
-	s.RLock()
-	record, found := s.ips[domain]
-	s.RUnlock()
-
-	if !found {
-		return nil, time.Time{}, errRecordNotFound
-	}
-
-	var ips, a, aaaa []net.Address
-	var expireAt time.Time
-	var err, lastErr error
-	updated := false
-	if option.IPv4Enable {
-		a, expireAt, err = record.A.getIPs()
-		if record.A != nil && record.A.TTL == 0 {
-			record.A = nil
-			updated = true
-		}
-		if err != nil {
-			lastErr = err
-		}
-		ips = append(ips, a...)
-	}
-
-	if option.IPv6Enable {
-		aaaa, expireAt, err = record.AAAA.getIPs()
-		if record.AAAA != nil && record.AAAA.TTL == 0 {
-			record.AAAA = nil
-			updated = true
-		}
-		if err != nil {
-			lastErr = err
-		}
-		ips = append(ips, aaaa...)
-	}
-
-	if updated {
-		s.Lock()
-		s.ips[domain] = record
-		s.Unlock()
-	}
-
-	if len(ips) > 0 {
-		ips, err := toNetIP(ips)
-		return ips, expireAt, err
-	}
-
-	if lastErr != nil {
-		return nil, expireAt, lastErr
-	}
-
-	return nil, expireAt, dns_feature.ErrEmptyResponse
-}
-
 // QueryIPWithTTL implements ServerWithTTL.
 func (s *ClassicNameServer) QueryIPWithTTL(ctx context.Context, domain string, clientIP net.IP, option dns_feature.IPOption, disableCache bool) ([]net.IP, time.Time, error) {
 	fqdn := Fqdn(domain)
 	if disableCache {
 		newError("DNS cache is disabled. Querying IP for ", domain, " at ", s.name).AtDebug().WriteToLog()
 	} else {
-		ips, expireAt, err := s.findIPsForDomain(fqdn, option)
+		ips, expireAt, err := s.cache.lookup(fqdn, option)
 		if err != errRecordNotFound {
 			newError(s.name, " cache HIT ", domain, " -> ", ips).Base(err).AtDebug().WriteToLog()
+			markCacheHit(ctx)
 			return ips, expireAt, err
 		}
 	}
@@ -455,7 +369,7 @@ func (s *ClassicNameServer) QueryIPWithTTL(ctx context.Context, domain string, c
 	s.sendQuery(ctx, fqdn, clientIP, option)
 
 	for {
-		ips, expireAt, err := s.findIPsForDomain(fqdn, option)
+		ips, expireAt, err := s.cache.findIPs(fqdn, option)
 		if err != errRecordNotFound {
 			return ips, expireAt, err
 		}
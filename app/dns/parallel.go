@@ -0,0 +1,100 @@
+package dns
+
+import (
+	"context"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common/errors"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+// queryResult is the answer of a client in a parallel lookup.
+type queryResult struct {
+	idx      int
+	ips      []net.IP
+	expireAt time.Time
+	err      error
+	info     *queryInfo
+}
+
+// parallelLookup queries the clients concurrently in batches of parallelCount, in the order they are sorted.
+// The next batch is queried only if all clients in the batch fail with errors that allow fallback.
+func (s *DNS) parallelLookup(clients []*Client, domain string, option feature_dns.IPOption, info *queryInfo) ([]net.IP, time.Time, error) {
+	batchSize := s.parallelCount
+	if batchSize <= 0 || batchSize > len(clients) {
+		batchSize = len(clients)
+	}
+
+	// The goroutines of the race strategy may outlive the lookup, which updates the info with the answer.
+	inbound := info.inbound
+	errs := []error{}
+	for start := 0; start < len(clients); start += batchSize {
+		batch := clients[start:min(start+batchSize, len(clients))]
+		results := make(chan *queryResult, len(batch))
+		for idx, client := range batch {
+			go func(idx int, client *Client) {
+				info := &queryInfo{server: client.Name(), inbound: inbound}
+				ips, expireAt, err := client.QueryIPWithTTL(contextWithQueryInfo(s.ctx, info), domain, option)
+				results <- &queryResult{idx: idx, ips: ips, expireAt: expireAt, err: err, info: info}
+			}(idx, client)
+		}
+
+		answers := make([]*queryResult, len(batch))
+		for range batch {
+			result := <-results
+			answers[result.idx] = result
+			client := batch[result.idx]
+			if len(result.ips) > 0 {
+				if s.parallel == ParallelStrategy_Race {
+					*info = *result.info
+					newError("domain ", domain, " is answered first by ", client.Name()).AtDebug().WriteToLog()
+					return result.ips, result.expireAt, nil
+				}
+				continue
+			}
+			if result.err != feature_dns.ErrEmptyResponse { // ErrEmptyResponse is not seen as failure, so no failed log
+				newError("failed to lookup ip for domain ", domain, " at server ", client.Name()).Base(result.err).WriteToLog()
+			}
+		}
+
+		if result := preferExpectedIPs(batch, answers); result != nil {
+			*info = *result.info
+			newError("domain ", domain, " is answered by ", batch[result.idx].Name()).AtDebug().WriteToLog()
+			return result.ips, result.expireAt, nil
+		}
+		for _, result := range answers {
+			if result.err != nil {
+				errs = append(errs, result.err)
+			}
+		}
+		for _, result := range answers {
+			if err := result.err; err != context.Canceled && err != context.DeadlineExceeded && err != errExpectedIPNonMatch {
+				return nil, result.expireAt, err // Only continue lookup for certain errors
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil, time.Time{}, feature_dns.ErrEmptyResponse
+	}
+	return nil, time.Time{}, newError("returning nil for domain ", domain).Base(errors.Combine(errs...))
+}
+
+// preferExpectedIPs returns the first answer from the clients with expected IPs, which have already filtered
+// their answers, or otherwise the first valid answer.
+func preferExpectedIPs(clients []*Client, answers []*queryResult) *queryResult {
+	var first *queryResult
+	for _, result := range answers {
+		if len(result.ips) == 0 {
+			continue
+		}
+		if len(clients[result.idx].expectIPs) > 0 {
+			return result
+		}
+		if first == nil {
+			first = result
+		}
+	}
+	return first
+}
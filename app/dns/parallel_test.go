@@ -0,0 +1,126 @@
+package dns
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+// delayedServer answers every query with a fixed IP after a delay.
+type delayedServer struct {
+	name  string
+	delay time.Duration
+	ip    net.IP
+	err   error
+}
+
+func (s *delayedServer) Name() string { return s.name }
+
+func (s *delayedServer) QueryIP(ctx context.Context, domain string, clientIP net.IP, option feature_dns.IPOption, disableCache bool) ([]net.IP, error) {
+	ips, _, err := s.QueryIPWithTTL(ctx, domain, clientIP, option, disableCache)
+	return ips, err
+}
+
+func (s *delayedServer) QueryIPWithTTL(ctx context.Context, _ string, _ net.IP, _ feature_dns.IPOption, _ bool) ([]net.IP, time.Time, error) {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, time.Time{}, ctx.Err()
+	}
+	if s.err != nil {
+		return nil, time.Time{}, s.err
+	}
+	return []net.IP{s.ip}, time.Now().Add(time.Minute), nil
+}
+
+func newParallelTestDNS(strategy ParallelStrategy, count int, servers ...*delayedServer) *DNS {
+	clients := make([]*Client, 0, len(servers))
+	for _, server := range servers {
+		clients = append(clients, &Client{
+			server:        server,
+			queryStrategy: feature_dns.IPOption{IPv4Enable: true, IPv6Enable: true},
+		})
+	}
+	return &DNS{
+		ctx:           context.Background(),
+		clients:       clients,
+		parallel:      strategy,
+		parallelCount: count,
+	}
+}
+
+func TestParallelLookupRace(t *testing.T) {
+	s := newParallelTestDNS(ParallelStrategy_Race, 0,
+		&delayedServer{name: "slow", delay: time.Second, ip: net.IP{1, 1, 1, 1}},
+		&delayedServer{name: "fast", delay: 10 * time.Millisecond, ip: net.IP{2, 2, 2, 2}},
+	)
+	start := time.Now()
+	ips, _, err := s.parallelLookup(s.clients, "example.com", feature_dns.IPOption{IPv4Enable: true}, new(queryInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ips) != 1 || !ips[0].Equal(net.IP{2, 2, 2, 2}) {
+		t.Error("expect answer from the fast server, but got ", ips)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Error("race lookup waits for the slow server: ", elapsed)
+	}
+}
+
+func TestParallelLookupBatches(t *testing.T) {
+	s := newParallelTestDNS(ParallelStrategy_Race, 2,
+		&delayedServer{name: "a", err: context.DeadlineExceeded},
+		&delayedServer{name: "b", err: errExpectedIPNonMatch},
+		&delayedServer{name: "c", ip: net.IP{3, 3, 3, 3}},
+	)
+	ips, _, err := s.parallelLookup(s.clients, "example.com", feature_dns.IPOption{IPv4Enable: true}, new(queryInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ips) != 1 || !ips[0].Equal(net.IP{3, 3, 3, 3}) {
+		t.Error("expect answer from the next batch, but got ", ips)
+	}
+
+	s = newParallelTestDNS(ParallelStrategy_Race, 2,
+		&delayedServer{name: "a", err: feature_dns.RCodeError(3)},
+		&delayedServer{name: "b", err: context.DeadlineExceeded},
+		&delayedServer{name: "c", ip: net.IP{3, 3, 3, 3}},
+	)
+	if _, _, err := s.parallelLookup(s.clients, "example.com", feature_dns.IPOption{IPv4Enable: true}, new(queryInfo)); err != feature_dns.RCodeError(3) {
+		t.Error("expect rcode error without fallback, but got ", err)
+	}
+}
+
+func TestParallelLookupPreferExpectedIPs(t *testing.T) {
+	s := newParallelTestDNS(ParallelStrategy_PreferExpectedIPs, 0,
+		&delayedServer{name: "polluted", delay: 10 * time.Millisecond, ip: net.IP{8, 8, 8, 8}},
+		&delayedServer{name: "trusted", delay: 50 * time.Millisecond, ip: net.IP{10, 0, 0, 1}},
+	)
+	matcher := new(router.GeoIPMatcher)
+	if err := matcher.Init([]*routercommon.CIDR{{Ip: []byte{10, 0, 0, 0}, Prefix: 8}}); err != nil {
+		t.Fatal(err)
+	}
+	s.clients[1].expectIPs = []*router.GeoIPMatcher{matcher}
+
+	ips, _, err := s.parallelLookup(s.clients, "example.com", feature_dns.IPOption{IPv4Enable: true}, new(queryInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ips) != 1 || !ips[0].Equal(net.IP{10, 0, 0, 1}) {
+		t.Error("expect answer matching expected IPs, but got ", ips)
+	}
+
+	s.clients[1].server.(*delayedServer).ip = net.IP{9, 9, 9, 9}
+	ips, _, err = s.parallelLookup(s.clients, "example.com", feature_dns.IPOption{IPv4Enable: true}, new(queryInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ips) != 1 || !ips[0].Equal(net.IP{8, 8, 8, 8}) {
+		t.Error("expect answer from the server without expected IPs, but got ", ips)
+	}
+}
@@ -0,0 +1,130 @@
+package dns
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/v2fly/v2ray-core/v5/common/log"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+// queryInfo describes how a lookup is answered, for the DNS log.
+type queryInfo struct {
+	server string
+	cached bool
+
+	// inbound is the inbound connection the lookup is made on behalf of, if any.
+	inbound *session.Inbound
+}
+
+type queryInfoKey struct{}
+
+func contextWithQueryInfo(ctx context.Context, info *queryInfo) context.Context {
+	return context.WithValue(ctx, queryInfoKey{}, info)
+}
+
+// markCacheHit records that the query in the context is answered from the cache.
+func markCacheHit(ctx context.Context) {
+	if info, ok := ctx.Value(queryInfoKey{}).(*queryInfo); ok && info != nil {
+		info.cached = true
+	}
+}
+
+// queryInboundFromContext returns the inbound connection the query in the context is made on behalf of.
+func queryInboundFromContext(ctx context.Context) *session.Inbound {
+	if info, ok := ctx.Value(queryInfoKey{}).(*queryInfo); ok && info != nil {
+		return info.inbound
+	}
+	return nil
+}
+
+// newDNSLog creates the DNS log of a lookup on behalf of the inbound connection in the context.
+func newDNSLog(ctx context.Context, domain string, qType string, info *queryInfo, start time.Time) *log.DNSMessage {
+	msg := &log.DNSMessage{
+		Domain:  domain,
+		Type:    qType,
+		Server:  info.server,
+		Cached:  info.cached,
+		Latency: time.Since(start),
+	}
+	if inbound := session.InboundFromContext(ctx); inbound != nil {
+		msg.Inbound = inbound.Tag
+		if inbound.Source.IsValid() {
+			msg.Source = inbound.Source
+		}
+	}
+	return msg
+}
+
+func ipQueryType(option feature_dns.IPOption) string {
+	var types []string
+	if option.IPv4Enable {
+		types = append(types, "A")
+	}
+	if option.IPv6Enable {
+		types = append(types, "AAAA")
+	}
+	return strings.Join(types, ",")
+}
+
+// LookupIPWithContext implements dns.ContextQuery.
+func (s *DNS) LookupIPWithContext(ctx context.Context, domain string, option feature_dns.IPOption) ([]net.IP, time.Time, error) {
+	option.FakeEnable = false
+	return s.lookupIPWithContext(ctx, domain, option)
+}
+
+// QueryRawWithContext implements dns.ContextQuery.
+func (s *DNS) QueryRawWithContext(ctx context.Context, request []byte) ([]byte, error) {
+	return s.queryRaw(ctx, request, false)
+}
+
+func (s *DNS) lookupIPWithContext(ctx context.Context, domain string, option feature_dns.IPOption) ([]net.IP, time.Time, error) {
+	start := time.Now()
+	info := &queryInfo{inbound: session.InboundFromContext(ctx)}
+	ips, expireAt, err := s.lookupIP(domain, option, info)
+
+	msg := newDNSLog(ctx, domain, ipQueryType(option), info, start)
+	if len(ips) > 0 {
+		msg.Answer = make([]string, 0, len(ips))
+		for _, ip := range ips {
+			msg.Answer = append(msg.Answer, ip.String())
+		}
+	} else {
+		msg.Error = err
+	}
+	log.Record(msg)
+	return ips, expireAt, err
+}
+
+func (s *DNS) queryRaw(ctx context.Context, request []byte, fakeEnabled bool) ([]byte, error) {
+	start := time.Now()
+	info := &queryInfo{inbound: session.InboundFromContext(ctx)}
+	response, err := s.queryRawInternal(request, fakeEnabled, info)
+
+	requestMsg := new(dns.Msg)
+	if requestMsg.Unpack(request) != nil || len(requestMsg.Question) == 0 {
+		return response, err
+	}
+	question := requestMsg.Question[0]
+	msg := newDNSLog(ctx, strings.TrimSuffix(strings.ToLower(question.Name), "."), dns.Type(question.Qtype).String(), info, start)
+	responseMsg := new(dns.Msg)
+	switch {
+	case err != nil:
+		msg.Error = err
+	case responseMsg.Unpack(response) != nil:
+		msg.Error = newError("failed to parse dns response")
+	case responseMsg.Rcode != dns.RcodeSuccess:
+		msg.Error = feature_dns.RCodeError(responseMsg.Rcode)
+	default:
+		for _, rr := range responseMsg.Answer {
+			msg.Answer = append(msg.Answer, strings.TrimPrefix(rr.String(), rr.Header().String()))
+		}
+	}
+	log.Record(msg)
+	return response, err
+}
@@ -0,0 +1,68 @@
+package dns
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/log"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	"github.com/v2fly/v2ray-core/v5/common/strmatcher"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+type dnsLogRecorder struct {
+	messages chan *log.DNSMessage
+}
+
+func (r *dnsLogRecorder) Handle(msg log.Message) {
+	if msg, ok := msg.(*log.DNSMessage); ok {
+		select {
+		case r.messages <- msg:
+		default:
+		}
+	}
+}
+
+func TestLookupIPWithContextLog(t *testing.T) {
+	recorder := &dnsLogRecorder{messages: make(chan *log.DNSMessage, 4)}
+	log.RegisterHandler(recorder)
+
+	hosts, err := NewStaticHosts(nil, nil)
+	common.Must(err)
+	s := &DNS{
+		ctx:           context.Background(),
+		hosts:         hosts,
+		clients:       []*Client{newCacheTestClient(&countingServer{ttl: time.Minute}, CacheStrategy_CacheServeStale)},
+		domainMatcher: strmatcher.NewLinearIndexMatcher(),
+	}
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		Tag:    "dns-in",
+		Source: net.UDPDestination(net.ParseAddress("192.168.1.2"), 12345),
+	})
+	option := feature_dns.IPOption{IPv4Enable: true}
+
+	for _, cached := range []bool{false, true} {
+		if _, _, err := s.LookupIPWithContext(ctx, "example.com", option); err != nil {
+			t.Fatal(err)
+		}
+		msg := <-recorder.messages
+		expected := &log.DNSMessage{
+			Source:  net.UDPDestination(net.ParseAddress("192.168.1.2"), 12345),
+			Inbound: "dns-in",
+			Domain:  "example.com",
+			Type:    "A",
+			Server:  "counting",
+			Answer:  []string{"1.2.3.4"},
+			Latency: msg.Latency,
+			Cached:  cached,
+		}
+		if r := cmp.Diff(msg, expected); r != "" {
+			t.Error(r)
+		}
+	}
+}
@@ -0,0 +1,495 @@
+package dns
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/task"
+	dns_feature "github.com/v2fly/v2ray-core/v5/features/dns"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
+)
+
+// defaultCacheSize is the number of domains cached if the cache size is not configured.
+const defaultCacheSize = 4096
+
+// cacheCounter is the counter of cache statistics used when stats manager is not available.
+type cacheCounter struct {
+	value int64
+}
+
+func (c *cacheCounter) Value() int64 {
+	return atomic.LoadInt64(&c.value)
+}
+
+func (c *cacheCounter) Set(newValue int64) int64 {
+	return atomic.SwapInt64(&c.value, newValue)
+}
+
+func (c *cacheCounter) Add(delta int64) int64 {
+	return atomic.AddInt64(&c.value, delta)
+}
+
+// cacheKey identifies the records of a domain, answered by the name server of the scope to the client subnet.
+type cacheKey struct {
+	scope  *recordCacheScope
+	subnet string
+	domain string
+}
+
+type cacheElement struct {
+	key    cacheKey
+	record record
+
+	// hits is the number of lookups since the IP records are updated, for prefetching popular records.
+	hits uint32
+	// refreshUntil is when the background refresh of the records times out, before which no other refresh starts.
+	refreshUntil time.Time
+}
+
+// recordCache caches the IP records of name servers, keeping at most capacity domains in LRU order.
+type recordCache struct {
+	sync.Mutex
+	capacity int
+	minTTL   uint32
+	maxTTL   uint32
+	elements map[cacheKey]*list.Element
+	order    *list.List
+	cleanup  *task.Periodic
+
+	hits      stats.Counter
+	misses    stats.Counter
+	evictions stats.Counter
+}
+
+func newRecordCache(capacity int, minTTL, maxTTL uint32) *recordCache {
+	if capacity <= 0 {
+		capacity = defaultCacheSize
+	}
+	c := &recordCache{
+		capacity:  capacity,
+		minTTL:    minTTL,
+		maxTTL:    maxTTL,
+		elements:  make(map[cacheKey]*list.Element),
+		order:     list.New(),
+		hits:      new(cacheCounter),
+		misses:    new(cacheCounter),
+		evictions: new(cacheCounter),
+	}
+	c.cleanup = &task.Periodic{
+		Interval: time.Minute,
+		Execute:  c.Cleanup,
+	}
+	return c
+}
+
+// registerCounters replaces the counters of the cache with the ones registered in the stats manager.
+func (c *recordCache) registerCounters(m stats.Manager) {
+	for name, counter := range map[string]*stats.Counter{
+		"dns>>>cache>>>hits":      &c.hits,
+		"dns>>>cache>>>misses":    &c.misses,
+		"dns>>>cache>>>evictions": &c.evictions,
+	} {
+		if registered, err := stats.GetOrRegisterCounter(m, name); err == nil {
+			registered.Add((*counter).Value())
+			*counter = registered
+		}
+	}
+}
+
+// newScope returns the view of the cache for the name server. Records of different scopes never mix, even if
+// their names are the same.
+func (c *recordCache) newScope(name string) *recordCacheScope {
+	return &recordCacheScope{cache: c, name: name}
+}
+
+// clamp returns the TTL clamped in [minTTL, maxTTL] and the expire time adjusted accordingly. Zero TTL means the
+// record must not be cached, so it is left untouched.
+func (c *recordCache) clamp(ttl uint32, expire time.Time) (uint32, time.Time) {
+	if ttl == 0 {
+		return ttl, expire
+	}
+	clamped := ttl
+	if c.minTTL > 0 && clamped < c.minTTL {
+		clamped = c.minTTL
+	}
+	if c.maxTTL > 0 && clamped > c.maxTTL {
+		clamped = c.maxTTL
+	}
+	return clamped, expire.Add(time.Duration(int64(clamped)-int64(ttl)) * time.Second)
+}
+
+// clampTTL returns a copy of the record with TTL clamped.
+func (c *recordCache) clampTTL(rec *IPRecord) *IPRecord {
+	if rec == nil {
+		return nil
+	}
+	clamped := *rec
+	clamped.TTL, clamped.Expire = c.clamp(rec.TTL, rec.Expire)
+	return &clamped
+}
+
+// clampSVCBTTL returns a copy of the record with TTL clamped.
+func (c *recordCache) clampSVCBTTL(rec *svcbRecord) *svcbRecord {
+	if rec == nil {
+		return nil
+	}
+	clamped := *rec
+	clamped.TTL, clamped.Expire = c.clamp(rec.TTL, rec.Expire)
+	return &clamped
+}
+
+func (c *recordCache) get(key cacheKey) (record, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	element, found := c.elements[key]
+	if !found {
+		return record{}, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*cacheElement).record, true
+}
+
+// set replaces the record of the key, removing it if the record is empty. It must be called with the lock held.
+func (c *recordCache) set(key cacheKey, rec record) {
+	element, found := c.elements[key]
+	if rec.isEmpty() {
+		if found {
+			c.order.Remove(element)
+			delete(c.elements, key)
+		}
+		return
+	}
+	if found {
+		element.Value.(*cacheElement).record = rec
+		c.order.MoveToFront(element)
+		return
+	}
+	c.elements[key] = c.order.PushFront(&cacheElement{key: key, record: rec})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.elements, oldest.Value.(*cacheElement).key)
+		c.evictions.Add(1)
+	}
+}
+
+// update merges the records into the cache if they are newer than the cached ones.
+func (c *recordCache) update(key cacheKey, newRec record) {
+	c.Lock()
+	rec := record{}
+	if element, found := c.elements[key]; found {
+		rec = element.Value.(*cacheElement).record
+	}
+	updated := false
+	if newA := c.clampTTL(newRec.A); isNewer(rec.A, newA) {
+		rec.A = newA
+		updated = true
+	}
+	if newAAAA := c.clampTTL(newRec.AAAA); isNewer(rec.AAAA, newAAAA) {
+		rec.AAAA = newAAAA
+		updated = true
+	}
+	refreshed := updated
+	if newHTTPS := c.clampSVCBTTL(newRec.HTTPS); svcbIsNewer(rec.HTTPS, newHTTPS) {
+		rec.HTTPS = newHTTPS
+		updated = true
+	}
+	if newSVCB := c.clampSVCBTTL(newRec.SVCB); svcbIsNewer(rec.SVCB, newSVCB) {
+		rec.SVCB = newSVCB
+		updated = true
+	}
+	if !updated {
+		c.Unlock()
+		return
+	}
+	c.set(key, rec)
+	if element, found := c.elements[key]; found && refreshed {
+		e := element.Value.(*cacheElement)
+		e.hits = 0
+		e.refreshUntil = time.Time{}
+	}
+	c.Unlock()
+	// The records may have been flushed already, so that there is nothing to clean up.
+	c.cleanup.Start()
+}
+
+// Cleanup removes expired records from the cache, except the ones to be served stale.
+func (c *recordCache) Cleanup() error {
+	now := time.Now()
+	c.Lock()
+	defer c.Unlock()
+
+	if c.order.Len() == 0 {
+		return newError("nothing to do. stopping...")
+	}
+
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		e := element.Value.(*cacheElement)
+		rec := e.record
+		if rec.A != nil && e.key.scope.staleDeadline(rec.A).Before(now) {
+			rec.A = nil
+		}
+		if rec.AAAA != nil && e.key.scope.staleDeadline(rec.AAAA).Before(now) {
+			rec.AAAA = nil
+		}
+		if rec.HTTPS != nil && rec.HTTPS.Expire.Before(now) {
+			rec.HTTPS = nil
+		}
+		if rec.SVCB != nil && rec.SVCB.Expire.Before(now) {
+			rec.SVCB = nil
+		}
+		if rec.isEmpty() {
+			newError(e.key.scope.name, " cleanup ", e.key.domain).AtDebug().WriteToLog()
+			c.order.Remove(element)
+			delete(c.elements, e.key)
+		} else {
+			e.record = rec
+		}
+		element = next
+	}
+	return nil
+}
+
+func matchCacheEntry(key cacheKey, server, domain string) bool {
+	return (server == "" || strings.EqualFold(key.scope.name, server)) &&
+		(domain == "" || strings.EqualFold(key.domain, Fqdn(domain)))
+}
+
+// Flush removes the records of the name server and domain. Empty server or domain matches all.
+func (c *recordCache) Flush(server, domain string) int {
+	c.Lock()
+	defer c.Unlock()
+
+	flushed := 0
+	for key, element := range c.elements {
+		if matchCacheEntry(key, server, domain) {
+			c.order.Remove(element)
+			delete(c.elements, key)
+			flushed++
+		}
+	}
+	return flushed
+}
+
+// Entries returns the records of the name server and domain from the most recently used, including the ones kept
+// to be served stale. Empty server or domain matches all.
+func (c *recordCache) Entries(server, domain string) []*dns_feature.CacheEntry {
+	c.Lock()
+	defer c.Unlock()
+
+	now := time.Now()
+	var entries []*dns_feature.CacheEntry
+	for element := c.order.Front(); element != nil; element = element.Next() {
+		e := element.Value.(*cacheElement)
+		if !matchCacheEntry(e.key, server, domain) {
+			continue
+		}
+		for _, r := range []struct {
+			reqType uint16
+			record  *IPRecord
+		}{{dns.TypeA, e.record.A}, {dns.TypeAAAA, e.record.AAAA}} {
+			if r.record == nil {
+				continue
+			}
+			ips, _ := toNetIP(r.record.IP)
+			entries = append(entries, &dns_feature.CacheEntry{
+				Server: e.key.scope.name,
+				Domain: e.key.domain,
+				Type:   r.reqType,
+				IPs:    ips,
+				RCode:  r.record.RCode,
+				Expire: r.record.Expire,
+				Stale:  r.record.TTL > 0 && r.record.Expire.Before(now),
+				Hits:   e.hits,
+			})
+		}
+		for _, r := range []struct {
+			reqType uint16
+			record  *svcbRecord
+		}{{dns.TypeHTTPS, e.record.HTTPS}, {dns.TypeSVCB, e.record.SVCB}} {
+			if r.record == nil {
+				continue
+			}
+			entries = append(entries, &dns_feature.CacheEntry{
+				Server: e.key.scope.name,
+				Domain: e.key.domain,
+				Type:   r.reqType,
+				RCode:  r.record.RCode,
+				Expire: r.record.Expire,
+			})
+		}
+	}
+	return entries
+}
+
+// Stats returns the statistics of the cache.
+func (c *recordCache) Stats() dns_feature.CacheStats {
+	c.Lock()
+	size := c.order.Len()
+	c.Unlock()
+	return dns_feature.CacheStats{
+		Size:      size,
+		Capacity:  c.capacity,
+		Hits:      c.hits.Value(),
+		Misses:    c.misses.Value(),
+		Evictions: c.evictions.Value(),
+	}
+}
+
+// recordCacheScope is the view of recordCache for a name server.
+type recordCacheScope struct {
+	cache  *recordCache
+	name   string
+	policy *cachePolicy
+	// base is the scope of the name server, if this is the view for a client subnet.
+	base   *recordCacheScope
+	subnet *net.IPNet
+}
+
+// forSubnet returns the view of the cache for the answers to the EDNS client subnet, which belongs to the same
+// name server but never mixes with the answers to other subnets. The records of all subnets share the LRU order
+// and capacity of the cache.
+func (s *recordCacheScope) forSubnet(subnet *net.IPNet) *recordCacheScope {
+	base := s
+	if s.base != nil {
+		base = s.base
+	}
+	return &recordCacheScope{cache: s.cache, name: s.name, policy: s.policy, base: base, subnet: subnet}
+}
+
+// key returns the cache key of the domain in the scope.
+func (s *recordCacheScope) key(domain string) cacheKey {
+	if s.base == nil {
+		return cacheKey{scope: s, domain: domain}
+	}
+	return cacheKey{scope: s.base, subnet: s.subnet.String(), domain: domain}
+}
+
+// update merges the records of the domain into the cache.
+func (s *recordCacheScope) update(domain string, newRec record) {
+	s.cache.update(s.key(domain), newRec)
+}
+
+// get returns the cached records of the domain.
+func (s *recordCacheScope) get(domain string) (record, bool) {
+	return s.cache.get(s.key(domain))
+}
+
+// updateServiceBinding merges the service bindings of the domain into the cache.
+func (s *recordCacheScope) updateServiceBinding(domain string, qtype uint16, newRec *svcbRecord) {
+	switch qtype {
+	case dns.TypeHTTPS:
+		s.update(domain, record{HTTPS: newRec})
+	case dns.TypeSVCB:
+		s.update(domain, record{SVCB: newRec})
+	}
+}
+
+// findServiceBinding returns the cached service bindings of the domain. Records with zero TTL are removed once they
+// are read.
+func (s *recordCacheScope) findServiceBinding(domain string, qtype uint16) ([]*dns_feature.SVCBRecord, time.Time, error) {
+	key := s.key(domain)
+	rec, found := s.cache.get(key)
+	if !found {
+		return nil, time.Time{}, errRecordNotFound
+	}
+	var binding **svcbRecord
+	switch qtype {
+	case dns.TypeHTTPS:
+		binding = &rec.HTTPS
+	case dns.TypeSVCB:
+		binding = &rec.SVCB
+	default:
+		return nil, time.Time{}, errRecordNotFound
+	}
+	records, expireAt, err := (*binding).getRecords()
+	if *binding != nil && (*binding).TTL == 0 {
+		*binding = nil
+		s.cache.Lock()
+		s.cache.set(key, rec)
+		s.cache.Unlock()
+	}
+	return records, expireAt, err
+}
+
+// lookup is findIPs counting the cache hits and misses, and serving the records by the cache policy of the scope.
+func (s *recordCacheScope) lookup(domain string, option dns_feature.IPOption) ([]net.IP, time.Time, error) {
+	return s.lookupWith(domain, option, s.findIPs)
+}
+
+// countLookup counts a cache hit or miss by the error of looking up the cache.
+func (s *recordCacheScope) countLookup(err error) {
+	if err == errRecordNotFound {
+		s.cache.misses.Add(1)
+	} else {
+		s.cache.hits.Add(1)
+	}
+}
+
+// findIPs returns the cached IPs of the domain. Records with zero TTL are removed once they are read.
+func (s *recordCacheScope) findIPs(domain string, option dns_feature.IPOption) ([]net.IP, time.Time, error) {
+	key := s.key(domain)
+	record, found := s.cache.get(key)
+	if !found {
+		return nil, time.Time{}, errRecordNotFound
+	}
+
+	var ips, a, aaaa []net.Address
+	var expireAt time.Time
+	var err, lastErr error
+	updated := false
+	if option.IPv4Enable {
+		a, expireAt, err = record.A.getIPs()
+		if record.A != nil && record.A.TTL == 0 {
+			record.A = nil
+			updated = true
+		}
+		if err != nil {
+			lastErr = err
+		}
+		ips = append(ips, a...)
+	}
+
+	if option.IPv6Enable {
+		aaaa, expireAt, err = record.AAAA.getIPs()
+		if record.AAAA != nil && record.AAAA.TTL == 0 {
+			record.AAAA = nil
+			updated = true
+		}
+		if err != nil {
+			lastErr = err
+		}
+		ips = append(ips, aaaa...)
+	}
+
+	if updated {
+		s.cache.Lock()
+		s.cache.set(key, record)
+		s.cache.Unlock()
+	}
+
+	if len(ips) > 0 {
+		ips, err := toNetIP(ips)
+		return ips, expireAt, err
+	}
+
+	if lastErr != nil {
+		return nil, expireAt, lastErr
+	}
+
+	return nil, expireAt, dns_feature.ErrEmptyResponse
+}
+
+// recordCacheUser is a name server caching its records in recordCache.
+type recordCacheUser interface {
+	// setRecordCache makes the name server cache its records in the scope of the shared cache.
+	setRecordCache(scope *recordCacheScope)
+}
@@ -0,0 +1,110 @@
+package dns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	dns_feature "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+func newTestIPRecord(ip string, ttl uint32) *IPRecord {
+	return &IPRecord{
+		IP:     []net.Address{net.ParseAddress(ip)},
+		Expire: time.Now().Add(time.Duration(ttl) * time.Second),
+		TTL:    ttl,
+	}
+}
+
+func TestRecordCacheEviction(t *testing.T) {
+	cache := newRecordCache(2, 0, 0)
+	scope := cache.newScope("test")
+	option := dns_feature.IPOption{IPv4Enable: true}
+
+	scope.update("a.com.", record{A: newTestIPRecord("1.1.1.1", 300)})
+	scope.update("b.com.", record{A: newTestIPRecord("2.2.2.2", 300)})
+	if _, _, err := scope.lookup("a.com.", option); err != nil {
+		t.Fatal(err)
+	}
+	scope.update("c.com.", record{A: newTestIPRecord("3.3.3.3", 300)})
+
+	if _, _, err := scope.lookup("b.com.", option); err != errRecordNotFound {
+		t.Error("expect least recently used domain to be evicted, but got ", err)
+	}
+	for _, domain := range []string{"a.com.", "c.com."} {
+		if _, _, err := scope.lookup(domain, option); err != nil {
+			t.Error(domain, ": ", err)
+		}
+	}
+
+	stats := cache.Stats()
+	if stats.Size != 2 || stats.Capacity != 2 || stats.Hits != 3 || stats.Misses != 1 || stats.Evictions != 1 {
+		t.Error("unexpected stats: ", stats)
+	}
+}
+
+func TestRecordCacheClampTTL(t *testing.T) {
+	cache := newRecordCache(0, 60, 600)
+	scope := cache.newScope("test")
+	option := dns_feature.IPOption{IPv4Enable: true}
+
+	testCases := []struct {
+		domain string
+		ttl    uint32
+		expect time.Duration
+	}{
+		{"short.com.", 5, 60 * time.Second},
+		{"normal.com.", 300, 300 * time.Second},
+		{"long.com.", 86400, 600 * time.Second},
+	}
+	for _, tc := range testCases {
+		scope.update(tc.domain, record{A: newTestIPRecord("1.1.1.1", tc.ttl)})
+		_, expireAt, err := scope.findIPs(tc.domain, option)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ttl := time.Until(expireAt); ttl > tc.expect || ttl < tc.expect-time.Second {
+			t.Error(tc.domain, ": expect TTL ", tc.expect, " but got ", ttl)
+		}
+	}
+}
+
+func TestRecordCacheScopes(t *testing.T) {
+	cache := newRecordCache(0, 0, 0)
+	scope1 := cache.newScope("UDP//1.1.1.1:53")
+	scope2 := cache.newScope("UDP//1.1.1.1:53")
+	scope3 := cache.newScope("UDP//8.8.8.8:53")
+	option := dns_feature.IPOption{IPv4Enable: true, IPv6Enable: true}
+
+	scope1.update("example.com.", record{A: newTestIPRecord("1.1.1.1", 300)})
+	scope1.update("example.com.", record{AAAA: newTestIPRecord("2001::1", 300)})
+	scope3.update("example.com.", record{A: newTestIPRecord("8.8.8.8", 300)})
+	scope3.update("example.org.", record{A: newTestIPRecord("8.8.4.4", 300)})
+
+	if ips, _, err := scope1.findIPs("example.com.", option); err != nil || len(ips) != 2 {
+		t.Error("expect both records, but got ", ips, err)
+	}
+	if _, _, err := scope2.findIPs("example.com.", option); err != errRecordNotFound {
+		t.Error("expect records not shared between scopes, but got ", err)
+	}
+
+	entries := cache.Entries("udp//1.1.1.1:53", "")
+	if len(entries) != 2 || entries[0].Type != dns.TypeA || entries[1].Type != dns.TypeAAAA {
+		t.Error("unexpected entries: ", entries)
+	}
+	if n := len(cache.Entries("", "example.com")); n != 3 {
+		t.Error("expect 3 entries of example.com, but got ", n)
+	}
+
+	if n := cache.Flush("", "example.com"); n != 2 {
+		t.Error("expect 2 domains flushed, but got ", n)
+	}
+	if n := cache.Flush("UDP//8.8.8.8:53", ""); n != 1 {
+		t.Error("expect 1 domain flushed, but got ", n)
+	}
+	if size := cache.Stats().Size; size != 0 {
+		t.Error("expect empty cache, but got ", size)
+	}
+}
@@ -0,0 +1,198 @@
+package dns
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/v2fly/v2ray-core/v5/common/errors"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	dns_feature "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+// svcbRecord is a cacheable item of service bindings for a domain
+type svcbRecord struct {
+	Records []*dns_feature.SVCBRecord
+	Expire  time.Time
+	RCode   int
+	TTL     uint32
+}
+
+func (r *svcbRecord) getRecords() ([]*dns_feature.SVCBRecord, time.Time, error) {
+	if r == nil || r.TTL > 0 && r.Expire.Before(time.Now()) {
+		return nil, time.Time{}, errRecordNotFound
+	}
+	if r.RCode != dns.RcodeSuccess {
+		return nil, r.Expire, dns_feature.RCodeError(r.RCode)
+	}
+	if len(r.Records) == 0 {
+		return nil, r.Expire, dns_feature.ErrEmptyResponse
+	}
+	return r.Records, r.Expire, nil
+}
+
+func svcbIsNewer(baseRec *svcbRecord, newRec *svcbRecord) bool {
+	if newRec == nil {
+		return false
+	}
+	if baseRec == nil {
+		return true
+	}
+	return baseRec.Expire.Before(newRec.Expire)
+}
+
+func toSVCBRecord(rr *dns.SVCB) *dns_feature.SVCBRecord {
+	rec := &dns_feature.SVCBRecord{
+		Priority: rr.Priority,
+		Target:   rr.Target,
+	}
+	for _, kv := range rr.Value {
+		switch v := kv.(type) {
+		case *dns.SVCBAlpn:
+			rec.ALPN = v.Alpn
+		case *dns.SVCBNoDefaultAlpn:
+			rec.NoDefaultALPN = true
+		case *dns.SVCBPort:
+			rec.Port = v.Port
+		case *dns.SVCBIPv4Hint:
+			rec.IPv4Hint = v.Hint
+		case *dns.SVCBIPv6Hint:
+			rec.IPv6Hint = v.Hint
+		case *dns.SVCBECHConfig:
+			rec.ECHConfig = v.ECH
+		}
+	}
+	return rec
+}
+
+// parseSVCBResponse parses service bindings from the returned payload. Records in AliasMode are not followed.
+func parseSVCBResponse(payload []byte, qtype uint16) (*svcbRecord, error) {
+	message := new(dns.Msg)
+	if err := message.Unpack(payload); err != nil {
+		return nil, newError("failed to parse DNS response").Base(err).AtWarning()
+	}
+
+	now := time.Now()
+	rec := &svcbRecord{
+		RCode:  message.Rcode,
+		Expire: now,
+	}
+	for _, answer := range message.Answer {
+		var rr *dns.SVCB
+		switch answer := answer.(type) {
+		case *dns.HTTPS:
+			rr = &answer.SVCB
+		case *dns.SVCB:
+			rr = answer
+		}
+		if rr == nil || answer.Header().Rrtype != qtype {
+			continue
+		}
+		if rec.TTL == 0 || answer.Header().Ttl < rec.TTL {
+			rec.TTL = answer.Header().Ttl
+			rec.Expire = now.Add(time.Duration(rec.TTL) * time.Second)
+		}
+		if rr.Priority == 0 {
+			continue
+		}
+		rec.Records = append(rec.Records, toSVCBRecord(rr))
+	}
+	sort.SliceStable(rec.Records, func(i, j int) bool {
+		return rec.Records[i].Priority < rec.Records[j].Priority
+	})
+
+	if len(rec.Records) == 0 && (message.Rcode == dns.RcodeSuccess || message.Rcode == dns.RcodeNameError) {
+		for _, ns := range message.Ns {
+			if soa, ok := ns.(*dns.SOA); ok {
+				rec.TTL = min(ns.Header().Ttl, soa.Minttl)
+				rec.Expire = now.Add(time.Duration(rec.TTL) * time.Second)
+			}
+		}
+	}
+	return rec, nil
+}
+
+// QueryServiceBinding sends HTTPS or SVCB query to the name server with the client's IP.
+func (c *Client) QueryServiceBinding(ctx context.Context, domain string, qtype uint16) ([]*dns_feature.SVCBRecord, time.Time, error) {
+	fqdn := Fqdn(domain)
+	disableCache := c.cacheStrategy == CacheStrategy_CacheDisabled || c.cache == nil
+	if !disableCache {
+		records, expireAt, err := c.cache.findServiceBinding(fqdn, qtype)
+		c.cache.countLookup(err)
+		if err != errRecordNotFound {
+			newError(c.Name(), " cache HIT ", domain, " ", dns.Type(qtype)).Base(err).AtDebug().WriteToLog()
+			return records, expireAt, err
+		}
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(fqdn, qtype)
+	if opt := genEDNS0Options(c.clientIP); opt != nil {
+		msg.Extra = append(msg.Extra, opt)
+	}
+	request, err := msg.Pack()
+	if err != nil {
+		return nil, time.Time{}, newError("failed to pack dns query").Base(err)
+	}
+	response, err := c.QueryRaw(ctx, request, false)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	rec, err := parseSVCBResponse(response, qtype)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if c.cache != nil {
+		c.cache.updateServiceBinding(fqdn, qtype, rec)
+	}
+	return rec.getRecords()
+}
+
+// LookupHTTPS implements dns.SVCBLookup.
+func (s *DNS) LookupHTTPS(domain string) ([]*dns_feature.SVCBRecord, time.Time, error) {
+	return s.lookupServiceBinding(domain, dns.TypeHTTPS)
+}
+
+// LookupSVCB implements dns.SVCBLookup.
+func (s *DNS) LookupSVCB(domain string) ([]*dns_feature.SVCBRecord, time.Time, error) {
+	return s.lookupServiceBinding(domain, dns.TypeSVCB)
+}
+
+func (s *DNS) lookupServiceBinding(domain string, qtype uint16) ([]*dns_feature.SVCBRecord, time.Time, error) {
+	if domain == "" {
+		return nil, time.Time{}, newError("empty domain name")
+	}
+	domain = strings.TrimSuffix(domain, ".")
+	if net.ParseAddress(domain).Family().IsIP() {
+		return nil, time.Time{}, newError("not a domain name: ", domain)
+	}
+
+	errs := []error{}
+	for _, client := range s.sortClients(domain, dns_feature.IPOption{}) {
+		if _, ok := client.server.(ServerRaw); !ok {
+			// Service bindings are queried with raw DNS messages, which the name server does not support.
+			continue
+		}
+		records, expireAt, err := client.QueryServiceBinding(s.ctx, domain, qtype)
+		if len(records) > 0 {
+			return records, expireAt, nil
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if err != dns_feature.ErrEmptyResponse {
+			newError("failed to lookup ", dns.Type(qtype), " for domain ", domain, " at server ", client.Name()).Base(err).WriteToLog()
+		}
+		if err != context.Canceled && err != context.DeadlineExceeded {
+			return nil, expireAt, err
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil, time.Time{}, dns_feature.ErrEmptyResponse
+	}
+	return nil, time.Time{}, newError("returning nil for domain ", domain).Base(errors.Combine(errs...))
+}
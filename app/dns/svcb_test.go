@@ -0,0 +1,110 @@
+package dns
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/miekg/dns"
+
+	dns_feature "github.com/v2fly/v2ray-core/v5/features/dns"
+)
+
+func TestParseSVCBResponse(t *testing.T) {
+	msg := new(dns.Msg)
+	msg.SetQuestion("example.com.", dns.TypeHTTPS)
+	msg.Response = true
+	for _, rr := range []string{
+		`example.com. 300 IN HTTPS 0 cdn.example.com.`,
+		`example.com. 600 IN HTTPS 2 . alpn="h2" ipv4hint="192.0.2.2"`,
+		`example.com. 300 IN HTTPS 1 . alpn="h3,h2" no-default-alpn port="8443" ipv4hint="192.0.2.1" ipv6hint="2001:db8::1"`,
+	} {
+		answer, err := dns.NewRR(rr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg.Answer = append(msg.Answer, answer)
+	}
+	payload, err := msg.Pack()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := parseSVCBResponse(payload, dns.TypeHTTPS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.TTL != 300 {
+		t.Error("expect minimal TTL 300, but got ", rec.TTL)
+	}
+	records, _, err := rec.getRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*dns_feature.SVCBRecord{
+		{
+			Priority:      1,
+			Target:        ".",
+			ALPN:          []string{"h3", "h2"},
+			NoDefaultALPN: true,
+			Port:          8443,
+			IPv4Hint:      []net.IP{net.ParseIP("192.0.2.1").To4()},
+			IPv6Hint:      []net.IP{net.ParseIP("2001:db8::1")},
+		},
+		{
+			Priority: 2,
+			Target:   ".",
+			ALPN:     []string{"h2"},
+			IPv4Hint: []net.IP{net.ParseIP("192.0.2.2").To4()},
+		},
+	}
+	if r := cmp.Diff(records, expected); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestParseSVCBResponseNegative(t *testing.T) {
+	msg := new(dns.Msg)
+	msg.SetQuestion("example.com.", dns.TypeHTTPS)
+	msg.Response = true
+	soa, err := dns.NewRR(`example.com. 3600 IN SOA ns.example.com. admin.example.com. 1 7200 3600 86400 60`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg.Ns = append(msg.Ns, soa)
+	payload, err := msg.Pack()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := parseSVCBResponse(payload, dns.TypeHTTPS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.TTL != 60 {
+		t.Error("expect negative TTL 60, but got ", rec.TTL)
+	}
+
+	scope := newRecordCache(0, 0, 0).newScope("test")
+	scope.updateServiceBinding("example.com.", dns.TypeHTTPS, rec)
+	if _, _, err := scope.findServiceBinding("example.com.", dns.TypeHTTPS); err != dns_feature.ErrEmptyResponse {
+		t.Error("expect cached empty response, but got ", err)
+	}
+	if _, _, err := scope.findServiceBinding("example.com.", dns.TypeSVCB); err != errRecordNotFound {
+		t.Error("expect SVCB record not found, but got ", err)
+	}
+}
+
+func TestServiceBindingZeroTTL(t *testing.T) {
+	scope := newRecordCache(0, 0, 0).newScope("test")
+	// The expiration keeps the record from the cleanup, which is not what is tested.
+	rec := &svcbRecord{RCode: dns.RcodeServerFailure, Expire: time.Now().Add(time.Minute)}
+	scope.updateServiceBinding("example.com.", dns.TypeHTTPS, rec)
+	if _, _, err := scope.findServiceBinding("example.com.", dns.TypeHTTPS); err == nil || err == errRecordNotFound {
+		t.Error("expect the zero TTL response to be read once, but got ", err)
+	}
+	if _, _, err := scope.findServiceBinding("example.com.", dns.TypeHTTPS); err != errRecordNotFound {
+		t.Error("expect the zero TTL response to be dropped, but got ", err)
+	}
+}
@@ -4,6 +4,7 @@ package command
 
 import (
 	"context"
+	"time"
 
 	grpc "google.golang.org/grpc"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/v2fly/v2ray-core/v5/app/log"
 	"github.com/v2fly/v2ray-core/v5/common"
 	cmlog "github.com/v2fly/v2ray-core/v5/common/log"
+	"github.com/v2fly/v2ray-core/v5/common/serial"
 )
 
 // LoggerServer is the implemention of LoggerService
@@ -46,9 +48,13 @@ func (s *LoggerServer) FollowLog(_ *FollowLogRequest, stream LoggerService_Follo
 	ctx, cancel := context.WithCancel(stream.Context())
 	defer cancel()
 	f := func(msg cmlog.Message) {
-		err := stream.Send(&FollowLogResponse{
+		response := &FollowLogResponse{
 			Message: msg.String(),
-		})
+		}
+		if msg, ok := msg.(*cmlog.DNSMessage); ok {
+			response.Dns = toDNSLog(msg)
+		}
+		err := stream.Send(response)
 		if err != nil {
 			cancel()
 		}
@@ -59,6 +65,24 @@ func (s *LoggerServer) FollowLog(_ *FollowLogRequest, stream LoggerService_Follo
 	return nil
 }
 
+func toDNSLog(msg *cmlog.DNSMessage) *DNSLog {
+	dnsLog := &DNSLog{
+		Source:              serial.ToString(msg.Source),
+		InboundTag:          msg.Inbound,
+		Domain:              msg.Domain,
+		Type:                msg.Type,
+		Server:              msg.Server,
+		Answer:              msg.Answer,
+		LatencyMicroseconds: msg.Latency.Microseconds(),
+		Cached:              msg.Cached,
+		Time:                time.Now().Unix(),
+	}
+	if msg.Error != nil {
+		dnsLog.Error = msg.Error.Error()
+	}
+	return dnsLog
+}
+
 func (s *LoggerServer) mustEmbedUnimplementedLoggerServiceServer() {}
 
 type service struct {
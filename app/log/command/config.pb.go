@@ -159,16 +159,137 @@ func (*FollowLogRequest) Descriptor() ([]byte, []int) {
 	return file_app_log_command_config_proto_rawDescGZIP(), []int{3}
 }
 
+type DNSLog struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Source and inbound tag of the requesting connection, if any.
+	Source     string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	InboundTag string `protobuf:"bytes,2,opt,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	Domain     string `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	Type       string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	// Upstream server that answered the lookup.
+	Server              string   `protobuf:"bytes,5,opt,name=server,proto3" json:"server,omitempty"`
+	Answer              []string `protobuf:"bytes,6,rep,name=answer,proto3" json:"answer,omitempty"`
+	Error               string   `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	LatencyMicroseconds int64    `protobuf:"varint,8,opt,name=latency_microseconds,json=latencyMicroseconds,proto3" json:"latency_microseconds,omitempty"`
+	Cached              bool     `protobuf:"varint,9,opt,name=cached,proto3" json:"cached,omitempty"`
+	// Unix time in seconds of the lookup.
+	Time          int64 `protobuf:"varint,10,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DNSLog) Reset() {
+	*x = DNSLog{}
+	mi := &file_app_log_command_config_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DNSLog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DNSLog) ProtoMessage() {}
+
+func (x *DNSLog) ProtoReflect() protoreflect.Message {
+	mi := &file_app_log_command_config_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DNSLog.ProtoReflect.Descriptor instead.
+func (*DNSLog) Descriptor() ([]byte, []int) {
+	return file_app_log_command_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DNSLog) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *DNSLog) GetInboundTag() string {
+	if x != nil {
+		return x.InboundTag
+	}
+	return ""
+}
+
+func (x *DNSLog) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *DNSLog) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *DNSLog) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *DNSLog) GetAnswer() []string {
+	if x != nil {
+		return x.Answer
+	}
+	return nil
+}
+
+func (x *DNSLog) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DNSLog) GetLatencyMicroseconds() int64 {
+	if x != nil {
+		return x.LatencyMicroseconds
+	}
+	return 0
+}
+
+func (x *DNSLog) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+func (x *DNSLog) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
 type FollowLogResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// Structured message if the log is a DNS log.
+	Dns           *DNSLog `protobuf:"bytes,2,opt,name=dns,proto3" json:"dns,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *FollowLogResponse) Reset() {
 	*x = FollowLogResponse{}
-	mi := &file_app_log_command_config_proto_msgTypes[4]
+	mi := &file_app_log_command_config_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -180,7 +301,7 @@ func (x *FollowLogResponse) String() string {
 func (*FollowLogResponse) ProtoMessage() {}
 
 func (x *FollowLogResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_log_command_config_proto_msgTypes[4]
+	mi := &file_app_log_command_config_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -193,7 +314,7 @@ func (x *FollowLogResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FollowLogResponse.ProtoReflect.Descriptor instead.
 func (*FollowLogResponse) Descriptor() ([]byte, []int) {
-	return file_app_log_command_config_proto_rawDescGZIP(), []int{4}
+	return file_app_log_command_config_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *FollowLogResponse) GetMessage() string {
@@ -203,6 +324,13 @@ func (x *FollowLogResponse) GetMessage() string {
 	return ""
 }
 
+func (x *FollowLogResponse) GetDns() *DNSLog {
+	if x != nil {
+		return x.Dns
+	}
+	return nil
+}
+
 var File_app_log_command_config_proto protoreflect.FileDescriptor
 
 const file_app_log_command_config_proto_rawDesc = "" +
@@ -211,9 +339,23 @@ const file_app_log_command_config_proto_rawDesc = "" +
 	"\x06Config\"\x16\n" +
 	"\x14RestartLoggerRequest\"\x17\n" +
 	"\x15RestartLoggerResponse\"\x12\n" +
-	"\x10FollowLogRequest\"-\n" +
+	"\x10FollowLogRequest\"\x92\x02\n" +
+	"\x06DNSLog\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x1f\n" +
+	"\vinbound_tag\x18\x02 \x01(\tR\n" +
+	"inboundTag\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x16\n" +
+	"\x06server\x18\x05 \x01(\tR\x06server\x12\x16\n" +
+	"\x06answer\x18\x06 \x03(\tR\x06answer\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x121\n" +
+	"\x14latency_microseconds\x18\b \x01(\x03R\x13latencyMicroseconds\x12\x16\n" +
+	"\x06cached\x18\t \x01(\bR\x06cached\x12\x12\n" +
+	"\x04time\x18\n" +
+	" \x01(\x03R\x04time\"c\n" +
 	"\x11FollowLogResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage2\xf5\x01\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x124\n" +
+	"\x03dns\x18\x02 \x01(\v2\".v2ray.core.app.log.command.DNSLogR\x03dns2\xf5\x01\n" +
 	"\rLoggerService\x12v\n" +
 	"\rRestartLogger\x120.v2ray.core.app.log.command.RestartLoggerRequest\x1a1.v2ray.core.app.log.command.RestartLoggerResponse\"\x00\x12l\n" +
 	"\tFollowLog\x12,.v2ray.core.app.log.command.FollowLogRequest\x1a-.v2ray.core.app.log.command.FollowLogResponse\"\x000\x01Bo\n" +
@@ -231,24 +373,26 @@ func file_app_log_command_config_proto_rawDescGZIP() []byte {
 	return file_app_log_command_config_proto_rawDescData
 }
 
-var file_app_log_command_config_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_app_log_command_config_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_app_log_command_config_proto_goTypes = []any{
 	(*Config)(nil),                // 0: v2ray.core.app.log.command.Config
 	(*RestartLoggerRequest)(nil),  // 1: v2ray.core.app.log.command.RestartLoggerRequest
 	(*RestartLoggerResponse)(nil), // 2: v2ray.core.app.log.command.RestartLoggerResponse
 	(*FollowLogRequest)(nil),      // 3: v2ray.core.app.log.command.FollowLogRequest
-	(*FollowLogResponse)(nil),     // 4: v2ray.core.app.log.command.FollowLogResponse
+	(*DNSLog)(nil),                // 4: v2ray.core.app.log.command.DNSLog
+	(*FollowLogResponse)(nil),     // 5: v2ray.core.app.log.command.FollowLogResponse
 }
 var file_app_log_command_config_proto_depIdxs = []int32{
-	1, // 0: v2ray.core.app.log.command.LoggerService.RestartLogger:input_type -> v2ray.core.app.log.command.RestartLoggerRequest
-	3, // 1: v2ray.core.app.log.command.LoggerService.FollowLog:input_type -> v2ray.core.app.log.command.FollowLogRequest
-	2, // 2: v2ray.core.app.log.command.LoggerService.RestartLogger:output_type -> v2ray.core.app.log.command.RestartLoggerResponse
-	4, // 3: v2ray.core.app.log.command.LoggerService.FollowLog:output_type -> v2ray.core.app.log.command.FollowLogResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	4, // 0: v2ray.core.app.log.command.FollowLogResponse.dns:type_name -> v2ray.core.app.log.command.DNSLog
+	1, // 1: v2ray.core.app.log.command.LoggerService.RestartLogger:input_type -> v2ray.core.app.log.command.RestartLoggerRequest
+	3, // 2: v2ray.core.app.log.command.LoggerService.FollowLog:input_type -> v2ray.core.app.log.command.FollowLogRequest
+	2, // 3: v2ray.core.app.log.command.LoggerService.RestartLogger:output_type -> v2ray.core.app.log.command.RestartLoggerResponse
+	5, // 4: v2ray.core.app.log.command.LoggerService.FollowLog:output_type -> v2ray.core.app.log.command.FollowLogResponse
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_app_log_command_config_proto_init() }
@@ -262,7 +406,7 @@ func file_app_log_command_config_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_log_command_config_proto_rawDesc), len(file_app_log_command_config_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
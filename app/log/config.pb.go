@@ -130,9 +130,11 @@ func (x *LogSpecification) GetPath() string {
 }
 
 type Config struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         *LogSpecification      `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
-	Access        *LogSpecification      `protobuf:"bytes,7,opt,name=access,proto3" json:"access,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Error  *LogSpecification      `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Access *LogSpecification      `protobuf:"bytes,7,opt,name=access,proto3" json:"access,omitempty"`
+	// Log of DNS lookups made through the DNS feature.
+	Dns           *LogSpecification `protobuf:"bytes,8,opt,name=dns,proto3" json:"dns,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -181,6 +183,13 @@ func (x *Config) GetAccess() *LogSpecification {
 	return nil
 }
 
+func (x *Config) GetDns() *LogSpecification {
+	if x != nil {
+		return x.Dns
+	}
+	return nil
+}
+
 var File_app_log_config_proto protoreflect.FileDescriptor
 
 const file_app_log_config_proto_rawDesc = "" +
@@ -189,10 +198,11 @@ const file_app_log_config_proto_rawDesc = "" +
 	"\x10LogSpecification\x12/\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x1b.v2ray.core.app.log.LogTypeR\x04type\x125\n" +
 	"\x05level\x18\x02 \x01(\x0e2\x1f.v2ray.core.common.log.SeverityR\x05level\x12\x12\n" +
-	"\x04path\x18\x03 \x01(\tR\x04path\"\xb4\x01\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\"\xec\x01\n" +
 	"\x06Config\x12:\n" +
 	"\x05error\x18\x06 \x01(\v2$.v2ray.core.app.log.LogSpecificationR\x05error\x12<\n" +
-	"\x06access\x18\a \x01(\v2$.v2ray.core.app.log.LogSpecificationR\x06access:\x12\x82\xb5\x18\x0e\n" +
+	"\x06access\x18\a \x01(\v2$.v2ray.core.app.log.LogSpecificationR\x06access\x126\n" +
+	"\x03dns\x18\b \x01(\v2$.v2ray.core.app.log.LogSpecificationR\x03dns:\x12\x82\xb5\x18\x0e\n" +
 	"\aservice\x12\x03logJ\x04\b\x01\x10\x02J\x04\b\x02\x10\x03J\x04\b\x03\x10\x04J\x04\b\x04\x10\x05J\x04\b\x05\x10\x06*5\n" +
 	"\aLogType\x12\b\n" +
 	"\x04None\x10\x00\x12\v\n" +
@@ -226,11 +236,12 @@ var file_app_log_config_proto_depIdxs = []int32{
 	3, // 1: v2ray.core.app.log.LogSpecification.level:type_name -> v2ray.core.common.log.Severity
 	1, // 2: v2ray.core.app.log.Config.error:type_name -> v2ray.core.app.log.LogSpecification
 	1, // 3: v2ray.core.app.log.Config.access:type_name -> v2ray.core.app.log.LogSpecification
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	1, // 4: v2ray.core.app.log.Config.dns:type_name -> v2ray.core.app.log.LogSpecification
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_app_log_config_proto_init() }
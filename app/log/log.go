@@ -17,6 +17,7 @@ type Instance struct {
 	config       *Config
 	accessLogger log.Handler
 	errorLogger  log.Handler
+	dnsLogger    log.Handler
 	followers    map[reflect.Value]func(msg log.Message)
 	active       bool
 }
@@ -31,6 +32,10 @@ func New(ctx context.Context, config *Config) (*Instance, error) {
 		config.Access = &LogSpecification{Type: LogType_None}
 	}
 
+	if config.Dns == nil {
+		config.Dns = &LogSpecification{Type: LogType_None}
+	}
+
 	g := &Instance{
 		config: config,
 		active: false,
@@ -69,6 +74,17 @@ func (g *Instance) initErrorLogger() error {
 	return nil
 }
 
+func (g *Instance) initDNSLogger() error {
+	handler, err := createHandler(g.config.Dns.Type, HandlerCreatorOptions{
+		Path: g.config.Dns.Path,
+	})
+	if err != nil {
+		return err
+	}
+	g.dnsLogger = handler
+	return nil
+}
+
 // Type implements common.HasType.
 func (*Instance) Type() interface{} {
 	return (*Instance)(nil)
@@ -90,6 +106,9 @@ func (g *Instance) startInternal() error {
 	if err := g.initErrorLogger(); err != nil {
 		return newError("failed to initialize error logger").Base(err).AtWarning()
 	}
+	if err := g.initDNSLogger(); err != nil {
+		return newError("failed to initialize DNS logger").Base(err).AtWarning()
+	}
 
 	return nil
 }
@@ -134,6 +153,10 @@ func (g *Instance) Handle(msg log.Message) {
 		if g.accessLogger != nil {
 			g.accessLogger.Handle(msg)
 		}
+	case *log.DNSMessage:
+		if g.dnsLogger != nil {
+			g.dnsLogger.Handle(msg)
+		}
 	case *log.GeneralMessage:
 		if g.errorLogger != nil && msg.Severity <= g.config.Error.Level {
 			g.errorLogger.Handle(msg)
@@ -162,6 +185,9 @@ func (g *Instance) Close() error {
 	common.Close(g.errorLogger)
 	g.errorLogger = nil
 
+	common.Close(g.dnsLogger)
+	g.dnsLogger = nil
+
 	return nil
 }
 
@@ -0,0 +1,127 @@
+package metrics
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/features/extension"
+	feature_stats "github.com/v2fly/v2ray-core/v5/features/stats"
+	"github.com/v2fly/v2ray-core/v5/transport/internet"
+)
+
+const openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// metricsService serves the statistics in OpenMetrics text format.
+type metricsService struct {
+	access    sync.Mutex
+	ctx       context.Context
+	config    *Config
+	stats     feature_stats.Manager
+	startTime time.Time
+	listener  net.Listener
+}
+
+// Type implements common.HasType.
+func (*metricsService) Type() interface{} {
+	return (*metricsService)(nil)
+}
+
+// Start implements common.Runnable.
+func (s *metricsService) Start() error {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	path := s.config.Path
+	if path == "" {
+		path = "/metrics"
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, s.serveMetrics)
+
+	listenAddr := s.config.ListenAddr
+	if listenAddr == "" {
+		listenAddr = "127.0.0.1"
+	}
+
+	var listener net.Listener
+	var err error
+	address := net.ParseAddress(listenAddr)
+	switch {
+	case address.Family().IsIP():
+		listener, err = internet.ListenSystem(s.ctx, &net.TCPAddr{IP: address.IP(), Port: int(s.config.ListenPort)}, nil)
+	case strings.EqualFold(address.Domain(), "localhost"):
+		listener, err = internet.ListenSystem(s.ctx, &net.TCPAddr{IP: net.IP{127, 0, 0, 1}, Port: int(s.config.ListenPort)}, nil)
+	default:
+		return newError("metrics service cannot listen on the address: ", address)
+	}
+	if err != nil {
+		return newError("metrics service cannot listen on the port ", s.config.ListenPort).Base(err)
+	}
+	s.listener = listener
+
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			newError("metrics service stopped serving").Base(err).AtDebug().WriteToLog()
+		}
+	}()
+	return nil
+}
+
+// Close implements common.Closable.
+func (s *metricsService) Close() error {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	if s.listener != nil {
+		return s.listener.Close()
+	}
+	return nil
+}
+
+func (s *metricsService) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", openMetricsContentType)
+	if err := s.writeMetrics(r.Context(), w); err != nil {
+		newError("failed to write metrics").Base(err).AtDebug().WriteToLog()
+	}
+}
+
+// observatory returns the observatory of the instance, if any. It is looked up on every scrape, as the
+// observatory is optional and may be registered later than this service.
+func (s *metricsService) observatory() extension.Observatory {
+	instance := core.FromContext(s.ctx)
+	if instance == nil {
+		return nil
+	}
+	if observatory, ok := instance.GetFeature(extension.ObservatoryType()).(extension.Observatory); ok {
+		return observatory
+	}
+	return nil
+}
+
+func newMetricsService(ctx context.Context, config *Config) (*metricsService, error) {
+	s := &metricsService{
+		ctx:       ctx,
+		config:    config,
+		startTime: time.Now(),
+	}
+	if err := core.RequireFeatures(ctx, func(stats feature_stats.Manager) {
+		s.stats = stats
+	}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return newMetricsService(ctx, config.(*Config))
+	}))
+}
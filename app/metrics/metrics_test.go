@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+	"github.com/v2fly/v2ray-core/v5/app/stats"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/testing/servers/tcp"
+)
+
+func newTestStats() *stats.Manager {
+	manager, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+	for name, value := range map[string]int64{
+		"inbound>>>socks>>>traffic>>>uplink":         100,
+		"user>>>love@v2fly.org>>>traffic>>>downlink": 200,
+		"rule>>>direct>>>hits":                       3,
+		"uptime":                                     4,
+		"inbound>>>socks>>>connections":              5,
+	} {
+		c, err := manager.RegisterCounter(name)
+		common.Must(err)
+		c.Set(value)
+	}
+	return manager
+}
+
+func TestWriteMetrics(t *testing.T) {
+	s := &metricsService{ctx: context.Background(), stats: newTestStats()}
+	var sb strings.Builder
+	common.Must(s.writeMetrics(context.Background(), &sb))
+	output := sb.String()
+
+	for _, line := range []string{
+		"# TYPE v2ray_traffic_bytes counter",
+		`v2ray_traffic_bytes_total{kind="inbound",tag="socks",direction="uplink"} 100`,
+		`v2ray_traffic_bytes_total{kind="user",tag="love@v2fly.org",direction="downlink"} 200`,
+		"# TYPE v2ray_hits counter",
+		`v2ray_hits_total{kind="rule",tag="direct"} 3`,
+		`v2ray_stats_total{name="uptime"} 4`,
+		"# TYPE v2ray_connections gauge",
+		`v2ray_connections{kind="inbound",tag="socks"} 5`,
+		"# TYPE v2ray_goroutines gauge",
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Error("expect line ", line, " in metrics:\n", output)
+		}
+	}
+	if !strings.HasSuffix(output, "# EOF\n") {
+		t.Error("expect metrics to end with EOF")
+	}
+}
+
+func TestWriteObservation(t *testing.T) {
+	m := newMetricSet()
+	m.addObservation(&observatory.ObservationResult{
+		Status: []*observatory.OutboundStatus{
+			{OutboundTag: "proxy\"1", Alive: true, Delay: 150, LastSeenTime: 1700000000},
+			{OutboundTag: "proxy2", Alive: false, Delay: 99999999},
+		},
+	})
+	var sb strings.Builder
+	common.Must(m.writeTo(&sb))
+	output := sb.String()
+
+	for _, line := range []string{
+		`v2ray_observatory_alive{outbound="proxy\"1"} 1`,
+		`v2ray_observatory_alive{outbound="proxy2"} 0`,
+		`v2ray_observatory_delay_seconds{outbound="proxy\"1"} 0.15`,
+		`v2ray_observatory_last_seen_timestamp_seconds{outbound="proxy\"1"} 1.7e+09`,
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Error("expect line ", line, " in metrics:\n", output)
+		}
+	}
+	if strings.Contains(output, `v2ray_observatory_last_seen_timestamp_seconds{outbound="proxy2"}`) {
+		t.Error("unexpected last seen time of the outbound never seen")
+	}
+}
+
+func TestServeMetrics(t *testing.T) {
+	port := tcp.PickPort()
+	s := &metricsService{
+		ctx:    context.Background(),
+		config: &Config{ListenAddr: "127.0.0.1", ListenPort: int32(port)},
+		stats:  newTestStats(),
+	}
+	common.Must(s.Start())
+	defer s.Close()
+
+	resp, err := http.Get("http://127.0.0.1:" + strconv.Itoa(int(port)) + "/metrics")
+	common.Must(err)
+	defer resp.Body.Close()
+	if contentType := resp.Header.Get("Content-Type"); contentType != openMetricsContentType {
+		t.Error("unexpected content type: ", contentType)
+	}
+	body, err := io.ReadAll(resp.Body)
+	common.Must(err)
+	if !strings.Contains(string(body), `v2ray_hits_total{kind="rule",tag="direct"} 3`) {
+		t.Error("unexpected metrics: ", string(body))
+	}
+}
+
+func TestServeMetricsOnLoopbackByDefault(t *testing.T) {
+	s := &metricsService{
+		ctx:    context.Background(),
+		config: &Config{ListenPort: int32(tcp.PickPort())},
+		stats:  newTestStats(),
+	}
+	common.Must(s.Start())
+	defer s.Close()
+
+	if addr := s.listener.Addr().(*net.TCPAddr); !addr.IP.Equal(net.IP{127, 0, 0, 1}) {
+		t.Error("expect listening on 127.0.0.1, but got ", addr)
+	}
+}
@@ -0,0 +1,210 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+	"github.com/v2fly/v2ray-core/v5/app/stats"
+	feature_stats "github.com/v2fly/v2ray-core/v5/features/stats"
+)
+
+const (
+	metricCounter = "counter"
+	metricGauge   = "gauge"
+)
+
+type label struct {
+	name  string
+	value string
+}
+
+type sample struct {
+	labels []label
+	value  float64
+}
+
+type family struct {
+	name    string
+	typ     string
+	help    string
+	samples []sample
+}
+
+// metricSet collects the samples of metric families, to be written in OpenMetrics text format.
+type metricSet struct {
+	families map[string]*family
+}
+
+func newMetricSet() *metricSet {
+	return &metricSet{families: make(map[string]*family)}
+}
+
+func (m *metricSet) add(name, typ, help string, value float64, labels ...label) {
+	f, found := m.families[name]
+	if !found {
+		f = &family{name: name, typ: typ, help: help}
+		m.families[name] = f
+	}
+	f.samples = append(f.samples, sample{labels: labels, value: value})
+}
+
+// addCounter adds the stats counter, parsing its name of the form kind>>>tag>>>metric or kind>>>tag>>>metric>>>direction
+// into labels. Counters of other names are exported by their full names.
+func (m *metricSet) addCounter(name string, value int64) {
+	parts := strings.Split(name, ">>>")
+	switch len(parts) {
+	case 3:
+		m.add(metricName(parts[2]), metricType(parts[2]), "V2Ray stats counter "+parts[2]+".", float64(value),
+			label{"kind", parts[0]}, label{"tag", parts[1]})
+	case 4:
+		m.add(metricName(parts[2]), metricType(parts[2]), "V2Ray stats counter "+parts[2]+".", float64(value),
+			label{"kind", parts[0]}, label{"tag", parts[1]}, label{"direction", parts[3]})
+	default:
+		m.add("v2ray_stats", metricCounter, "V2Ray stats counter.", float64(value), label{"name", name})
+	}
+}
+
+// metricType returns the type of the metric family for the stats counter, which is a gauge for the counters
+// that go up and down.
+func metricType(metric string) string {
+	switch metric {
+	case "online", "connections", "source_ips":
+		return metricGauge
+	default:
+		return metricCounter
+	}
+}
+
+// metricName returns the name of the metric family for the stats counter.
+func metricName(metric string) string {
+	if metric == "traffic" {
+		return "v2ray_traffic_bytes"
+	}
+	var sb strings.Builder
+	sb.WriteString("v2ray_")
+	for _, r := range metric {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+func (m *metricSet) addSysStats(startTime time.Time) {
+	var rtm runtime.MemStats
+	runtime.ReadMemStats(&rtm)
+
+	m.add("v2ray_uptime_seconds", metricGauge, "Time since V2Ray started.", time.Since(startTime).Seconds())
+	m.add("v2ray_goroutines", metricGauge, "Number of goroutines.", float64(runtime.NumGoroutine()))
+	m.add("v2ray_memory_alloc_bytes", metricGauge, "Bytes of allocated heap objects.", float64(rtm.Alloc))
+	m.add("v2ray_memory_total_alloc_bytes", metricCounter, "Cumulative bytes allocated for heap objects.", float64(rtm.TotalAlloc))
+	m.add("v2ray_memory_sys_bytes", metricGauge, "Bytes of memory obtained from the OS.", float64(rtm.Sys))
+	m.add("v2ray_memory_mallocs", metricCounter, "Cumulative count of heap objects allocated.", float64(rtm.Mallocs))
+	m.add("v2ray_memory_frees", metricCounter, "Cumulative count of heap objects freed.", float64(rtm.Frees))
+	m.add("v2ray_memory_live_objects", metricGauge, "Number of live heap objects.", float64(rtm.Mallocs-rtm.Frees))
+	m.add("v2ray_gc", metricCounter, "Number of completed GC cycles.", float64(rtm.NumGC))
+	m.add("v2ray_gc_pause_seconds", metricCounter, "Cumulative time of GC pauses.", float64(rtm.PauseTotalNs)/1e9)
+}
+
+func (m *metricSet) addObservation(result *observatory.ObservationResult) {
+	for _, status := range result.Status {
+		outbound := label{"outbound", status.OutboundTag}
+		alive := 0.0
+		if status.Alive {
+			alive = 1
+		}
+		m.add("v2ray_observatory_alive", metricGauge, "Whether the outbound is alive.", alive, outbound)
+		m.add("v2ray_observatory_delay_seconds", metricGauge, "Delay of the outbound.", float64(status.Delay)/1000, outbound)
+		if status.LastSeenTime > 0 {
+			m.add("v2ray_observatory_last_seen_timestamp_seconds", metricGauge, "Time the outbound is last seen alive.", float64(status.LastSeenTime), outbound)
+		}
+	}
+}
+
+// writeTo writes the metric families in OpenMetrics text format, sorted by their names and labels.
+func (m *metricSet) writeTo(w io.Writer) error {
+	names := make([]string, 0, len(m.families))
+	for name := range m.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		f := m.families[name]
+		fmt.Fprintf(&sb, "# TYPE %s %s\n", f.name, f.typ)
+		fmt.Fprintf(&sb, "# HELP %s %s\n", f.name, escape(f.help, false))
+		lines := make([]string, 0, len(f.samples))
+		for _, s := range f.samples {
+			var line strings.Builder
+			line.WriteString(f.name)
+			if f.typ == metricCounter {
+				line.WriteString("_total")
+			}
+			if len(s.labels) > 0 {
+				line.WriteByte('{')
+				for i, l := range s.labels {
+					if i > 0 {
+						line.WriteByte(',')
+					}
+					line.WriteString(l.name)
+					line.WriteString(`="`)
+					line.WriteString(escape(l.value, true))
+					line.WriteByte('"')
+				}
+				line.WriteByte('}')
+			}
+			line.WriteByte(' ')
+			line.WriteString(strconv.FormatFloat(s.value, 'g', -1, 64))
+			lines = append(lines, line.String())
+		}
+		sort.Strings(lines)
+		for _, line := range lines {
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+		}
+	}
+	sb.WriteString("# EOF\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func escape(s string, quoted bool) string {
+	r := strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	if quoted {
+		r = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	}
+	return r.Replace(s)
+}
+
+// writeMetrics writes the stats counters, the runtime statistics and the observation of the outbounds.
+func (s *metricsService) writeMetrics(ctx context.Context, w io.Writer) error {
+	m := newMetricSet()
+	if manager, ok := s.stats.(*stats.Manager); ok {
+		manager.VisitCounters(func(name string, c feature_stats.Counter) bool {
+			m.addCounter(name, c.Value())
+			return true
+		})
+	}
+	m.addSysStats(s.startTime)
+	if observer := s.observatory(); observer != nil {
+		if result, err := observer.GetObservation(ctx); err == nil {
+			if result, ok := result.(*observatory.ObservationResult); ok {
+				m.addObservation(result)
+			}
+		} else {
+			newError("failed to get observation").Base(err).AtDebug().WriteToLog()
+		}
+	}
+	return m.writeTo(w)
+}
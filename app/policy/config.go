@@ -73,6 +73,11 @@ func (p *Policy) ToCorePolicy() policy.Session {
 	if p.Stats != nil {
 		cp.Stats.UserUplink = p.Stats.UserUplink
 		cp.Stats.UserDownlink = p.Stats.UserDownlink
+		cp.Stats.UserOnline = p.Stats.UserOnline
+		cp.Stats.UserSourceIPs = p.Stats.UserSourceIps
+		if window := p.Stats.SourceIpWindow.Duration(); window > 0 {
+			cp.Stats.SourceIPWindow = window
+		}
 	}
 	if p.Buffer != nil {
 		cp.Buffer.PerConnection = p.Buffer.Connection
@@ -84,10 +89,12 @@ func (p *Policy) ToCorePolicy() policy.Session {
 func (p *SystemPolicy) ToCorePolicy() policy.System {
 	return policy.System{
 		Stats: policy.SystemStats{
-			InboundUplink:    p.Stats.InboundUplink,
-			InboundDownlink:  p.Stats.InboundDownlink,
-			OutboundUplink:   p.Stats.OutboundUplink,
-			OutboundDownlink: p.Stats.OutboundDownlink,
+			InboundUplink:        p.Stats.InboundUplink,
+			InboundDownlink:      p.Stats.InboundDownlink,
+			OutboundUplink:       p.Stats.OutboundUplink,
+			OutboundDownlink:     p.Stats.OutboundDownlink,
+			InboundConnections:   p.Stats.InboundConnections,
+			OutboundDestinations: p.Stats.OutboundDestinations,
 		},
 		OverrideAccessLogDest: p.OverrideAccessLogDest,
 	}
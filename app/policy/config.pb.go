@@ -294,11 +294,16 @@ func (x *Policy_Timeout) GetDownlinkOnly() *Second {
 }
 
 type Policy_Stats struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserUplink    bool                   `protobuf:"varint,1,opt,name=user_uplink,json=userUplink,proto3" json:"user_uplink,omitempty"`
-	UserDownlink  bool                   `protobuf:"varint,2,opt,name=user_downlink,json=userDownlink,proto3" json:"user_downlink,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	UserUplink   bool                   `protobuf:"varint,1,opt,name=user_uplink,json=userUplink,proto3" json:"user_uplink,omitempty"`
+	UserDownlink bool                   `protobuf:"varint,2,opt,name=user_downlink,json=userDownlink,proto3" json:"user_downlink,omitempty"`
+	// Gauge of the connections of the user being handled.
+	UserOnline bool `protobuf:"varint,3,opt,name=user_online,json=userOnline,proto3" json:"user_online,omitempty"`
+	// Number of distinct source IPs of the user in the window.
+	UserSourceIps  bool    `protobuf:"varint,4,opt,name=user_source_ips,json=userSourceIps,proto3" json:"user_source_ips,omitempty"`
+	SourceIpWindow *Second `protobuf:"bytes,5,opt,name=source_ip_window,json=sourceIpWindow,proto3" json:"source_ip_window,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Policy_Stats) Reset() {
@@ -345,6 +350,27 @@ func (x *Policy_Stats) GetUserDownlink() bool {
 	return false
 }
 
+func (x *Policy_Stats) GetUserOnline() bool {
+	if x != nil {
+		return x.UserOnline
+	}
+	return false
+}
+
+func (x *Policy_Stats) GetUserSourceIps() bool {
+	if x != nil {
+		return x.UserSourceIps
+	}
+	return false
+}
+
+func (x *Policy_Stats) GetSourceIpWindow() *Second {
+	if x != nil {
+		return x.SourceIpWindow
+	}
+	return nil
+}
+
 type Policy_Buffer struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Buffer size per connection, in bytes. -1 for unlimited buffer.
@@ -396,8 +422,12 @@ type SystemPolicy_Stats struct {
 	InboundDownlink  bool                   `protobuf:"varint,2,opt,name=inbound_downlink,json=inboundDownlink,proto3" json:"inbound_downlink,omitempty"`
 	OutboundUplink   bool                   `protobuf:"varint,3,opt,name=outbound_uplink,json=outboundUplink,proto3" json:"outbound_uplink,omitempty"`
 	OutboundDownlink bool                   `protobuf:"varint,4,opt,name=outbound_downlink,json=outboundDownlink,proto3" json:"outbound_downlink,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// Gauge of the connections being handled by inbounds.
+	InboundConnections bool `protobuf:"varint,5,opt,name=inbound_connections,json=inboundConnections,proto3" json:"inbound_connections,omitempty"`
+	// Traffic of the top destinations per outbound and user.
+	OutboundDestinations bool `protobuf:"varint,6,opt,name=outbound_destinations,json=outboundDestinations,proto3" json:"outbound_destinations,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *SystemPolicy_Stats) Reset() {
@@ -458,13 +488,27 @@ func (x *SystemPolicy_Stats) GetOutboundDownlink() bool {
 	return false
 }
 
+func (x *SystemPolicy_Stats) GetInboundConnections() bool {
+	if x != nil {
+		return x.InboundConnections
+	}
+	return false
+}
+
+func (x *SystemPolicy_Stats) GetOutboundDestinations() bool {
+	if x != nil {
+		return x.OutboundDestinations
+	}
+	return false
+}
+
 var File_app_policy_config_proto protoreflect.FileDescriptor
 
 const file_app_policy_config_proto_rawDesc = "" +
 	"\n" +
 	"\x17app/policy/config.proto\x12\x15v2ray.core.app.policy\x1a common/protoext/extensions.proto\"\x1e\n" +
 	"\x06Second\x12\x14\n" +
-	"\x05value\x18\x01 \x01(\rR\x05value\"\xd0\x04\n" +
+	"\x05value\x18\x01 \x01(\rR\x05value\"\xe3\x05\n" +
 	"\x06Policy\x12?\n" +
 	"\atimeout\x18\x01 \x01(\v2%.v2ray.core.app.policy.Policy.TimeoutR\atimeout\x129\n" +
 	"\x05stats\x18\x02 \x01(\v2#.v2ray.core.app.policy.Policy.StatsR\x05stats\x12<\n" +
@@ -474,23 +518,29 @@ const file_app_policy_config_proto_rawDesc = "" +
 	"\x0fconnection_idle\x18\x02 \x01(\v2\x1d.v2ray.core.app.policy.SecondR\x0econnectionIdle\x12>\n" +
 	"\vuplink_only\x18\x03 \x01(\v2\x1d.v2ray.core.app.policy.SecondR\n" +
 	"uplinkOnly\x12B\n" +
-	"\rdownlink_only\x18\x04 \x01(\v2\x1d.v2ray.core.app.policy.SecondR\fdownlinkOnly\x1aM\n" +
+	"\rdownlink_only\x18\x04 \x01(\v2\x1d.v2ray.core.app.policy.SecondR\fdownlinkOnly\x1a\xdf\x01\n" +
 	"\x05Stats\x12\x1f\n" +
 	"\vuser_uplink\x18\x01 \x01(\bR\n" +
 	"userUplink\x12#\n" +
-	"\ruser_downlink\x18\x02 \x01(\bR\fuserDownlink\x1a(\n" +
+	"\ruser_downlink\x18\x02 \x01(\bR\fuserDownlink\x12\x1f\n" +
+	"\vuser_online\x18\x03 \x01(\bR\n" +
+	"userOnline\x12&\n" +
+	"\x0fuser_source_ips\x18\x04 \x01(\bR\ruserSourceIps\x12G\n" +
+	"\x10source_ip_window\x18\x05 \x01(\v2\x1d.v2ray.core.app.policy.SecondR\x0esourceIpWindow\x1a(\n" +
 	"\x06Buffer\x12\x1e\n" +
 	"\n" +
 	"connection\x18\x01 \x01(\x05R\n" +
-	"connection\"\xba\x02\n" +
+	"connection\"\xa0\x03\n" +
 	"\fSystemPolicy\x12?\n" +
 	"\x05stats\x18\x01 \x01(\v2).v2ray.core.app.policy.SystemPolicy.StatsR\x05stats\x127\n" +
-	"\x18override_access_log_dest\x18\x02 \x01(\bR\x15overrideAccessLogDest\x1a\xaf\x01\n" +
+	"\x18override_access_log_dest\x18\x02 \x01(\bR\x15overrideAccessLogDest\x1a\x95\x02\n" +
 	"\x05Stats\x12%\n" +
 	"\x0einbound_uplink\x18\x01 \x01(\bR\rinboundUplink\x12)\n" +
 	"\x10inbound_downlink\x18\x02 \x01(\bR\x0finboundDownlink\x12'\n" +
 	"\x0foutbound_uplink\x18\x03 \x01(\bR\x0eoutboundUplink\x12+\n" +
-	"\x11outbound_downlink\x18\x04 \x01(\bR\x10outboundDownlink\"\xf5\x01\n" +
+	"\x11outbound_downlink\x18\x04 \x01(\bR\x10outboundDownlink\x12/\n" +
+	"\x13inbound_connections\x18\x05 \x01(\bR\x12inboundConnections\x123\n" +
+	"\x15outbound_destinations\x18\x06 \x01(\bR\x14outboundDestinations\"\xf5\x01\n" +
 	"\x06Config\x12>\n" +
 	"\x05level\x18\x01 \x03(\v2(.v2ray.core.app.policy.Config.LevelEntryR\x05level\x12;\n" +
 	"\x06system\x18\x02 \x01(\v2#.v2ray.core.app.policy.SystemPolicyR\x06system\x1aW\n" +
@@ -536,12 +586,13 @@ var file_app_policy_config_proto_depIdxs = []int32{
 	0,  // 7: v2ray.core.app.policy.Policy.Timeout.connection_idle:type_name -> v2ray.core.app.policy.Second
 	0,  // 8: v2ray.core.app.policy.Policy.Timeout.uplink_only:type_name -> v2ray.core.app.policy.Second
 	0,  // 9: v2ray.core.app.policy.Policy.Timeout.downlink_only:type_name -> v2ray.core.app.policy.Second
-	1,  // 10: v2ray.core.app.policy.Config.LevelEntry.value:type_name -> v2ray.core.app.policy.Policy
-	11, // [11:11] is the sub-list for method output_type
-	11, // [11:11] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	0,  // 10: v2ray.core.app.policy.Policy.Stats.source_ip_window:type_name -> v2ray.core.app.policy.Second
+	1,  // 11: v2ray.core.app.policy.Config.LevelEntry.value:type_name -> v2ray.core.app.policy.Policy
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_app_policy_config_proto_init() }
@@ -65,6 +65,7 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 	}
 
 	uplinkCounter, downlinkCounter := getStatCounter(core.MustFromContext(ctx), tag)
+	connStats := getConnectionStats(core.MustFromContext(ctx), tag)
 
 	nl := p.Network()
 	pr := receiverConfig.PortRange
@@ -100,6 +101,7 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 				sniffingConfig:  receiverConfig.GetEffectiveSniffingSettings(),
 				uplinkCounter:   uplinkCounter,
 				downlinkCounter: downlinkCounter,
+				connStats:       connStats,
 				ctx:             ctx,
 			}
 			h.workers = append(h.workers, worker)
@@ -121,6 +123,7 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 					sniffingConfig:  receiverConfig.GetEffectiveSniffingSettings(),
 					uplinkCounter:   uplinkCounter,
 					downlinkCounter: downlinkCounter,
+					connStats:       connStats,
 					ctx:             ctx,
 				}
 				h.workers = append(h.workers, worker)
@@ -137,6 +140,7 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 					sniffingConfig:  receiverConfig.GetEffectiveSniffingSettings(),
 					uplinkCounter:   uplinkCounter,
 					downlinkCounter: downlinkCounter,
+					connStats:       connStats,
 					stream:          mss,
 				}
 				h.workers = append(h.workers, worker)
@@ -115,6 +115,7 @@ func (h *DynamicInboundHandler) refresh() error {
 	}
 
 	uplinkCounter, downlinkCounter := getStatCounter(h.v, h.tag)
+	connStats := getConnectionStats(h.v, h.tag)
 
 	for i := uint32(0); i < concurrency; i++ {
 		port := h.allocatePort()
@@ -137,6 +138,7 @@ func (h *DynamicInboundHandler) refresh() error {
 				sniffingConfig:  h.receiverConfig.GetEffectiveSniffingSettings(),
 				uplinkCounter:   uplinkCounter,
 				downlinkCounter: downlinkCounter,
+				connStats:       connStats,
 				ctx:             h.ctx,
 			}
 			if err := worker.Start(); err != nil {
@@ -157,6 +159,7 @@ func (h *DynamicInboundHandler) refresh() error {
 				sniffingConfig:  h.receiverConfig.GetEffectiveSniffingSettings(),
 				uplinkCounter:   uplinkCounter,
 				downlinkCounter: downlinkCounter,
+				connStats:       connStats,
 				stream:          h.streamSettings,
 			}
 			if err := worker.Start(); err != nil {
@@ -0,0 +1,115 @@
+package inbound
+
+import (
+	"context"
+	"sync"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	"github.com/v2fly/v2ray-core/v5/features/policy"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
+	"github.com/v2fly/v2ray-core/v5/transport"
+)
+
+// connectionStats maintains the gauges of the connections handled by the workers of an inbound.
+type connectionStats struct {
+	policy      policy.Manager
+	stats       stats.Manager
+	connections stats.Gauge
+}
+
+func getConnectionStats(v *core.Instance, tag string) *connectionStats {
+	s := &connectionStats{
+		policy: v.GetFeature(policy.ManagerType()).(policy.Manager),
+		stats:  v.GetFeature(stats.ManagerType()).(stats.Manager),
+	}
+	if len(tag) > 0 && s.policy.ForSystem().Stats.InboundConnections {
+		name := "inbound>>>" + tag + ">>>connections"
+		if g, _ := stats.GetOrRegisterGauge(s.stats, name); g != nil {
+			s.connections = g
+		}
+	}
+	return s
+}
+
+// track counts a connection being handled, until the returned function is called. The returned dispatcher
+// counts the user of the connection when it is first dispatched, as users are authenticated by the proxy.
+func (s *connectionStats) track(dispatcher routing.Dispatcher) (routing.Dispatcher, func()) {
+	if s == nil {
+		return dispatcher, func() {}
+	}
+	if s.connections != nil {
+		s.connections.Add(1)
+	}
+	d := &userStatsDispatcher{Dispatcher: dispatcher, stats: s}
+	return d, func() {
+		if s.connections != nil {
+			s.connections.Add(-1)
+		}
+		d.close()
+	}
+}
+
+// userStatsDispatcher is the dispatcher for a connection, which maintains the stats of its user.
+type userStatsDispatcher struct {
+	routing.Dispatcher
+	stats *connectionStats
+
+	access  sync.Mutex
+	tracked bool
+	closed  bool
+	online  stats.Gauge
+}
+
+// Dispatch implements routing.Dispatcher.
+func (d *userStatsDispatcher) Dispatch(ctx context.Context, dest net.Destination) (*transport.Link, error) {
+	d.trackUser(ctx)
+	return d.Dispatcher.Dispatch(ctx, dest)
+}
+
+func (d *userStatsDispatcher) trackUser(ctx context.Context) {
+	inbound := session.InboundFromContext(ctx)
+	if inbound == nil || inbound.User == nil || len(inbound.User.Email) == 0 {
+		return
+	}
+
+	d.access.Lock()
+	defer d.access.Unlock()
+
+	if d.tracked || d.closed {
+		return
+	}
+	d.tracked = true
+
+	user := inbound.User
+	p := d.stats.policy.ForLevel(user.Level)
+	if p.Stats.UserOnline {
+		name := "user>>>" + user.Email + ">>>online"
+		if g, _ := stats.GetOrRegisterGauge(d.stats.stats, name); g != nil {
+			g.Add(1)
+			d.online = g
+		}
+	}
+	if p.Stats.UserSourceIPs && inbound.Source.IsValid() && inbound.Source.Address.Family().IsIP() {
+		name := "user>>>" + user.Email + ">>>source_ips"
+		c, err := stats.GetOrRegisterUniqueCounter(d.stats.stats, name, p.Stats.SourceIPWindow)
+		if err != nil {
+			newError("failed to get source IP counter of user ", user.Email).Base(err).AtDebug().WriteToLog(session.ExportIDToError(ctx))
+			return
+		}
+		c.Record(inbound.Source.Address.String())
+	}
+}
+
+func (d *userStatsDispatcher) close() {
+	d.access.Lock()
+	defer d.access.Unlock()
+
+	d.closed = true
+	if d.online != nil {
+		d.online.Add(-1)
+		d.online = nil
+	}
+}
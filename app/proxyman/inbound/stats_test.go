@@ -0,0 +1,66 @@
+package inbound
+
+import (
+	"context"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/app/policy"
+	"github.com/v2fly/v2ray-core/v5/app/stats"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+	"github.com/v2fly/v2ray-core/v5/transport"
+)
+
+type nopDispatcher struct {
+	routing.Dispatcher
+}
+
+func (nopDispatcher) Dispatch(context.Context, net.Destination) (*transport.Link, error) {
+	return &transport.Link{}, nil
+}
+
+func TestConnectionStats(t *testing.T) {
+	policyManager, err := policy.New(context.Background(), &policy.Config{
+		Level: map[uint32]*policy.Policy{
+			0: {Stats: &policy.Policy_Stats{UserOnline: true, UserSourceIps: true}},
+		},
+	})
+	common.Must(err)
+	statsManager, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+	connections, err := statsManager.RegisterGauge("inbound>>>socks>>>connections")
+	common.Must(err)
+	s := &connectionStats{policy: policyManager, stats: statsManager, connections: connections}
+
+	dispatch := func(source string) func() {
+		dispatcher, untrack := s.track(nopDispatcher{})
+		ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+			Source: net.TCPDestination(net.ParseAddress(source), 1234),
+			User:   &protocol.MemoryUser{Email: "love@v2fly.org"},
+		})
+		for i := 0; i < 2; i++ {
+			_, err := dispatcher.Dispatch(ctx, net.TCPDestination(net.ParseAddress("v2fly.org"), 443))
+			common.Must(err)
+		}
+		return untrack
+	}
+	untrack1 := dispatch("1.2.3.4")
+	untrack2 := dispatch("1.2.3.4")
+	untrack3 := dispatch("5.6.7.8")
+
+	online := statsManager.GetCounter("user>>>love@v2fly.org>>>online")
+	sourceIPs := statsManager.GetCounter("user>>>love@v2fly.org>>>source_ips")
+	if online.Value() != 3 || connections.Value() != 3 || sourceIPs.Value() != 2 {
+		t.Error("unexpected stats: ", online.Value(), " ", connections.Value(), " ", sourceIPs.Value())
+	}
+
+	untrack1()
+	untrack2()
+	untrack3()
+	if online.Value() != 0 || connections.Value() != 0 || sourceIPs.Value() != 2 {
+		t.Error("unexpected stats after connections closed: ", online.Value(), " ", connections.Value(), " ", sourceIPs.Value())
+	}
+}
@@ -2,6 +2,7 @@ package restfulapi
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
@@ -11,6 +12,7 @@ import (
 
 	core "github.com/v2fly/v2ray-core/v5"
 	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
 	"github.com/v2fly/v2ray-core/v5/transport/internet"
 )
 
@@ -47,6 +49,80 @@ func (rs *restfulService) tagStats(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+type Session struct {
+	ID          uint32 `json:"id"`
+	InboundTag  string `json:"inboundTag"`
+	User        string `json:"user"`
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	OutboundTag string `json:"outboundTag"`
+	StartTime   int64  `json:"startTime"`
+	Uplink      int64  `json:"uplink"`
+	Downlink    int64  `json:"downlink"`
+}
+
+func (rs *restfulService) sessionRegistry(w http.ResponseWriter, r *http.Request) routing.SessionRegistry {
+	registry, ok := rs.dispatcher.(routing.SessionRegistry)
+	if !ok {
+		render.Status(r, http.StatusNotImplemented)
+		render.JSON(w, r, render.M{})
+		return nil
+	}
+	return registry
+}
+
+func (rs *restfulService) listSessions(w http.ResponseWriter, r *http.Request) {
+	registry := rs.sessionRegistry(w, r)
+	if registry == nil {
+		return
+	}
+	user := r.URL.Query().Get("user")
+	inboundTag := r.URL.Query().Get("inbound")
+
+	sessions := []*Session{}
+	for _, info := range registry.Sessions() {
+		if (user != "" && !strings.EqualFold(info.User, user)) || (inboundTag != "" && info.InboundTag != inboundTag) {
+			continue
+		}
+		session := &Session{
+			ID:          info.ID,
+			InboundTag:  info.InboundTag,
+			User:        info.User,
+			OutboundTag: info.OutboundTag,
+			StartTime:   info.Start.Unix(),
+			Uplink:      info.Uplink,
+			Downlink:    info.Downlink,
+		}
+		if info.Source.IsValid() {
+			session.Source = info.Source.String()
+		}
+		if info.Target.IsValid() {
+			session.Target = info.Target.String()
+		}
+		sessions = append(sessions, session)
+	}
+	render.JSON(w, r, sessions)
+}
+
+func (rs *restfulService) closeSession(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusUnprocessableEntity)
+		render.JSON(w, r, render.M{})
+		return
+	}
+	registry := rs.sessionRegistry(w, r)
+	if registry == nil {
+		return
+	}
+	if !registry.CloseSession(uint32(id)) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, render.M{})
+		return
+	}
+	render.JSON(w, r, render.M{})
+}
+
 func (rs *restfulService) version(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, render.M{"version": core.Version()})
 }
@@ -68,15 +144,24 @@ func (rs *restfulService) TokenAuthMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func (rs *restfulService) start() error {
+// router returns the handler of the API, where the /v1 routes require the auth token.
+func (rs *restfulService) router() http.Handler {
 	r := chi.NewRouter()
 	r.Use(middleware.Heartbeat("/ping"))
 
-	validate = validator.New()
 	r.Route("/v1", func(r chi.Router) {
+		r.Use(rs.TokenAuthMiddleware)
 		r.Get("/{bound_type}/{tag}/stats", rs.tagStats)
+		r.Get("/sessions", rs.listSessions)
+		r.Delete("/sessions/{id}", rs.closeSession)
 	})
 	r.Get("/version", rs.version)
+	return r
+}
+
+func (rs *restfulService) start() error {
+	validate = validator.New()
+	r := rs.router()
 
 	var listener net.Listener
 	var err error
@@ -7,6 +7,7 @@ import (
 
 	core "github.com/v2fly/v2ray-core/v5"
 	"github.com/v2fly/v2ray-core/v5/features"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
 	feature_stats "github.com/v2fly/v2ray-core/v5/features/stats"
 )
 
@@ -17,7 +18,8 @@ type restfulService struct {
 	config   *Config
 	access   sync.Mutex
 
-	stats feature_stats.Manager
+	stats      feature_stats.Manager
+	dispatcher routing.Dispatcher
 
 	ctx context.Context
 }
@@ -41,16 +43,17 @@ func (rs *restfulService) Close() error {
 	return nil
 }
 
-func (rs *restfulService) init(config *Config, stats feature_stats.Manager) {
+func (rs *restfulService) init(config *Config, stats feature_stats.Manager, dispatcher routing.Dispatcher) {
 	rs.stats = stats
+	rs.dispatcher = dispatcher
 	rs.config = config
 }
 
 func newRestfulService(ctx context.Context, config *Config) (features.Feature, error) {
 	r := new(restfulService)
 	r.ctx = ctx
-	if err := core.RequireFeatures(ctx, func(stats feature_stats.Manager) {
-		r.init(config, stats)
+	if err := core.RequireFeatures(ctx, func(stats feature_stats.Manager, dispatcher routing.Dispatcher) {
+		r.init(config, stats, dispatcher)
 	}); err != nil {
 		return nil, err
 	}
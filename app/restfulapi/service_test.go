@@ -1,6 +1,8 @@
 package restfulapi
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 
@@ -12,3 +14,30 @@ func TestTypeReturnAnonymousType(t *testing.T) {
 	serviceType := service.Type()
 	assert.Empty(t, reflect.TypeOf(serviceType).Name(), "must return anonymous type")
 }
+
+func TestSessionsRequireAuthToken(t *testing.T) {
+	service := &restfulService{config: &Config{AuthToken: "token"}}
+	router := service.router()
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/v1/sessions", nil),
+		httptest.NewRequest(http.MethodDelete, "/v1/sessions/1", nil),
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, req.Method+" "+req.URL.Path)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/sessions/1", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Without a dispatcher tracking sessions, authorized requests reach the handler but are not implemented.
+	req = httptest.NewRequest(http.MethodDelete, "/v1/sessions/1", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
@@ -3,14 +3,24 @@ package router
 import (
 	"context"
 
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/features"
 	"github.com/v2fly/v2ray-core/v5/features/extension"
 	"github.com/v2fly/v2ray-core/v5/features/outbound"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
 )
 
 type BalancingStrategy interface {
 	PickOutbound([]string) string
 }
 
+// ContextualBalancingStrategy is a BalancingStrategy that takes the routing context into account.
+type ContextualBalancingStrategy interface {
+	PickOutboundForContext(routing.Context, []string) string
+}
+
 type BalancingPrincipleTarget interface {
 	GetPrincipleTarget([]string) []string
 }
@@ -24,8 +34,8 @@ type Balancer struct {
 	override override
 }
 
-// PickOutbound picks the tag of an outbound
-func (b *Balancer) PickOutbound() (string, error) {
+// PickOutbound picks the tag of an outbound for the routing context, which may be nil
+func (b *Balancer) PickOutbound(ctx routing.Context) (string, error) {
 	candidates, err := b.SelectOutbounds()
 	if err != nil {
 		if b.fallbackTag != "" {
@@ -37,6 +47,8 @@ func (b *Balancer) PickOutbound() (string, error) {
 	var tag string
 	if o := b.override.Get(); o != "" {
 		tag = o
+	} else if s, ok := b.strategy.(ContextualBalancingStrategy); ok && ctx != nil {
+		tag = s.PickOutboundForContext(ctx, candidates)
 	} else {
 		tag = b.strategy.PickOutbound(candidates)
 	}
@@ -51,12 +63,74 @@ func (b *Balancer) PickOutbound() (string, error) {
 	return tag, nil
 }
 
+// peekOutbound returns the candidates of the balancer, and the outbound it picks for sure if there is one, e.g. the
+// override target. Unlike PickOutbound, the state of the balancing strategy is left untouched.
+func (b *Balancer) peekOutbound() (string, []string, error) {
+	candidates, err := b.SelectOutbounds()
+	if err != nil {
+		if b.fallbackTag != "" {
+			return b.fallbackTag, nil, nil
+		}
+		return "", nil, err
+	}
+	if o := b.override.Get(); o != "" {
+		return o, candidates, nil
+	}
+	if len(candidates) == 0 {
+		return b.fallbackTag, nil, nil
+	}
+	return "", candidates, nil
+}
+
 func (b *Balancer) InjectContext(ctx context.Context) {
 	if contextReceiver, ok := b.strategy.(extension.ContextReceiver); ok {
 		contextReceiver.InjectContext(ctx)
 	}
 }
 
+// findObservatory returns the observatory with the tag, or nil if there is no observatory.
+func findObservatory(ctx context.Context, tag string) extension.Observatory {
+	feature := core.MustFromContext(ctx).GetFeature(extension.ObservatoryType())
+	if feature == nil {
+		return nil
+	}
+	observer := feature.(extension.Observatory)
+	if tag != "" {
+		return common.Must2(observer.(features.TaggedFeatures).GetFeaturesByTag(tag)).(extension.Observatory)
+	}
+	return observer
+}
+
+// filterAliveOutbounds filters away candidates observed dead. Candidates are considered alive
+// unless observed otherwise.
+func filterAliveOutbounds(ctx context.Context, observer extension.Observatory, candidates []string) []string {
+	if observer == nil {
+		return candidates
+	}
+	observeReport, err := observer.GetObservation(ctx)
+	if err != nil {
+		newError("cannot get observe report").Base(err).WriteToLog()
+		return candidates
+	}
+	result, ok := observeReport.(*observatory.ObservationResult)
+	if !ok {
+		return candidates
+	}
+	dead := make(map[string]bool)
+	for _, status := range result.Status {
+		if !status.Alive {
+			dead[status.OutboundTag] = true
+		}
+	}
+	aliveTags := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !dead[candidate] {
+			aliveTags = append(aliveTags, candidate)
+		}
+	}
+	return aliveTags
+}
+
 // SelectOutbounds select outbounds with selectors of the Balancer
 func (b *Balancer) SelectOutbounds() ([]string, error) {
 	hs, ok := b.ohm.(outbound.HandlerSelector)
@@ -69,7 +143,7 @@ func (b *Balancer) SelectOutbounds() ([]string, error) {
 
 // GetPrincipleTarget implements routing.BalancerPrincipleTarget
 func (r *Router) GetPrincipleTarget(tag string) ([]string, error) {
-	if b, ok := r.balancers[tag]; ok {
+	if b, ok := r.getBalancer(tag); ok {
 		if s, ok := b.strategy.(BalancingPrincipleTarget); ok {
 			candidates, err := b.SelectOutbounds()
 			if err != nil {
@@ -84,7 +158,7 @@ func (r *Router) GetPrincipleTarget(tag string) ([]string, error) {
 
 // SetOverrideTarget implements routing.BalancerOverrider
 func (r *Router) SetOverrideTarget(tag, target string) error {
-	if b, ok := r.balancers[tag]; ok {
+	if b, ok := r.getBalancer(tag); ok {
 		b.override.Put(target)
 		return nil
 	}
@@ -93,7 +167,7 @@ func (r *Router) SetOverrideTarget(tag, target string) error {
 
 // GetOverrideTarget implements routing.BalancerOverrider
 func (r *Router) GetOverrideTarget(tag string) (string, error) {
-	if b, ok := r.balancers[tag]; ok {
+	if b, ok := r.getBalancer(tag); ok {
 		return b.override.Get(), nil
 	}
 	return "", newError("cannot find tag")
@@ -55,6 +55,55 @@ func (s *routingServer) OverrideBalancerTarget(ctx context.Context, request *Ove
 	return nil, newError("unsupported router implementation")
 }
 
+func (s *routingServer) AddRule(ctx context.Context, request *AddRuleRequest) (*AddRuleResponse, error) {
+	if rm, ok := s.router.(routing.RuleManager); ok {
+		if request.Config == nil {
+			return nil, newError("empty router config")
+		}
+		return &AddRuleResponse{}, rm.AddRule(request.Config, request.Prepend)
+	}
+	return nil, newError("unsupported router implementation")
+}
+
+func (s *routingServer) RemoveRule(ctx context.Context, request *RemoveRuleRequest) (*RemoveRuleResponse, error) {
+	if rm, ok := s.router.(routing.RuleManager); ok {
+		return &RemoveRuleResponse{}, rm.RemoveRule(request.RuleTag)
+	}
+	return nil, newError("unsupported router implementation")
+}
+
+func (s *routingServer) ReplaceRules(ctx context.Context, request *ReplaceRulesRequest) (*ReplaceRulesResponse, error) {
+	if rm, ok := s.router.(routing.RuleManager); ok {
+		if request.Config == nil {
+			return nil, newError("empty router config")
+		}
+		return &ReplaceRulesResponse{}, rm.ReplaceRules(request.Config)
+	}
+	return nil, newError("unsupported router implementation")
+}
+
+func (s *routingServer) GetRuleStats(ctx context.Context, request *GetRuleStatsRequest) (*GetRuleStatsResponse, error) {
+	sr, ok := s.router.(routing.RuleStatsReader)
+	if !ok {
+		return nil, newError("unsupported router implementation")
+	}
+	response := &GetRuleStatsResponse{}
+	for _, rs := range sr.GetRuleStats(request.RuleTag, request.Reset_) {
+		msg := &RuleStats{
+			RuleTag:      rs.RuleTag,
+			Index:        int32(rs.Index),
+			OutboundTag:  rs.OutboundTag,
+			BalancingTag: rs.BalancingTag,
+			Hits:         rs.Hits,
+		}
+		if !rs.LastMatch.IsZero() {
+			msg.LastMatchTime = rs.LastMatch.Unix()
+		}
+		response.Stats = append(response.Stats, msg)
+	}
+	return response, nil
+}
+
 // NewRoutingServer creates a statistics service with statistics manager.
 func NewRoutingServer(router routing.Router, routingStats stats.Channel) RoutingServiceServer {
 	return &routingServer{
@@ -78,6 +127,37 @@ func (s *routingServer) TestRoute(ctx context.Context, request *TestRouteRequest
 	return AsProtobufMessage(request.FieldSelectors)(route), nil
 }
 
+func (s *routingServer) ExplainRoute(ctx context.Context, request *ExplainRouteRequest) (*ExplainRouteResponse, error) {
+	if request.RoutingContext == nil {
+		return nil, newError("Invalid routing request.")
+	}
+	re, ok := s.router.(routing.RouteExplainer)
+	if !ok {
+		return nil, newError("unsupported router implementation")
+	}
+	route, traces, err := re.ExplainRoute(AsRoutingContext(request.RoutingContext))
+	if err != nil && err != common.ErrNoClue {
+		return nil, err
+	}
+	response := &ExplainRouteResponse{}
+	if route != nil {
+		response.Result = AsProtobufMessage(request.FieldSelectors)(route)
+	}
+	for _, trace := range traces {
+		response.Trace = append(response.Trace, &RuleTrace{
+			Index:        int32(trace.Index),
+			RuleTag:      trace.RuleTag,
+			OutboundTag:  trace.OutboundTag,
+			BalancingTag: trace.BalancingTag,
+			Matched:      trace.Matched,
+			Reason:       trace.Reason,
+			Resolved:     trace.Resolved,
+			Candidate:    trace.Candidates,
+		})
+	}
+	return response, nil
+}
+
 func (s *routingServer) SubscribeRoutingStats(request *SubscribeRoutingStatsRequest, stream RoutingService_SubscribeRoutingStatsServer) error {
 	if s.routingStats == nil {
 		return newError("Routing statistics not enabled.")
@@ -1,6 +1,7 @@
 package command
 
 import (
+	router "github.com/v2fly/v2ray-core/v5/app/router"
 	net "github.com/v2fly/v2ray-core/v5/common/net"
 	_ "github.com/v2fly/v2ray-core/v5/common/protoext"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
@@ -281,6 +282,223 @@ func (x *TestRouteRequest) GetPublishResult() bool {
 	return false
 }
 
+// ExplainRouteRequest tests a routing result like TestRouteRequest, and also
+// returns how each routing rule is evaluated.
+// * RoutingContext is the routing message without outbound information.
+// * FieldSelectors selects the fields to return in the routing result. All
+// fields are returned if left empty.
+type ExplainRouteRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RoutingContext *RoutingContext        `protobuf:"bytes,1,opt,name=RoutingContext,proto3" json:"RoutingContext,omitempty"`
+	FieldSelectors []string               `protobuf:"bytes,2,rep,name=FieldSelectors,proto3" json:"FieldSelectors,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExplainRouteRequest) Reset() {
+	*x = ExplainRouteRequest{}
+	mi := &file_app_router_command_command_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainRouteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainRouteRequest) ProtoMessage() {}
+
+func (x *ExplainRouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_command_command_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainRouteRequest.ProtoReflect.Descriptor instead.
+func (*ExplainRouteRequest) Descriptor() ([]byte, []int) {
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ExplainRouteRequest) GetRoutingContext() *RoutingContext {
+	if x != nil {
+		return x.RoutingContext
+	}
+	return nil
+}
+
+func (x *ExplainRouteRequest) GetFieldSelectors() []string {
+	if x != nil {
+		return x.FieldSelectors
+	}
+	return nil
+}
+
+type RuleTrace struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Index of the rule in the routing rule list.
+	Index        int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	RuleTag      string `protobuf:"bytes,2,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	OutboundTag  string `protobuf:"bytes,3,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
+	BalancingTag string `protobuf:"bytes,4,opt,name=balancing_tag,json=balancingTag,proto3" json:"balancing_tag,omitempty"`
+	Matched      bool   `protobuf:"varint,5,opt,name=matched,proto3" json:"matched,omitempty"`
+	// The first condition of the rule that does not match.
+	Reason string `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Whether the rule is evaluated again with IPs resolved from target domain.
+	Resolved bool `protobuf:"varint,7,opt,name=resolved,proto3" json:"resolved,omitempty"`
+	// Outbounds the balancer of the matched rule picks from. The outbound is not
+	// picked when explaining, so that the balancing strategy is left untouched.
+	Candidate     []string `protobuf:"bytes,8,rep,name=candidate,proto3" json:"candidate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuleTrace) Reset() {
+	*x = RuleTrace{}
+	mi := &file_app_router_command_command_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleTrace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleTrace) ProtoMessage() {}
+
+func (x *RuleTrace) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_command_command_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleTrace.ProtoReflect.Descriptor instead.
+func (*RuleTrace) Descriptor() ([]byte, []int) {
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RuleTrace) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *RuleTrace) GetRuleTag() string {
+	if x != nil {
+		return x.RuleTag
+	}
+	return ""
+}
+
+func (x *RuleTrace) GetOutboundTag() string {
+	if x != nil {
+		return x.OutboundTag
+	}
+	return ""
+}
+
+func (x *RuleTrace) GetBalancingTag() string {
+	if x != nil {
+		return x.BalancingTag
+	}
+	return ""
+}
+
+func (x *RuleTrace) GetMatched() bool {
+	if x != nil {
+		return x.Matched
+	}
+	return false
+}
+
+func (x *RuleTrace) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RuleTrace) GetResolved() bool {
+	if x != nil {
+		return x.Resolved
+	}
+	return false
+}
+
+func (x *RuleTrace) GetCandidate() []string {
+	if x != nil {
+		return x.Candidate
+	}
+	return nil
+}
+
+// ExplainRouteResponse contains the routing result, which is absent if no
+// rule matches and the default outbound is to be used, and evaluation of
+// routing rules in order.
+type ExplainRouteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *RoutingContext        `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Trace         []*RuleTrace           `protobuf:"bytes,2,rep,name=trace,proto3" json:"trace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainRouteResponse) Reset() {
+	*x = ExplainRouteResponse{}
+	mi := &file_app_router_command_command_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainRouteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainRouteResponse) ProtoMessage() {}
+
+func (x *ExplainRouteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_command_command_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainRouteResponse.ProtoReflect.Descriptor instead.
+func (*ExplainRouteResponse) Descriptor() ([]byte, []int) {
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ExplainRouteResponse) GetResult() *RoutingContext {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ExplainRouteResponse) GetTrace() []*RuleTrace {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
 type PrincipleTargetInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Tag           []string               `protobuf:"bytes,1,rep,name=tag,proto3" json:"tag,omitempty"`
@@ -290,7 +508,7 @@ type PrincipleTargetInfo struct {
 
 func (x *PrincipleTargetInfo) Reset() {
 	*x = PrincipleTargetInfo{}
-	mi := &file_app_router_command_command_proto_msgTypes[3]
+	mi := &file_app_router_command_command_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -302,7 +520,7 @@ func (x *PrincipleTargetInfo) String() string {
 func (*PrincipleTargetInfo) ProtoMessage() {}
 
 func (x *PrincipleTargetInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_command_command_proto_msgTypes[3]
+	mi := &file_app_router_command_command_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -315,7 +533,7 @@ func (x *PrincipleTargetInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PrincipleTargetInfo.ProtoReflect.Descriptor instead.
 func (*PrincipleTargetInfo) Descriptor() ([]byte, []int) {
-	return file_app_router_command_command_proto_rawDescGZIP(), []int{3}
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *PrincipleTargetInfo) GetTag() []string {
@@ -334,7 +552,7 @@ type OverrideInfo struct {
 
 func (x *OverrideInfo) Reset() {
 	*x = OverrideInfo{}
-	mi := &file_app_router_command_command_proto_msgTypes[4]
+	mi := &file_app_router_command_command_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -346,7 +564,7 @@ func (x *OverrideInfo) String() string {
 func (*OverrideInfo) ProtoMessage() {}
 
 func (x *OverrideInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_command_command_proto_msgTypes[4]
+	mi := &file_app_router_command_command_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -359,7 +577,7 @@ func (x *OverrideInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OverrideInfo.ProtoReflect.Descriptor instead.
 func (*OverrideInfo) Descriptor() ([]byte, []int) {
-	return file_app_router_command_command_proto_rawDescGZIP(), []int{4}
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *OverrideInfo) GetTarget() string {
@@ -379,7 +597,7 @@ type BalancerMsg struct {
 
 func (x *BalancerMsg) Reset() {
 	*x = BalancerMsg{}
-	mi := &file_app_router_command_command_proto_msgTypes[5]
+	mi := &file_app_router_command_command_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -391,7 +609,7 @@ func (x *BalancerMsg) String() string {
 func (*BalancerMsg) ProtoMessage() {}
 
 func (x *BalancerMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_command_command_proto_msgTypes[5]
+	mi := &file_app_router_command_command_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -404,7 +622,7 @@ func (x *BalancerMsg) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BalancerMsg.ProtoReflect.Descriptor instead.
 func (*BalancerMsg) Descriptor() ([]byte, []int) {
-	return file_app_router_command_command_proto_rawDescGZIP(), []int{5}
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *BalancerMsg) GetOverride() *OverrideInfo {
@@ -430,7 +648,7 @@ type GetBalancerInfoRequest struct {
 
 func (x *GetBalancerInfoRequest) Reset() {
 	*x = GetBalancerInfoRequest{}
-	mi := &file_app_router_command_command_proto_msgTypes[6]
+	mi := &file_app_router_command_command_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -442,7 +660,7 @@ func (x *GetBalancerInfoRequest) String() string {
 func (*GetBalancerInfoRequest) ProtoMessage() {}
 
 func (x *GetBalancerInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_command_command_proto_msgTypes[6]
+	mi := &file_app_router_command_command_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -455,7 +673,7 @@ func (x *GetBalancerInfoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBalancerInfoRequest.ProtoReflect.Descriptor instead.
 func (*GetBalancerInfoRequest) Descriptor() ([]byte, []int) {
-	return file_app_router_command_command_proto_rawDescGZIP(), []int{6}
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetBalancerInfoRequest) GetTag() string {
@@ -474,7 +692,7 @@ type GetBalancerInfoResponse struct {
 
 func (x *GetBalancerInfoResponse) Reset() {
 	*x = GetBalancerInfoResponse{}
-	mi := &file_app_router_command_command_proto_msgTypes[7]
+	mi := &file_app_router_command_command_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -486,7 +704,7 @@ func (x *GetBalancerInfoResponse) String() string {
 func (*GetBalancerInfoResponse) ProtoMessage() {}
 
 func (x *GetBalancerInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_command_command_proto_msgTypes[7]
+	mi := &file_app_router_command_command_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -499,7 +717,7 @@ func (x *GetBalancerInfoResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBalancerInfoResponse.ProtoReflect.Descriptor instead.
 func (*GetBalancerInfoResponse) Descriptor() ([]byte, []int) {
-	return file_app_router_command_command_proto_rawDescGZIP(), []int{7}
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *GetBalancerInfoResponse) GetBalancer() *BalancerMsg {
@@ -519,7 +737,7 @@ type OverrideBalancerTargetRequest struct {
 
 func (x *OverrideBalancerTargetRequest) Reset() {
 	*x = OverrideBalancerTargetRequest{}
-	mi := &file_app_router_command_command_proto_msgTypes[8]
+	mi := &file_app_router_command_command_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -531,7 +749,7 @@ func (x *OverrideBalancerTargetRequest) String() string {
 func (*OverrideBalancerTargetRequest) ProtoMessage() {}
 
 func (x *OverrideBalancerTargetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_command_command_proto_msgTypes[8]
+	mi := &file_app_router_command_command_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -544,7 +762,7 @@ func (x *OverrideBalancerTargetRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OverrideBalancerTargetRequest.ProtoReflect.Descriptor instead.
 func (*OverrideBalancerTargetRequest) Descriptor() ([]byte, []int) {
-	return file_app_router_command_command_proto_rawDescGZIP(), []int{8}
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *OverrideBalancerTargetRequest) GetBalancerTag() string {
@@ -569,7 +787,7 @@ type OverrideBalancerTargetResponse struct {
 
 func (x *OverrideBalancerTargetResponse) Reset() {
 	*x = OverrideBalancerTargetResponse{}
-	mi := &file_app_router_command_command_proto_msgTypes[9]
+	mi := &file_app_router_command_command_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -581,7 +799,7 @@ func (x *OverrideBalancerTargetResponse) String() string {
 func (*OverrideBalancerTargetResponse) ProtoMessage() {}
 
 func (x *OverrideBalancerTargetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_command_command_proto_msgTypes[9]
+	mi := &file_app_router_command_command_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -594,7 +812,448 @@ func (x *OverrideBalancerTargetResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OverrideBalancerTargetResponse.ProtoReflect.Descriptor instead.
 func (*OverrideBalancerTargetResponse) Descriptor() ([]byte, []int) {
-	return file_app_router_command_command_proto_rawDescGZIP(), []int{9}
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{12}
+}
+
+// AddRuleRequest adds routing rules and balancing rules to a running router.
+// * Config holds the rules to add. Its domain_strategy is ignored.
+// * Prepend inserts the rules before existing rules if set true, otherwise
+// they are appended.
+type AddRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        *router.Config         `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	Prepend       bool                   `protobuf:"varint,2,opt,name=prepend,proto3" json:"prepend,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRuleRequest) Reset() {
+	*x = AddRuleRequest{}
+	mi := &file_app_router_command_command_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRuleRequest) ProtoMessage() {}
+
+func (x *AddRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_command_command_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRuleRequest.ProtoReflect.Descriptor instead.
+func (*AddRuleRequest) Descriptor() ([]byte, []int) {
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AddRuleRequest) GetConfig() *router.Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *AddRuleRequest) GetPrepend() bool {
+	if x != nil {
+		return x.Prepend
+	}
+	return false
+}
+
+type AddRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRuleResponse) Reset() {
+	*x = AddRuleResponse{}
+	mi := &file_app_router_command_command_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRuleResponse) ProtoMessage() {}
+
+func (x *AddRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_command_command_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRuleResponse.ProtoReflect.Descriptor instead.
+func (*AddRuleResponse) Descriptor() ([]byte, []int) {
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{14}
+}
+
+// RemoveRuleRequest removes all routing rules with the given rule tag.
+type RemoveRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleTag       string                 `protobuf:"bytes,1,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRuleRequest) Reset() {
+	*x = RemoveRuleRequest{}
+	mi := &file_app_router_command_command_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRuleRequest) ProtoMessage() {}
+
+func (x *RemoveRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_command_command_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRuleRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRuleRequest) Descriptor() ([]byte, []int) {
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RemoveRuleRequest) GetRuleTag() string {
+	if x != nil {
+		return x.RuleTag
+	}
+	return ""
+}
+
+type RemoveRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRuleResponse) Reset() {
+	*x = RemoveRuleResponse{}
+	mi := &file_app_router_command_command_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRuleResponse) ProtoMessage() {}
+
+func (x *RemoveRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_command_command_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRuleResponse.ProtoReflect.Descriptor instead.
+func (*RemoveRuleResponse) Descriptor() ([]byte, []int) {
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{16}
+}
+
+// ReplaceRulesRequest atomically replaces all routing rules and balancing
+// rules of a running router. Its domain_strategy is ignored.
+type ReplaceRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        *router.Config         `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplaceRulesRequest) Reset() {
+	*x = ReplaceRulesRequest{}
+	mi := &file_app_router_command_command_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplaceRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaceRulesRequest) ProtoMessage() {}
+
+func (x *ReplaceRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_command_command_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaceRulesRequest.ProtoReflect.Descriptor instead.
+func (*ReplaceRulesRequest) Descriptor() ([]byte, []int) {
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReplaceRulesRequest) GetConfig() *router.Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type ReplaceRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplaceRulesResponse) Reset() {
+	*x = ReplaceRulesResponse{}
+	mi := &file_app_router_command_command_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplaceRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaceRulesResponse) ProtoMessage() {}
+
+func (x *ReplaceRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_command_command_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaceRulesResponse.ProtoReflect.Descriptor instead.
+func (*ReplaceRulesResponse) Descriptor() ([]byte, []int) {
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{18}
+}
+
+// GetRuleStatsRequest queries hit statistics of routing rules.
+// * RuleTag selects rules with the given rule tag. All rules are returned if
+// left empty.
+// * Reset resets hit counts of the selected rules after fetching them.
+type GetRuleStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleTag       string                 `protobuf:"bytes,1,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	Reset_        bool                   `protobuf:"varint,2,opt,name=reset,proto3" json:"reset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRuleStatsRequest) Reset() {
+	*x = GetRuleStatsRequest{}
+	mi := &file_app_router_command_command_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRuleStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRuleStatsRequest) ProtoMessage() {}
+
+func (x *GetRuleStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_command_command_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRuleStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetRuleStatsRequest) Descriptor() ([]byte, []int) {
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetRuleStatsRequest) GetRuleTag() string {
+	if x != nil {
+		return x.RuleTag
+	}
+	return ""
+}
+
+func (x *GetRuleStatsRequest) GetReset_() bool {
+	if x != nil {
+		return x.Reset_
+	}
+	return false
+}
+
+type RuleStats struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	RuleTag string                 `protobuf:"bytes,1,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	// Index of the rule in the routing rule list.
+	Index        int32  `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	OutboundTag  string `protobuf:"bytes,3,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
+	BalancingTag string `protobuf:"bytes,4,opt,name=balancing_tag,json=balancingTag,proto3" json:"balancing_tag,omitempty"`
+	Hits         int64  `protobuf:"varint,5,opt,name=hits,proto3" json:"hits,omitempty"`
+	// Unix timestamp in seconds of the last match, 0 if never matched.
+	LastMatchTime int64 `protobuf:"varint,6,opt,name=last_match_time,json=lastMatchTime,proto3" json:"last_match_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuleStats) Reset() {
+	*x = RuleStats{}
+	mi := &file_app_router_command_command_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleStats) ProtoMessage() {}
+
+func (x *RuleStats) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_command_command_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleStats.ProtoReflect.Descriptor instead.
+func (*RuleStats) Descriptor() ([]byte, []int) {
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RuleStats) GetRuleTag() string {
+	if x != nil {
+		return x.RuleTag
+	}
+	return ""
+}
+
+func (x *RuleStats) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *RuleStats) GetOutboundTag() string {
+	if x != nil {
+		return x.OutboundTag
+	}
+	return ""
+}
+
+func (x *RuleStats) GetBalancingTag() string {
+	if x != nil {
+		return x.BalancingTag
+	}
+	return ""
+}
+
+func (x *RuleStats) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *RuleStats) GetLastMatchTime() int64 {
+	if x != nil {
+		return x.LastMatchTime
+	}
+	return 0
+}
+
+type GetRuleStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         []*RuleStats           `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRuleStatsResponse) Reset() {
+	*x = GetRuleStatsResponse{}
+	mi := &file_app_router_command_command_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRuleStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRuleStatsResponse) ProtoMessage() {}
+
+func (x *GetRuleStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_command_command_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRuleStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetRuleStatsResponse) Descriptor() ([]byte, []int) {
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetRuleStatsResponse) GetStats() []*RuleStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
 }
 
 type Config struct {
@@ -605,7 +1264,7 @@ type Config struct {
 
 func (x *Config) Reset() {
 	*x = Config{}
-	mi := &file_app_router_command_command_proto_msgTypes[10]
+	mi := &file_app_router_command_command_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -617,7 +1276,7 @@ func (x *Config) String() string {
 func (*Config) ProtoMessage() {}
 
 func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_command_command_proto_msgTypes[10]
+	mi := &file_app_router_command_command_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -630,14 +1289,14 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Config.ProtoReflect.Descriptor instead.
 func (*Config) Descriptor() ([]byte, []int) {
-	return file_app_router_command_command_proto_rawDescGZIP(), []int{10}
+	return file_app_router_command_command_proto_rawDescGZIP(), []int{22}
 }
 
 var File_app_router_command_command_proto protoreflect.FileDescriptor
 
 const file_app_router_command_command_proto_rawDesc = "" +
 	"\n" +
-	" app/router/command/command.proto\x12\x1dv2ray.core.app.router.command\x1a common/protoext/extensions.proto\x1a\x18common/net/network.proto\"\xa8\x04\n" +
+	" app/router/command/command.proto\x12\x1dv2ray.core.app.router.command\x1a common/protoext/extensions.proto\x1a\x18common/net/network.proto\x1a\x17app/router/config.proto\"\xa8\x04\n" +
 	"\x0eRoutingContext\x12\x1e\n" +
 	"\n" +
 	"InboundTag\x18\x01 \x01(\tR\n" +
@@ -668,7 +1327,22 @@ const file_app_router_command_command_proto_rawDesc = "" +
 	"\x10TestRouteRequest\x12U\n" +
 	"\x0eRoutingContext\x18\x01 \x01(\v2-.v2ray.core.app.router.command.RoutingContextR\x0eRoutingContext\x12&\n" +
 	"\x0eFieldSelectors\x18\x02 \x03(\tR\x0eFieldSelectors\x12$\n" +
-	"\rPublishResult\x18\x03 \x01(\bR\rPublishResult\"'\n" +
+	"\rPublishResult\x18\x03 \x01(\bR\rPublishResult\"\x94\x01\n" +
+	"\x13ExplainRouteRequest\x12U\n" +
+	"\x0eRoutingContext\x18\x01 \x01(\v2-.v2ray.core.app.router.command.RoutingContextR\x0eRoutingContext\x12&\n" +
+	"\x0eFieldSelectors\x18\x02 \x03(\tR\x0eFieldSelectors\"\xf0\x01\n" +
+	"\tRuleTrace\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x19\n" +
+	"\brule_tag\x18\x02 \x01(\tR\aruleTag\x12!\n" +
+	"\foutbound_tag\x18\x03 \x01(\tR\voutboundTag\x12#\n" +
+	"\rbalancing_tag\x18\x04 \x01(\tR\fbalancingTag\x12\x18\n" +
+	"\amatched\x18\x05 \x01(\bR\amatched\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x1a\n" +
+	"\bresolved\x18\a \x01(\bR\bresolved\x12\x1c\n" +
+	"\tcandidate\x18\b \x03(\tR\tcandidate\"\x9d\x01\n" +
+	"\x14ExplainRouteResponse\x12E\n" +
+	"\x06result\x18\x01 \x01(\v2-.v2ray.core.app.router.command.RoutingContextR\x06result\x12>\n" +
+	"\x05trace\x18\x02 \x03(\v2(.v2ray.core.app.router.command.RuleTraceR\x05trace\"'\n" +
 	"\x13PrincipleTargetInfo\x12\x10\n" +
 	"\x03tag\x18\x01 \x03(\tR\x03tag\"&\n" +
 	"\fOverrideInfo\x12\x16\n" +
@@ -683,14 +1357,42 @@ const file_app_router_command_command_proto_rawDesc = "" +
 	"\x1dOverrideBalancerTargetRequest\x12 \n" +
 	"\vbalancerTag\x18\x01 \x01(\tR\vbalancerTag\x12\x16\n" +
 	"\x06target\x18\x02 \x01(\tR\x06target\" \n" +
-	"\x1eOverrideBalancerTargetResponse\"#\n" +
+	"\x1eOverrideBalancerTargetResponse\"a\n" +
+	"\x0eAddRuleRequest\x125\n" +
+	"\x06config\x18\x01 \x01(\v2\x1d.v2ray.core.app.router.ConfigR\x06config\x12\x18\n" +
+	"\aprepend\x18\x02 \x01(\bR\aprepend\"\x11\n" +
+	"\x0fAddRuleResponse\".\n" +
+	"\x11RemoveRuleRequest\x12\x19\n" +
+	"\brule_tag\x18\x01 \x01(\tR\aruleTag\"\x14\n" +
+	"\x12RemoveRuleResponse\"L\n" +
+	"\x13ReplaceRulesRequest\x125\n" +
+	"\x06config\x18\x01 \x01(\v2\x1d.v2ray.core.app.router.ConfigR\x06config\"\x16\n" +
+	"\x14ReplaceRulesResponse\"F\n" +
+	"\x13GetRuleStatsRequest\x12\x19\n" +
+	"\brule_tag\x18\x01 \x01(\tR\aruleTag\x12\x14\n" +
+	"\x05reset\x18\x02 \x01(\bR\x05reset\"\xc0\x01\n" +
+	"\tRuleStats\x12\x19\n" +
+	"\brule_tag\x18\x01 \x01(\tR\aruleTag\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\x05R\x05index\x12!\n" +
+	"\foutbound_tag\x18\x03 \x01(\tR\voutboundTag\x12#\n" +
+	"\rbalancing_tag\x18\x04 \x01(\tR\fbalancingTag\x12\x12\n" +
+	"\x04hits\x18\x05 \x01(\x03R\x04hits\x12&\n" +
+	"\x0flast_match_time\x18\x06 \x01(\x03R\rlastMatchTime\"V\n" +
+	"\x14GetRuleStatsResponse\x12>\n" +
+	"\x05stats\x18\x01 \x03(\v2(.v2ray.core.app.router.command.RuleStatsR\x05stats\"#\n" +
 	"\x06Config:\x19\x82\xb5\x18\x15\n" +
-	"\vgrpcservice\x12\x06router2\xa8\x04\n" +
+	"\vgrpcservice\x12\x06router2\xfa\b\n" +
 	"\x0eRoutingService\x12\x87\x01\n" +
 	"\x15SubscribeRoutingStats\x12;.v2ray.core.app.router.command.SubscribeRoutingStatsRequest\x1a-.v2ray.core.app.router.command.RoutingContext\"\x000\x01\x12m\n" +
-	"\tTestRoute\x12/.v2ray.core.app.router.command.TestRouteRequest\x1a-.v2ray.core.app.router.command.RoutingContext\"\x00\x12\x82\x01\n" +
+	"\tTestRoute\x12/.v2ray.core.app.router.command.TestRouteRequest\x1a-.v2ray.core.app.router.command.RoutingContext\"\x00\x12y\n" +
+	"\fExplainRoute\x122.v2ray.core.app.router.command.ExplainRouteRequest\x1a3.v2ray.core.app.router.command.ExplainRouteResponse\"\x00\x12\x82\x01\n" +
 	"\x0fGetBalancerInfo\x125.v2ray.core.app.router.command.GetBalancerInfoRequest\x1a6.v2ray.core.app.router.command.GetBalancerInfoResponse\"\x00\x12\x97\x01\n" +
-	"\x16OverrideBalancerTarget\x12<.v2ray.core.app.router.command.OverrideBalancerTargetRequest\x1a=.v2ray.core.app.router.command.OverrideBalancerTargetResponse\"\x00Bx\n" +
+	"\x16OverrideBalancerTarget\x12<.v2ray.core.app.router.command.OverrideBalancerTargetRequest\x1a=.v2ray.core.app.router.command.OverrideBalancerTargetResponse\"\x00\x12j\n" +
+	"\aAddRule\x12-.v2ray.core.app.router.command.AddRuleRequest\x1a..v2ray.core.app.router.command.AddRuleResponse\"\x00\x12s\n" +
+	"\n" +
+	"RemoveRule\x120.v2ray.core.app.router.command.RemoveRuleRequest\x1a1.v2ray.core.app.router.command.RemoveRuleResponse\"\x00\x12y\n" +
+	"\fReplaceRules\x122.v2ray.core.app.router.command.ReplaceRulesRequest\x1a3.v2ray.core.app.router.command.ReplaceRulesResponse\"\x00\x12y\n" +
+	"\fGetRuleStats\x122.v2ray.core.app.router.command.GetRuleStatsRequest\x1a3.v2ray.core.app.router.command.GetRuleStatsResponse\"\x00Bx\n" +
 	"!com.v2ray.core.app.router.commandP\x01Z1github.com/v2fly/v2ray-core/v5/app/router/command\xaa\x02\x1dV2Ray.Core.App.Router.Commandb\x06proto3"
 
 var (
@@ -705,42 +1407,71 @@ func file_app_router_command_command_proto_rawDescGZIP() []byte {
 	return file_app_router_command_command_proto_rawDescData
 }
 
-var file_app_router_command_command_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_app_router_command_command_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_app_router_command_command_proto_goTypes = []any{
 	(*RoutingContext)(nil),                 // 0: v2ray.core.app.router.command.RoutingContext
 	(*SubscribeRoutingStatsRequest)(nil),   // 1: v2ray.core.app.router.command.SubscribeRoutingStatsRequest
 	(*TestRouteRequest)(nil),               // 2: v2ray.core.app.router.command.TestRouteRequest
-	(*PrincipleTargetInfo)(nil),            // 3: v2ray.core.app.router.command.PrincipleTargetInfo
-	(*OverrideInfo)(nil),                   // 4: v2ray.core.app.router.command.OverrideInfo
-	(*BalancerMsg)(nil),                    // 5: v2ray.core.app.router.command.BalancerMsg
-	(*GetBalancerInfoRequest)(nil),         // 6: v2ray.core.app.router.command.GetBalancerInfoRequest
-	(*GetBalancerInfoResponse)(nil),        // 7: v2ray.core.app.router.command.GetBalancerInfoResponse
-	(*OverrideBalancerTargetRequest)(nil),  // 8: v2ray.core.app.router.command.OverrideBalancerTargetRequest
-	(*OverrideBalancerTargetResponse)(nil), // 9: v2ray.core.app.router.command.OverrideBalancerTargetResponse
-	(*Config)(nil),                         // 10: v2ray.core.app.router.command.Config
-	nil,                                    // 11: v2ray.core.app.router.command.RoutingContext.AttributesEntry
-	(net.Network)(0),                       // 12: v2ray.core.common.net.Network
+	(*ExplainRouteRequest)(nil),            // 3: v2ray.core.app.router.command.ExplainRouteRequest
+	(*RuleTrace)(nil),                      // 4: v2ray.core.app.router.command.RuleTrace
+	(*ExplainRouteResponse)(nil),           // 5: v2ray.core.app.router.command.ExplainRouteResponse
+	(*PrincipleTargetInfo)(nil),            // 6: v2ray.core.app.router.command.PrincipleTargetInfo
+	(*OverrideInfo)(nil),                   // 7: v2ray.core.app.router.command.OverrideInfo
+	(*BalancerMsg)(nil),                    // 8: v2ray.core.app.router.command.BalancerMsg
+	(*GetBalancerInfoRequest)(nil),         // 9: v2ray.core.app.router.command.GetBalancerInfoRequest
+	(*GetBalancerInfoResponse)(nil),        // 10: v2ray.core.app.router.command.GetBalancerInfoResponse
+	(*OverrideBalancerTargetRequest)(nil),  // 11: v2ray.core.app.router.command.OverrideBalancerTargetRequest
+	(*OverrideBalancerTargetResponse)(nil), // 12: v2ray.core.app.router.command.OverrideBalancerTargetResponse
+	(*AddRuleRequest)(nil),                 // 13: v2ray.core.app.router.command.AddRuleRequest
+	(*AddRuleResponse)(nil),                // 14: v2ray.core.app.router.command.AddRuleResponse
+	(*RemoveRuleRequest)(nil),              // 15: v2ray.core.app.router.command.RemoveRuleRequest
+	(*RemoveRuleResponse)(nil),             // 16: v2ray.core.app.router.command.RemoveRuleResponse
+	(*ReplaceRulesRequest)(nil),            // 17: v2ray.core.app.router.command.ReplaceRulesRequest
+	(*ReplaceRulesResponse)(nil),           // 18: v2ray.core.app.router.command.ReplaceRulesResponse
+	(*GetRuleStatsRequest)(nil),            // 19: v2ray.core.app.router.command.GetRuleStatsRequest
+	(*RuleStats)(nil),                      // 20: v2ray.core.app.router.command.RuleStats
+	(*GetRuleStatsResponse)(nil),           // 21: v2ray.core.app.router.command.GetRuleStatsResponse
+	(*Config)(nil),                         // 22: v2ray.core.app.router.command.Config
+	nil,                                    // 23: v2ray.core.app.router.command.RoutingContext.AttributesEntry
+	(net.Network)(0),                       // 24: v2ray.core.common.net.Network
+	(*router.Config)(nil),                  // 25: v2ray.core.app.router.Config
 }
 var file_app_router_command_command_proto_depIdxs = []int32{
-	12, // 0: v2ray.core.app.router.command.RoutingContext.Network:type_name -> v2ray.core.common.net.Network
-	11, // 1: v2ray.core.app.router.command.RoutingContext.Attributes:type_name -> v2ray.core.app.router.command.RoutingContext.AttributesEntry
+	24, // 0: v2ray.core.app.router.command.RoutingContext.Network:type_name -> v2ray.core.common.net.Network
+	23, // 1: v2ray.core.app.router.command.RoutingContext.Attributes:type_name -> v2ray.core.app.router.command.RoutingContext.AttributesEntry
 	0,  // 2: v2ray.core.app.router.command.TestRouteRequest.RoutingContext:type_name -> v2ray.core.app.router.command.RoutingContext
-	4,  // 3: v2ray.core.app.router.command.BalancerMsg.override:type_name -> v2ray.core.app.router.command.OverrideInfo
-	3,  // 4: v2ray.core.app.router.command.BalancerMsg.principle_target:type_name -> v2ray.core.app.router.command.PrincipleTargetInfo
-	5,  // 5: v2ray.core.app.router.command.GetBalancerInfoResponse.balancer:type_name -> v2ray.core.app.router.command.BalancerMsg
-	1,  // 6: v2ray.core.app.router.command.RoutingService.SubscribeRoutingStats:input_type -> v2ray.core.app.router.command.SubscribeRoutingStatsRequest
-	2,  // 7: v2ray.core.app.router.command.RoutingService.TestRoute:input_type -> v2ray.core.app.router.command.TestRouteRequest
-	6,  // 8: v2ray.core.app.router.command.RoutingService.GetBalancerInfo:input_type -> v2ray.core.app.router.command.GetBalancerInfoRequest
-	8,  // 9: v2ray.core.app.router.command.RoutingService.OverrideBalancerTarget:input_type -> v2ray.core.app.router.command.OverrideBalancerTargetRequest
-	0,  // 10: v2ray.core.app.router.command.RoutingService.SubscribeRoutingStats:output_type -> v2ray.core.app.router.command.RoutingContext
-	0,  // 11: v2ray.core.app.router.command.RoutingService.TestRoute:output_type -> v2ray.core.app.router.command.RoutingContext
-	7,  // 12: v2ray.core.app.router.command.RoutingService.GetBalancerInfo:output_type -> v2ray.core.app.router.command.GetBalancerInfoResponse
-	9,  // 13: v2ray.core.app.router.command.RoutingService.OverrideBalancerTarget:output_type -> v2ray.core.app.router.command.OverrideBalancerTargetResponse
-	10, // [10:14] is the sub-list for method output_type
-	6,  // [6:10] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	0,  // 3: v2ray.core.app.router.command.ExplainRouteRequest.RoutingContext:type_name -> v2ray.core.app.router.command.RoutingContext
+	0,  // 4: v2ray.core.app.router.command.ExplainRouteResponse.result:type_name -> v2ray.core.app.router.command.RoutingContext
+	4,  // 5: v2ray.core.app.router.command.ExplainRouteResponse.trace:type_name -> v2ray.core.app.router.command.RuleTrace
+	7,  // 6: v2ray.core.app.router.command.BalancerMsg.override:type_name -> v2ray.core.app.router.command.OverrideInfo
+	6,  // 7: v2ray.core.app.router.command.BalancerMsg.principle_target:type_name -> v2ray.core.app.router.command.PrincipleTargetInfo
+	8,  // 8: v2ray.core.app.router.command.GetBalancerInfoResponse.balancer:type_name -> v2ray.core.app.router.command.BalancerMsg
+	25, // 9: v2ray.core.app.router.command.AddRuleRequest.config:type_name -> v2ray.core.app.router.Config
+	25, // 10: v2ray.core.app.router.command.ReplaceRulesRequest.config:type_name -> v2ray.core.app.router.Config
+	20, // 11: v2ray.core.app.router.command.GetRuleStatsResponse.stats:type_name -> v2ray.core.app.router.command.RuleStats
+	1,  // 12: v2ray.core.app.router.command.RoutingService.SubscribeRoutingStats:input_type -> v2ray.core.app.router.command.SubscribeRoutingStatsRequest
+	2,  // 13: v2ray.core.app.router.command.RoutingService.TestRoute:input_type -> v2ray.core.app.router.command.TestRouteRequest
+	3,  // 14: v2ray.core.app.router.command.RoutingService.ExplainRoute:input_type -> v2ray.core.app.router.command.ExplainRouteRequest
+	9,  // 15: v2ray.core.app.router.command.RoutingService.GetBalancerInfo:input_type -> v2ray.core.app.router.command.GetBalancerInfoRequest
+	11, // 16: v2ray.core.app.router.command.RoutingService.OverrideBalancerTarget:input_type -> v2ray.core.app.router.command.OverrideBalancerTargetRequest
+	13, // 17: v2ray.core.app.router.command.RoutingService.AddRule:input_type -> v2ray.core.app.router.command.AddRuleRequest
+	15, // 18: v2ray.core.app.router.command.RoutingService.RemoveRule:input_type -> v2ray.core.app.router.command.RemoveRuleRequest
+	17, // 19: v2ray.core.app.router.command.RoutingService.ReplaceRules:input_type -> v2ray.core.app.router.command.ReplaceRulesRequest
+	19, // 20: v2ray.core.app.router.command.RoutingService.GetRuleStats:input_type -> v2ray.core.app.router.command.GetRuleStatsRequest
+	0,  // 21: v2ray.core.app.router.command.RoutingService.SubscribeRoutingStats:output_type -> v2ray.core.app.router.command.RoutingContext
+	0,  // 22: v2ray.core.app.router.command.RoutingService.TestRoute:output_type -> v2ray.core.app.router.command.RoutingContext
+	5,  // 23: v2ray.core.app.router.command.RoutingService.ExplainRoute:output_type -> v2ray.core.app.router.command.ExplainRouteResponse
+	10, // 24: v2ray.core.app.router.command.RoutingService.GetBalancerInfo:output_type -> v2ray.core.app.router.command.GetBalancerInfoResponse
+	12, // 25: v2ray.core.app.router.command.RoutingService.OverrideBalancerTarget:output_type -> v2ray.core.app.router.command.OverrideBalancerTargetResponse
+	14, // 26: v2ray.core.app.router.command.RoutingService.AddRule:output_type -> v2ray.core.app.router.command.AddRuleResponse
+	16, // 27: v2ray.core.app.router.command.RoutingService.RemoveRule:output_type -> v2ray.core.app.router.command.RemoveRuleResponse
+	18, // 28: v2ray.core.app.router.command.RoutingService.ReplaceRules:output_type -> v2ray.core.app.router.command.ReplaceRulesResponse
+	21, // 29: v2ray.core.app.router.command.RoutingService.GetRuleStats:output_type -> v2ray.core.app.router.command.GetRuleStatsResponse
+	21, // [21:30] is the sub-list for method output_type
+	12, // [12:21] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_app_router_command_command_proto_init() }
@@ -754,7 +1485,7 @@ func file_app_router_command_command_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_router_command_command_proto_rawDesc), len(file_app_router_command_command_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
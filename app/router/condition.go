@@ -1,6 +1,7 @@
 package router
 
 import (
+	"fmt"
 	"strings"
 
 	"go.starlark.net/starlark"
@@ -16,6 +17,23 @@ type Condition interface {
 	Apply(ctx routing.Context) bool
 }
 
+// ConditionExplainer is a Condition that can describe why it does not match a routing context.
+type ConditionExplainer interface {
+	// Explain returns the reason why the condition does not match ctx.
+	Explain(ctx routing.Context) string
+}
+
+// ExplainCondition checks cond against ctx, and returns the reason if it does not match.
+func ExplainCondition(cond Condition, ctx routing.Context) (bool, string) {
+	if cond.Apply(ctx) {
+		return true, ""
+	}
+	if explainer, ok := cond.(ConditionExplainer); ok {
+		return false, explainer.Explain(ctx)
+	}
+	return false, fmt.Sprintf("%T: no match", cond)
+}
+
 type ConditionChan []Condition
 
 func NewConditionChan() *ConditionChan {
@@ -38,6 +56,16 @@ func (v *ConditionChan) Apply(ctx routing.Context) bool {
 	return true
 }
 
+// Explain implements ConditionExplainer, returning the reason of the first condition that does not match.
+func (v *ConditionChan) Explain(ctx routing.Context) string {
+	for _, cond := range *v {
+		if matched, reason := ExplainCondition(cond, ctx); !matched {
+			return reason
+		}
+	}
+	return ""
+}
+
 func (v *ConditionChan) Len() int {
 	return len(*v)
 }
@@ -103,6 +131,15 @@ func (m *DomainMatcher) Apply(ctx routing.Context) bool {
 	return m.Match(strings.ToLower(domain))
 }
 
+// Explain implements ConditionExplainer.
+func (m *DomainMatcher) Explain(ctx routing.Context) string {
+	domain := ctx.GetTargetDomain()
+	if len(domain) == 0 {
+		return "domain matcher: no target domain"
+	}
+	return "domain matcher: no match for " + domain
+}
+
 type MultiGeoIPMatcher struct {
 	matchers []*GeoIPMatcher
 	onSource bool
@@ -144,6 +181,35 @@ func (m *MultiGeoIPMatcher) Apply(ctx routing.Context) bool {
 	return false
 }
 
+// Explain implements ConditionExplainer.
+func (m *MultiGeoIPMatcher) Explain(ctx routing.Context) string {
+	name := "geoip"
+	ips := ctx.GetTargetIPs()
+	if m.onSource {
+		name = "source geoip"
+		ips = ctx.GetSourceIPs()
+	}
+	if len(ips) == 0 {
+		return name + ": no IP"
+	}
+	ipStrings := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		ipStrings = append(ipStrings, ip.String())
+	}
+	codes := make([]string, 0, len(m.matchers))
+	for _, matcher := range m.matchers {
+		code := strings.ToLower(matcher.countryCode)
+		if code == "" {
+			code = "custom"
+		}
+		if matcher.reverseMatch {
+			code = "!" + code
+		}
+		codes = append(codes, code)
+	}
+	return name + ": " + strings.Join(ipStrings, ",") + " not in " + strings.Join(codes, ",")
+}
+
 type PortMatcher struct {
 	port     net.MemoryPortList
 	onSource bool
@@ -165,6 +231,14 @@ func (v *PortMatcher) Apply(ctx routing.Context) bool {
 	return v.port.Contains(ctx.GetTargetPort())
 }
 
+// Explain implements ConditionExplainer.
+func (v *PortMatcher) Explain(ctx routing.Context) string {
+	if v.onSource {
+		return "source port: " + ctx.GetSourcePort().String() + " not in list"
+	}
+	return "port: " + ctx.GetTargetPort().String() + " not in list"
+}
+
 type NetworkMatcher struct {
 	list [8]bool
 }
@@ -182,6 +256,11 @@ func (v NetworkMatcher) Apply(ctx routing.Context) bool {
 	return v.list[int(ctx.GetNetwork())]
 }
 
+// Explain implements ConditionExplainer.
+func (v NetworkMatcher) Explain(ctx routing.Context) string {
+	return "network: " + ctx.GetNetwork().SystemString() + " not in list"
+}
+
 type UserMatcher struct {
 	user []string
 }
@@ -212,6 +291,15 @@ func (v *UserMatcher) Apply(ctx routing.Context) bool {
 	return false
 }
 
+// Explain implements ConditionExplainer.
+func (v *UserMatcher) Explain(ctx routing.Context) string {
+	user := ctx.GetUser()
+	if len(user) == 0 {
+		return "user: no user"
+	}
+	return "user: " + user + " not in list"
+}
+
 type InboundTagMatcher struct {
 	tags []string
 }
@@ -242,6 +330,15 @@ func (v *InboundTagMatcher) Apply(ctx routing.Context) bool {
 	return false
 }
 
+// Explain implements ConditionExplainer.
+func (v *InboundTagMatcher) Explain(ctx routing.Context) string {
+	tag := ctx.GetInboundTag()
+	if len(tag) == 0 {
+		return "inbound tag: no inbound tag"
+	}
+	return "inbound tag: " + tag + " not in list"
+}
+
 type ProtocolMatcher struct {
 	protocols []string
 }
@@ -274,6 +371,15 @@ func (m *ProtocolMatcher) Apply(ctx routing.Context) bool {
 	return false
 }
 
+// Explain implements ConditionExplainer.
+func (m *ProtocolMatcher) Explain(ctx routing.Context) string {
+	protocol := ctx.GetProtocol()
+	if len(protocol) == 0 {
+		return "protocol: no sniffed protocol"
+	}
+	return "protocol: " + protocol + " not in " + strings.Join(m.protocols, ",")
+}
+
 type AttributeMatcher struct {
 	program *starlark.Program
 }
@@ -323,3 +429,11 @@ func (m *AttributeMatcher) Apply(ctx routing.Context) bool {
 	}
 	return m.Match(attributes)
 }
+
+// Explain implements ConditionExplainer.
+func (m *AttributeMatcher) Explain(ctx routing.Context) string {
+	if ctx.GetAttributes() == nil {
+		return "attributes: no attributes"
+	}
+	return "attributes: expression not satisfied"
+}
@@ -0,0 +1,91 @@
+package router
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+// HTTPMethodMatcher matches the method of HTTP requests.
+type HTTPMethodMatcher struct {
+	methods []string
+}
+
+func NewHTTPMethodMatcher(methods []string) *HTTPMethodMatcher {
+	m := &HTTPMethodMatcher{}
+	for _, method := range methods {
+		m.methods = append(m.methods, strings.ToUpper(method))
+	}
+	return m
+}
+
+// Apply implements Condition.
+func (m *HTTPMethodMatcher) Apply(ctx routing.Context) bool {
+	method := ctx.GetAttributes()[":method"]
+	for _, v := range m.methods {
+		if method == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Explain implements ConditionExplainer.
+func (m *HTTPMethodMatcher) Explain(ctx routing.Context) string {
+	method, found := ctx.GetAttributes()[":method"]
+	if !found {
+		return "http method: no http request"
+	}
+	return "http method: " + method + " not in " + strings.Join(m.methods, ",")
+}
+
+// HTTPPathMatcher matches the path of HTTP requests by prefix or regular expression.
+type HTTPPathMatcher struct {
+	prefixes []string
+	patterns []*regexp.Regexp
+}
+
+func NewHTTPPathMatcher(paths []string) (*HTTPPathMatcher, error) {
+	m := &HTTPPathMatcher{}
+	for _, path := range paths {
+		if pattern, found := strings.CutPrefix(path, "regexp:"); found {
+			r, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, newError("invalid http path regexp: ", pattern).Base(err)
+			}
+			m.patterns = append(m.patterns, r)
+			continue
+		}
+		m.prefixes = append(m.prefixes, path)
+	}
+	return m, nil
+}
+
+// Apply implements Condition.
+func (m *HTTPPathMatcher) Apply(ctx routing.Context) bool {
+	path, found := ctx.GetAttributes()[":path"]
+	if !found {
+		return false
+	}
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	for _, pattern := range m.patterns {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Explain implements ConditionExplainer.
+func (m *HTTPPathMatcher) Explain(ctx routing.Context) string {
+	path, found := ctx.GetAttributes()[":path"]
+	if !found {
+		return "http path: no http request"
+	}
+	return "http path: " + path + " not matched"
+}
@@ -0,0 +1,228 @@
+package router
+
+import (
+	"strings"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+const defaultScriptMaxSteps = 100000
+
+// ScriptMatcher matches routing contexts with a Starlark script. The script is either an expression on ctx,
+// or a program defining function match(ctx). Functions geoip(ip, code) and geosite(domain, code) check membership
+// of the GeoIPs and GeoSites provided to the matcher.
+type ScriptMatcher struct {
+	match    starlark.Callable
+	maxSteps uint64
+	geoips   map[string]*GeoIPMatcher
+	geosites map[string]*DomainMatcher
+}
+
+// NewScriptMatcher compiles the script and creates a ScriptMatcher. Each evaluation of the script is limited to
+// maxSteps execution steps, or a default limit if maxSteps is 0.
+func NewScriptMatcher(code string, maxSteps uint64, geoips []*routercommon.GeoIP, geosites []*routercommon.GeoSite) (*ScriptMatcher, error) {
+	if maxSteps == 0 {
+		maxSteps = defaultScriptMaxSteps
+	}
+	m := &ScriptMatcher{
+		maxSteps: maxSteps,
+		geoips:   make(map[string]*GeoIPMatcher, len(geoips)),
+		geosites: make(map[string]*DomainMatcher, len(geosites)),
+	}
+	for _, geoip := range geoips {
+		matcher, err := globalGeoIPContainer.Add(geoip)
+		if err != nil {
+			return nil, err
+		}
+		m.geoips[strings.ToLower(geoipCode(geoip))] = matcher
+	}
+	for _, geosite := range geosites {
+		matcher, err := NewDomainMatcher("", geosite.Domain)
+		if err != nil {
+			return nil, newError("failed to build geosite ", geosite.CountryCode).Base(err)
+		}
+		code := geosite.CountryCode
+		if code == "" {
+			code = geosite.Code
+		}
+		m.geosites[strings.ToLower(code)] = matcher
+	}
+
+	options := syntax.LegacyFileOptions()
+	if _, err := options.ParseExpr("script.star", code, 0); err == nil {
+		code = "def match(ctx):\n    return (" + code + "\n    )\n"
+	}
+	predeclared := starlark.StringDict{
+		"geoip":   starlark.NewBuiltin("geoip", m.builtinGeoIP),
+		"geosite": starlark.NewBuiltin("geosite", m.builtinGeoSite),
+	}
+	thread := m.newThread()
+	globals, err := starlark.ExecFileOptions(options, thread, "script.star", code, predeclared)
+	if err != nil {
+		return nil, newError("failed to load script").Base(err)
+	}
+	globals.Freeze()
+	match, ok := globals["match"].(starlark.Callable)
+	if !ok {
+		return nil, newError("script is neither an expression nor defines function match(ctx)")
+	}
+	m.match = match
+	return m, nil
+}
+
+func geoipCode(geoip *routercommon.GeoIP) string {
+	if geoip.CountryCode != "" {
+		return geoip.CountryCode
+	}
+	return geoip.Code
+}
+
+func (m *ScriptMatcher) newThread() *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: "router script",
+	}
+	thread.SetMaxExecutionSteps(m.maxSteps)
+	return thread
+}
+
+func (m *ScriptMatcher) builtinGeoIP(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var ipString, code string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 2, &ipString, &code); err != nil {
+		return nil, err
+	}
+	matcher, found := m.geoips[strings.ToLower(code)]
+	if !found {
+		return nil, newError("geoip ", code, " is not provided to the script")
+	}
+	ip := net.ParseIP(ipString)
+	if ip == nil {
+		return starlark.False, nil
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return starlark.Bool(matcher.Match(ip)), nil
+}
+
+func (m *ScriptMatcher) builtinGeoSite(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var domain, code string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 2, &domain, &code); err != nil {
+		return nil, err
+	}
+	matcher, found := m.geosites[strings.ToLower(code)]
+	if !found {
+		return nil, newError("geosite ", code, " is not provided to the script")
+	}
+	return starlark.Bool(len(domain) > 0 && matcher.Match(strings.ToLower(domain))), nil
+}
+
+func (m *ScriptMatcher) evaluate(ctx routing.Context) (bool, error) {
+	result, err := starlark.Call(m.newThread(), m.match, starlark.Tuple{&scriptContext{ctx: ctx}}, nil)
+	if err != nil {
+		return false, err
+	}
+	return bool(result.Truth()), nil
+}
+
+// Apply implements Condition.
+func (m *ScriptMatcher) Apply(ctx routing.Context) bool {
+	matched, err := m.evaluate(ctx)
+	if err != nil {
+		newError("script matcher").Base(err).WriteToLog()
+	}
+	return matched
+}
+
+// Explain implements ConditionExplainer.
+func (m *ScriptMatcher) Explain(ctx routing.Context) string {
+	if _, err := m.evaluate(ctx); err != nil {
+		return "script: " + err.Error()
+	}
+	return "script: not satisfied"
+}
+
+// scriptContext exposes routing.Context to scripts. Attributes are read lazily, so that target IPs are only
+// resolved when the script accesses them.
+type scriptContext struct {
+	ctx routing.Context
+}
+
+var scriptContextAttrNames = []string{
+	"attrs", "domain", "inbound_tag", "network", "protocol",
+	"source_ips", "source_port", "target_ips", "target_port", "user",
+}
+
+func ipsToStarlark(ips []net.IP) *starlark.List {
+	values := make([]starlark.Value, 0, len(ips))
+	for _, ip := range ips {
+		values = append(values, starlark.String(ip.String()))
+	}
+	list := starlark.NewList(values)
+	list.Freeze()
+	return list
+}
+
+// Attr implements starlark.HasAttrs.
+func (c *scriptContext) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "attrs":
+		attrs := new(starlark.Dict)
+		for key, value := range c.ctx.GetAttributes() {
+			attrs.SetKey(starlark.String(key), starlark.String(value))
+		}
+		attrs.Freeze()
+		return attrs, nil
+	case "domain":
+		return starlark.String(c.ctx.GetTargetDomain()), nil
+	case "inbound_tag":
+		return starlark.String(c.ctx.GetInboundTag()), nil
+	case "network":
+		return starlark.String(c.ctx.GetNetwork().SystemString()), nil
+	case "protocol":
+		return starlark.String(c.ctx.GetProtocol()), nil
+	case "source_ips":
+		return ipsToStarlark(c.ctx.GetSourceIPs()), nil
+	case "source_port":
+		return starlark.MakeInt(int(c.ctx.GetSourcePort())), nil
+	case "target_ips":
+		return ipsToStarlark(c.ctx.GetTargetIPs()), nil
+	case "target_port":
+		return starlark.MakeInt(int(c.ctx.GetTargetPort())), nil
+	case "user":
+		return starlark.String(c.ctx.GetUser()), nil
+	}
+	return nil, nil
+}
+
+// AttrNames implements starlark.HasAttrs.
+func (c *scriptContext) AttrNames() []string {
+	return scriptContextAttrNames
+}
+
+// String implements starlark.Value.
+func (c *scriptContext) String() string {
+	return "routing_context"
+}
+
+// Type implements starlark.Value.
+func (c *scriptContext) Type() string {
+	return "routing_context"
+}
+
+// Freeze implements starlark.Value.
+func (c *scriptContext) Freeze() {}
+
+// Truth implements starlark.Value.
+func (c *scriptContext) Truth() starlark.Bool {
+	return starlark.True
+}
+
+// Hash implements starlark.Value.
+func (c *scriptContext) Hash() (uint32, error) {
+	return 0, newError("unhashable type: routing_context")
+}
@@ -0,0 +1,108 @@
+package router_test
+
+import (
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+	routing_session "github.com/v2fly/v2ray-core/v5/features/routing/session"
+)
+
+func TestScriptMatcher(t *testing.T) {
+	geoips := []*routercommon.GeoIP{
+		{
+			CountryCode: "PRIVATE",
+			Cidr: []*routercommon.CIDR{
+				{Ip: []byte{10, 0, 0, 0}, Prefix: 8},
+			},
+		},
+	}
+	geosites := []*routercommon.GeoSite{
+		{
+			CountryCode: "ads",
+			Domain: []*routercommon.Domain{
+				{Type: routercommon.Domain_RootDomain, Value: "ads.example.com"},
+			},
+		},
+	}
+	newContext := func(domain string, port net.Port, source net.Address, user string) routing.Context {
+		return &routing_session.Context{
+			Inbound: &session.Inbound{
+				Source: net.TCPDestination(source, 12345),
+				Tag:    "in",
+				User:   &protocol.MemoryUser{Email: user},
+			},
+			Outbound: &session.Outbound{
+				Target: net.TCPDestination(net.DomainAddress(domain), port),
+			},
+		}
+	}
+
+	cases := []struct {
+		script string
+		input  routing.Context
+		output bool
+	}{
+		{
+			script: `ctx.domain.endswith(".v2fly.org") and ctx.target_port == 443`,
+			input:  newContext("www.v2fly.org", 443, net.LocalHostIP, ""),
+			output: true,
+		},
+		{
+			script: `ctx.domain.endswith(".v2fly.org") and ctx.target_port == 443`,
+			input:  newContext("www.v2fly.org", 80, net.LocalHostIP, ""),
+			output: false,
+		},
+		{
+			script: "def match(ctx):\n    return ctx.user in [\"a@v2fly.org\", \"b@v2fly.org\"] and ctx.inbound_tag == \"in\"\n",
+			input:  newContext("www.v2fly.org", 443, net.LocalHostIP, "b@v2fly.org"),
+			output: true,
+		},
+		{
+			script: `any([geoip(ip, "private") for ip in ctx.source_ips])`,
+			input:  newContext("www.v2fly.org", 443, net.ParseAddress("10.1.2.3"), ""),
+			output: true,
+		},
+		{
+			script: `any([geoip(ip, "private") for ip in ctx.source_ips])`,
+			input:  newContext("www.v2fly.org", 443, net.ParseAddress("192.168.1.1"), ""),
+			output: false,
+		},
+		{
+			script: `geosite(ctx.domain, "ads") and ctx.network == "tcp"`,
+			input:  newContext("img.ads.example.com", 443, net.LocalHostIP, ""),
+			output: true,
+		},
+		{
+			script: `geosite(ctx.domain, "unknown")`,
+			input:  newContext("img.ads.example.com", 443, net.LocalHostIP, ""),
+			output: false,
+		},
+	}
+	for _, tc := range cases {
+		matcher, err := router.NewScriptMatcher(tc.script, 0, geoips, geosites)
+		common.Must(err)
+		if v := matcher.Apply(tc.input); v != tc.output {
+			t.Error("for script ", tc.script, " expect ", tc.output, " but got ", v)
+		}
+	}
+}
+
+func TestScriptMatcherLimits(t *testing.T) {
+	matcher, err := router.NewScriptMatcher("def match(ctx):\n    for i in range(1000000):\n        pass\n    return True\n", 1000, nil, nil)
+	common.Must(err)
+	if matcher.Apply(withBackground()) {
+		t.Error("expect script exceeding step budget not to match")
+	}
+
+	for _, script := range []string{"ctx.domain ==", "x = 1\n"} {
+		if _, err := router.NewScriptMatcher(script, 0, nil, nil); err == nil {
+			t.Error("expect error for script ", script)
+		}
+	}
+}
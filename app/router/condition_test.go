@@ -299,6 +299,77 @@ func TestRoutingRule(t *testing.T) {
 				},
 			},
 		},
+		{
+			rule: &router.RoutingRule{
+				HttpMethod: []string{"get", "HEAD"},
+				HttpPath:   []string{"/api/", "regexp:^/static/.*\\.js$"},
+			},
+			test: []ruleTest{
+				{
+					input:  withContent(&session.Content{Protocol: "http1", Attributes: map[string]string{":method": "GET", ":path": "/api/v1"}}),
+					output: true,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "http1", Attributes: map[string]string{":method": "HEAD", ":path": "/static/a/b.js"}}),
+					output: true,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "http1", Attributes: map[string]string{":method": "GET", ":path": "/static/a.css"}}),
+					output: false,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "http1", Attributes: map[string]string{":method": "POST", ":path": "/api/v1"}}),
+					output: false,
+				},
+			},
+		},
+		{
+			rule: &router.RoutingRule{
+				TlsAlpn: []string{"=h2", "spdy/3"},
+			},
+			test: []ruleTest{
+				{
+					input:  withContent(&session.Content{Protocol: "tls", Attributes: map[string]string{"tls.alpn": "h2"}}),
+					output: true,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "tls", Attributes: map[string]string{"tls.alpn": "h2,http/1.1"}}),
+					output: false,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "tls", Attributes: map[string]string{"tls.alpn": "spdy/3,http/1.1"}}),
+					output: true,
+				},
+				{
+					input:  withBackground(),
+					output: false,
+				},
+			},
+		},
+		{
+			rule: &router.RoutingRule{
+				TlsVersion:     []string{"1.3"},
+				TlsFingerprint: []string{"t13d1516h2_8daaf6152771_b0da82dd1658", "B8F81673C0E1D29908346F3BAB892B9B"},
+			},
+			test: []ruleTest{
+				{
+					input:  withContent(&session.Content{Protocol: "tls", Attributes: map[string]string{"tls.versions": "1.3,1.2", "tls.ja3": "0", "tls.ja4": "t13d1516h2_8daaf6152771_b0da82dd1658"}}),
+					output: true,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "tls", Attributes: map[string]string{"tls.versions": "1.3", "tls.ja3": "b8f81673c0e1d29908346f3bab892b9b", "tls.ja4": "0"}}),
+					output: true,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "tls", Attributes: map[string]string{"tls.versions": "1.2", "tls.ja3": "b8f81673c0e1d29908346f3bab892b9b", "tls.ja4": "0"}}),
+					output: false,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "tls", Attributes: map[string]string{"tls.versions": "1.3", "tls.ja3": "0", "tls.ja4": "0"}}),
+					output: false,
+				},
+			},
+		},
 	}
 
 	for _, test := range cases {
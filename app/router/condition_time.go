@@ -0,0 +1,184 @@
+package router
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+const minutesPerDay = 24 * 60
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseTimeWindow parses a time window such as "Mon-Fri 09:00-18:00", "Sat,Sun" or "22:00-06:00".
+func ParseTimeWindow(s string) (*TimeWindow, error) {
+	window := &TimeWindow{EndMinute: minutesPerDay}
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, newError("invalid time window: ", s)
+	}
+	for _, field := range fields {
+		if strings.Contains(field, ":") {
+			start, end, found := strings.Cut(field, "-")
+			if !found {
+				return nil, newError("invalid time range: ", field)
+			}
+			var err error
+			if window.StartMinute, err = parseClock(start); err != nil {
+				return nil, err
+			}
+			if window.EndMinute, err = parseClock(end); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		weekdays, err := parseWeekdays(field)
+		if err != nil {
+			return nil, err
+		}
+		window.Weekday = append(window.Weekday, weekdays...)
+	}
+	return window, nil
+}
+
+func parseClock(s string) (uint32, error) {
+	hour, minute, found := strings.Cut(s, ":")
+	if !found {
+		return 0, newError("invalid time: ", s)
+	}
+	h, err := strconv.ParseUint(hour, 10, 32)
+	if err != nil {
+		return 0, newError("invalid time: ", s).Base(err)
+	}
+	m, err := strconv.ParseUint(minute, 10, 32)
+	if err != nil {
+		return 0, newError("invalid time: ", s).Base(err)
+	}
+	if m >= 60 || h*60+m > minutesPerDay {
+		return 0, newError("invalid time: ", s)
+	}
+	return uint32(h*60 + m), nil
+}
+
+func parseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(s)
+	if len(s) >= 3 {
+		if weekday, found := weekdayNames[s[:3]]; found {
+			return weekday, nil
+		}
+	}
+	return 0, newError("invalid weekday: ", s)
+}
+
+func parseWeekdays(s string) ([]uint32, error) {
+	var weekdays []uint32
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := parseWeekday(from)
+		if err != nil {
+			return nil, err
+		}
+		if !isRange {
+			weekdays = append(weekdays, uint32(first))
+			continue
+		}
+		last, err := parseWeekday(to)
+		if err != nil {
+			return nil, err
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			weekdays = append(weekdays, uint32(day))
+			if day == last {
+				break
+			}
+		}
+	}
+	return weekdays, nil
+}
+
+type timeWindow struct {
+	weekdays [7]bool
+	start    uint32
+	end      uint32
+}
+
+func (w *timeWindow) contains(weekday time.Weekday, minute uint32) bool {
+	if w.start < w.end {
+		return w.weekdays[weekday] && minute >= w.start && minute < w.end
+	}
+	// The window wraps around midnight into the next day.
+	if minute >= w.start {
+		return w.weekdays[weekday]
+	}
+	return minute < w.end && w.weekdays[(weekday+6)%7]
+}
+
+type TimeMatcher struct {
+	windows  []timeWindow
+	location *time.Location
+}
+
+// NewTimeMatcher creates a matcher for the given windows, evaluated in the IANA time zone named by zone.
+func NewTimeMatcher(windows []*TimeWindow, zone string) (*TimeMatcher, error) {
+	location := time.Local
+	if zone != "" {
+		var err error
+		if location, err = time.LoadLocation(zone); err != nil {
+			return nil, newError("invalid time zone: ", zone).Base(err)
+		}
+	}
+	matcher := &TimeMatcher{location: location}
+	for _, window := range windows {
+		if window.StartMinute > minutesPerDay || window.EndMinute > minutesPerDay {
+			return nil, newError("invalid time window: ", window.StartMinute, "-", window.EndMinute)
+		}
+		w := timeWindow{
+			start: window.StartMinute,
+			end:   window.EndMinute,
+		}
+		if len(window.Weekday) == 0 {
+			w.weekdays = [7]bool{true, true, true, true, true, true, true}
+		}
+		for _, day := range window.Weekday {
+			if day > 6 {
+				return nil, newError("invalid weekday: ", day)
+			}
+			w.weekdays[day] = true
+		}
+		matcher.windows = append(matcher.windows, w)
+	}
+	return matcher, nil
+}
+
+// Match returns true if t falls into any of the windows.
+func (m *TimeMatcher) Match(t time.Time) bool {
+	t = t.In(m.location)
+	weekday := t.Weekday()
+	minute := uint32(t.Hour()*60 + t.Minute())
+	for i := range m.windows {
+		if m.windows[i].contains(weekday, minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply implements Condition.
+func (m *TimeMatcher) Apply(ctx routing.Context) bool {
+	return m.Match(time.Now())
+}
+
+// Explain implements ConditionExplainer.
+func (m *TimeMatcher) Explain(ctx routing.Context) string {
+	return "time: " + time.Now().In(m.location).Format("Mon 15:04 MST") + " not in time windows"
+}
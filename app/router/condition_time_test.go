@@ -0,0 +1,87 @@
+package router_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/common"
+)
+
+func TestParseTimeWindow(t *testing.T) {
+	cases := []struct {
+		input  string
+		output *router.TimeWindow
+		err    bool
+	}{
+		{
+			input:  "Mon-Fri 09:00-18:00",
+			output: &router.TimeWindow{Weekday: []uint32{1, 2, 3, 4, 5}, StartMinute: 540, EndMinute: 1080},
+		},
+		{
+			input:  "sat,Sunday",
+			output: &router.TimeWindow{Weekday: []uint32{6, 0}, StartMinute: 0, EndMinute: 1440},
+		},
+		{
+			input:  "Fri-Mon",
+			output: &router.TimeWindow{Weekday: []uint32{5, 6, 0, 1}, StartMinute: 0, EndMinute: 1440},
+		},
+		{
+			input:  "22:30-06:00",
+			output: &router.TimeWindow{StartMinute: 1350, EndMinute: 360},
+		},
+		{input: "", err: true},
+		{input: "Mon 25:00-26:00", err: true},
+		{input: "Foo 09:00-10:00", err: true},
+		{input: "09:00", err: true},
+	}
+	for _, tc := range cases {
+		window, err := router.ParseTimeWindow(tc.input)
+		if tc.err {
+			if err == nil {
+				t.Error("expect error for ", tc.input)
+			}
+			continue
+		}
+		common.Must(err)
+		if r := cmp.Diff(window, tc.output, protocmp.Transform()); r != "" {
+			t.Error("unexpected window for ", tc.input, ": ", r)
+		}
+	}
+}
+
+func TestTimeMatcher(t *testing.T) {
+	officeHours := common.Must2(router.ParseTimeWindow("Mon-Fri 09:00-18:00")).(*router.TimeWindow)
+	fridayNight := common.Must2(router.ParseTimeWindow("Fri 22:00-02:00")).(*router.TimeWindow)
+	matcher, err := router.NewTimeMatcher([]*router.TimeWindow{officeHours, fridayNight}, "UTC")
+	common.Must(err)
+
+	cases := []struct {
+		time   string
+		output bool
+	}{
+		{"2024-06-03T09:00:00Z", true},  // Monday
+		{"2024-06-03T08:59:00Z", false}, // Monday
+		{"2024-06-07T17:59:00Z", true},  // Friday
+		{"2024-06-07T18:00:00Z", false}, // Friday
+		{"2024-06-08T12:00:00Z", false}, // Saturday
+		{"2024-06-07T23:00:00Z", true},  // Friday
+		{"2024-06-08T01:59:00Z", true},  // Saturday, continued from Friday
+		{"2024-06-09T01:00:00Z", false}, // Sunday
+		{"2024-06-03T02:00:00+08:00", false},
+	}
+	for _, tc := range cases {
+		now, err := time.Parse(time.RFC3339, tc.time)
+		common.Must(err)
+		if v := matcher.Match(now); v != tc.output {
+			t.Error("for ", tc.time, " expect ", tc.output, " but got ", v)
+		}
+	}
+
+	if _, err := router.NewTimeMatcher([]*router.TimeWindow{officeHours}, "Invalid/Zone"); err == nil {
+		t.Error("expect error for invalid time zone")
+	}
+}
@@ -0,0 +1,127 @@
+package router
+
+import (
+	"strings"
+
+	"github.com/v2fly/v2ray-core/v5/common/protocol/tls"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+// listAttribute returns the comma separated values of a content attribute.
+func listAttribute(ctx routing.Context, key string) []string {
+	value := ctx.GetAttributes()[key]
+	if len(value) == 0 {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// ALPNMatcher matches the ALPN offered in TLS ClientHello.
+type ALPNMatcher struct {
+	offered []string
+	exact   []string
+}
+
+func NewALPNMatcher(alpn []string) *ALPNMatcher {
+	m := &ALPNMatcher{}
+	for _, a := range alpn {
+		if exact, found := strings.CutPrefix(a, "="); found {
+			m.exact = append(m.exact, exact)
+		} else if len(a) > 0 {
+			m.offered = append(m.offered, a)
+		}
+	}
+	return m
+}
+
+// Apply implements Condition.
+func (m *ALPNMatcher) Apply(ctx routing.Context) bool {
+	value := ctx.GetAttributes()[tls.AttributeALPN]
+	for _, exact := range m.exact {
+		if value == exact {
+			return true
+		}
+	}
+	for _, alpn := range listAttribute(ctx, tls.AttributeALPN) {
+		for _, offered := range m.offered {
+			if alpn == offered {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Explain implements ConditionExplainer.
+func (m *ALPNMatcher) Explain(ctx routing.Context) string {
+	value, found := ctx.GetAttributes()[tls.AttributeALPN]
+	if !found {
+		return "tls alpn: no sniffed client hello"
+	}
+	return "tls alpn: [" + value + "] not matched"
+}
+
+// TLSVersionMatcher matches the TLS versions supported by the client.
+type TLSVersionMatcher struct {
+	versions []string
+}
+
+func NewTLSVersionMatcher(versions []string) *TLSVersionMatcher {
+	return &TLSVersionMatcher{versions: versions}
+}
+
+// Apply implements Condition.
+func (m *TLSVersionMatcher) Apply(ctx routing.Context) bool {
+	for _, version := range listAttribute(ctx, tls.AttributeVersions) {
+		for _, v := range m.versions {
+			if version == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Explain implements ConditionExplainer.
+func (m *TLSVersionMatcher) Explain(ctx routing.Context) string {
+	value, found := ctx.GetAttributes()[tls.AttributeVersions]
+	if !found {
+		return "tls version: no sniffed client hello"
+	}
+	return "tls version: [" + value + "] not in " + strings.Join(m.versions, ",")
+}
+
+// TLSFingerprintMatcher matches the JA3 or JA4 fingerprint of TLS ClientHello.
+type TLSFingerprintMatcher struct {
+	fingerprints map[string]bool
+}
+
+func NewTLSFingerprintMatcher(fingerprints []string) *TLSFingerprintMatcher {
+	m := &TLSFingerprintMatcher{fingerprints: make(map[string]bool, len(fingerprints))}
+	for _, fingerprint := range fingerprints {
+		m.fingerprints[strings.ToLower(fingerprint)] = true
+	}
+	return m
+}
+
+// Apply implements Condition.
+func (m *TLSFingerprintMatcher) Apply(ctx routing.Context) bool {
+	attributes := ctx.GetAttributes()
+	if ja3, found := attributes[tls.AttributeJA3]; found && m.fingerprints[ja3] {
+		return true
+	}
+	if ja4, found := attributes[tls.AttributeJA4]; found && m.fingerprints[ja4] {
+		return true
+	}
+	return false
+}
+
+// Explain implements ConditionExplainer.
+func (m *TLSFingerprintMatcher) Explain(ctx routing.Context) string {
+	attributes := ctx.GetAttributes()
+	ja3, found := attributes[tls.AttributeJA3]
+	if !found {
+		return "tls fingerprint: no sniffed client hello"
+	}
+	return "tls fingerprint: " + ja3 + " and " + attributes[tls.AttributeJA4] + " not matched"
+}
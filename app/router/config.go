@@ -3,6 +3,8 @@ package router
 import (
 	"context"
 	"encoding/json"
+	"sync/atomic"
+	"time"
 
 	"github.com/golang/protobuf/jsonpb"
 
@@ -11,19 +13,25 @@ import (
 	"github.com/v2fly/v2ray-core/v5/common/serial"
 	"github.com/v2fly/v2ray-core/v5/features/outbound"
 	"github.com/v2fly/v2ray-core/v5/features/routing"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/v5cfg"
 )
 
 type Rule struct {
 	Tag           string
+	RuleTag       string
 	Balancer      *Balancer
 	Condition     Condition
 	SetAttributes []*SetAttribute
+
+	balancingTag string
+	hits         stats.Counter
+	lastMatch    int64
 }
 
-func (r *Rule) GetTag() (string, error) {
+func (r *Rule) GetTag(ctx routing.Context) (string, error) {
 	if r.Balancer != nil {
-		return r.Balancer.PickOutbound()
+		return r.Balancer.PickOutbound(ctx)
 	}
 	return r.Tag, nil
 }
@@ -33,6 +41,30 @@ func (r *Rule) Apply(ctx routing.Context) bool {
 	return r.Condition.Apply(ctx)
 }
 
+func (r *Rule) recordHit() {
+	if r.hits != nil {
+		r.hits.Add(1)
+	}
+	atomic.StoreInt64(&r.lastMatch, time.Now().Unix())
+}
+
+// hitCounter is a stats.Counter for rules not registered in stats manager.
+type hitCounter struct {
+	value int64
+}
+
+func (c *hitCounter) Value() int64 {
+	return atomic.LoadInt64(&c.value)
+}
+
+func (c *hitCounter) Set(newValue int64) int64 {
+	return atomic.SwapInt64(&c.value, newValue)
+}
+
+func (c *hitCounter) Add(delta int64) int64 {
+	return atomic.AddInt64(&c.value, delta)
+}
+
 func (rr *RoutingRule) BuildCondition() (Condition, error) {
 	conds := NewConditionChan()
 
@@ -120,6 +152,46 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 		conds.Add(cond)
 	}
 
+	if len(rr.Script) > 0 {
+		cond, err := NewScriptMatcher(rr.Script, rr.ScriptMaxSteps, rr.ScriptGeoip, rr.ScriptGeosite)
+		if err != nil {
+			return nil, err
+		}
+		conds.Add(cond)
+	}
+
+	if len(rr.TlsAlpn) > 0 {
+		conds.Add(NewALPNMatcher(rr.TlsAlpn))
+	}
+
+	if len(rr.TlsVersion) > 0 {
+		conds.Add(NewTLSVersionMatcher(rr.TlsVersion))
+	}
+
+	if len(rr.TlsFingerprint) > 0 {
+		conds.Add(NewTLSFingerprintMatcher(rr.TlsFingerprint))
+	}
+
+	if len(rr.HttpMethod) > 0 {
+		conds.Add(NewHTTPMethodMatcher(rr.HttpMethod))
+	}
+
+	if len(rr.HttpPath) > 0 {
+		cond, err := NewHTTPPathMatcher(rr.HttpPath)
+		if err != nil {
+			return nil, err
+		}
+		conds.Add(cond)
+	}
+
+	if len(rr.TimeWindow) > 0 {
+		cond, err := NewTimeMatcher(rr.TimeWindow, rr.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		conds.Add(cond)
+	}
+
 	if conds.Len() == 0 {
 		return nil, newError("this rule has no effective fields").AtWarning()
 	}
@@ -193,6 +265,34 @@ func (br *BalancingRule) Build(ohm outbound.Manager, dispatcher routing.Dispatch
 			ohm:       ohm, fallbackTag: br.FallbackTag,
 			strategy: randomStrategy,
 		}, nil
+	case "consistenthash":
+		i, err := serial.GetInstanceOf(br.StrategySettings)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := i.(*StrategyConsistentHashConfig)
+		if !ok {
+			return nil, newError("not a StrategyConsistentHashConfig").AtError()
+		}
+		return &Balancer{
+			selectors: br.OutboundSelector,
+			strategy:  NewConsistentHashStrategy(s),
+			ohm:       ohm, fallbackTag: br.FallbackTag,
+		}, nil
+	case "weighted":
+		i, err := serial.GetInstanceOf(br.StrategySettings)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := i.(*StrategyWeightedConfig)
+		if !ok {
+			return nil, newError("not a StrategyWeightedConfig").AtError()
+		}
+		return &Balancer{
+			selectors: br.OutboundSelector,
+			strategy:  NewWeightedStrategy(s),
+			ohm:       ohm, fallbackTag: br.FallbackTag,
+		}, nil
 	default:
 		return nil, newError("unrecognized balancer type")
 	}
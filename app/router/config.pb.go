@@ -75,6 +75,58 @@ func (DomainStrategy) EnumDescriptor() ([]byte, []int) {
 	return file_app_router_config_proto_rawDescGZIP(), []int{0}
 }
 
+type StrategyConsistentHashConfig_Key int32
+
+const (
+	// Hash the source IP of the connection.
+	StrategyConsistentHashConfig_SourceIp StrategyConsistentHashConfig_Key = 0
+	// Hash the email of the user.
+	StrategyConsistentHashConfig_UserEmail StrategyConsistentHashConfig_Key = 1
+	// Hash the target domain, or the target IP if there is no domain.
+	StrategyConsistentHashConfig_TargetDomain StrategyConsistentHashConfig_Key = 2
+)
+
+// Enum value maps for StrategyConsistentHashConfig_Key.
+var (
+	StrategyConsistentHashConfig_Key_name = map[int32]string{
+		0: "SourceIp",
+		1: "UserEmail",
+		2: "TargetDomain",
+	}
+	StrategyConsistentHashConfig_Key_value = map[string]int32{
+		"SourceIp":     0,
+		"UserEmail":    1,
+		"TargetDomain": 2,
+	}
+)
+
+func (x StrategyConsistentHashConfig_Key) Enum() *StrategyConsistentHashConfig_Key {
+	p := new(StrategyConsistentHashConfig_Key)
+	*p = x
+	return p
+}
+
+func (x StrategyConsistentHashConfig_Key) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StrategyConsistentHashConfig_Key) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_router_config_proto_enumTypes[1].Descriptor()
+}
+
+func (StrategyConsistentHashConfig_Key) Type() protoreflect.EnumType {
+	return &file_app_router_config_proto_enumTypes[1]
+}
+
+func (x StrategyConsistentHashConfig_Key) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StrategyConsistentHashConfig_Key.Descriptor instead.
+func (StrategyConsistentHashConfig_Key) EnumDescriptor() ([]byte, []int) {
+	return file_app_router_config_proto_rawDescGZIP(), []int{9, 0}
+}
+
 type SetAttribute struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -127,6 +179,70 @@ func (x *SetAttribute) GetValue() string {
 	return ""
 }
 
+// TimeWindow is a recurring period of wall-clock time.
+type TimeWindow struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Days of week this window starts on, 0 for Sunday. Empty for every day.
+	Weekday []uint32 `protobuf:"varint,1,rep,packed,name=weekday,proto3" json:"weekday,omitempty"`
+	// Start and end of the window in minutes since midnight. If end is not
+	// after start, the window ends on the next day.
+	StartMinute   uint32 `protobuf:"varint,2,opt,name=start_minute,json=startMinute,proto3" json:"start_minute,omitempty"`
+	EndMinute     uint32 `protobuf:"varint,3,opt,name=end_minute,json=endMinute,proto3" json:"end_minute,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeWindow) Reset() {
+	*x = TimeWindow{}
+	mi := &file_app_router_config_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeWindow) ProtoMessage() {}
+
+func (x *TimeWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_config_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeWindow.ProtoReflect.Descriptor instead.
+func (*TimeWindow) Descriptor() ([]byte, []int) {
+	return file_app_router_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TimeWindow) GetWeekday() []uint32 {
+	if x != nil {
+		return x.Weekday
+	}
+	return nil
+}
+
+func (x *TimeWindow) GetStartMinute() uint32 {
+	if x != nil {
+		return x.StartMinute
+	}
+	return 0
+}
+
+func (x *TimeWindow) GetEndMinute() uint32 {
+	if x != nil {
+		return x.EndMinute
+	}
+	return 0
+}
+
 type RoutingRule struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to TargetTag:
@@ -174,6 +290,36 @@ type RoutingRule struct {
 	Attributes     string          `protobuf:"bytes,15,opt,name=attributes,proto3" json:"attributes,omitempty"`
 	DomainMatcher  string          `protobuf:"bytes,17,opt,name=domain_matcher,json=domainMatcher,proto3" json:"domain_matcher,omitempty"`
 	SetAttribute   []*SetAttribute `protobuf:"bytes,18,rep,name=set_attribute,json=setAttribute,proto3" json:"set_attribute,omitempty"`
+	// Tag of this rule. Rules can be removed from a running router by tag.
+	RuleTag string `protobuf:"bytes,19,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	// List of wall-clock windows for matching.
+	TimeWindow []*TimeWindow `protobuf:"bytes,20,rep,name=time_window,json=timeWindow,proto3" json:"time_window,omitempty"`
+	// IANA time zone of time_window, such as "Asia/Shanghai". Local time zone is
+	// used if empty.
+	TimeZone string `protobuf:"bytes,21,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Starlark script for matching. It is either an expression on ctx, or a
+	// program defining function match(ctx).
+	Script string `protobuf:"bytes,22,opt,name=script,proto3" json:"script,omitempty"`
+	// Maximum execution steps of each script evaluation. A default limit is
+	// used if 0.
+	ScriptMaxSteps uint64 `protobuf:"varint,23,opt,name=script_max_steps,json=scriptMaxSteps,proto3" json:"script_max_steps,omitempty"`
+	// GeoIPs and GeoSites available to script functions geoip(ip, code) and
+	// geosite(domain, code).
+	ScriptGeoip   []*routercommon.GeoIP   `protobuf:"bytes,24,rep,name=script_geoip,json=scriptGeoip,proto3" json:"script_geoip,omitempty"`
+	ScriptGeosite []*routercommon.GeoSite `protobuf:"bytes,25,rep,name=script_geosite,json=scriptGeosite,proto3" json:"script_geosite,omitempty"`
+	// ALPN offered in TLS ClientHello. "h2" matches clients offering h2, while
+	// "=h2" matches clients offering exactly h2.
+	TlsAlpn []string `protobuf:"bytes,26,rep,name=tls_alpn,json=tlsAlpn,proto3" json:"tls_alpn,omitempty"`
+	// TLS versions supported by the client, such as "1.3".
+	TlsVersion []string `protobuf:"bytes,27,rep,name=tls_version,json=tlsVersion,proto3" json:"tls_version,omitempty"`
+	// JA3 (MD5 hex) or JA4 fingerprints of TLS ClientHello.
+	TlsFingerprint []string `protobuf:"bytes,28,rep,name=tls_fingerprint,json=tlsFingerprint,proto3" json:"tls_fingerprint,omitempty"`
+	// HTTP methods of sniffed or proxied requests, such as "GET".
+	HttpMethod []string `protobuf:"bytes,29,rep,name=http_method,json=httpMethod,proto3" json:"http_method,omitempty"`
+	// HTTP paths of sniffed or proxied requests. An entry matches paths with it
+	// as prefix, or matches paths with a regular expression if it starts with
+	// "regexp:".
+	HttpPath []string `protobuf:"bytes,30,rep,name=http_path,json=httpPath,proto3" json:"http_path,omitempty"`
 	// geo_domain instruct simplified config loader to load geo domain rule and fill in domain field.
 	GeoDomain     []*routercommon.GeoSite `protobuf:"bytes,68001,rep,name=geo_domain,json=geoDomain,proto3" json:"geo_domain,omitempty"`
 	unknownFields protoimpl.UnknownFields
@@ -182,7 +328,7 @@ type RoutingRule struct {
 
 func (x *RoutingRule) Reset() {
 	*x = RoutingRule{}
-	mi := &file_app_router_config_proto_msgTypes[1]
+	mi := &file_app_router_config_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -194,7 +340,7 @@ func (x *RoutingRule) String() string {
 func (*RoutingRule) ProtoMessage() {}
 
 func (x *RoutingRule) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[1]
+	mi := &file_app_router_config_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -207,7 +353,7 @@ func (x *RoutingRule) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoutingRule.ProtoReflect.Descriptor instead.
 func (*RoutingRule) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{1}
+	return file_app_router_config_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *RoutingRule) GetTargetTag() isRoutingRule_TargetTag {
@@ -351,6 +497,90 @@ func (x *RoutingRule) GetSetAttribute() []*SetAttribute {
 	return nil
 }
 
+func (x *RoutingRule) GetRuleTag() string {
+	if x != nil {
+		return x.RuleTag
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetTimeWindow() []*TimeWindow {
+	if x != nil {
+		return x.TimeWindow
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetScriptMaxSteps() uint64 {
+	if x != nil {
+		return x.ScriptMaxSteps
+	}
+	return 0
+}
+
+func (x *RoutingRule) GetScriptGeoip() []*routercommon.GeoIP {
+	if x != nil {
+		return x.ScriptGeoip
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetScriptGeosite() []*routercommon.GeoSite {
+	if x != nil {
+		return x.ScriptGeosite
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetTlsAlpn() []string {
+	if x != nil {
+		return x.TlsAlpn
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetTlsVersion() []string {
+	if x != nil {
+		return x.TlsVersion
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetTlsFingerprint() []string {
+	if x != nil {
+		return x.TlsFingerprint
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetHttpMethod() []string {
+	if x != nil {
+		return x.HttpMethod
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetHttpPath() []string {
+	if x != nil {
+		return x.HttpPath
+	}
+	return nil
+}
+
 func (x *RoutingRule) GetGeoDomain() []*routercommon.GeoSite {
 	if x != nil {
 		return x.GeoDomain
@@ -389,7 +619,7 @@ type BalancingRule struct {
 
 func (x *BalancingRule) Reset() {
 	*x = BalancingRule{}
-	mi := &file_app_router_config_proto_msgTypes[2]
+	mi := &file_app_router_config_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -401,7 +631,7 @@ func (x *BalancingRule) String() string {
 func (*BalancingRule) ProtoMessage() {}
 
 func (x *BalancingRule) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[2]
+	mi := &file_app_router_config_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -414,7 +644,7 @@ func (x *BalancingRule) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BalancingRule.ProtoReflect.Descriptor instead.
 func (*BalancingRule) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{2}
+	return file_app_router_config_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *BalancingRule) GetTag() string {
@@ -463,7 +693,7 @@ type StrategyWeight struct {
 
 func (x *StrategyWeight) Reset() {
 	*x = StrategyWeight{}
-	mi := &file_app_router_config_proto_msgTypes[3]
+	mi := &file_app_router_config_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -475,7 +705,7 @@ func (x *StrategyWeight) String() string {
 func (*StrategyWeight) ProtoMessage() {}
 
 func (x *StrategyWeight) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[3]
+	mi := &file_app_router_config_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -488,7 +718,7 @@ func (x *StrategyWeight) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StrategyWeight.ProtoReflect.Descriptor instead.
 func (*StrategyWeight) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{3}
+	return file_app_router_config_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *StrategyWeight) GetRegexp() bool {
@@ -522,7 +752,7 @@ type StrategyRandomConfig struct {
 
 func (x *StrategyRandomConfig) Reset() {
 	*x = StrategyRandomConfig{}
-	mi := &file_app_router_config_proto_msgTypes[4]
+	mi := &file_app_router_config_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -534,7 +764,7 @@ func (x *StrategyRandomConfig) String() string {
 func (*StrategyRandomConfig) ProtoMessage() {}
 
 func (x *StrategyRandomConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[4]
+	mi := &file_app_router_config_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -547,7 +777,7 @@ func (x *StrategyRandomConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StrategyRandomConfig.ProtoReflect.Descriptor instead.
 func (*StrategyRandomConfig) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{4}
+	return file_app_router_config_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *StrategyRandomConfig) GetObserverTag() string {
@@ -574,7 +804,7 @@ type StrategyLeastPingConfig struct {
 
 func (x *StrategyLeastPingConfig) Reset() {
 	*x = StrategyLeastPingConfig{}
-	mi := &file_app_router_config_proto_msgTypes[5]
+	mi := &file_app_router_config_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -586,7 +816,7 @@ func (x *StrategyLeastPingConfig) String() string {
 func (*StrategyLeastPingConfig) ProtoMessage() {}
 
 func (x *StrategyLeastPingConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[5]
+	mi := &file_app_router_config_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -599,7 +829,7 @@ func (x *StrategyLeastPingConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StrategyLeastPingConfig.ProtoReflect.Descriptor instead.
 func (*StrategyLeastPingConfig) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{5}
+	return file_app_router_config_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *StrategyLeastPingConfig) GetObserverTag() string {
@@ -625,7 +855,7 @@ type StrategyFallbackConfig struct {
 
 func (x *StrategyFallbackConfig) Reset() {
 	*x = StrategyFallbackConfig{}
-	mi := &file_app_router_config_proto_msgTypes[6]
+	mi := &file_app_router_config_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -637,7 +867,7 @@ func (x *StrategyFallbackConfig) String() string {
 func (*StrategyFallbackConfig) ProtoMessage() {}
 
 func (x *StrategyFallbackConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[6]
+	mi := &file_app_router_config_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -650,7 +880,7 @@ func (x *StrategyFallbackConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StrategyFallbackConfig.ProtoReflect.Descriptor instead.
 func (*StrategyFallbackConfig) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{6}
+	return file_app_router_config_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *StrategyFallbackConfig) GetObserverTag() string {
@@ -679,7 +909,7 @@ type StrategyLeastLoadConfig struct {
 
 func (x *StrategyLeastLoadConfig) Reset() {
 	*x = StrategyLeastLoadConfig{}
-	mi := &file_app_router_config_proto_msgTypes[7]
+	mi := &file_app_router_config_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -691,7 +921,7 @@ func (x *StrategyLeastLoadConfig) String() string {
 func (*StrategyLeastLoadConfig) ProtoMessage() {}
 
 func (x *StrategyLeastLoadConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[7]
+	mi := &file_app_router_config_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -704,7 +934,7 @@ func (x *StrategyLeastLoadConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StrategyLeastLoadConfig.ProtoReflect.Descriptor instead.
 func (*StrategyLeastLoadConfig) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{7}
+	return file_app_router_config_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *StrategyLeastLoadConfig) GetCosts() []*StrategyWeight {
@@ -749,6 +979,119 @@ func (x *StrategyLeastLoadConfig) GetObserverTag() string {
 	return ""
 }
 
+type StrategyConsistentHashConfig struct {
+	state         protoimpl.MessageState           `protogen:"open.v1"`
+	Key           StrategyConsistentHashConfig_Key `protobuf:"varint,1,opt,name=key,proto3,enum=v2ray.core.app.router.StrategyConsistentHashConfig_Key" json:"key,omitempty"`
+	ObserverTag   string                           `protobuf:"bytes,7,opt,name=observer_tag,json=observerTag,proto3" json:"observer_tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StrategyConsistentHashConfig) Reset() {
+	*x = StrategyConsistentHashConfig{}
+	mi := &file_app_router_config_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StrategyConsistentHashConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StrategyConsistentHashConfig) ProtoMessage() {}
+
+func (x *StrategyConsistentHashConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_config_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StrategyConsistentHashConfig.ProtoReflect.Descriptor instead.
+func (*StrategyConsistentHashConfig) Descriptor() ([]byte, []int) {
+	return file_app_router_config_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StrategyConsistentHashConfig) GetKey() StrategyConsistentHashConfig_Key {
+	if x != nil {
+		return x.Key
+	}
+	return StrategyConsistentHashConfig_SourceIp
+}
+
+func (x *StrategyConsistentHashConfig) GetObserverTag() string {
+	if x != nil {
+		return x.ObserverTag
+	}
+	return ""
+}
+
+type StrategyWeightedConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// weight of outbounds, outbounds matching no weight have weight 1
+	Weights       []*StrategyWeight `protobuf:"bytes,1,rep,name=weights,proto3" json:"weights,omitempty"`
+	ObserverTag   string            `protobuf:"bytes,7,opt,name=observer_tag,json=observerTag,proto3" json:"observer_tag,omitempty"`
+	AliveOnly     bool              `protobuf:"varint,8,opt,name=alive_only,json=aliveOnly,proto3" json:"alive_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StrategyWeightedConfig) Reset() {
+	*x = StrategyWeightedConfig{}
+	mi := &file_app_router_config_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StrategyWeightedConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StrategyWeightedConfig) ProtoMessage() {}
+
+func (x *StrategyWeightedConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_config_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StrategyWeightedConfig.ProtoReflect.Descriptor instead.
+func (*StrategyWeightedConfig) Descriptor() ([]byte, []int) {
+	return file_app_router_config_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StrategyWeightedConfig) GetWeights() []*StrategyWeight {
+	if x != nil {
+		return x.Weights
+	}
+	return nil
+}
+
+func (x *StrategyWeightedConfig) GetObserverTag() string {
+	if x != nil {
+		return x.ObserverTag
+	}
+	return ""
+}
+
+func (x *StrategyWeightedConfig) GetAliveOnly() bool {
+	if x != nil {
+		return x.AliveOnly
+	}
+	return false
+}
+
 type Config struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	DomainStrategy DomainStrategy         `protobuf:"varint,1,opt,name=domain_strategy,json=domainStrategy,proto3,enum=v2ray.core.app.router.DomainStrategy" json:"domain_strategy,omitempty"`
@@ -760,7 +1103,7 @@ type Config struct {
 
 func (x *Config) Reset() {
 	*x = Config{}
-	mi := &file_app_router_config_proto_msgTypes[8]
+	mi := &file_app_router_config_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -772,7 +1115,7 @@ func (x *Config) String() string {
 func (*Config) ProtoMessage() {}
 
 func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[8]
+	mi := &file_app_router_config_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -785,7 +1128,7 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Config.ProtoReflect.Descriptor instead.
 func (*Config) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{8}
+	return file_app_router_config_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *Config) GetDomainStrategy() DomainStrategy {
@@ -838,6 +1181,36 @@ type SimplifiedRoutingRule struct {
 	Attributes     string          `protobuf:"bytes,15,opt,name=attributes,proto3" json:"attributes,omitempty"`
 	DomainMatcher  string          `protobuf:"bytes,17,opt,name=domain_matcher,json=domainMatcher,proto3" json:"domain_matcher,omitempty"`
 	SetAttribute   []*SetAttribute `protobuf:"bytes,18,rep,name=set_attribute,json=setAttribute,proto3" json:"set_attribute,omitempty"`
+	// Tag of this rule. Rules can be removed from a running router by tag.
+	RuleTag string `protobuf:"bytes,19,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	// List of wall-clock windows for matching, such as "Mon-Fri 09:00-18:00".
+	TimeWindow []string `protobuf:"bytes,20,rep,name=time_window,json=timeWindow,proto3" json:"time_window,omitempty"`
+	// IANA time zone of time_window, such as "Asia/Shanghai". Local time zone is
+	// used if empty.
+	TimeZone string `protobuf:"bytes,21,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Starlark script for matching. It is either an expression on ctx, or a
+	// program defining function match(ctx).
+	Script string `protobuf:"bytes,22,opt,name=script,proto3" json:"script,omitempty"`
+	// Maximum execution steps of each script evaluation. A default limit is
+	// used if 0.
+	ScriptMaxSteps uint64 `protobuf:"varint,23,opt,name=script_max_steps,json=scriptMaxSteps,proto3" json:"script_max_steps,omitempty"`
+	// GeoIPs and GeoSites available to script functions geoip(ip, code) and
+	// geosite(domain, code).
+	ScriptGeoip   []*routercommon.GeoIP   `protobuf:"bytes,24,rep,name=script_geoip,json=scriptGeoip,proto3" json:"script_geoip,omitempty"`
+	ScriptGeosite []*routercommon.GeoSite `protobuf:"bytes,25,rep,name=script_geosite,json=scriptGeosite,proto3" json:"script_geosite,omitempty"`
+	// ALPN offered in TLS ClientHello. "h2" matches clients offering h2, while
+	// "=h2" matches clients offering exactly h2.
+	TlsAlpn []string `protobuf:"bytes,26,rep,name=tls_alpn,json=tlsAlpn,proto3" json:"tls_alpn,omitempty"`
+	// TLS versions supported by the client, such as "1.3".
+	TlsVersion []string `protobuf:"bytes,27,rep,name=tls_version,json=tlsVersion,proto3" json:"tls_version,omitempty"`
+	// JA3 (MD5 hex) or JA4 fingerprints of TLS ClientHello.
+	TlsFingerprint []string `protobuf:"bytes,28,rep,name=tls_fingerprint,json=tlsFingerprint,proto3" json:"tls_fingerprint,omitempty"`
+	// HTTP methods of sniffed or proxied requests, such as "GET".
+	HttpMethod []string `protobuf:"bytes,29,rep,name=http_method,json=httpMethod,proto3" json:"http_method,omitempty"`
+	// HTTP paths of sniffed or proxied requests. An entry matches paths with it
+	// as prefix, or matches paths with a regular expression if it starts with
+	// "regexp:".
+	HttpPath []string `protobuf:"bytes,30,rep,name=http_path,json=httpPath,proto3" json:"http_path,omitempty"`
 	// geo_domain instruct simplified config loader to load geo domain rule and fill in domain field.
 	GeoDomain     []*routercommon.GeoSite `protobuf:"bytes,68001,rep,name=geo_domain,json=geoDomain,proto3" json:"geo_domain,omitempty"`
 	unknownFields protoimpl.UnknownFields
@@ -846,7 +1219,7 @@ type SimplifiedRoutingRule struct {
 
 func (x *SimplifiedRoutingRule) Reset() {
 	*x = SimplifiedRoutingRule{}
-	mi := &file_app_router_config_proto_msgTypes[9]
+	mi := &file_app_router_config_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -858,7 +1231,7 @@ func (x *SimplifiedRoutingRule) String() string {
 func (*SimplifiedRoutingRule) ProtoMessage() {}
 
 func (x *SimplifiedRoutingRule) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[9]
+	mi := &file_app_router_config_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -871,7 +1244,7 @@ func (x *SimplifiedRoutingRule) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SimplifiedRoutingRule.ProtoReflect.Descriptor instead.
 func (*SimplifiedRoutingRule) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{9}
+	return file_app_router_config_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *SimplifiedRoutingRule) GetTargetTag() isSimplifiedRoutingRule_TargetTag {
@@ -983,6 +1356,90 @@ func (x *SimplifiedRoutingRule) GetSetAttribute() []*SetAttribute {
 	return nil
 }
 
+func (x *SimplifiedRoutingRule) GetRuleTag() string {
+	if x != nil {
+		return x.RuleTag
+	}
+	return ""
+}
+
+func (x *SimplifiedRoutingRule) GetTimeWindow() []string {
+	if x != nil {
+		return x.TimeWindow
+	}
+	return nil
+}
+
+func (x *SimplifiedRoutingRule) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *SimplifiedRoutingRule) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+func (x *SimplifiedRoutingRule) GetScriptMaxSteps() uint64 {
+	if x != nil {
+		return x.ScriptMaxSteps
+	}
+	return 0
+}
+
+func (x *SimplifiedRoutingRule) GetScriptGeoip() []*routercommon.GeoIP {
+	if x != nil {
+		return x.ScriptGeoip
+	}
+	return nil
+}
+
+func (x *SimplifiedRoutingRule) GetScriptGeosite() []*routercommon.GeoSite {
+	if x != nil {
+		return x.ScriptGeosite
+	}
+	return nil
+}
+
+func (x *SimplifiedRoutingRule) GetTlsAlpn() []string {
+	if x != nil {
+		return x.TlsAlpn
+	}
+	return nil
+}
+
+func (x *SimplifiedRoutingRule) GetTlsVersion() []string {
+	if x != nil {
+		return x.TlsVersion
+	}
+	return nil
+}
+
+func (x *SimplifiedRoutingRule) GetTlsFingerprint() []string {
+	if x != nil {
+		return x.TlsFingerprint
+	}
+	return nil
+}
+
+func (x *SimplifiedRoutingRule) GetHttpMethod() []string {
+	if x != nil {
+		return x.HttpMethod
+	}
+	return nil
+}
+
+func (x *SimplifiedRoutingRule) GetHttpPath() []string {
+	if x != nil {
+		return x.HttpPath
+	}
+	return nil
+}
+
 func (x *SimplifiedRoutingRule) GetGeoDomain() []*routercommon.GeoSite {
 	if x != nil {
 		return x.GeoDomain
@@ -1019,7 +1476,7 @@ type SimplifiedConfig struct {
 
 func (x *SimplifiedConfig) Reset() {
 	*x = SimplifiedConfig{}
-	mi := &file_app_router_config_proto_msgTypes[10]
+	mi := &file_app_router_config_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1031,7 +1488,7 @@ func (x *SimplifiedConfig) String() string {
 func (*SimplifiedConfig) ProtoMessage() {}
 
 func (x *SimplifiedConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[10]
+	mi := &file_app_router_config_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1044,7 +1501,7 @@ func (x *SimplifiedConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SimplifiedConfig.ProtoReflect.Descriptor instead.
 func (*SimplifiedConfig) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{10}
+	return file_app_router_config_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *SimplifiedConfig) GetDomainStrategy() DomainStrategy {
@@ -1075,7 +1532,13 @@ const file_app_router_config_proto_rawDesc = "" +
 	"\x17app/router/config.proto\x12\x15v2ray.core.app.router\x1a\x19google/protobuf/any.proto\x1a\x15common/net/port.proto\x1a\x18common/net/network.proto\x1a common/protoext/extensions.proto\x1a$app/router/routercommon/common.proto\"6\n" +
 	"\fSetAttribute\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\"\xca\b\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"h\n" +
+	"\n" +
+	"TimeWindow\x12\x18\n" +
+	"\aweekday\x18\x01 \x03(\rR\aweekday\x12!\n" +
+	"\fstart_minute\x18\x02 \x01(\rR\vstartMinute\x12\x1d\n" +
+	"\n" +
+	"end_minute\x18\x03 \x01(\rR\tendMinute\"\xcd\f\n" +
 	"\vRoutingRule\x12\x12\n" +
 	"\x03tag\x18\x01 \x01(\tH\x00R\x03tag\x12%\n" +
 	"\rbalancing_tag\x18\f \x01(\tH\x00R\fbalancingTag\x12B\n" +
@@ -1101,7 +1564,22 @@ const file_app_router_config_proto_rawDesc = "" +
 	"attributes\x18\x0f \x01(\tR\n" +
 	"attributes\x12%\n" +
 	"\x0edomain_matcher\x18\x11 \x01(\tR\rdomainMatcher\x12H\n" +
-	"\rset_attribute\x18\x12 \x03(\v2#.v2ray.core.app.router.SetAttributeR\fsetAttribute\x12L\n" +
+	"\rset_attribute\x18\x12 \x03(\v2#.v2ray.core.app.router.SetAttributeR\fsetAttribute\x12\x19\n" +
+	"\brule_tag\x18\x13 \x01(\tR\aruleTag\x12B\n" +
+	"\vtime_window\x18\x14 \x03(\v2!.v2ray.core.app.router.TimeWindowR\n" +
+	"timeWindow\x12\x1b\n" +
+	"\ttime_zone\x18\x15 \x01(\tR\btimeZone\x12\x16\n" +
+	"\x06script\x18\x16 \x01(\tR\x06script\x12(\n" +
+	"\x10script_max_steps\x18\x17 \x01(\x04R\x0escriptMaxSteps\x12L\n" +
+	"\fscript_geoip\x18\x18 \x03(\v2).v2ray.core.app.router.routercommon.GeoIPR\vscriptGeoip\x12R\n" +
+	"\x0escript_geosite\x18\x19 \x03(\v2+.v2ray.core.app.router.routercommon.GeoSiteR\rscriptGeosite\x12\x19\n" +
+	"\btls_alpn\x18\x1a \x03(\tR\atlsAlpn\x12\x1f\n" +
+	"\vtls_version\x18\x1b \x03(\tR\n" +
+	"tlsVersion\x12'\n" +
+	"\x0ftls_fingerprint\x18\x1c \x03(\tR\x0etlsFingerprint\x12\x1f\n" +
+	"\vhttp_method\x18\x1d \x03(\tR\n" +
+	"httpMethod\x12\x1b\n" +
+	"\thttp_path\x18\x1e \x03(\tR\bhttpPath\x12L\n" +
 	"\n" +
 	"geo_domain\x18\xa1\x93\x04 \x03(\v2+.v2ray.core.app.router.routercommon.GeoSiteR\tgeoDomainB\f\n" +
 	"\n" +
@@ -1135,11 +1613,25 @@ const file_app_router_config_proto_rawDesc = "" +
 	"\x06maxRTT\x18\x05 \x01(\x03R\x06maxRTT\x12\x1c\n" +
 	"\ttolerance\x18\x06 \x01(\x02R\ttolerance\x12!\n" +
 	"\fobserver_tag\x18\a \x01(\tR\vobserverTag:\x19\x82\xb5\x18\x15\n" +
-	"\bbalancer\x12\tleastload\"\xdd\x01\n" +
+	"\bbalancer\x12\tleastload\"\xe2\x01\n" +
+	"\x1cStrategyConsistentHashConfig\x12I\n" +
+	"\x03key\x18\x01 \x01(\x0e27.v2ray.core.app.router.StrategyConsistentHashConfig.KeyR\x03key\x12!\n" +
+	"\fobserver_tag\x18\a \x01(\tR\vobserverTag\"4\n" +
+	"\x03Key\x12\f\n" +
+	"\bSourceIp\x10\x00\x12\r\n" +
+	"\tUserEmail\x10\x01\x12\x10\n" +
+	"\fTargetDomain\x10\x02:\x1e\x82\xb5\x18\x1a\n" +
+	"\bbalancer\x12\x0econsistenthash\"\xb5\x01\n" +
+	"\x16StrategyWeightedConfig\x12?\n" +
+	"\aweights\x18\x01 \x03(\v2%.v2ray.core.app.router.StrategyWeightR\aweights\x12!\n" +
+	"\fobserver_tag\x18\a \x01(\tR\vobserverTag\x12\x1d\n" +
+	"\n" +
+	"alive_only\x18\b \x01(\bR\taliveOnly:\x18\x82\xb5\x18\x14\n" +
+	"\bbalancer\x12\bweighted\"\xdd\x01\n" +
 	"\x06Config\x12N\n" +
 	"\x0fdomain_strategy\x18\x01 \x01(\x0e2%.v2ray.core.app.router.DomainStrategyR\x0edomainStrategy\x126\n" +
 	"\x04rule\x18\x02 \x03(\v2\".v2ray.core.app.router.RoutingRuleR\x04rule\x12K\n" +
-	"\x0ebalancing_rule\x18\x03 \x03(\v2$.v2ray.core.app.router.BalancingRuleR\rbalancingRule\"\xf5\x05\n" +
+	"\x0ebalancing_rule\x18\x03 \x03(\v2$.v2ray.core.app.router.BalancingRuleR\rbalancingRule\"\xd5\t\n" +
 	"\x15SimplifiedRoutingRule\x12\x12\n" +
 	"\x03tag\x18\x01 \x01(\tH\x00R\x03tag\x12%\n" +
 	"\rbalancing_tag\x18\f \x01(\tH\x00R\fbalancingTag\x12B\n" +
@@ -1159,7 +1651,22 @@ const file_app_router_config_proto_rawDesc = "" +
 	"attributes\x18\x0f \x01(\tR\n" +
 	"attributes\x12%\n" +
 	"\x0edomain_matcher\x18\x11 \x01(\tR\rdomainMatcher\x12H\n" +
-	"\rset_attribute\x18\x12 \x03(\v2#.v2ray.core.app.router.SetAttributeR\fsetAttribute\x12L\n" +
+	"\rset_attribute\x18\x12 \x03(\v2#.v2ray.core.app.router.SetAttributeR\fsetAttribute\x12\x19\n" +
+	"\brule_tag\x18\x13 \x01(\tR\aruleTag\x12\x1f\n" +
+	"\vtime_window\x18\x14 \x03(\tR\n" +
+	"timeWindow\x12\x1b\n" +
+	"\ttime_zone\x18\x15 \x01(\tR\btimeZone\x12\x16\n" +
+	"\x06script\x18\x16 \x01(\tR\x06script\x12(\n" +
+	"\x10script_max_steps\x18\x17 \x01(\x04R\x0escriptMaxSteps\x12L\n" +
+	"\fscript_geoip\x18\x18 \x03(\v2).v2ray.core.app.router.routercommon.GeoIPR\vscriptGeoip\x12R\n" +
+	"\x0escript_geosite\x18\x19 \x03(\v2+.v2ray.core.app.router.routercommon.GeoSiteR\rscriptGeosite\x12\x19\n" +
+	"\btls_alpn\x18\x1a \x03(\tR\atlsAlpn\x12\x1f\n" +
+	"\vtls_version\x18\x1b \x03(\tR\n" +
+	"tlsVersion\x12'\n" +
+	"\x0ftls_fingerprint\x18\x1c \x03(\tR\x0etlsFingerprint\x12\x1f\n" +
+	"\vhttp_method\x18\x1d \x03(\tR\n" +
+	"httpMethod\x12\x1b\n" +
+	"\thttp_path\x18\x1e \x03(\tR\bhttpPath\x12L\n" +
 	"\n" +
 	"geo_domain\x18\xa1\x93\x04 \x03(\v2+.v2ray.core.app.router.routercommon.GeoSiteR\tgeoDomainB\f\n" +
 	"\n" +
@@ -1189,63 +1696,74 @@ func file_app_router_config_proto_rawDescGZIP() []byte {
 	return file_app_router_config_proto_rawDescData
 }
 
-var file_app_router_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_app_router_config_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_app_router_config_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_app_router_config_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_app_router_config_proto_goTypes = []any{
-	(DomainStrategy)(0),             // 0: v2ray.core.app.router.DomainStrategy
-	(*SetAttribute)(nil),            // 1: v2ray.core.app.router.SetAttribute
-	(*RoutingRule)(nil),             // 2: v2ray.core.app.router.RoutingRule
-	(*BalancingRule)(nil),           // 3: v2ray.core.app.router.BalancingRule
-	(*StrategyWeight)(nil),          // 4: v2ray.core.app.router.StrategyWeight
-	(*StrategyRandomConfig)(nil),    // 5: v2ray.core.app.router.StrategyRandomConfig
-	(*StrategyLeastPingConfig)(nil), // 6: v2ray.core.app.router.StrategyLeastPingConfig
-	(*StrategyFallbackConfig)(nil),  // 7: v2ray.core.app.router.StrategyFallbackConfig
-	(*StrategyLeastLoadConfig)(nil), // 8: v2ray.core.app.router.StrategyLeastLoadConfig
-	(*Config)(nil),                  // 9: v2ray.core.app.router.Config
-	(*SimplifiedRoutingRule)(nil),   // 10: v2ray.core.app.router.SimplifiedRoutingRule
-	(*SimplifiedConfig)(nil),        // 11: v2ray.core.app.router.SimplifiedConfig
-	(*routercommon.Domain)(nil),     // 12: v2ray.core.app.router.routercommon.Domain
-	(*routercommon.CIDR)(nil),       // 13: v2ray.core.app.router.routercommon.CIDR
-	(*routercommon.GeoIP)(nil),      // 14: v2ray.core.app.router.routercommon.GeoIP
-	(*net.PortRange)(nil),           // 15: v2ray.core.common.net.PortRange
-	(*net.PortList)(nil),            // 16: v2ray.core.common.net.PortList
-	(*net.NetworkList)(nil),         // 17: v2ray.core.common.net.NetworkList
-	(net.Network)(0),                // 18: v2ray.core.common.net.Network
-	(*routercommon.GeoSite)(nil),    // 19: v2ray.core.app.router.routercommon.GeoSite
-	(*anypb.Any)(nil),               // 20: google.protobuf.Any
+	(DomainStrategy)(0),                   // 0: v2ray.core.app.router.DomainStrategy
+	(StrategyConsistentHashConfig_Key)(0), // 1: v2ray.core.app.router.StrategyConsistentHashConfig.Key
+	(*SetAttribute)(nil),                  // 2: v2ray.core.app.router.SetAttribute
+	(*TimeWindow)(nil),                    // 3: v2ray.core.app.router.TimeWindow
+	(*RoutingRule)(nil),                   // 4: v2ray.core.app.router.RoutingRule
+	(*BalancingRule)(nil),                 // 5: v2ray.core.app.router.BalancingRule
+	(*StrategyWeight)(nil),                // 6: v2ray.core.app.router.StrategyWeight
+	(*StrategyRandomConfig)(nil),          // 7: v2ray.core.app.router.StrategyRandomConfig
+	(*StrategyLeastPingConfig)(nil),       // 8: v2ray.core.app.router.StrategyLeastPingConfig
+	(*StrategyFallbackConfig)(nil),        // 9: v2ray.core.app.router.StrategyFallbackConfig
+	(*StrategyLeastLoadConfig)(nil),       // 10: v2ray.core.app.router.StrategyLeastLoadConfig
+	(*StrategyConsistentHashConfig)(nil),  // 11: v2ray.core.app.router.StrategyConsistentHashConfig
+	(*StrategyWeightedConfig)(nil),        // 12: v2ray.core.app.router.StrategyWeightedConfig
+	(*Config)(nil),                        // 13: v2ray.core.app.router.Config
+	(*SimplifiedRoutingRule)(nil),         // 14: v2ray.core.app.router.SimplifiedRoutingRule
+	(*SimplifiedConfig)(nil),              // 15: v2ray.core.app.router.SimplifiedConfig
+	(*routercommon.Domain)(nil),           // 16: v2ray.core.app.router.routercommon.Domain
+	(*routercommon.CIDR)(nil),             // 17: v2ray.core.app.router.routercommon.CIDR
+	(*routercommon.GeoIP)(nil),            // 18: v2ray.core.app.router.routercommon.GeoIP
+	(*net.PortRange)(nil),                 // 19: v2ray.core.common.net.PortRange
+	(*net.PortList)(nil),                  // 20: v2ray.core.common.net.PortList
+	(*net.NetworkList)(nil),               // 21: v2ray.core.common.net.NetworkList
+	(net.Network)(0),                      // 22: v2ray.core.common.net.Network
+	(*routercommon.GeoSite)(nil),          // 23: v2ray.core.app.router.routercommon.GeoSite
+	(*anypb.Any)(nil),                     // 24: google.protobuf.Any
 }
 var file_app_router_config_proto_depIdxs = []int32{
-	12, // 0: v2ray.core.app.router.RoutingRule.domain:type_name -> v2ray.core.app.router.routercommon.Domain
-	13, // 1: v2ray.core.app.router.RoutingRule.cidr:type_name -> v2ray.core.app.router.routercommon.CIDR
-	14, // 2: v2ray.core.app.router.RoutingRule.geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
-	15, // 3: v2ray.core.app.router.RoutingRule.port_range:type_name -> v2ray.core.common.net.PortRange
-	16, // 4: v2ray.core.app.router.RoutingRule.port_list:type_name -> v2ray.core.common.net.PortList
-	17, // 5: v2ray.core.app.router.RoutingRule.network_list:type_name -> v2ray.core.common.net.NetworkList
-	18, // 6: v2ray.core.app.router.RoutingRule.networks:type_name -> v2ray.core.common.net.Network
-	13, // 7: v2ray.core.app.router.RoutingRule.source_cidr:type_name -> v2ray.core.app.router.routercommon.CIDR
-	14, // 8: v2ray.core.app.router.RoutingRule.source_geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
-	16, // 9: v2ray.core.app.router.RoutingRule.source_port_list:type_name -> v2ray.core.common.net.PortList
-	1,  // 10: v2ray.core.app.router.RoutingRule.set_attribute:type_name -> v2ray.core.app.router.SetAttribute
-	19, // 11: v2ray.core.app.router.RoutingRule.geo_domain:type_name -> v2ray.core.app.router.routercommon.GeoSite
-	20, // 12: v2ray.core.app.router.BalancingRule.strategy_settings:type_name -> google.protobuf.Any
-	4,  // 13: v2ray.core.app.router.StrategyLeastLoadConfig.costs:type_name -> v2ray.core.app.router.StrategyWeight
-	0,  // 14: v2ray.core.app.router.Config.domain_strategy:type_name -> v2ray.core.app.router.DomainStrategy
-	2,  // 15: v2ray.core.app.router.Config.rule:type_name -> v2ray.core.app.router.RoutingRule
-	3,  // 16: v2ray.core.app.router.Config.balancing_rule:type_name -> v2ray.core.app.router.BalancingRule
-	12, // 17: v2ray.core.app.router.SimplifiedRoutingRule.domain:type_name -> v2ray.core.app.router.routercommon.Domain
-	14, // 18: v2ray.core.app.router.SimplifiedRoutingRule.geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
-	17, // 19: v2ray.core.app.router.SimplifiedRoutingRule.networks:type_name -> v2ray.core.common.net.NetworkList
-	14, // 20: v2ray.core.app.router.SimplifiedRoutingRule.source_geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
-	1,  // 21: v2ray.core.app.router.SimplifiedRoutingRule.set_attribute:type_name -> v2ray.core.app.router.SetAttribute
-	19, // 22: v2ray.core.app.router.SimplifiedRoutingRule.geo_domain:type_name -> v2ray.core.app.router.routercommon.GeoSite
-	0,  // 23: v2ray.core.app.router.SimplifiedConfig.domain_strategy:type_name -> v2ray.core.app.router.DomainStrategy
-	10, // 24: v2ray.core.app.router.SimplifiedConfig.rule:type_name -> v2ray.core.app.router.SimplifiedRoutingRule
-	3,  // 25: v2ray.core.app.router.SimplifiedConfig.balancing_rule:type_name -> v2ray.core.app.router.BalancingRule
-	26, // [26:26] is the sub-list for method output_type
-	26, // [26:26] is the sub-list for method input_type
-	26, // [26:26] is the sub-list for extension type_name
-	26, // [26:26] is the sub-list for extension extendee
-	0,  // [0:26] is the sub-list for field type_name
+	16, // 0: v2ray.core.app.router.RoutingRule.domain:type_name -> v2ray.core.app.router.routercommon.Domain
+	17, // 1: v2ray.core.app.router.RoutingRule.cidr:type_name -> v2ray.core.app.router.routercommon.CIDR
+	18, // 2: v2ray.core.app.router.RoutingRule.geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
+	19, // 3: v2ray.core.app.router.RoutingRule.port_range:type_name -> v2ray.core.common.net.PortRange
+	20, // 4: v2ray.core.app.router.RoutingRule.port_list:type_name -> v2ray.core.common.net.PortList
+	21, // 5: v2ray.core.app.router.RoutingRule.network_list:type_name -> v2ray.core.common.net.NetworkList
+	22, // 6: v2ray.core.app.router.RoutingRule.networks:type_name -> v2ray.core.common.net.Network
+	17, // 7: v2ray.core.app.router.RoutingRule.source_cidr:type_name -> v2ray.core.app.router.routercommon.CIDR
+	18, // 8: v2ray.core.app.router.RoutingRule.source_geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
+	20, // 9: v2ray.core.app.router.RoutingRule.source_port_list:type_name -> v2ray.core.common.net.PortList
+	2,  // 10: v2ray.core.app.router.RoutingRule.set_attribute:type_name -> v2ray.core.app.router.SetAttribute
+	3,  // 11: v2ray.core.app.router.RoutingRule.time_window:type_name -> v2ray.core.app.router.TimeWindow
+	18, // 12: v2ray.core.app.router.RoutingRule.script_geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
+	23, // 13: v2ray.core.app.router.RoutingRule.script_geosite:type_name -> v2ray.core.app.router.routercommon.GeoSite
+	23, // 14: v2ray.core.app.router.RoutingRule.geo_domain:type_name -> v2ray.core.app.router.routercommon.GeoSite
+	24, // 15: v2ray.core.app.router.BalancingRule.strategy_settings:type_name -> google.protobuf.Any
+	6,  // 16: v2ray.core.app.router.StrategyLeastLoadConfig.costs:type_name -> v2ray.core.app.router.StrategyWeight
+	1,  // 17: v2ray.core.app.router.StrategyConsistentHashConfig.key:type_name -> v2ray.core.app.router.StrategyConsistentHashConfig.Key
+	6,  // 18: v2ray.core.app.router.StrategyWeightedConfig.weights:type_name -> v2ray.core.app.router.StrategyWeight
+	0,  // 19: v2ray.core.app.router.Config.domain_strategy:type_name -> v2ray.core.app.router.DomainStrategy
+	4,  // 20: v2ray.core.app.router.Config.rule:type_name -> v2ray.core.app.router.RoutingRule
+	5,  // 21: v2ray.core.app.router.Config.balancing_rule:type_name -> v2ray.core.app.router.BalancingRule
+	16, // 22: v2ray.core.app.router.SimplifiedRoutingRule.domain:type_name -> v2ray.core.app.router.routercommon.Domain
+	18, // 23: v2ray.core.app.router.SimplifiedRoutingRule.geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
+	21, // 24: v2ray.core.app.router.SimplifiedRoutingRule.networks:type_name -> v2ray.core.common.net.NetworkList
+	18, // 25: v2ray.core.app.router.SimplifiedRoutingRule.source_geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
+	2,  // 26: v2ray.core.app.router.SimplifiedRoutingRule.set_attribute:type_name -> v2ray.core.app.router.SetAttribute
+	18, // 27: v2ray.core.app.router.SimplifiedRoutingRule.script_geoip:type_name -> v2ray.core.app.router.routercommon.GeoIP
+	23, // 28: v2ray.core.app.router.SimplifiedRoutingRule.script_geosite:type_name -> v2ray.core.app.router.routercommon.GeoSite
+	23, // 29: v2ray.core.app.router.SimplifiedRoutingRule.geo_domain:type_name -> v2ray.core.app.router.routercommon.GeoSite
+	0,  // 30: v2ray.core.app.router.SimplifiedConfig.domain_strategy:type_name -> v2ray.core.app.router.DomainStrategy
+	14, // 31: v2ray.core.app.router.SimplifiedConfig.rule:type_name -> v2ray.core.app.router.SimplifiedRoutingRule
+	5,  // 32: v2ray.core.app.router.SimplifiedConfig.balancing_rule:type_name -> v2ray.core.app.router.BalancingRule
+	33, // [33:33] is the sub-list for method output_type
+	33, // [33:33] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
 }
 
 func init() { file_app_router_config_proto_init() }
@@ -1253,11 +1771,11 @@ func file_app_router_config_proto_init() {
 	if File_app_router_config_proto != nil {
 		return
 	}
-	file_app_router_config_proto_msgTypes[1].OneofWrappers = []any{
+	file_app_router_config_proto_msgTypes[2].OneofWrappers = []any{
 		(*RoutingRule_Tag)(nil),
 		(*RoutingRule_BalancingTag)(nil),
 	}
-	file_app_router_config_proto_msgTypes[9].OneofWrappers = []any{
+	file_app_router_config_proto_msgTypes[12].OneofWrappers = []any{
 		(*SimplifiedRoutingRule_Tag)(nil),
 		(*SimplifiedRoutingRule_BalancingTag)(nil),
 	}
@@ -1266,8 +1784,8 @@ func file_app_router_config_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_router_config_proto_rawDesc), len(file_app_router_config_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   11,
+			NumEnums:      2,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -4,24 +4,40 @@ package router
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
 
 	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/platform"
 	"github.com/v2fly/v2ray-core/v5/features/dns"
 	"github.com/v2fly/v2ray-core/v5/features/outbound"
 	"github.com/v2fly/v2ray-core/v5/features/routing"
 	routing_dns "github.com/v2fly/v2ray-core/v5/features/routing/dns"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/geodata"
 )
 
 // Router is an implementation of routing.Router.
 type Router struct {
+	access         sync.RWMutex
 	domainStrategy DomainStrategy
 	rules          []*Rule
 	balancers      map[string]*Balancer
 	dns            dns.Client
+
+	ctx        context.Context
+	ohm        outbound.Manager
+	dispatcher routing.Dispatcher
+	stats      stats.Manager
+
+	// updateAccess serializes modifications of rules and balancers.
+	updateAccess sync.Mutex
 }
 
 // Route is an implementation of routing.Route.
@@ -33,55 +49,285 @@ type Route struct {
 }
 
 // Init initializes the Router.
-func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm outbound.Manager, dispatcher routing.Dispatcher) error {
+func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm outbound.Manager, dispatcher routing.Dispatcher, sm stats.Manager) error {
 	r.domainStrategy = config.DomainStrategy
 	r.dns = d
+	r.ctx = ctx
+	r.ohm = ohm
+	r.dispatcher = dispatcher
+	r.stats = sm
+
+	balancers, err := r.buildBalancers(config.BalancingRule, nil)
+	if err != nil {
+		return err
+	}
+	rules, err := r.buildRules(config.Rule, balancers, nil)
+	if err != nil {
+		return err
+	}
+	r.balancers = balancers
+	r.rules = rules
 
-	r.balancers = make(map[string]*Balancer, len(config.BalancingRule))
-	for _, rule := range config.BalancingRule {
-		balancer, err := rule.Build(ohm, dispatcher)
+	return nil
+}
+
+// buildBalancers builds the balancers in addition to the existing ones. Balancer tags must be unique.
+func (r *Router) buildBalancers(balancingRules []*BalancingRule, existing map[string]*Balancer) (map[string]*Balancer, error) {
+	balancers := make(map[string]*Balancer, len(existing)+len(balancingRules))
+	for tag, balancer := range existing {
+		balancers[tag] = balancer
+	}
+	for _, rule := range balancingRules {
+		if _, found := balancers[rule.Tag]; found {
+			return nil, newError("balancer ", rule.Tag, " already exists")
+		}
+		balancer, err := rule.Build(r.ohm, r.dispatcher)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		balancer.InjectContext(ctx)
-		r.balancers[rule.Tag] = balancer
+		balancer.InjectContext(r.ctx)
+		balancers[rule.Tag] = balancer
 	}
+	return balancers, nil
+}
 
-	r.rules = make([]*Rule, 0, len(config.Rule))
-	for _, rule := range config.Rule {
+// buildRules builds the routing rules in addition to the existing ones. Rule tags must be unique, as rules are
+// removed and counted by their tags.
+func (r *Router) buildRules(routingRules []*RoutingRule, balancers map[string]*Balancer, existing []*Rule) ([]*Rule, error) {
+	ruleTags := make(map[string]bool)
+	for _, rule := range existing {
+		ruleTags[rule.RuleTag] = true
+	}
+	rules := make([]*Rule, 0, len(routingRules))
+	for _, rule := range routingRules {
+		if tag := rule.GetRuleTag(); len(tag) > 0 {
+			if ruleTags[tag] {
+				return nil, newError("rule ", tag, " already exists")
+			}
+			ruleTags[tag] = true
+		}
 		cond, err := rule.BuildCondition()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		rr := &Rule{
 			Condition:     cond,
 			Tag:           rule.GetTag(),
+			RuleTag:       rule.GetRuleTag(),
 			SetAttributes: rule.GetSetAttribute(),
 		}
 		btag := rule.GetBalancingTag()
 		if len(btag) > 0 {
-			brule, found := r.balancers[btag]
+			brule, found := balancers[btag]
 			if !found {
-				return newError("balancer ", btag, " not found")
+				return nil, newError("balancer ", btag, " not found")
 			}
 			rr.Balancer = brule
+			rr.balancingTag = btag
+		}
+		rr.hits = r.getHitCounter(rr.RuleTag)
+		rules = append(rules, rr)
+	}
+	return rules, nil
+}
+
+// getHitCounter returns the stats counter of the rule with the given tag, or a standalone one if it is not available.
+// The counter is owned by a single rule, since buildRules rejects duplicate rule tags.
+func (r *Router) getHitCounter(tag string) stats.Counter {
+	if len(tag) > 0 && r.stats != nil {
+		if c, err := stats.GetOrRegisterCounter(r.stats, ruleHitCounterName(tag)); err == nil {
+			return c
 		}
-		r.rules = append(r.rules, rr)
+	}
+	return new(hitCounter)
+}
+
+// unregisterHitCounters unregisters stats counters of tags in old rules but not in new rules.
+func (r *Router) unregisterHitCounters(oldRules, newRules []*Rule) {
+	if r.stats == nil {
+		return
+	}
+	tags := make(map[string]bool)
+	for _, rule := range newRules {
+		tags[rule.RuleTag] = true
+	}
+	for _, rule := range oldRules {
+		if len(rule.RuleTag) > 0 && !tags[rule.RuleTag] {
+			tags[rule.RuleTag] = true
+			r.stats.UnregisterCounter(ruleHitCounterName(rule.RuleTag))
+		}
+	}
+}
+
+func ruleHitCounterName(tag string) string {
+	return "rule>>>" + tag + ">>>hits"
+}
+
+// GetRuleStats implements routing.RuleStatsReader.
+func (r *Router) GetRuleStats(tag string, reset bool) []*routing.RuleStats {
+	rules := r.getRules()
+	result := make([]*routing.RuleStats, 0, len(rules))
+	for i, rule := range rules {
+		if len(tag) > 0 && rule.RuleTag != tag {
+			continue
+		}
+		ruleStats := &routing.RuleStats{
+			Index:        i,
+			RuleTag:      rule.RuleTag,
+			OutboundTag:  rule.Tag,
+			BalancingTag: rule.balancingTag,
+		}
+		if reset {
+			ruleStats.Hits = rule.hits.Set(0)
+		} else {
+			ruleStats.Hits = rule.hits.Value()
+		}
+		if lastMatch := atomic.LoadInt64(&rule.lastMatch); lastMatch > 0 {
+			ruleStats.LastMatch = time.Unix(lastMatch, 0)
+		}
+		result = append(result, ruleStats)
+	}
+	return result
+}
+
+// AddRule implements routing.RuleManager.
+func (r *Router) AddRule(config proto.Message, prepend bool) error {
+	c, ok := config.(*Config)
+	if !ok {
+		return newError("not a router config")
+	}
+
+	r.updateAccess.Lock()
+	defer r.updateAccess.Unlock()
+
+	balancers, err := r.buildBalancers(c.BalancingRule, r.balancers)
+	if err != nil {
+		return err
+	}
+	added, err := r.buildRules(c.Rule, balancers, r.rules)
+	if err != nil {
+		return err
+	}
+	rules := make([]*Rule, 0, len(r.rules)+len(added))
+	if prepend {
+		rules = append(append(rules, added...), r.rules...)
+	} else {
+		rules = append(append(rules, r.rules...), added...)
+	}
+
+	r.access.Lock()
+	r.rules = rules
+	r.balancers = balancers
+	r.access.Unlock()
+	return nil
+}
+
+// RemoveRule implements routing.RuleManager.
+func (r *Router) RemoveRule(tag string) error {
+	if len(tag) == 0 {
+		return newError("empty rule tag")
+	}
+
+	r.updateAccess.Lock()
+	defer r.updateAccess.Unlock()
+
+	rules := make([]*Rule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		if rule.RuleTag != tag {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == len(r.rules) {
+		return newError("rule ", tag, " not found")
+	}
+
+	r.access.Lock()
+	oldRules := r.rules
+	r.rules = rules
+	r.access.Unlock()
+	r.unregisterHitCounters(oldRules, rules)
+	return nil
+}
+
+// ReplaceRules implements routing.RuleManager. Domain strategy of the router is left unchanged.
+func (r *Router) ReplaceRules(config proto.Message) error {
+	c, ok := config.(*Config)
+	if !ok {
+		return newError("not a router config")
+	}
+
+	r.updateAccess.Lock()
+	defer r.updateAccess.Unlock()
+
+	balancers, err := r.buildBalancers(c.BalancingRule, nil)
+	if err != nil {
+		return err
+	}
+	rules, err := r.buildRules(c.Rule, balancers, nil)
+	if err != nil {
+		return err
 	}
 
+	r.access.Lock()
+	oldRules := r.rules
+	r.rules = rules
+	r.balancers = balancers
+	r.access.Unlock()
+	r.unregisterHitCounters(oldRules, rules)
 	return nil
 }
 
+func (r *Router) getRules() []*Rule {
+	r.access.RLock()
+	defer r.access.RUnlock()
+	return r.rules
+}
+
+func (r *Router) getBalancer(tag string) (*Balancer, bool) {
+	r.access.RLock()
+	defer r.access.RUnlock()
+	b, ok := r.balancers[tag]
+	return b, ok
+}
+
 // PickRoute implements routing.Router.
 func (r *Router) PickRoute(ctx routing.Context) (routing.Route, error) {
-	rule, ctx, err := r.pickRouteInternal(ctx)
+	rule, ctx, err := r.pickRouteInternal(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
-	tag, err := rule.GetTag()
+	return newRoute(rule, ctx)
+}
+
+// ExplainRoute implements routing.RouteExplainer.
+func (r *Router) ExplainRoute(ctx routing.Context) (routing.Route, []*routing.RuleTrace, error) {
+	var traces []*routing.RuleTrace
+	rule, ctx, err := r.pickRouteInternal(ctx, &traces)
+	if err != nil {
+		return nil, traces, err
+	}
+	if rule.Balancer == nil {
+		route, err := newRoute(rule, ctx)
+		return route, traces, err
+	}
+	// Picking an outbound would advance the balancing strategy, so the candidates are explained instead.
+	tag, candidates, err := rule.Balancer.peekOutbound()
+	if err != nil {
+		return nil, traces, err
+	}
+	traces[len(traces)-1].Candidates = candidates
+	return newRouteWithTag(rule, ctx, tag), traces, nil
+}
+
+func newRoute(rule *Rule, ctx routing.Context) (*Route, error) {
+	tag, err := rule.GetTag(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return newRouteWithTag(rule, ctx, tag), nil
+}
+
+func newRouteWithTag(rule *Rule, ctx routing.Context, tag string) *Route {
 	route := &Route{Context: ctx, outboundTag: tag}
 	if len(rule.SetAttributes) > 0 {
 		route.sessionAttributes = make(map[string]string, len(rule.SetAttributes))
@@ -95,10 +341,12 @@ func (r *Router) PickRoute(ctx routing.Context) (routing.Route, error) {
 			route.sessionAttributes = nil
 		}
 	}
-	return route, nil
+	return route
 }
 
-func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context, error) {
+// pickRouteInternal finds the first matching rule. If traces is not nil, evaluation of each rule is appended to it,
+// and rule statistics are left untouched. The matched rule is always the last one traced.
+func (r *Router) pickRouteInternal(ctx routing.Context, traces *[]*routing.RuleTrace) (*Rule, routing.Context, error) {
 	// SkipDNSResolve is set from DNS module.
 	// the DOH remote server maybe a domain name,
 	// this prevents cycle resolving dead loop
@@ -108,10 +356,10 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 		ctx = routing_dns.ContextWithDNSClient(ctx, r.dns)
 	}
 
-	for _, rule := range r.rules {
-		if rule.Apply(ctx) {
-			return rule, ctx, nil
-		}
+	rules := r.getRules()
+
+	if rule := matchRules(rules, ctx, traces, false); rule != nil {
+		return rule, ctx, nil
 	}
 
 	if r.domainStrategy != DomainStrategy_IpIfNonMatch || len(ctx.GetTargetDomain()) == 0 || skipDNSResolve {
@@ -121,15 +369,39 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 	ctx = routing_dns.ContextWithDNSClient(ctx, r.dns)
 
 	// Try applying rules again if we have IPs.
-	for _, rule := range r.rules {
-		if rule.Apply(ctx) {
-			return rule, ctx, nil
-		}
+	if rule := matchRules(rules, ctx, traces, true); rule != nil {
+		return rule, ctx, nil
 	}
 
 	return nil, ctx, common.ErrNoClue
 }
 
+func matchRules(rules []*Rule, ctx routing.Context, traces *[]*routing.RuleTrace, resolved bool) *Rule {
+	for i, rule := range rules {
+		if traces == nil {
+			if rule.Apply(ctx) {
+				rule.recordHit()
+				return rule
+			}
+			continue
+		}
+		matched, reason := ExplainCondition(rule.Condition, ctx)
+		*traces = append(*traces, &routing.RuleTrace{
+			Index:        i,
+			RuleTag:      rule.RuleTag,
+			OutboundTag:  rule.Tag,
+			BalancingTag: rule.balancingTag,
+			Matched:      matched,
+			Reason:       reason,
+			Resolved:     resolved,
+		})
+		if matched {
+			return rule
+		}
+	}
+	return nil
+}
+
 // Start implements common.Runnable.
 func (r *Router) Start() error {
 	return nil
@@ -169,8 +441,8 @@ func (r *Route) GetSessionAttributes() map[string]string {
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		r := new(Router)
-		if err := core.RequireFeatures(ctx, func(d dns.Client, ohm outbound.Manager, dispatcher routing.Dispatcher) error {
-			return r.Init(ctx, config.(*Config), d, ohm, dispatcher)
+		if err := core.RequireFeatures(ctx, func(d dns.Client, ohm outbound.Manager, dispatcher routing.Dispatcher, sm stats.Manager) error {
+			return r.Init(ctx, config.(*Config), d, ohm, dispatcher, sm)
 		}); err != nil {
 			return nil, err
 		}
@@ -200,52 +472,18 @@ func init() {
 		for _, v := range simplifiedConfig.Rule {
 			rule := new(RoutingRule)
 
-			for _, geo := range v.Geoip {
-				if geo.Code != "" {
-					filepath := "geoip.dat"
-					if geo.FilePath != "" {
-						filepath = geo.FilePath
-					} else {
-						geo.CountryCode = geo.Code
-					}
-					var err error
-					geo.Cidr, err = geoLoader.LoadIP(filepath, geo.Code)
-					if err != nil {
-						return nil, newError("unable to load geoip").Base(err)
-					}
-				}
+			if err := loadSimplifiedGeoIP(geoLoader, v.Geoip); err != nil {
+				return nil, err
 			}
 			rule.Geoip = v.Geoip
 
-			for _, geo := range v.SourceGeoip {
-				if geo.Code != "" {
-					filepath := "geoip.dat"
-					if geo.FilePath != "" {
-						filepath = geo.FilePath
-					} else {
-						geo.CountryCode = geo.Code
-					}
-					var err error
-					geo.Cidr, err = geoLoader.LoadIP(filepath, geo.Code)
-					if err != nil {
-						return nil, newError("unable to load geoip").Base(err)
-					}
-				}
+			if err := loadSimplifiedGeoIP(geoLoader, v.SourceGeoip); err != nil {
+				return nil, err
 			}
 			rule.SourceGeoip = v.SourceGeoip
 
-			for _, geo := range v.GeoDomain {
-				if geo.Code != "" {
-					filepath := "geosite.dat"
-					if geo.FilePath != "" {
-						filepath = geo.FilePath
-					}
-					var err error
-					geo.Domain, err = geoLoader.LoadGeoSiteWithAttr(filepath, geo.Code)
-					if err != nil {
-						return nil, newError("unable to load geodomain").Base(err)
-					}
-				}
+			if err := loadSimplifiedGeoSite(geoLoader, v.GeoDomain); err != nil {
+				return nil, err
 			}
 			if v.PortList != "" {
 				portList := &cfgcommon.PortList{}
@@ -263,12 +501,36 @@ func init() {
 				}
 				rule.SourcePortList = portList.Build()
 			}
+			for _, window := range v.TimeWindow {
+				timeWindow, err := ParseTimeWindow(window)
+				if err != nil {
+					return nil, err
+				}
+				rule.TimeWindow = append(rule.TimeWindow, timeWindow)
+			}
+			rule.TimeZone = v.TimeZone
+			if err := loadSimplifiedGeoIP(geoLoader, v.ScriptGeoip); err != nil {
+				return nil, err
+			}
+			if err := loadSimplifiedGeoSite(geoLoader, v.ScriptGeosite); err != nil {
+				return nil, err
+			}
+			rule.Script = v.Script
+			rule.ScriptMaxSteps = v.ScriptMaxSteps
+			rule.ScriptGeoip = v.ScriptGeoip
+			rule.ScriptGeosite = v.ScriptGeosite
+			rule.TlsAlpn = v.TlsAlpn
+			rule.TlsVersion = v.TlsVersion
+			rule.TlsFingerprint = v.TlsFingerprint
+			rule.HttpMethod = v.HttpMethod
+			rule.HttpPath = v.HttpPath
 			rule.Domain = v.Domain
 			rule.GeoDomain = v.GeoDomain
 			rule.Networks = v.Networks.GetNetwork()
 			rule.Protocol = v.Protocol
 			rule.Attributes = v.Attributes
 			rule.SetAttribute = append(rule.SetAttribute, v.SetAttribute...)
+			rule.RuleTag = v.RuleTag
 			rule.UserEmail = v.UserEmail
 			rule.InboundTag = v.InboundTag
 			rule.DomainMatcher = v.DomainMatcher
@@ -289,3 +551,39 @@ func init() {
 		return common.CreateObject(ctx, fullConfig)
 	}))
 }
+
+func loadSimplifiedGeoIP(geoLoader geodata.Loader, geoips []*routercommon.GeoIP) error {
+	for _, geo := range geoips {
+		if geo.Code != "" {
+			filepath := "geoip.dat"
+			if geo.FilePath != "" {
+				filepath = geo.FilePath
+			} else {
+				geo.CountryCode = geo.Code
+			}
+			var err error
+			geo.Cidr, err = geoLoader.LoadIP(filepath, geo.Code)
+			if err != nil {
+				return newError("unable to load geoip").Base(err)
+			}
+		}
+	}
+	return nil
+}
+
+func loadSimplifiedGeoSite(geoLoader geodata.Loader, geosites []*routercommon.GeoSite) error {
+	for _, geo := range geosites {
+		if geo.Code != "" {
+			filepath := "geosite.dat"
+			if geo.FilePath != "" {
+				filepath = geo.FilePath
+			}
+			var err error
+			geo.Domain, err = geoLoader.LoadGeoSiteWithAttr(filepath, geo.Code)
+			if err != nil {
+				return newError("unable to load geodomain").Base(err)
+			}
+		}
+	}
+	return nil
+}
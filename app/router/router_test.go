@@ -8,8 +8,10 @@ import (
 
 	. "github.com/v2fly/v2ray-core/v5/app/router"
 	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+	"github.com/v2fly/v2ray-core/v5/app/stats"
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/serial"
 	"github.com/v2fly/v2ray-core/v5/common/session"
 	"github.com/v2fly/v2ray-core/v5/features/outbound"
 	routing_session "github.com/v2fly/v2ray-core/v5/features/routing/session"
@@ -44,7 +46,7 @@ func TestSimpleRouter(t *testing.T) {
 	common.Must(r.Init(context.TODO(), config, mockDNS, &mockOutboundManager{
 		Manager:         mockOhm,
 		HandlerSelector: mockHs,
-	}, nil))
+	}, nil, nil))
 
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2fly.org"), 80)})
 	route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
@@ -83,7 +85,7 @@ func TestRouteSetAttribute(t *testing.T) {
 	common.Must(r.Init(context.TODO(), config, mockDNS, &mockOutboundManager{
 		Manager:         mockOhm,
 		HandlerSelector: mockHs,
-	}, nil))
+	}, nil, nil))
 
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2fly.org"), 80)})
 	route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
@@ -129,7 +131,7 @@ func TestSimpleBalancer(t *testing.T) {
 	common.Must(r.Init(context.TODO(), config, mockDNS, &mockOutboundManager{
 		Manager:         mockOhm,
 		HandlerSelector: mockHs,
-	}, nil))
+	}, nil, nil))
 
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2fly.org"), 80)})
 	route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
@@ -139,6 +141,334 @@ func TestSimpleBalancer(t *testing.T) {
 	}
 }
 
+func TestRuleManager(t *testing.T) {
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "default",
+				},
+				Networks: []net.Network{net.Network_TCP},
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockDNS := mocks.NewDNSClient(mockCtl)
+	mockOhm := mocks.NewOutboundManager(mockCtl)
+	mockHs := mocks.NewOutboundHandlerSelector(mockCtl)
+
+	mockHs.EXPECT().Select(gomock.Eq([]string{"test-"})).Return([]string{"test"}).AnyTimes()
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), config, mockDNS, &mockOutboundManager{
+		Manager:         mockOhm,
+		HandlerSelector: mockHs,
+	}, nil, nil))
+
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2fly.org"), 80)})
+	pick := func() string {
+		route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
+		common.Must(err)
+		return route.GetOutboundTag()
+	}
+
+	common.Must(r.AddRule(&Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_BalancingTag{
+					BalancingTag: "balance",
+				},
+				RuleTag: "added",
+				Domain: []*routercommon.Domain{
+					{Type: routercommon.Domain_RootDomain, Value: "v2fly.org"},
+				},
+			},
+		},
+		BalancingRule: []*BalancingRule{
+			{
+				Tag:              "balance",
+				OutboundSelector: []string{"test-"},
+			},
+		},
+	}, true))
+	if tag := pick(); tag != "test" {
+		t.Error("expect tag 'test', but actually ", tag)
+	}
+
+	if err := r.AddRule(&Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{Tag: "test"},
+				RuleTag:   "added",
+				Networks:  []net.Network{net.Network_UDP},
+			},
+		},
+	}, false); err == nil {
+		t.Error("expect error on duplicated rule tag")
+	}
+
+	if err := r.AddRule(&Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{Tag: "test"},
+				RuleTag:   "twice",
+				Networks:  []net.Network{net.Network_UDP},
+			},
+			{
+				TargetTag: &RoutingRule_Tag{Tag: "test"},
+				RuleTag:   "twice",
+				Networks:  []net.Network{net.Network_TCP},
+			},
+		},
+	}, false); err == nil {
+		t.Error("expect error on rule tag duplicated in the request")
+	}
+	if err := r.RemoveRule("twice"); err == nil {
+		t.Error("expect no rule added on error")
+	}
+
+	common.Must(r.RemoveRule("added"))
+	if tag := pick(); tag != "default" {
+		t.Error("expect tag 'default', but actually ", tag)
+	}
+	if err := r.RemoveRule("added"); err == nil {
+		t.Error("expect error on removing nonexistent rule")
+	}
+
+	common.Must(r.ReplaceRules(&Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{Tag: "replaced"},
+				Networks:  []net.Network{net.Network_TCP},
+			},
+		},
+	}))
+	if tag := pick(); tag != "replaced" {
+		t.Error("expect tag 'replaced', but actually ", tag)
+	}
+	if _, err := r.GetOverrideTarget("balance"); err == nil {
+		t.Error("expect balancer to be removed by ReplaceRules")
+	}
+
+	if err := r.ReplaceRules(&Config{
+		Rule: []*RoutingRule{
+			{TargetTag: &RoutingRule_Tag{Tag: "test"}, RuleTag: "twice", Networks: []net.Network{net.Network_UDP}},
+			{TargetTag: &RoutingRule_Tag{Tag: "test"}, RuleTag: "twice", Networks: []net.Network{net.Network_TCP}},
+		},
+	}); err == nil {
+		t.Error("expect error on duplicated rule tag in ReplaceRules")
+	}
+	if err := r.ReplaceRules(&Config{
+		BalancingRule: []*BalancingRule{
+			{Tag: "balance", OutboundSelector: []string{"test-"}},
+			{Tag: "balance", OutboundSelector: []string{"test-"}},
+		},
+	}); err == nil {
+		t.Error("expect error on duplicated balancer tag in ReplaceRules")
+	}
+	if tag := pick(); tag != "replaced" {
+		t.Error("expect rules unchanged on error, but actually ", tag)
+	}
+
+	if err := new(Router).Init(context.TODO(), &Config{
+		Rule: []*RoutingRule{
+			{TargetTag: &RoutingRule_Tag{Tag: "test"}, RuleTag: "twice", Networks: []net.Network{net.Network_UDP}},
+			{TargetTag: &RoutingRule_Tag{Tag: "test"}, RuleTag: "twice", Networks: []net.Network{net.Network_TCP}},
+		},
+	}, mockDNS, nil, nil, nil); err == nil {
+		t.Error("expect error on duplicated rule tag in Init")
+	}
+}
+
+func TestRuleStats(t *testing.T) {
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{Tag: "direct"},
+				RuleTag:   "direct-domains",
+				Domain: []*routercommon.Domain{
+					{Type: routercommon.Domain_RootDomain, Value: "v2fly.org"},
+				},
+			},
+			{
+				TargetTag: &RoutingRule_Tag{Tag: "proxy"},
+				Networks:  []net.Network{net.Network_TCP},
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockDNS := mocks.NewDNSClient(mockCtl)
+	statsManager, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), config, mockDNS, nil, nil, statsManager))
+
+	for _, domain := range []string{"v2fly.org", "www.v2fly.org", "example.com"} {
+		ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress(domain), 80)})
+		_, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
+		common.Must(err)
+	}
+
+	counter := statsManager.GetCounter("rule>>>direct-domains>>>hits")
+	if counter == nil {
+		t.Fatal("rule hit counter not registered")
+	}
+	if v := counter.Value(); v != 2 {
+		t.Error("expect 2 hits, but actually ", v)
+	}
+
+	ruleStats := r.GetRuleStats("", false)
+	if len(ruleStats) != 2 {
+		t.Fatal("expect stats of 2 rules, but actually ", len(ruleStats))
+	}
+	if ruleStats[1].Hits != 1 || ruleStats[1].OutboundTag != "proxy" || ruleStats[1].LastMatch.IsZero() {
+		t.Error("unexpected stats of untagged rule: ", ruleStats[1])
+	}
+
+	ruleStats = r.GetRuleStats("direct-domains", true)
+	if len(ruleStats) != 1 || ruleStats[0].Hits != 2 {
+		t.Error("unexpected stats of tagged rule: ", ruleStats)
+	}
+	if v := counter.Value(); v != 0 {
+		t.Error("expect counter to be reset, but actually ", v)
+	}
+
+	if err := r.AddRule(&Config{
+		Rule: []*RoutingRule{
+			{TargetTag: &RoutingRule_Tag{Tag: "proxy"}, RuleTag: "direct-domains", Networks: []net.Network{net.Network_UDP}},
+		},
+	}, true); err == nil {
+		t.Error("expect error on sharing the hit counter of a rule tag")
+	}
+	if ruleStats := r.GetRuleStats("direct-domains", false); len(ruleStats) != 1 || ruleStats[0].Index != 0 {
+		t.Error("expect a single rule of the tag, but got ", ruleStats)
+	}
+
+	common.Must(r.RemoveRule("direct-domains"))
+	if statsManager.GetCounter("rule>>>direct-domains>>>hits") != nil {
+		t.Error("expect rule hit counter to be unregistered")
+	}
+}
+
+func TestExplainRoute(t *testing.T) {
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{Tag: "direct"},
+				RuleTag:   "direct-domains",
+				Domain: []*routercommon.Domain{
+					{Type: routercommon.Domain_RootDomain, Value: "v2fly.org"},
+				},
+			},
+			{
+				TargetTag:  &RoutingRule_Tag{Tag: "blocked"},
+				Networks:   []net.Network{net.Network_TCP},
+				InboundTag: []string{"socks"},
+			},
+			{
+				TargetTag: &RoutingRule_Tag{Tag: "proxy"},
+				Networks:  []net.Network{net.Network_TCP},
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), config, mocks.NewDNSClient(mockCtl), nil, nil, nil))
+
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("example.com"), 80)})
+	route, traces, err := r.ExplainRoute(routing_session.AsRoutingContext(ctx))
+	common.Must(err)
+	if tag := route.GetOutboundTag(); tag != "proxy" {
+		t.Error("expect tag 'proxy', but actually ", tag)
+	}
+	if len(traces) != 3 {
+		t.Fatal("expect 3 traces, but actually ", len(traces))
+	}
+	if traces[0].Matched || traces[0].RuleTag != "direct-domains" || traces[0].Reason != "domain matcher: no match for example.com" {
+		t.Error("unexpected trace: ", traces[0])
+	}
+	if traces[1].Matched || traces[1].Reason != "inbound tag: no inbound tag" {
+		t.Error("unexpected trace: ", traces[1])
+	}
+	if !traces[2].Matched || traces[2].Index != 2 {
+		t.Error("unexpected trace: ", traces[2])
+	}
+
+	ruleStats := r.GetRuleStats("", false)
+	if ruleStats[2].Hits != 0 {
+		t.Error("expect explaining not to count hits")
+	}
+}
+
+func TestExplainRouteBalancer(t *testing.T) {
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_BalancingTag{BalancingTag: "balance"},
+				Networks:  []net.Network{net.Network_TCP},
+			},
+		},
+		BalancingRule: []*BalancingRule{
+			{
+				Tag:              "balance",
+				OutboundSelector: []string{"test-"},
+				Strategy:         "weighted",
+				StrategySettings: serial.ToTypedMessage(&StrategyWeightedConfig{}),
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockHs := mocks.NewOutboundHandlerSelector(mockCtl)
+	mockHs.EXPECT().Select(gomock.Eq([]string{"test-"})).Return([]string{"test-a", "test-b"}).AnyTimes()
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), config, mocks.NewDNSClient(mockCtl), &mockOutboundManager{
+		Manager:         mocks.NewOutboundManager(mockCtl),
+		HandlerSelector: mockHs,
+	}, nil, nil))
+
+	ctx := routing_session.AsRoutingContext(session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2fly.org"), 80)}))
+	for i := 0; i < 3; i++ {
+		route, traces, err := r.ExplainRoute(ctx)
+		common.Must(err)
+		if tag := route.GetOutboundTag(); tag != "" {
+			t.Error("expect no outbound picked by explaining, but actually ", tag)
+		}
+		if len(traces) != 1 || len(traces[0].Candidates) != 2 {
+			t.Fatal("expect candidates of the balancer in the trace, but actually ", traces)
+		}
+	}
+
+	// The round robin starts over, as if the route was never explained.
+	for _, expected := range []string{"test-a", "test-b", "test-a"} {
+		route, err := r.PickRoute(ctx)
+		common.Must(err)
+		if tag := route.GetOutboundTag(); tag != expected {
+			t.Error("expect tag ", expected, ", but actually ", tag)
+		}
+	}
+
+	common.Must(r.SetOverrideTarget("balance", "test-b"))
+	route, _, err := r.ExplainRoute(ctx)
+	common.Must(err)
+	if tag := route.GetOutboundTag(); tag != "test-b" {
+		t.Error("expect the override target, but actually ", tag)
+	}
+}
+
 /*
 
 Do not work right now: need a full client setup
@@ -179,7 +509,7 @@ func TestLeastLoadBalancer(t *testing.T) {
 	common.Must(r.Init(context.TODO(), config, mockDNS, &mockOutboundManager{
 		Manager:         mockOhm,
 		HandlerSelector: mockHs,
-	}, nil))
+	}, nil, nil))
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2ray.com"), 80)})
 	route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
 	common.Must(err)
@@ -213,7 +543,7 @@ func TestIPOnDemand(t *testing.T) {
 	mockDNS.EXPECT().LookupIP(gomock.Eq("v2fly.org")).Return([]net.IP{{192, 168, 0, 1}}, nil).AnyTimes()
 
 	r := new(Router)
-	common.Must(r.Init(context.TODO(), config, mockDNS, nil, nil))
+	common.Must(r.Init(context.TODO(), config, mockDNS, nil, nil, nil))
 
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2fly.org"), 80)})
 	route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
@@ -248,7 +578,7 @@ func TestIPIfNonMatchDomain(t *testing.T) {
 	mockDNS.EXPECT().LookupIP(gomock.Eq("v2fly.org")).Return([]net.IP{{192, 168, 0, 1}}, nil).AnyTimes()
 
 	r := new(Router)
-	common.Must(r.Init(context.TODO(), config, mockDNS, nil, nil))
+	common.Must(r.Init(context.TODO(), config, mockDNS, nil, nil, nil))
 
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2fly.org"), 80)})
 	route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
@@ -282,7 +612,7 @@ func TestIPIfNonMatchIP(t *testing.T) {
 	mockDNS := mocks.NewDNSClient(mockCtl)
 
 	r := new(Router)
-	common.Must(r.Init(context.TODO(), config, mockDNS, nil, nil))
+	common.Must(r.Init(context.TODO(), config, mockDNS, nil, nil, nil))
 
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.LocalHostIP, 80)})
 	route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
@@ -0,0 +1,110 @@
+package router
+
+import (
+	"context"
+	"hash/fnv"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/dice"
+	"github.com/v2fly/v2ray-core/v5/features/extension"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+// ConsistentHashStrategy maps a key of the connection to an outbound with rendezvous hashing,
+// so that connections with the same key stick to the same outbound, and only keys mapped
+// to a dead outbound are moved when it dies.
+type ConsistentHashStrategy struct {
+	ctx         context.Context
+	settings    *StrategyConsistentHashConfig
+	observatory extension.Observatory
+}
+
+// NewConsistentHashStrategy creates a new ConsistentHashStrategy with settings
+func NewConsistentHashStrategy(settings *StrategyConsistentHashConfig) *ConsistentHashStrategy {
+	return &ConsistentHashStrategy{
+		settings: settings,
+	}
+}
+
+func (s *ConsistentHashStrategy) InjectContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+func (s *ConsistentHashStrategy) GetPrincipleTarget(candidates []string) []string {
+	return s.aliveCandidates(candidates)
+}
+
+// PickOutbound implements BalancingStrategy. Without a routing context there is no key to hash,
+// so an alive candidate is picked at random.
+func (s *ConsistentHashStrategy) PickOutbound(candidates []string) string {
+	candidates = s.aliveCandidates(candidates)
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[dice.Roll(len(candidates))]
+}
+
+// PickOutboundForContext implements ContextualBalancingStrategy.
+func (s *ConsistentHashStrategy) PickOutboundForContext(ctx routing.Context, candidates []string) string {
+	key := s.hashKey(ctx)
+	if key == "" {
+		return s.PickOutbound(candidates)
+	}
+	return rendezvousPick(key, s.aliveCandidates(candidates))
+}
+
+func (s *ConsistentHashStrategy) hashKey(ctx routing.Context) string {
+	switch s.settings.Key {
+	case StrategyConsistentHashConfig_UserEmail:
+		return ctx.GetUser()
+	case StrategyConsistentHashConfig_TargetDomain:
+		if domain := ctx.GetTargetDomain(); domain != "" {
+			return domain
+		}
+		if ips := ctx.GetTargetIPs(); len(ips) > 0 {
+			return ips[0].String()
+		}
+	default:
+		if ips := ctx.GetSourceIPs(); len(ips) > 0 {
+			return ips[0].String()
+		}
+	}
+	return ""
+}
+
+// rendezvousPick returns the candidate with the highest score for key.
+func rendezvousPick(key string, candidates []string) string {
+	var picked string
+	var best uint64
+	for _, candidate := range candidates {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write([]byte(candidate))
+		if score := mix64(h.Sum64()); picked == "" || score > best {
+			picked, best = candidate, score
+		}
+	}
+	return picked
+}
+
+// mix64 is the finalizer of splitmix64, which spreads FNV hashes of similar inputs.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func (s *ConsistentHashStrategy) aliveCandidates(candidates []string) []string {
+	if s.observatory == nil && s.ctx != nil {
+		s.observatory = findObservatory(s.ctx, s.settings.ObserverTag)
+	}
+	return filterAliveOutbounds(s.ctx, s.observatory, candidates)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*StrategyConsistentHashConfig)(nil), nil))
+}
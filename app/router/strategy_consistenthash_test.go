@@ -0,0 +1,88 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	routing_session "github.com/v2fly/v2ray-core/v5/features/routing/session"
+)
+
+type staticObservatory struct {
+	result *observatory.ObservationResult
+}
+
+func (o *staticObservatory) Type() interface{} { return nil }
+func (o *staticObservatory) Start() error      { return nil }
+func (o *staticObservatory) Close() error      { return nil }
+
+func (o *staticObservatory) GetObservation(ctx context.Context) (proto.Message, error) {
+	return o.result, nil
+}
+
+func TestConsistentHashStrategy(t *testing.T) {
+	candidates := []string{"a", "b", "c", "d"}
+	observer := &staticObservatory{result: &observatory.ObservationResult{}}
+	strategy := NewConsistentHashStrategy(&StrategyConsistentHashConfig{})
+	strategy.observatory = observer
+
+	picks := make(map[string]string)
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		source := fmt.Sprintf("10.0.%d.%d", i/256, i%256)
+		ctx := &routing_session.Context{
+			Inbound: &session.Inbound{Source: net.TCPDestination(net.ParseAddress(source), 1234)},
+		}
+		tag := strategy.PickOutboundForContext(ctx, candidates)
+		if again := strategy.PickOutboundForContext(ctx, candidates); again != tag {
+			t.Fatal("expect ", source, " to stick to ", tag, " but got ", again)
+		}
+		picks[source] = tag
+		counts[tag]++
+	}
+	for _, candidate := range candidates {
+		if counts[candidate] < 150 {
+			t.Error("outbound ", candidate, " is picked only ", counts[candidate], " times")
+		}
+	}
+
+	observer.result.Status = []*observatory.OutboundStatus{{OutboundTag: "b", Alive: false}}
+	for source, previous := range picks {
+		ctx := &routing_session.Context{
+			Inbound: &session.Inbound{Source: net.TCPDestination(net.ParseAddress(source), 1234)},
+		}
+		tag := strategy.PickOutboundForContext(ctx, candidates)
+		if tag == "b" {
+			t.Fatal("dead outbound picked for ", source)
+		}
+		if previous != "b" && tag != previous {
+			t.Error("expect ", source, " to stay on ", previous, " but moved to ", tag)
+		}
+	}
+}
+
+func TestConsistentHashStrategyKey(t *testing.T) {
+	ctx := &routing_session.Context{
+		Inbound:  &session.Inbound{Source: net.TCPDestination(net.ParseAddress("10.0.0.1"), 1234)},
+		Outbound: &session.Outbound{Target: net.TCPDestination(net.DomainAddress("www.v2fly.org"), 443)},
+	}
+	cases := []struct {
+		key    StrategyConsistentHashConfig_Key
+		output string
+	}{
+		{StrategyConsistentHashConfig_SourceIp, "10.0.0.1"},
+		{StrategyConsistentHashConfig_UserEmail, ""},
+		{StrategyConsistentHashConfig_TargetDomain, "www.v2fly.org"},
+	}
+	for _, tc := range cases {
+		strategy := NewConsistentHashStrategy(&StrategyConsistentHashConfig{Key: tc.key})
+		if key := strategy.hashKey(ctx); key != tc.output {
+			t.Error("for ", tc.key, " expect ", tc.output, " but got ", key)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package router
+
+import (
+	"context"
+	"sync"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/features/extension"
+)
+
+// WeightedStrategy distributes connections to outbounds in proportion to their weights,
+// using smooth weighted round-robin, so that the split is deterministic.
+type WeightedStrategy struct {
+	ctx         context.Context
+	settings    *StrategyWeightedConfig
+	weights     *WeightManager
+	observatory extension.Observatory
+
+	access  sync.Mutex
+	current map[string]float64
+}
+
+// NewWeightedStrategy creates a new WeightedStrategy with settings
+func NewWeightedStrategy(settings *StrategyWeightedConfig) *WeightedStrategy {
+	return &WeightedStrategy{
+		settings: settings,
+		weights: NewWeightManager(
+			settings.Weights, 1,
+			func(value, weight float64) float64 {
+				return value * weight
+			},
+		),
+		current: make(map[string]float64),
+	}
+}
+
+func (s *WeightedStrategy) InjectContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+func (s *WeightedStrategy) GetPrincipleTarget(candidates []string) []string {
+	return s.filterCandidates(candidates)
+}
+
+func (s *WeightedStrategy) PickOutbound(candidates []string) string {
+	candidates = s.filterCandidates(candidates)
+
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	var picked string
+	var total float64
+	current := make(map[string]float64, len(candidates))
+	for _, candidate := range candidates {
+		weight := s.weights.Get(candidate)
+		if weight <= 0 {
+			continue
+		}
+		total += weight
+		current[candidate] = s.current[candidate] + weight
+		if picked == "" || current[candidate] > current[picked] {
+			picked = candidate
+		}
+	}
+	if picked != "" {
+		current[picked] -= total
+	}
+	// state of outbounds no longer selected is dropped
+	s.current = current
+	return picked
+}
+
+func (s *WeightedStrategy) filterCandidates(candidates []string) []string {
+	if !s.settings.AliveOnly {
+		return candidates
+	}
+	if s.observatory == nil && s.ctx != nil {
+		s.observatory = findObservatory(s.ctx, s.settings.ObserverTag)
+	}
+	return filterAliveOutbounds(s.ctx, s.observatory, candidates)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*StrategyWeightedConfig)(nil), nil))
+}
@@ -0,0 +1,53 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+)
+
+func TestWeightedStrategy(t *testing.T) {
+	strategy := NewWeightedStrategy(&StrategyWeightedConfig{
+		Weights: []*StrategyWeight{
+			{Match: "a", Value: 70},
+			{Match: "b", Value: 20},
+			{Regexp: true, Match: "^c$", Value: 10},
+		},
+	})
+	candidates := []string{"a", "b", "c"}
+
+	counts := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		counts[strategy.PickOutbound(candidates)]++
+	}
+	if counts["a"] != 70 || counts["b"] != 20 || counts["c"] != 10 {
+		t.Error("unexpected split: ", counts)
+	}
+
+	// outbounds without weight setting have weight 1
+	counts = make(map[string]int)
+	for i := 0; i < 102; i++ {
+		counts[strategy.PickOutbound([]string{"b", "d"})]++
+	}
+	if counts["b"] != 97 || counts["d"] != 5 {
+		t.Error("unexpected split: ", counts)
+	}
+}
+
+func TestWeightedStrategyAliveOnly(t *testing.T) {
+	strategy := NewWeightedStrategy(&StrategyWeightedConfig{
+		Weights: []*StrategyWeight{
+			{Match: "a", Value: 70},
+			{Match: "b", Value: 30},
+		},
+		AliveOnly: true,
+	})
+	strategy.observatory = &staticObservatory{result: &observatory.ObservationResult{
+		Status: []*observatory.OutboundStatus{{OutboundTag: "a", Alive: false}},
+	}}
+	for i := 0; i < 10; i++ {
+		if tag := strategy.PickOutbound([]string{"a", "b"}); tag != "b" {
+			t.Fatal("expect b but got ", tag)
+		}
+	}
+}
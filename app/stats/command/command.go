@@ -5,6 +5,8 @@ package command
 import (
 	"context"
 	"runtime"
+	"sort"
+	"strings"
 	"time"
 
 	grpc "google.golang.org/grpc"
@@ -34,36 +36,46 @@ func (s *statsServer) GetStats(ctx context.Context, request *GetStatsRequest) (*
 	if c == nil {
 		return nil, newError(request.Name, " not found.")
 	}
-	var value int64
-	if request.Reset_ {
-		value = c.Set(0)
-	} else {
-		value = c.Value()
-	}
 	return &GetStatsResponse{
 		Stat: &Stat{
 			Name:  request.Name,
-			Value: value,
+			Value: counterValue(c, request.Reset_),
 		},
 	}, nil
 }
 
-func (s *statsServer) QueryStats(ctx context.Context, request *QueryStatsRequest) (*QueryStatsResponse, error) {
-	mgroup := &strmatcher.LinearIndexMatcher{}
-	if request.Pattern != "" {
-		request.Patterns = append(request.Patterns, request.Pattern)
+// counterValue returns the value of the counter, resetting it if reset is true, except gauges which are never reset.
+func counterValue(c feature_stats.Counter, reset bool) int64 {
+	if _, isGauge := c.(feature_stats.Gauge); reset && !isGauge {
+		return c.Set(0)
 	}
+	return c.Value()
+}
+
+func newNameMatcher(patterns []string, regexp bool) (*strmatcher.LinearIndexMatcher, error) {
+	mgroup := &strmatcher.LinearIndexMatcher{}
 	t := strmatcher.Substr
-	if request.Regexp {
+	if regexp {
 		t = strmatcher.Regex
 	}
-	for _, p := range request.Patterns {
+	for _, p := range patterns {
 		m, err := t.New(p)
 		if err != nil {
 			return nil, err
 		}
 		mgroup.Add(m)
 	}
+	return mgroup, nil
+}
+
+func (s *statsServer) QueryStats(ctx context.Context, request *QueryStatsRequest) (*QueryStatsResponse, error) {
+	if request.Pattern != "" {
+		request.Patterns = append(request.Patterns, request.Pattern)
+	}
+	mgroup, err := newNameMatcher(request.Patterns, request.Regexp)
+	if err != nil {
+		return nil, err
+	}
 
 	response := &QueryStatsResponse{}
 
@@ -74,15 +86,9 @@ func (s *statsServer) QueryStats(ctx context.Context, request *QueryStatsRequest
 
 	manager.VisitCounters(func(name string, c feature_stats.Counter) bool {
 		if mgroup.Size() == 0 || len(mgroup.Match(name)) > 0 {
-			var value int64
-			if request.Reset_ {
-				value = c.Set(0)
-			} else {
-				value = c.Value()
-			}
 			response.Stat = append(response.Stat, &Stat{
 				Name:  name,
-				Value: value,
+				Value: counterValue(c, request.Reset_),
 			})
 		}
 		return true
@@ -91,6 +97,90 @@ func (s *statsServer) QueryStats(ctx context.Context, request *QueryStatsRequest
 	return response, nil
 }
 
+func (s *statsServer) QueryStatsHistory(ctx context.Context, request *QueryStatsHistoryRequest) (*QueryStatsHistoryResponse, error) {
+	mgroup, err := newNameMatcher(request.Patterns, request.Regexp)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, ok := s.stats.(*stats.Manager)
+	if !ok {
+		return nil, newError("QueryStatsHistory only works its own stats.Manager.")
+	}
+
+	interval := time.Second
+	if request.Interval == QueryStatsHistoryRequest_Minute {
+		interval = time.Minute
+	}
+
+	var names []string
+	manager.VisitCounters(func(name string, c feature_stats.Counter) bool {
+		if mgroup.Size() == 0 || len(mgroup.Match(name)) > 0 {
+			names = append(names, name)
+		}
+		return true
+	})
+	sort.Strings(names)
+
+	response := &QueryStatsHistoryResponse{}
+	for _, name := range names {
+		samples, err := manager.GetCounterHistory(name, interval)
+		if err != nil {
+			return nil, err
+		}
+		if len(samples) == 0 {
+			continue
+		}
+		history := &StatsHistory{Name: name}
+		for i, sample := range samples {
+			var rate float64
+			if i > 0 {
+				rate = sample.Rate(samples[i-1])
+			}
+			history.Sample = append(history.Sample, &StatsSample{
+				Timestamp: sample.Time.Unix(),
+				Value:     sample.Value,
+				Rate:      rate,
+			})
+		}
+		if limit := int(request.Limit); limit > 0 && len(history.Sample) > limit {
+			history.Sample = history.Sample[len(history.Sample)-limit:]
+		}
+		response.History = append(response.History, history)
+	}
+
+	return response, nil
+}
+
+func (s *statsServer) GetTopDestinations(ctx context.Context, request *GetTopDestinationsRequest) (*GetTopDestinationsResponse, error) {
+	recorder, ok := s.stats.(feature_stats.DestinationRecorder)
+	if !ok {
+		return nil, newError("top destinations are not supported by the stats manager")
+	}
+
+	response := &GetTopDestinationsResponse{}
+	for _, destination := range recorder.TopDestinations() {
+		if request.OutboundTag != "" && destination.OutboundTag != request.OutboundTag {
+			continue
+		}
+		if request.User != "" && !strings.EqualFold(destination.User, request.User) {
+			continue
+		}
+		response.Destination = append(response.Destination, &DestinationStat{
+			OutboundTag: destination.OutboundTag,
+			User:        destination.User,
+			Domain:      destination.Domain,
+			Bytes:       destination.Bytes,
+			Error:       destination.Error,
+		})
+		if request.Limit > 0 && len(response.Destination) >= int(request.Limit) {
+			break
+		}
+	}
+
+	return response, nil
+}
+
 func (s *statsServer) GetSysStats(ctx context.Context, request *SysStatsRequest) (*SysStatsResponse, error) {
 	var rtm runtime.MemStats
 	runtime.ReadMemStats(&rtm)
@@ -16,11 +16,58 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type QueryStatsHistoryRequest_Interval int32
+
+const (
+	QueryStatsHistoryRequest_Second QueryStatsHistoryRequest_Interval = 0
+	QueryStatsHistoryRequest_Minute QueryStatsHistoryRequest_Interval = 1
+)
+
+// Enum value maps for QueryStatsHistoryRequest_Interval.
+var (
+	QueryStatsHistoryRequest_Interval_name = map[int32]string{
+		0: "Second",
+		1: "Minute",
+	}
+	QueryStatsHistoryRequest_Interval_value = map[string]int32{
+		"Second": 0,
+		"Minute": 1,
+	}
+)
+
+func (x QueryStatsHistoryRequest_Interval) Enum() *QueryStatsHistoryRequest_Interval {
+	p := new(QueryStatsHistoryRequest_Interval)
+	*p = x
+	return p
+}
+
+func (x QueryStatsHistoryRequest_Interval) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QueryStatsHistoryRequest_Interval) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_stats_command_command_proto_enumTypes[0].Descriptor()
+}
+
+func (QueryStatsHistoryRequest_Interval) Type() protoreflect.EnumType {
+	return &file_app_stats_command_command_proto_enumTypes[0]
+}
+
+func (x QueryStatsHistoryRequest_Interval) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QueryStatsHistoryRequest_Interval.Descriptor instead.
+func (QueryStatsHistoryRequest_Interval) EnumDescriptor() ([]byte, []int) {
+	return file_app_stats_command_command_proto_rawDescGZIP(), []int{7, 0}
+}
+
 type GetStatsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Name of the stat counter.
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	// Whether or not to reset the counter to fetching its value.
+	// Whether or not to reset the counter to fetching its value. Gauges, such as
+	// the connections and the online users, are never reset.
 	Reset_        bool `protobuf:"varint,2,opt,name=reset,proto3" json:"reset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -431,6 +478,418 @@ func (x *SysStatsResponse) GetUptime() uint32 {
 	return 0
 }
 
+type QueryStatsHistoryRequest struct {
+	state    protoimpl.MessageState            `protogen:"open.v1"`
+	Patterns []string                          `protobuf:"bytes,1,rep,name=patterns,proto3" json:"patterns,omitempty"`
+	Regexp   bool                              `protobuf:"varint,2,opt,name=regexp,proto3" json:"regexp,omitempty"`
+	Interval QueryStatsHistoryRequest_Interval `protobuf:"varint,3,opt,name=interval,proto3,enum=v2ray.core.app.stats.command.QueryStatsHistoryRequest_Interval" json:"interval,omitempty"`
+	// Maximum number of the latest samples returned for each counter, all samples if 0.
+	Limit         uint32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryStatsHistoryRequest) Reset() {
+	*x = QueryStatsHistoryRequest{}
+	mi := &file_app_stats_command_command_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryStatsHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryStatsHistoryRequest) ProtoMessage() {}
+
+func (x *QueryStatsHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_stats_command_command_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryStatsHistoryRequest.ProtoReflect.Descriptor instead.
+func (*QueryStatsHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_app_stats_command_command_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *QueryStatsHistoryRequest) GetPatterns() []string {
+	if x != nil {
+		return x.Patterns
+	}
+	return nil
+}
+
+func (x *QueryStatsHistoryRequest) GetRegexp() bool {
+	if x != nil {
+		return x.Regexp
+	}
+	return false
+}
+
+func (x *QueryStatsHistoryRequest) GetInterval() QueryStatsHistoryRequest_Interval {
+	if x != nil {
+		return x.Interval
+	}
+	return QueryStatsHistoryRequest_Second
+}
+
+func (x *QueryStatsHistoryRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type StatsSample struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unix timestamp in seconds when the sample was taken.
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Value     int64 `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Average change per second since the previous sample. Resets of the
+	// counter are not counted as changes.
+	Rate          float64 `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsSample) Reset() {
+	*x = StatsSample{}
+	mi := &file_app_stats_command_command_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsSample) ProtoMessage() {}
+
+func (x *StatsSample) ProtoReflect() protoreflect.Message {
+	mi := &file_app_stats_command_command_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsSample.ProtoReflect.Descriptor instead.
+func (*StatsSample) Descriptor() ([]byte, []int) {
+	return file_app_stats_command_command_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StatsSample) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *StatsSample) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *StatsSample) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+type StatsHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Sample        []*StatsSample         `protobuf:"bytes,2,rep,name=sample,proto3" json:"sample,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsHistory) Reset() {
+	*x = StatsHistory{}
+	mi := &file_app_stats_command_command_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsHistory) ProtoMessage() {}
+
+func (x *StatsHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_app_stats_command_command_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsHistory.ProtoReflect.Descriptor instead.
+func (*StatsHistory) Descriptor() ([]byte, []int) {
+	return file_app_stats_command_command_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StatsHistory) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StatsHistory) GetSample() []*StatsSample {
+	if x != nil {
+		return x.Sample
+	}
+	return nil
+}
+
+type QueryStatsHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	History       []*StatsHistory        `protobuf:"bytes,1,rep,name=history,proto3" json:"history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryStatsHistoryResponse) Reset() {
+	*x = QueryStatsHistoryResponse{}
+	mi := &file_app_stats_command_command_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryStatsHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryStatsHistoryResponse) ProtoMessage() {}
+
+func (x *QueryStatsHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_stats_command_command_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryStatsHistoryResponse.ProtoReflect.Descriptor instead.
+func (*QueryStatsHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_app_stats_command_command_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *QueryStatsHistoryResponse) GetHistory() []*StatsHistory {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type GetTopDestinationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only the destinations through the outbound, if not empty.
+	OutboundTag string `protobuf:"bytes,1,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
+	// Only the destinations of the user, if not empty.
+	User string `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	// Maximum number of the destinations returned, all destinations if 0.
+	Limit         uint32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopDestinationsRequest) Reset() {
+	*x = GetTopDestinationsRequest{}
+	mi := &file_app_stats_command_command_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopDestinationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopDestinationsRequest) ProtoMessage() {}
+
+func (x *GetTopDestinationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_stats_command_command_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopDestinationsRequest.ProtoReflect.Descriptor instead.
+func (*GetTopDestinationsRequest) Descriptor() ([]byte, []int) {
+	return file_app_stats_command_command_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetTopDestinationsRequest) GetOutboundTag() string {
+	if x != nil {
+		return x.OutboundTag
+	}
+	return ""
+}
+
+func (x *GetTopDestinationsRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *GetTopDestinationsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type DestinationStat struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	OutboundTag string                 `protobuf:"bytes,1,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
+	User        string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Domain      string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Estimated traffic in bytes, which is overestimated by at most error bytes.
+	Bytes         int64 `protobuf:"varint,4,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Error         int64 `protobuf:"varint,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DestinationStat) Reset() {
+	*x = DestinationStat{}
+	mi := &file_app_stats_command_command_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DestinationStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestinationStat) ProtoMessage() {}
+
+func (x *DestinationStat) ProtoReflect() protoreflect.Message {
+	mi := &file_app_stats_command_command_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestinationStat.ProtoReflect.Descriptor instead.
+func (*DestinationStat) Descriptor() ([]byte, []int) {
+	return file_app_stats_command_command_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DestinationStat) GetOutboundTag() string {
+	if x != nil {
+		return x.OutboundTag
+	}
+	return ""
+}
+
+func (x *DestinationStat) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *DestinationStat) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *DestinationStat) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *DestinationStat) GetError() int64 {
+	if x != nil {
+		return x.Error
+	}
+	return 0
+}
+
+type GetTopDestinationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Destination   []*DestinationStat     `protobuf:"bytes,1,rep,name=destination,proto3" json:"destination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopDestinationsResponse) Reset() {
+	*x = GetTopDestinationsResponse{}
+	mi := &file_app_stats_command_command_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopDestinationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopDestinationsResponse) ProtoMessage() {}
+
+func (x *GetTopDestinationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_stats_command_command_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopDestinationsResponse.ProtoReflect.Descriptor instead.
+func (*GetTopDestinationsResponse) Descriptor() ([]byte, []int) {
+	return file_app_stats_command_command_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetTopDestinationsResponse) GetDestination() []*DestinationStat {
+	if x != nil {
+		return x.Destination
+	}
+	return nil
+}
+
 type Config struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -439,7 +898,7 @@ type Config struct {
 
 func (x *Config) Reset() {
 	*x = Config{}
-	mi := &file_app_stats_command_command_proto_msgTypes[7]
+	mi := &file_app_stats_command_command_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -451,7 +910,7 @@ func (x *Config) String() string {
 func (*Config) ProtoMessage() {}
 
 func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_app_stats_command_command_proto_msgTypes[7]
+	mi := &file_app_stats_command_command_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -464,7 +923,7 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Config.ProtoReflect.Descriptor instead.
 func (*Config) Descriptor() ([]byte, []int) {
-	return file_app_stats_command_command_proto_rawDescGZIP(), []int{7}
+	return file_app_stats_command_command_proto_rawDescGZIP(), []int{14}
 }
 
 var File_app_stats_command_command_proto protoreflect.FileDescriptor
@@ -501,14 +960,47 @@ const file_app_stats_command_command_proto_rawDesc = "" +
 	"\vLiveObjects\x18\b \x01(\x04R\vLiveObjects\x12\"\n" +
 	"\fPauseTotalNs\x18\t \x01(\x04R\fPauseTotalNs\x12\x16\n" +
 	"\x06Uptime\x18\n" +
-	" \x01(\rR\x06Uptime\"\"\n" +
+	" \x01(\rR\x06Uptime\"\xe5\x01\n" +
+	"\x18QueryStatsHistoryRequest\x12\x1a\n" +
+	"\bpatterns\x18\x01 \x03(\tR\bpatterns\x12\x16\n" +
+	"\x06regexp\x18\x02 \x01(\bR\x06regexp\x12[\n" +
+	"\binterval\x18\x03 \x01(\x0e2?.v2ray.core.app.stats.command.QueryStatsHistoryRequest.IntervalR\binterval\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\rR\x05limit\"\"\n" +
+	"\bInterval\x12\n" +
+	"\n" +
+	"\x06Second\x10\x00\x12\n" +
+	"\n" +
+	"\x06Minute\x10\x01\"U\n" +
+	"\vStatsSample\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value\x12\x12\n" +
+	"\x04rate\x18\x03 \x01(\x01R\x04rate\"e\n" +
+	"\fStatsHistory\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12A\n" +
+	"\x06sample\x18\x02 \x03(\v2).v2ray.core.app.stats.command.StatsSampleR\x06sample\"a\n" +
+	"\x19QueryStatsHistoryResponse\x12D\n" +
+	"\ahistory\x18\x01 \x03(\v2*.v2ray.core.app.stats.command.StatsHistoryR\ahistory\"h\n" +
+	"\x19GetTopDestinationsRequest\x12!\n" +
+	"\foutbound_tag\x18\x01 \x01(\tR\voutboundTag\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\rR\x05limit\"\x8c\x01\n" +
+	"\x0fDestinationStat\x12!\n" +
+	"\foutbound_tag\x18\x01 \x01(\tR\voutboundTag\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x14\n" +
+	"\x05bytes\x18\x04 \x01(\x03R\x05bytes\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\x03R\x05error\"m\n" +
+	"\x1aGetTopDestinationsResponse\x12O\n" +
+	"\vdestination\x18\x01 \x03(\v2-.v2ray.core.app.stats.command.DestinationStatR\vdestination\"\"\n" +
 	"\x06Config:\x18\x82\xb5\x18\x14\n" +
-	"\vgrpcservice\x12\x05stats2\xde\x02\n" +
+	"\vgrpcservice\x12\x05stats2\xf3\x04\n" +
 	"\fStatsService\x12k\n" +
 	"\bGetStats\x12-.v2ray.core.app.stats.command.GetStatsRequest\x1a..v2ray.core.app.stats.command.GetStatsResponse\"\x00\x12q\n" +
 	"\n" +
 	"QueryStats\x12/.v2ray.core.app.stats.command.QueryStatsRequest\x1a0.v2ray.core.app.stats.command.QueryStatsResponse\"\x00\x12n\n" +
-	"\vGetSysStats\x12-.v2ray.core.app.stats.command.SysStatsRequest\x1a..v2ray.core.app.stats.command.SysStatsResponse\"\x00Bu\n" +
+	"\vGetSysStats\x12-.v2ray.core.app.stats.command.SysStatsRequest\x1a..v2ray.core.app.stats.command.SysStatsResponse\"\x00\x12\x86\x01\n" +
+	"\x11QueryStatsHistory\x126.v2ray.core.app.stats.command.QueryStatsHistoryRequest\x1a7.v2ray.core.app.stats.command.QueryStatsHistoryResponse\"\x00\x12\x89\x01\n" +
+	"\x12GetTopDestinations\x127.v2ray.core.app.stats.command.GetTopDestinationsRequest\x1a8.v2ray.core.app.stats.command.GetTopDestinationsResponse\"\x00Bu\n" +
 	" com.v2ray.core.app.stats.commandP\x01Z0github.com/v2fly/v2ray-core/v5/app/stats/command\xaa\x02\x1cV2Ray.Core.App.Stats.Commandb\x06proto3"
 
 var (
@@ -523,31 +1015,48 @@ func file_app_stats_command_command_proto_rawDescGZIP() []byte {
 	return file_app_stats_command_command_proto_rawDescData
 }
 
-var file_app_stats_command_command_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_app_stats_command_command_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_app_stats_command_command_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_app_stats_command_command_proto_goTypes = []any{
-	(*GetStatsRequest)(nil),    // 0: v2ray.core.app.stats.command.GetStatsRequest
-	(*Stat)(nil),               // 1: v2ray.core.app.stats.command.Stat
-	(*GetStatsResponse)(nil),   // 2: v2ray.core.app.stats.command.GetStatsResponse
-	(*QueryStatsRequest)(nil),  // 3: v2ray.core.app.stats.command.QueryStatsRequest
-	(*QueryStatsResponse)(nil), // 4: v2ray.core.app.stats.command.QueryStatsResponse
-	(*SysStatsRequest)(nil),    // 5: v2ray.core.app.stats.command.SysStatsRequest
-	(*SysStatsResponse)(nil),   // 6: v2ray.core.app.stats.command.SysStatsResponse
-	(*Config)(nil),             // 7: v2ray.core.app.stats.command.Config
+	(QueryStatsHistoryRequest_Interval)(0), // 0: v2ray.core.app.stats.command.QueryStatsHistoryRequest.Interval
+	(*GetStatsRequest)(nil),                // 1: v2ray.core.app.stats.command.GetStatsRequest
+	(*Stat)(nil),                           // 2: v2ray.core.app.stats.command.Stat
+	(*GetStatsResponse)(nil),               // 3: v2ray.core.app.stats.command.GetStatsResponse
+	(*QueryStatsRequest)(nil),              // 4: v2ray.core.app.stats.command.QueryStatsRequest
+	(*QueryStatsResponse)(nil),             // 5: v2ray.core.app.stats.command.QueryStatsResponse
+	(*SysStatsRequest)(nil),                // 6: v2ray.core.app.stats.command.SysStatsRequest
+	(*SysStatsResponse)(nil),               // 7: v2ray.core.app.stats.command.SysStatsResponse
+	(*QueryStatsHistoryRequest)(nil),       // 8: v2ray.core.app.stats.command.QueryStatsHistoryRequest
+	(*StatsSample)(nil),                    // 9: v2ray.core.app.stats.command.StatsSample
+	(*StatsHistory)(nil),                   // 10: v2ray.core.app.stats.command.StatsHistory
+	(*QueryStatsHistoryResponse)(nil),      // 11: v2ray.core.app.stats.command.QueryStatsHistoryResponse
+	(*GetTopDestinationsRequest)(nil),      // 12: v2ray.core.app.stats.command.GetTopDestinationsRequest
+	(*DestinationStat)(nil),                // 13: v2ray.core.app.stats.command.DestinationStat
+	(*GetTopDestinationsResponse)(nil),     // 14: v2ray.core.app.stats.command.GetTopDestinationsResponse
+	(*Config)(nil),                         // 15: v2ray.core.app.stats.command.Config
 }
 var file_app_stats_command_command_proto_depIdxs = []int32{
-	1, // 0: v2ray.core.app.stats.command.GetStatsResponse.stat:type_name -> v2ray.core.app.stats.command.Stat
-	1, // 1: v2ray.core.app.stats.command.QueryStatsResponse.stat:type_name -> v2ray.core.app.stats.command.Stat
-	0, // 2: v2ray.core.app.stats.command.StatsService.GetStats:input_type -> v2ray.core.app.stats.command.GetStatsRequest
-	3, // 3: v2ray.core.app.stats.command.StatsService.QueryStats:input_type -> v2ray.core.app.stats.command.QueryStatsRequest
-	5, // 4: v2ray.core.app.stats.command.StatsService.GetSysStats:input_type -> v2ray.core.app.stats.command.SysStatsRequest
-	2, // 5: v2ray.core.app.stats.command.StatsService.GetStats:output_type -> v2ray.core.app.stats.command.GetStatsResponse
-	4, // 6: v2ray.core.app.stats.command.StatsService.QueryStats:output_type -> v2ray.core.app.stats.command.QueryStatsResponse
-	6, // 7: v2ray.core.app.stats.command.StatsService.GetSysStats:output_type -> v2ray.core.app.stats.command.SysStatsResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	2,  // 0: v2ray.core.app.stats.command.GetStatsResponse.stat:type_name -> v2ray.core.app.stats.command.Stat
+	2,  // 1: v2ray.core.app.stats.command.QueryStatsResponse.stat:type_name -> v2ray.core.app.stats.command.Stat
+	0,  // 2: v2ray.core.app.stats.command.QueryStatsHistoryRequest.interval:type_name -> v2ray.core.app.stats.command.QueryStatsHistoryRequest.Interval
+	9,  // 3: v2ray.core.app.stats.command.StatsHistory.sample:type_name -> v2ray.core.app.stats.command.StatsSample
+	10, // 4: v2ray.core.app.stats.command.QueryStatsHistoryResponse.history:type_name -> v2ray.core.app.stats.command.StatsHistory
+	13, // 5: v2ray.core.app.stats.command.GetTopDestinationsResponse.destination:type_name -> v2ray.core.app.stats.command.DestinationStat
+	1,  // 6: v2ray.core.app.stats.command.StatsService.GetStats:input_type -> v2ray.core.app.stats.command.GetStatsRequest
+	4,  // 7: v2ray.core.app.stats.command.StatsService.QueryStats:input_type -> v2ray.core.app.stats.command.QueryStatsRequest
+	6,  // 8: v2ray.core.app.stats.command.StatsService.GetSysStats:input_type -> v2ray.core.app.stats.command.SysStatsRequest
+	8,  // 9: v2ray.core.app.stats.command.StatsService.QueryStatsHistory:input_type -> v2ray.core.app.stats.command.QueryStatsHistoryRequest
+	12, // 10: v2ray.core.app.stats.command.StatsService.GetTopDestinations:input_type -> v2ray.core.app.stats.command.GetTopDestinationsRequest
+	3,  // 11: v2ray.core.app.stats.command.StatsService.GetStats:output_type -> v2ray.core.app.stats.command.GetStatsResponse
+	5,  // 12: v2ray.core.app.stats.command.StatsService.QueryStats:output_type -> v2ray.core.app.stats.command.QueryStatsResponse
+	7,  // 13: v2ray.core.app.stats.command.StatsService.GetSysStats:output_type -> v2ray.core.app.stats.command.SysStatsResponse
+	11, // 14: v2ray.core.app.stats.command.StatsService.QueryStatsHistory:output_type -> v2ray.core.app.stats.command.QueryStatsHistoryResponse
+	14, // 15: v2ray.core.app.stats.command.StatsService.GetTopDestinations:output_type -> v2ray.core.app.stats.command.GetTopDestinationsResponse
+	11, // [11:16] is the sub-list for method output_type
+	6,  // [6:11] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_app_stats_command_command_proto_init() }
@@ -560,13 +1069,14 @@ func file_app_stats_command_command_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_stats_command_command_proto_rawDesc), len(file_app_stats_command_command_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      1,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_app_stats_command_command_proto_goTypes,
 		DependencyIndexes: file_app_stats_command_command_proto_depIdxs,
+		EnumInfos:         file_app_stats_command_command_proto_enumTypes,
 		MessageInfos:      file_app_stats_command_command_proto_msgTypes,
 	}.Build()
 	File_app_stats_command_command_proto = out.File
@@ -13,9 +13,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	StatsService_GetStats_FullMethodName    = "/v2ray.core.app.stats.command.StatsService/GetStats"
-	StatsService_QueryStats_FullMethodName  = "/v2ray.core.app.stats.command.StatsService/QueryStats"
-	StatsService_GetSysStats_FullMethodName = "/v2ray.core.app.stats.command.StatsService/GetSysStats"
+	StatsService_GetStats_FullMethodName           = "/v2ray.core.app.stats.command.StatsService/GetStats"
+	StatsService_QueryStats_FullMethodName         = "/v2ray.core.app.stats.command.StatsService/QueryStats"
+	StatsService_GetSysStats_FullMethodName        = "/v2ray.core.app.stats.command.StatsService/GetSysStats"
+	StatsService_QueryStatsHistory_FullMethodName  = "/v2ray.core.app.stats.command.StatsService/QueryStatsHistory"
+	StatsService_GetTopDestinations_FullMethodName = "/v2ray.core.app.stats.command.StatsService/GetTopDestinations"
 )
 
 // StatsServiceClient is the client API for StatsService service.
@@ -25,6 +27,8 @@ type StatsServiceClient interface {
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
 	QueryStats(ctx context.Context, in *QueryStatsRequest, opts ...grpc.CallOption) (*QueryStatsResponse, error)
 	GetSysStats(ctx context.Context, in *SysStatsRequest, opts ...grpc.CallOption) (*SysStatsResponse, error)
+	QueryStatsHistory(ctx context.Context, in *QueryStatsHistoryRequest, opts ...grpc.CallOption) (*QueryStatsHistoryResponse, error)
+	GetTopDestinations(ctx context.Context, in *GetTopDestinationsRequest, opts ...grpc.CallOption) (*GetTopDestinationsResponse, error)
 }
 
 type statsServiceClient struct {
@@ -65,6 +69,26 @@ func (c *statsServiceClient) GetSysStats(ctx context.Context, in *SysStatsReques
 	return out, nil
 }
 
+func (c *statsServiceClient) QueryStatsHistory(ctx context.Context, in *QueryStatsHistoryRequest, opts ...grpc.CallOption) (*QueryStatsHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryStatsHistoryResponse)
+	err := c.cc.Invoke(ctx, StatsService_QueryStatsHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *statsServiceClient) GetTopDestinations(ctx context.Context, in *GetTopDestinationsRequest, opts ...grpc.CallOption) (*GetTopDestinationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTopDestinationsResponse)
+	err := c.cc.Invoke(ctx, StatsService_GetTopDestinations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StatsServiceServer is the server API for StatsService service.
 // All implementations must embed UnimplementedStatsServiceServer
 // for forward compatibility.
@@ -72,6 +96,8 @@ type StatsServiceServer interface {
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
 	QueryStats(context.Context, *QueryStatsRequest) (*QueryStatsResponse, error)
 	GetSysStats(context.Context, *SysStatsRequest) (*SysStatsResponse, error)
+	QueryStatsHistory(context.Context, *QueryStatsHistoryRequest) (*QueryStatsHistoryResponse, error)
+	GetTopDestinations(context.Context, *GetTopDestinationsRequest) (*GetTopDestinationsResponse, error)
 	mustEmbedUnimplementedStatsServiceServer()
 }
 
@@ -91,6 +117,12 @@ func (UnimplementedStatsServiceServer) QueryStats(context.Context, *QueryStatsRe
 func (UnimplementedStatsServiceServer) GetSysStats(context.Context, *SysStatsRequest) (*SysStatsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetSysStats not implemented")
 }
+func (UnimplementedStatsServiceServer) QueryStatsHistory(context.Context, *QueryStatsHistoryRequest) (*QueryStatsHistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method QueryStatsHistory not implemented")
+}
+func (UnimplementedStatsServiceServer) GetTopDestinations(context.Context, *GetTopDestinationsRequest) (*GetTopDestinationsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTopDestinations not implemented")
+}
 func (UnimplementedStatsServiceServer) mustEmbedUnimplementedStatsServiceServer() {}
 func (UnimplementedStatsServiceServer) testEmbeddedByValue()                      {}
 
@@ -166,6 +198,42 @@ func _StatsService_GetSysStats_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StatsService_QueryStatsHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryStatsHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StatsServiceServer).QueryStatsHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StatsService_QueryStatsHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StatsServiceServer).QueryStatsHistory(ctx, req.(*QueryStatsHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StatsService_GetTopDestinations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopDestinationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StatsServiceServer).GetTopDestinations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StatsService_GetTopDestinations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StatsServiceServer).GetTopDestinations(ctx, req.(*GetTopDestinationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // StatsService_ServiceDesc is the grpc.ServiceDesc for StatsService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -185,6 +253,14 @@ var StatsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetSysStats",
 			Handler:    _StatsService_GetSysStats_Handler,
 		},
+		{
+			MethodName: "QueryStatsHistory",
+			Handler:    _StatsService_QueryStatsHistory_Handler,
+		},
+		{
+			MethodName: "GetTopDestinations",
+			Handler:    _StatsService_GetTopDestinations_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "app/stats/command/command.proto",
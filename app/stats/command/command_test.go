@@ -90,3 +90,90 @@ func TestQueryStats(t *testing.T) {
 		t.Error(r)
 	}
 }
+
+func TestQueryStatsResetKeepsGauges(t *testing.T) {
+	m, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+
+	counter, err := m.RegisterCounter("inbound>>>socks>>>traffic>>>uplink")
+	common.Must(err)
+	counter.Set(100)
+	gauge, err := m.RegisterGauge("inbound>>>socks>>>connections")
+	common.Must(err)
+	gauge.Add(2)
+
+	s := NewStatsServer(m)
+	for i := 0; i < 2; i++ {
+		_, err := s.QueryStats(context.Background(), &QueryStatsRequest{Pattern: "socks", Reset_: true})
+		common.Must(err)
+	}
+	resp, err := s.GetStats(context.Background(), &GetStatsRequest{Name: "inbound>>>socks>>>connections", Reset_: true})
+	common.Must(err)
+	if resp.Stat.Value != 2 || gauge.Value() != 2 {
+		t.Error("expect gauge not reset, but got ", resp.Stat.Value, " ", gauge.Value())
+	}
+	if counter.Value() != 0 {
+		t.Error("expect counter reset, but got ", counter.Value())
+	}
+	gauge.Add(-2)
+	if gauge.Value() != 0 {
+		t.Error("expect gauge back to 0, but got ", gauge.Value())
+	}
+}
+
+func TestQueryStatsHistory(t *testing.T) {
+	m, err := stats.NewManager(context.Background(), &stats.Config{
+		History: &stats.HistoryConfig{SecondSamples: 60},
+	})
+	common.Must(err)
+
+	sc, err := m.RegisterCounter("test_counter")
+	common.Must(err)
+	sc.Set(1)
+
+	common.Must(m.Start())
+	defer m.Close()
+
+	s := NewStatsServer(m)
+	resp, err := s.QueryStatsHistory(context.Background(), &QueryStatsHistoryRequest{
+		Patterns: []string{"counter"},
+		Limit:    1,
+	})
+	common.Must(err)
+	if len(resp.History) != 1 || resp.History[0].Name != "test_counter" ||
+		len(resp.History[0].Sample) != 1 || resp.History[0].Sample[0].Value != 1 {
+		t.Error("unexpected history: ", resp.History)
+	}
+
+	if _, err := s.QueryStatsHistory(context.Background(), &QueryStatsHistoryRequest{
+		Interval: QueryStatsHistoryRequest_Minute,
+	}); err == nil {
+		t.Error("expect error of minute history not enabled")
+	}
+}
+
+func TestGetTopDestinations(t *testing.T) {
+	m, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+	m.RecordDestination("proxy", "love@v2fly.org", "v2fly.org", 300)
+	m.RecordDestination("proxy", "", "example.com", 200)
+	m.RecordDestination("direct", "love@v2fly.org", "example.com", 100)
+
+	s := NewStatsServer(m)
+	resp, err := s.GetTopDestinations(context.Background(), &GetTopDestinationsRequest{
+		OutboundTag: "proxy",
+		Limit:       1,
+	})
+	common.Must(err)
+	if r := cmp.Diff(resp.Destination, []*DestinationStat{
+		{OutboundTag: "proxy", User: "love@v2fly.org", Domain: "v2fly.org", Bytes: 300},
+	}, cmpopts.IgnoreUnexported(DestinationStat{})); r != "" {
+		t.Error(r)
+	}
+
+	resp, err = s.GetTopDestinations(context.Background(), &GetTopDestinationsRequest{User: "love@v2fly.org"})
+	common.Must(err)
+	if len(resp.Destination) != 2 || resp.Destination[1].OutboundTag != "direct" {
+		t.Error("unexpected destinations: ", resp.Destination)
+	}
+}
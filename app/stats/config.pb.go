@@ -17,9 +17,12 @@ const (
 )
 
 type Config struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	History *HistoryConfig         `protobuf:"bytes,1,opt,name=history,proto3" json:"history,omitempty"`
+	// Number of the destinations of most traffic kept. Default 1024.
+	TopDestinations uint32 `protobuf:"varint,2,opt,name=top_destinations,json=topDestinations,proto3" json:"top_destinations,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Config) Reset() {
@@ -52,6 +55,93 @@ func (*Config) Descriptor() ([]byte, []int) {
 	return file_app_stats_config_proto_rawDescGZIP(), []int{0}
 }
 
+func (x *Config) GetHistory() *HistoryConfig {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+func (x *Config) GetTopDestinations() uint32 {
+	if x != nil {
+		return x.TopDestinations
+	}
+	return 0
+}
+
+// HistoryConfig enables the time series of counters, sampled every second
+// and every minute into fixed-size rings.
+type HistoryConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of per-second samples kept for each counter.
+	SecondSamples uint32 `protobuf:"varint,1,opt,name=second_samples,json=secondSamples,proto3" json:"second_samples,omitempty"`
+	// Number of per-minute samples kept for each counter.
+	MinuteSamples uint32 `protobuf:"varint,2,opt,name=minute_samples,json=minuteSamples,proto3" json:"minute_samples,omitempty"`
+	// Counters recorded, matched by substring or regexp. All counters if empty.
+	Patterns      []string `protobuf:"bytes,3,rep,name=patterns,proto3" json:"patterns,omitempty"`
+	Regexp        bool     `protobuf:"varint,4,opt,name=regexp,proto3" json:"regexp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryConfig) Reset() {
+	*x = HistoryConfig{}
+	mi := &file_app_stats_config_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryConfig) ProtoMessage() {}
+
+func (x *HistoryConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_app_stats_config_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryConfig.ProtoReflect.Descriptor instead.
+func (*HistoryConfig) Descriptor() ([]byte, []int) {
+	return file_app_stats_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HistoryConfig) GetSecondSamples() uint32 {
+	if x != nil {
+		return x.SecondSamples
+	}
+	return 0
+}
+
+func (x *HistoryConfig) GetMinuteSamples() uint32 {
+	if x != nil {
+		return x.MinuteSamples
+	}
+	return 0
+}
+
+func (x *HistoryConfig) GetPatterns() []string {
+	if x != nil {
+		return x.Patterns
+	}
+	return nil
+}
+
+func (x *HistoryConfig) GetRegexp() bool {
+	if x != nil {
+		return x.Regexp
+	}
+	return false
+}
+
 type ChannelConfig struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	Blocking        bool                   `protobuf:"varint,1,opt,name=Blocking,proto3" json:"Blocking,omitempty"`
@@ -63,7 +153,7 @@ type ChannelConfig struct {
 
 func (x *ChannelConfig) Reset() {
 	*x = ChannelConfig{}
-	mi := &file_app_stats_config_proto_msgTypes[1]
+	mi := &file_app_stats_config_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -75,7 +165,7 @@ func (x *ChannelConfig) String() string {
 func (*ChannelConfig) ProtoMessage() {}
 
 func (x *ChannelConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_app_stats_config_proto_msgTypes[1]
+	mi := &file_app_stats_config_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -88,7 +178,7 @@ func (x *ChannelConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChannelConfig.ProtoReflect.Descriptor instead.
 func (*ChannelConfig) Descriptor() ([]byte, []int) {
-	return file_app_stats_config_proto_rawDescGZIP(), []int{1}
+	return file_app_stats_config_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ChannelConfig) GetBlocking() bool {
@@ -116,9 +206,16 @@ var File_app_stats_config_proto protoreflect.FileDescriptor
 
 const file_app_stats_config_proto_rawDesc = "" +
 	"\n" +
-	"\x16app/stats/config.proto\x12\x14v2ray.core.app.stats\x1a common/protoext/extensions.proto\"\x1e\n" +
-	"\x06Config:\x14\x82\xb5\x18\x10\n" +
-	"\aservice\x12\x05stats\"u\n" +
+	"\x16app/stats/config.proto\x12\x14v2ray.core.app.stats\x1a common/protoext/extensions.proto\"\x88\x01\n" +
+	"\x06Config\x12=\n" +
+	"\ahistory\x18\x01 \x01(\v2#.v2ray.core.app.stats.HistoryConfigR\ahistory\x12)\n" +
+	"\x10top_destinations\x18\x02 \x01(\rR\x0ftopDestinations:\x14\x82\xb5\x18\x10\n" +
+	"\aservice\x12\x05stats\"\x91\x01\n" +
+	"\rHistoryConfig\x12%\n" +
+	"\x0esecond_samples\x18\x01 \x01(\rR\rsecondSamples\x12%\n" +
+	"\x0eminute_samples\x18\x02 \x01(\rR\rminuteSamples\x12\x1a\n" +
+	"\bpatterns\x18\x03 \x03(\tR\bpatterns\x12\x16\n" +
+	"\x06regexp\x18\x04 \x01(\bR\x06regexp\"u\n" +
 	"\rChannelConfig\x12\x1a\n" +
 	"\bBlocking\x18\x01 \x01(\bR\bBlocking\x12(\n" +
 	"\x0fSubscriberLimit\x18\x02 \x01(\x05R\x0fSubscriberLimit\x12\x1e\n" +
@@ -139,17 +236,19 @@ func file_app_stats_config_proto_rawDescGZIP() []byte {
 	return file_app_stats_config_proto_rawDescData
 }
 
-var file_app_stats_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_app_stats_config_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_app_stats_config_proto_goTypes = []any{
 	(*Config)(nil),        // 0: v2ray.core.app.stats.Config
-	(*ChannelConfig)(nil), // 1: v2ray.core.app.stats.ChannelConfig
+	(*HistoryConfig)(nil), // 1: v2ray.core.app.stats.HistoryConfig
+	(*ChannelConfig)(nil), // 2: v2ray.core.app.stats.ChannelConfig
 }
 var file_app_stats_config_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: v2ray.core.app.stats.Config.history:type_name -> v2ray.core.app.stats.HistoryConfig
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_app_stats_config_proto_init() }
@@ -163,7 +262,7 @@ func file_app_stats_config_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_stats_config_proto_rawDesc), len(file_app_stats_config_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -1,10 +1,17 @@
 package stats
 
-import "sync/atomic"
+import (
+	"sync"
+	"sync/atomic"
+)
 
 // Counter is an implementation of stats.Counter.
 type Counter struct {
 	value int64
+
+	// access serializes Set against Total, so that a reset is never seen half done.
+	access  sync.Mutex
+	cleared int64
 }
 
 // Value implements stats.Counter.
@@ -14,10 +21,37 @@ func (c *Counter) Value() int64 {
 
 // Set implements stats.Counter.
 func (c *Counter) Set(newValue int64) int64 {
-	return atomic.SwapInt64(&c.value, newValue)
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	oldValue := atomic.SwapInt64(&c.value, newValue)
+	c.cleared += oldValue - newValue
+	return oldValue
 }
 
 // Add implements stats.Counter.
 func (c *Counter) Add(delta int64) int64 {
 	return atomic.AddInt64(&c.value, delta)
 }
+
+// Total returns the value of the counter as if it was never set, which is not affected by resets.
+func (c *Counter) Total() int64 {
+	_, total := c.snapshot()
+	return total
+}
+
+func (c *Counter) snapshot() (value int64, total int64) {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	value = atomic.LoadInt64(&c.value)
+	return value, value + c.cleared
+}
+
+// Gauge is an implementation of stats.Gauge.
+type Gauge struct {
+	Counter
+}
+
+// IsGauge implements stats.Gauge.
+func (*Gauge) IsGauge() {}
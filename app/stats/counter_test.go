@@ -29,3 +29,25 @@ func TestStatsCounter(t *testing.T) {
 		t.Fatal("unexpected Value() return: ", v, ", wanted ", 0)
 	}
 }
+
+func TestGetOrRegisterGauge(t *testing.T) {
+	m, err := NewManager(context.Background(), &Config{})
+	common.Must(err)
+
+	g1, err := stats.GetOrRegisterGauge(m, "inbound>>>socks>>>connections")
+	common.Must(err)
+	g2, err := stats.GetOrRegisterGauge(m, "inbound>>>socks>>>connections")
+	common.Must(err)
+	if g1 != g2 {
+		t.Error("expect the same gauge")
+	}
+
+	_, err = m.RegisterCounter("inbound>>>socks>>>traffic>>>uplink")
+	common.Must(err)
+	if _, err := stats.GetOrRegisterGauge(m, "inbound>>>socks>>>traffic>>>uplink"); err == nil {
+		t.Error("expect error getting a plain counter as gauge")
+	}
+	if _, err := stats.GetOrRegisterGauge(stats.NoopManager{}, "test"); err == nil {
+		t.Error("expect error of unsupported manager")
+	}
+}
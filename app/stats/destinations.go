@@ -0,0 +1,112 @@
+package stats
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+
+	"github.com/v2fly/v2ray-core/v5/features/stats"
+)
+
+const defaultTopDestinations = 1024
+
+type destinationKey struct {
+	outboundTag string
+	user        string
+	domain      string
+}
+
+type destinationEntry struct {
+	key   destinationKey
+	bytes int64
+	error int64
+	index int
+}
+
+// destinationHeap is a min-heap of the entries by their bytes.
+type destinationHeap []*destinationEntry
+
+func (h destinationHeap) Len() int           { return len(h) }
+func (h destinationHeap) Less(i, j int) bool { return h[i].bytes < h[j].bytes }
+func (h destinationHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *destinationHeap) Push(x interface{}) {
+	entry := x.(*destinationEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *destinationHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// destinationSketch keeps the destinations of most traffic with the space-saving algorithm. When it is full, a new
+// destination replaces the one of least traffic, taking over its bytes as the error, so memory stays bounded while
+// heavy hitters are never missed.
+type destinationSketch struct {
+	access   sync.Mutex
+	capacity int
+	entries  map[destinationKey]*destinationEntry
+	heap     destinationHeap
+}
+
+func newDestinationSketch(capacity uint32) *destinationSketch {
+	if capacity == 0 {
+		capacity = defaultTopDestinations
+	}
+	return &destinationSketch{
+		capacity: int(capacity),
+		entries:  make(map[destinationKey]*destinationEntry),
+	}
+}
+
+func (s *destinationSketch) record(key destinationKey, bytes int64) {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	if entry, found := s.entries[key]; found {
+		entry.bytes += bytes
+		heap.Fix(&s.heap, entry.index)
+		return
+	}
+	if len(s.heap) < s.capacity {
+		entry := &destinationEntry{key: key, bytes: bytes}
+		s.entries[key] = entry
+		heap.Push(&s.heap, entry)
+		return
+	}
+	entry := s.heap[0]
+	delete(s.entries, entry.key)
+	entry.key = key
+	entry.error = entry.bytes
+	entry.bytes += bytes
+	s.entries[key] = entry
+	heap.Fix(&s.heap, 0)
+}
+
+func (s *destinationSketch) top() []*stats.DestinationTraffic {
+	s.access.Lock()
+	destinations := make([]*stats.DestinationTraffic, 0, len(s.heap))
+	for _, entry := range s.heap {
+		destinations = append(destinations, &stats.DestinationTraffic{
+			OutboundTag: entry.key.outboundTag,
+			User:        entry.key.user,
+			Domain:      entry.key.domain,
+			Bytes:       entry.bytes,
+			Error:       entry.error,
+		})
+	}
+	s.access.Unlock()
+
+	sort.Slice(destinations, func(i, j int) bool {
+		return destinations[i].Bytes > destinations[j].Bytes
+	})
+	return destinations
+}
@@ -0,0 +1,37 @@
+package stats_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	. "github.com/v2fly/v2ray-core/v5/app/stats"
+	"github.com/v2fly/v2ray-core/v5/common"
+)
+
+func TestTopDestinations(t *testing.T) {
+	m, err := NewManager(context.Background(), &Config{TopDestinations: 4})
+	common.Must(err)
+
+	for i := 0; i < 100; i++ {
+		m.RecordDestination("proxy", "love@v2fly.org", "v2fly.org", 1000)
+		m.RecordDestination("direct", "", "example.com", 500)
+		m.RecordDestination("proxy", "", "noise"+strconv.Itoa(i)+".com", 10)
+	}
+
+	top := m.TopDestinations()
+	if len(top) != 4 {
+		t.Fatal("expect 4 destinations kept, but got ", len(top))
+	}
+	if d := top[0]; d.OutboundTag != "proxy" || d.User != "love@v2fly.org" || d.Domain != "v2fly.org" || d.Bytes != 100000 || d.Error != 0 {
+		t.Error("unexpected top destination: ", d)
+	}
+	if d := top[1]; d.OutboundTag != "direct" || d.Domain != "example.com" || d.Bytes != 50000 {
+		t.Error("unexpected second destination: ", d)
+	}
+	for _, d := range top[2:] {
+		if d.Bytes-d.Error > 10 {
+			t.Error("unexpected guaranteed bytes of noise destination: ", d)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common/strmatcher"
+	"github.com/v2fly/v2ray-core/v5/common/task"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
+)
+
+// HistorySample is a sample of a counter in its history.
+type HistorySample struct {
+	Time time.Time
+	// Value is the value of the counter when sampled.
+	Value int64
+	// Total is the total of the counter when sampled, which is not affected by resets.
+	Total int64
+}
+
+// Rate returns the average change per second of the counter since the previous sample.
+func (s HistorySample) Rate(previous HistorySample) float64 {
+	elapsed := s.Time.Sub(previous.Time).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Total-previous.Total) / elapsed
+}
+
+// sampler is a counter of this package, which can be sampled.
+type sampler interface {
+	snapshot() (value int64, total int64)
+}
+
+// historyRing is a fixed-size ring of samples.
+type historyRing struct {
+	samples []HistorySample
+	next    int
+	full    bool
+}
+
+func newHistoryRing(size uint32) *historyRing {
+	if size == 0 {
+		return nil
+	}
+	return &historyRing{samples: make([]HistorySample, size)}
+}
+
+func (r *historyRing) push(sample HistorySample) {
+	r.samples[r.next] = sample
+	r.next++
+	if r.next == len(r.samples) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// list returns the samples in the ring, oldest first.
+func (r *historyRing) list() []HistorySample {
+	if !r.full {
+		return append([]HistorySample(nil), r.samples[:r.next]...)
+	}
+	samples := make([]HistorySample, 0, len(r.samples))
+	samples = append(samples, r.samples[r.next:]...)
+	return append(samples, r.samples[:r.next]...)
+}
+
+type counterHistory struct {
+	seconds *historyRing
+	minutes *historyRing
+}
+
+// history records the time series of the counters in a manager.
+type history struct {
+	access  sync.RWMutex
+	config  *HistoryConfig
+	matcher strmatcher.IndexMatcher
+	series  map[string]*counterHistory
+	ticks   uint64
+	task    *task.Periodic
+}
+
+func newHistory(config *HistoryConfig, m *Manager) (*history, error) {
+	h := &history{
+		config:  config,
+		matcher: strmatcher.NewLinearIndexMatcher(),
+		series:  make(map[string]*counterHistory),
+	}
+	t := strmatcher.Substr
+	if config.Regexp {
+		t = strmatcher.Regex
+	}
+	for _, p := range config.Patterns {
+		matcher, err := t.New(p)
+		if err != nil {
+			return nil, newError("invalid history pattern ", p).Base(err)
+		}
+		h.matcher.Add(matcher)
+	}
+	h.task = &task.Periodic{
+		Interval: time.Second,
+		Execute: func() error {
+			h.record(m, time.Now())
+			return nil
+		},
+	}
+	return h, nil
+}
+
+// record takes a per-second sample of the counters, and a per-minute sample every 60 calls.
+func (h *history) record(m *Manager, now time.Time) {
+	samples := make(map[string]HistorySample)
+	m.VisitCounters(func(name string, c stats.Counter) bool {
+		if h.matcher.Size() > 0 && !h.matcher.MatchAny(name) {
+			return true
+		}
+		if counter, ok := c.(sampler); ok {
+			value, total := counter.snapshot()
+			samples[name] = HistorySample{Time: now, Value: value, Total: total}
+		}
+		return true
+	})
+
+	h.access.Lock()
+	defer h.access.Unlock()
+
+	for name := range h.series {
+		if _, found := samples[name]; !found {
+			delete(h.series, name)
+		}
+	}
+	minute := h.ticks%60 == 0
+	h.ticks++
+	for name, sample := range samples {
+		series, found := h.series[name]
+		if !found {
+			series = &counterHistory{
+				seconds: newHistoryRing(h.config.SecondSamples),
+				minutes: newHistoryRing(h.config.MinuteSamples),
+			}
+			h.series[name] = series
+		}
+		if series.seconds != nil {
+			series.seconds.push(sample)
+		}
+		if minute && series.minutes != nil {
+			series.minutes.push(sample)
+		}
+	}
+}
+
+func (h *history) get(name string, interval time.Duration) ([]HistorySample, error) {
+	var size uint32
+	switch interval {
+	case time.Second:
+		size = h.config.SecondSamples
+	case time.Minute:
+		size = h.config.MinuteSamples
+	default:
+		return nil, newError("unsupported history interval ", interval)
+	}
+	if size == 0 {
+		return nil, newError("history of interval ", interval, " is not enabled")
+	}
+
+	h.access.RLock()
+	defer h.access.RUnlock()
+
+	series, found := h.series[name]
+	if !found {
+		return nil, nil
+	}
+	if interval == time.Minute {
+		return series.minutes.list(), nil
+	}
+	return series.seconds.list(), nil
+}
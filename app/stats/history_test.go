@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+)
+
+func TestHistoryRing(t *testing.T) {
+	r := newHistoryRing(3)
+	for i := int64(1); i <= 5; i++ {
+		r.push(HistorySample{Value: i})
+		samples := r.list()
+		if len(samples) > 3 || samples[len(samples)-1].Value != i || samples[0].Value != max(1, i-2) {
+			t.Error("unexpected samples after ", i, " pushes: ", samples)
+		}
+	}
+}
+
+func TestHistoryRecord(t *testing.T) {
+	m, err := NewManager(context.Background(), &Config{
+		History: &HistoryConfig{SecondSamples: 4, MinuteSamples: 2, Patterns: []string{"traffic"}},
+	})
+	common.Must(err)
+	c, err := m.RegisterCounter("user>>>love@v2fly.org>>>traffic>>>uplink")
+	common.Must(err)
+	_, err = m.RegisterCounter("rule>>>direct>>>hits")
+	common.Must(err)
+
+	start := time.Unix(1700000000, 0)
+	for i := 0; i < 61; i++ {
+		c.Add(100)
+		if i == 59 {
+			// Resets by other clients shall not break the rates.
+			c.Set(0)
+		}
+		m.history.record(m, start.Add(time.Duration(i)*time.Second))
+	}
+
+	seconds, err := m.GetCounterHistory("user>>>love@v2fly.org>>>traffic>>>uplink", time.Second)
+	common.Must(err)
+	if len(seconds) != 4 {
+		t.Fatal("expect 4 samples, but got ", len(seconds))
+	}
+	for i := 1; i < len(seconds); i++ {
+		if rate := seconds[i].Rate(seconds[i-1]); rate != 100 {
+			t.Error("unexpected rate ", rate, " of sample ", i)
+		}
+	}
+	if seconds[3].Value != 100 || seconds[3].Total != 6100 {
+		t.Error("unexpected last sample: ", seconds[3])
+	}
+
+	minutes, err := m.GetCounterHistory("user>>>love@v2fly.org>>>traffic>>>uplink", time.Minute)
+	common.Must(err)
+	if len(minutes) != 2 || minutes[1].Rate(minutes[0]) != 100 {
+		t.Error("unexpected minute samples: ", minutes)
+	}
+
+	if samples, err := m.GetCounterHistory("rule>>>direct>>>hits", time.Second); err != nil || len(samples) != 0 {
+		t.Error("expect no history of counter not matched")
+	}
+
+	common.Must(m.UnregisterCounter("user>>>love@v2fly.org>>>traffic>>>uplink"))
+	m.history.record(m, start.Add(61*time.Second))
+	if samples, _ := m.GetCounterHistory("user>>>love@v2fly.org>>>traffic>>>uplink", time.Second); len(samples) != 0 {
+		t.Error("expect history removed with the counter")
+	}
+}
+
+func TestHistoryDisabled(t *testing.T) {
+	m, err := NewManager(context.Background(), &Config{History: &HistoryConfig{SecondSamples: 4}})
+	common.Must(err)
+	if _, err := m.GetCounterHistory("test", time.Minute); err == nil {
+		t.Error("expect error of minute history not enabled")
+	}
+
+	m, err = NewManager(context.Background(), &Config{})
+	common.Must(err)
+	if _, err := m.GetCounterHistory("test", time.Second); err == nil {
+		t.Error("expect error of history not enabled")
+	}
+}
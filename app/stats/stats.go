@@ -5,6 +5,7 @@ package stats
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/errors"
@@ -14,16 +15,27 @@ import (
 // Manager is an implementation of stats.Manager.
 type Manager struct {
 	access   sync.RWMutex
-	counters map[string]*Counter
+	counters map[string]stats.Counter
 	channels map[string]*Channel
+	history  *history
+	top      *destinationSketch
 	running  bool
 }
 
 // NewManager creates an instance of Statistics Manager.
 func NewManager(ctx context.Context, config *Config) (*Manager, error) {
 	m := &Manager{
-		counters: make(map[string]*Counter),
+		counters: make(map[string]stats.Counter),
 		channels: make(map[string]*Channel),
+		top:      newDestinationSketch(config.TopDestinations),
+	}
+
+	if h := config.History; h != nil && (h.SecondSamples > 0 || h.MinuteSamples > 0) {
+		history, err := newHistory(h, m)
+		if err != nil {
+			return nil, err
+		}
+		m.history = history
 	}
 
 	return m, nil
@@ -48,6 +60,34 @@ func (m *Manager) RegisterCounter(name string) (stats.Counter, error) {
 	return c, nil
 }
 
+// RegisterUniqueCounter implements stats.UniqueCounterManager.
+func (m *Manager) RegisterUniqueCounter(name string, window time.Duration) (stats.UniqueCounter, error) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if _, found := m.counters[name]; found {
+		return nil, newError("Counter ", name, " already registered.")
+	}
+	newError("create new unique counter ", name).AtDebug().WriteToLog()
+	c := NewUniqueCounter(window)
+	m.counters[name] = c
+	return c, nil
+}
+
+// RegisterGauge implements stats.GaugeManager.
+func (m *Manager) RegisterGauge(name string) (stats.Gauge, error) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if _, found := m.counters[name]; found {
+		return nil, newError("Counter ", name, " already registered.")
+	}
+	newError("create new gauge ", name).AtDebug().WriteToLog()
+	g := new(Gauge)
+	m.counters[name] = g
+	return g, nil
+}
+
 // UnregisterCounter implements stats.Manager.
 func (m *Manager) UnregisterCounter(name string) error {
 	m.access.Lock()
@@ -83,6 +123,25 @@ func (m *Manager) VisitCounters(visitor func(string, stats.Counter) bool) {
 	}
 }
 
+// GetCounterHistory returns the samples of the counter taken at the interval, which is either time.Second
+// or time.Minute, oldest first.
+func (m *Manager) GetCounterHistory(name string, interval time.Duration) ([]HistorySample, error) {
+	if m.history == nil {
+		return nil, newError("stats history is not enabled")
+	}
+	return m.history.get(name, interval)
+}
+
+// RecordDestination implements stats.DestinationRecorder.
+func (m *Manager) RecordDestination(outboundTag, user, domain string, bytes int64) {
+	m.top.record(destinationKey{outboundTag: outboundTag, user: user, domain: domain}, bytes)
+}
+
+// TopDestinations implements stats.DestinationRecorder.
+func (m *Manager) TopDestinations() []*stats.DestinationTraffic {
+	return m.top.top()
+}
+
 // RegisterChannel implements stats.Manager.
 func (m *Manager) RegisterChannel(name string) (stats.Channel, error) {
 	m.access.Lock()
@@ -126,6 +185,17 @@ func (m *Manager) GetChannel(name string) stats.Channel {
 
 // Start implements common.Runnable.
 func (m *Manager) Start() error {
+	if err := m.startChannels(); err != nil {
+		return err
+	}
+	if m.history != nil {
+		// The first samples are taken synchronously, which visits the counters.
+		return m.history.task.Start()
+	}
+	return nil
+}
+
+func (m *Manager) startChannels() error {
 	m.access.Lock()
 	defer m.access.Unlock()
 	m.running = true
@@ -143,10 +213,16 @@ func (m *Manager) Start() error {
 
 // Close implement common.Closable.
 func (m *Manager) Close() error {
+	errs := []error{}
+	if m.history != nil {
+		if err := m.history.task.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	m.access.Lock()
 	defer m.access.Unlock()
 	m.running = false
-	errs := []error{}
 	for name, channel := range m.channels {
 		newError("remove channel ", name).AtDebug().WriteToLog()
 		delete(m.channels, name)
@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// UniqueCounter is an implementation of stats.UniqueCounter.
+type UniqueCounter struct {
+	Counter
+
+	window       time.Duration
+	recordAccess sync.Mutex
+	records      map[string]time.Time
+}
+
+// NewUniqueCounter creates a unique counter of the window.
+func NewUniqueCounter(window time.Duration) *UniqueCounter {
+	return &UniqueCounter{
+		window:  window,
+		records: make(map[string]time.Time),
+	}
+}
+
+// Record implements stats.UniqueCounter.
+func (c *UniqueCounter) Record(value string) {
+	c.recordAccess.Lock()
+	defer c.recordAccess.Unlock()
+
+	now := time.Now()
+	c.records[value] = now
+	c.expire(now)
+}
+
+// expire removes the values not seen in the window, and updates the count.
+func (c *UniqueCounter) expire(now time.Time) {
+	for value, seen := range c.records {
+		if now.Sub(seen) > c.window {
+			delete(c.records, value)
+		}
+	}
+	atomic.StoreInt64(&c.Counter.value, int64(len(c.records)))
+}
+
+// Value implements stats.Counter.
+func (c *UniqueCounter) Value() int64 {
+	c.recordAccess.Lock()
+	defer c.recordAccess.Unlock()
+
+	c.expire(time.Now())
+	return c.Counter.Value()
+}
+
+// Set implements stats.Counter. It forgets all the values recorded, so the count starts over.
+func (c *UniqueCounter) Set(int64) int64 {
+	c.recordAccess.Lock()
+	defer c.recordAccess.Unlock()
+
+	c.records = make(map[string]time.Time)
+	return c.Counter.Set(0)
+}
+
+func (c *UniqueCounter) snapshot() (value int64, total int64) {
+	c.recordAccess.Lock()
+	c.expire(time.Now())
+	c.recordAccess.Unlock()
+
+	return c.Counter.snapshot()
+}
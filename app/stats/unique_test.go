@@ -0,0 +1,85 @@
+package stats_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/v2fly/v2ray-core/v5/app/stats"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
+)
+
+func TestUniqueCounter(t *testing.T) {
+	c := NewUniqueCounter(100 * time.Millisecond)
+	c.Record("1.1.1.1")
+	c.Record("1.1.1.1")
+	c.Record("8.8.8.8")
+	if v := c.Value(); v != 2 {
+		t.Error("expect 2 distinct values, but got ", v)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	c.Record("8.8.4.4")
+	if v := c.Value(); v != 1 {
+		t.Error("expect values out of the window expired, but got ", v)
+	}
+
+	if v := c.Set(0); v != 1 {
+		t.Error("expect previous value 1, but got ", v)
+	}
+	if v := c.Value(); v != 0 {
+		t.Error("expect values forgotten after reset, but got ", v)
+	}
+}
+
+func TestGetOrRegisterUniqueCounter(t *testing.T) {
+	m, err := NewManager(context.Background(), &Config{})
+	common.Must(err)
+
+	c1, err := stats.GetOrRegisterUniqueCounter(m, "user>>>love@v2fly.org>>>source_ips", time.Minute)
+	common.Must(err)
+	c2, err := stats.GetOrRegisterUniqueCounter(m, "user>>>love@v2fly.org>>>source_ips", time.Minute)
+	common.Must(err)
+	if c1 != c2 {
+		t.Error("expect the same unique counter")
+	}
+	if m.GetCounter("user>>>love@v2fly.org>>>source_ips") == nil {
+		t.Error("expect unique counter visible as counter")
+	}
+
+	_, err = m.RegisterCounter("user>>>love@v2fly.org>>>online")
+	common.Must(err)
+	if _, err := stats.GetOrRegisterUniqueCounter(m, "user>>>love@v2fly.org>>>online", time.Minute); err == nil {
+		t.Error("expect error getting a plain counter as unique counter")
+	}
+	if _, err := stats.GetOrRegisterUniqueCounter(stats.NoopManager{}, "test", time.Minute); err == nil {
+		t.Error("expect error of unsupported manager")
+	}
+}
+
+func TestGetOrRegisterUniqueCounterConcurrently(t *testing.T) {
+	m, err := NewManager(context.Background(), &Config{})
+	common.Must(err)
+
+	var wg sync.WaitGroup
+	counters := make([]stats.UniqueCounter, 16)
+	for i := range counters {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c, err := stats.GetOrRegisterUniqueCounter(m, "user>>>love@v2fly.org>>>source_ips", time.Minute)
+			if err != nil {
+				t.Error(err)
+			}
+			counters[i] = c
+		}(i)
+	}
+	wg.Wait()
+	for _, c := range counters {
+		if c != counters[0] {
+			t.Fatal("expect the same unique counter")
+		}
+	}
+}
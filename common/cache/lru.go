@@ -10,6 +10,8 @@ type Lru interface {
 	Get(key interface{}) (value interface{}, ok bool)
 	GetKeyFromValue(value interface{}) (key interface{}, ok bool)
 	Put(key, value interface{})
+	// Range calls f for each entry from the least recently used, until f returns false.
+	Range(f func(key, value interface{}) bool)
 }
 
 type lru struct {
@@ -78,3 +80,14 @@ func (l *lru) Put(key, value interface{}) {
 	}
 	l.mu.Unlock()
 }
+
+func (l *lru) Range(f func(key, value interface{}) bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for element := l.doubleLinkedlist.Back(); element != nil; element = element.Prev() {
+		e := element.Value.(*lruElement)
+		if !f(e.key, e.value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package log
+
+import (
+	"strings"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common/serial"
+)
+
+// DNSMessage is a log message of a DNS lookup.
+type DNSMessage struct {
+	// Source is the source of the requesting inbound connection, if any.
+	Source interface{}
+	// Inbound is the tag of the requesting inbound, if any.
+	Inbound string
+	Domain  string
+	Type    string
+	// Server is the name of the upstream server that answered the lookup.
+	Server  string
+	Answer  []string
+	Error   error
+	Latency time.Duration
+	// Cached indicates that the lookup is answered from the cache.
+	Cached bool
+}
+
+// String implements Message.
+func (m *DNSMessage) String() string {
+	builder := strings.Builder{}
+	if source := serial.ToString(m.Source); len(source) > 0 {
+		builder.WriteString(source)
+		builder.WriteByte(' ')
+	}
+	if len(m.Inbound) > 0 {
+		builder.WriteString("[")
+		builder.WriteString(m.Inbound)
+		builder.WriteString("] ")
+	}
+	builder.WriteString(m.Domain)
+	builder.WriteByte(' ')
+	builder.WriteString(m.Type)
+	if len(m.Server) > 0 {
+		builder.WriteString(" via ")
+		builder.WriteString(m.Server)
+	}
+	if m.Error != nil {
+		builder.WriteString(" error: ")
+		builder.WriteString(m.Error.Error())
+	} else {
+		builder.WriteString(" -> [")
+		builder.WriteString(strings.Join(m.Answer, " "))
+		builder.WriteByte(']')
+	}
+	builder.WriteByte(' ')
+	builder.WriteString(m.Latency.Round(time.Microsecond).String())
+	if m.Cached {
+		builder.WriteString(" cached")
+	}
+	return builder.String()
+}
@@ -1,7 +1,9 @@
 package log_test
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -31,3 +33,29 @@ func TestLogRecord(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestDNSMessage(t *testing.T) {
+	msg := &log.DNSMessage{
+		Source:  net.TCPDestination(net.ParseAddress("192.168.1.2"), 53),
+		Inbound: "dns-in",
+		Domain:  "v2fly.org",
+		Type:    "A",
+		Server:  "UDP:8.8.8.8:53",
+		Answer:  []string{"1.2.3.4", "5.6.7.8"},
+		Latency: 1500 * time.Microsecond,
+		Cached:  true,
+	}
+	if diff := cmp.Diff("tcp:192.168.1.2:53 [dns-in] v2fly.org A via UDP:8.8.8.8:53 -> [1.2.3.4 5.6.7.8] 1.5ms cached", msg.String()); diff != "" {
+		t.Error(diff)
+	}
+
+	msg = &log.DNSMessage{
+		Domain:  "v2fly.org",
+		Type:    "AAAA",
+		Error:   errors.New("timeout"),
+		Latency: time.Second,
+	}
+	if diff := cmp.Diff("v2fly.org AAAA error: timeout 1s", msg.String()); diff != "" {
+		t.Error(diff)
+	}
+}
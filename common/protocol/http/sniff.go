@@ -3,6 +3,7 @@ package http
 import (
 	"bytes"
 	"errors"
+	"net/url"
 	"strings"
 
 	"github.com/v2fly/v2ray-core/v5/common"
@@ -10,7 +11,10 @@ import (
 )
 
 type SniffHeader struct {
-	host string
+	host    string
+	method  string
+	path    string
+	headers map[string]string
 }
 
 func (h *SniffHeader) Protocol() string {
@@ -24,7 +28,33 @@ func (h *SniffHeader) Domain() string {
 	return ""
 }
 
+// Method returns the upper case method of the request.
+func (h *SniffHeader) Method() string {
+	return h.method
+}
+
+// Path returns the path of the request, without query.
+func (h *SniffHeader) Path() string {
+	return h.path
+}
+
+// Attributes returns the method, path and selected headers of the request as content attributes,
+// in the same form as set by the HTTP proxy inbound.
+func (h *SniffHeader) Attributes() map[string]string {
+	attributes := map[string]string{
+		":method": h.method,
+		":path":   h.path,
+	}
+	for key, value := range h.headers {
+		attributes[key] = value
+	}
+	return attributes
+}
+
 var (
+	// attributeHeaders are the headers carried into content attributes
+	attributeHeaders = [...]string{"user-agent", "referer", "content-type", "accept"}
+
 	// refer to https://pkg.go.dev/net/http@master#pkg-constants
 	methods = [...]string{"get", "post", "head", "put", "delete", "options", "connect", "patch", "trace"}
 
@@ -53,6 +83,13 @@ func SniffHTTP(b []byte) (*SniffHeader, error) {
 	sh := &SniffHeader{}
 
 	headers := bytes.Split(b, []byte{'\n'})
+	if requestLine := strings.Fields(string(headers[0])); len(requestLine) >= 2 {
+		sh.method = strings.ToUpper(requestLine[0])
+		sh.path = requestLine[1]
+		if u, err := url.ParseRequestURI(requestLine[1]); err == nil {
+			sh.path = u.Path
+		}
+	}
 	for i := 1; i < len(headers); i++ {
 		header := headers[i]
 		if len(header) == 0 {
@@ -71,6 +108,14 @@ func SniffHTTP(b []byte) (*SniffHeader, error) {
 			}
 			sh.host = dest.Address.String()
 		}
+		for _, h := range &attributeHeaders {
+			if key == h {
+				if sh.headers == nil {
+					sh.headers = make(map[string]string)
+				}
+				sh.headers[key] = string(bytes.TrimSpace(parts[1]))
+			}
+		}
 	}
 
 	if len(sh.host) > 0 {
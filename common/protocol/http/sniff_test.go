@@ -3,6 +3,9 @@ package http_test
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/v2fly/v2ray-core/v5/common"
 	. "github.com/v2fly/v2ray-core/v5/common/protocol/http"
 )
 
@@ -103,3 +106,34 @@ first_name=John&last_name=Doe&action=Submit`,
 		}
 	}
 }
+
+func TestHTTPAttributes(t *testing.T) {
+	cases := []struct {
+		input      string
+		attributes map[string]string
+	}{
+		{
+			input: "get /api/v1/users?id=1 HTTP/1.1\r\nHost: www.v2fly.org\r\nUser-Agent: curl/8.0\r\nCookie: a=b\r\n\r\n",
+			attributes: map[string]string{
+				":method":    "GET",
+				":path":      "/api/v1/users",
+				"user-agent": "curl/8.0",
+			},
+		},
+		{
+			input: "POST http://www.v2fly.org/static/a.js HTTP/1.1\r\nHost: www.v2fly.org\r\n\r\n",
+			attributes: map[string]string{
+				":method": "POST",
+				":path":   "/static/a.js",
+			},
+		},
+	}
+
+	for _, test := range cases {
+		header, err := SniffHTTP([]byte(test.input))
+		common.Must(err)
+		if r := cmp.Diff(header.Attributes(), test.attributes); r != "" {
+			t.Error(r)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package sniffer is the registry of the protocol sniffers, which recognize the protocol of the first payload
+// of a connection for routing and destination override.
+package sniffer
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+import (
+	"context"
+	"sync"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/protocol/bittorrent"
+	"github.com/v2fly/v2ray-core/v5/common/protocol/dns"
+	"github.com/v2fly/v2ray-core/v5/common/protocol/http"
+	"github.com/v2fly/v2ray-core/v5/common/protocol/quic"
+	"github.com/v2fly/v2ray-core/v5/common/protocol/tls"
+)
+
+// Result is the result of a sniffer.
+type Result interface {
+	Protocol() string
+	Domain() string
+}
+
+// Func sniffs the protocol of the first payload of a connection. It returns common.ErrNoClue
+// if the protocol is not recognized, or protocol.ErrProtoNeedMoreData if more payload is needed.
+type Func func(ctx context.Context, payload []byte) (Result, error)
+
+// Sniffer is a registered sniffer.
+type Sniffer struct {
+	Name    string
+	Network net.Network
+	Sniff   Func
+}
+
+var (
+	access   sync.RWMutex
+	sniffers []Sniffer
+)
+
+// Register registers a sniffer of the network. Sniffers are tried in the order of registration.
+// The name can be referenced by destination override of sniffing settings, to override destination
+// with the domain sniffed by the sniffer. It is expected to be called in init() of the package
+// implementing the sniffer.
+func Register(name string, network net.Network, sniff Func) error {
+	access.Lock()
+	defer access.Unlock()
+
+	for _, s := range sniffers {
+		if s.Name == name && s.Network == network {
+			return newError("sniffer ", name, " is already registered for ", network)
+		}
+	}
+	sniffers = append(sniffers, Sniffer{Name: name, Network: network, Sniff: sniff})
+	return nil
+}
+
+// Unregister removes the sniffer of the name and network. It is mainly for tests registering temporary sniffers.
+func Unregister(name string, network net.Network) {
+	access.Lock()
+	defer access.Unlock()
+
+	for i, s := range sniffers {
+		if s.Name == name && s.Network == network {
+			sniffers = append(sniffers[:i:i], sniffers[i+1:]...)
+			return
+		}
+	}
+}
+
+// IsRegistered returns true if a sniffer is registered with the name.
+func IsRegistered(name string) bool {
+	access.RLock()
+	defer access.RUnlock()
+
+	for _, s := range sniffers {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Registered returns the registered sniffers, in the order of registration.
+func Registered() []Sniffer {
+	access.RLock()
+	defer access.RUnlock()
+
+	return append([]Sniffer(nil), sniffers...)
+}
+
+func init() {
+	common.Must(Register("http", net.Network_TCP, func(c context.Context, b []byte) (Result, error) { return http.SniffHTTP(b) }))
+	common.Must(Register("tls", net.Network_TCP, func(c context.Context, b []byte) (Result, error) { return tls.SniffTLS(b) }))
+	common.Must(Register("quic", net.Network_UDP, func(c context.Context, b []byte) (Result, error) { return quic.SniffQUIC(b) }))
+	common.Must(Register("bittorrent", net.Network_TCP, func(c context.Context, b []byte) (Result, error) { return bittorrent.SniffBittorrent(b) }))
+	common.Must(Register("utp", net.Network_UDP, func(c context.Context, b []byte) (Result, error) { return bittorrent.SniffUTP(b) }))
+	common.Must(Register("bittorrent_udp_tracker", net.Network_UDP, func(c context.Context, b []byte) (Result, error) { return bittorrent.SniffUDPTracker(b) }))
+	common.Must(Register("dns", net.Network_UDP, func(c context.Context, b []byte) (Result, error) { return dns.SniffDNS(b) }))
+	common.Must(Register("dns", net.Network_TCP, func(c context.Context, b []byte) (Result, error) { return dns.SniffTCPDNS(b) }))
+}
@@ -0,0 +1,264 @@
+package tls
+
+import (
+	"crypto/md5" // nolint: gosec
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Keys of the content attributes carrying the ClientHello details.
+const (
+	AttributeALPN     = "tls.alpn"
+	AttributeVersions = "tls.versions"
+	AttributeJA3      = "tls.ja3"
+	AttributeJA4      = "tls.ja4"
+)
+
+const (
+	extensionServerName          = 0x0000
+	extensionSupportedGroups     = 0x000a
+	extensionECPointFormats      = 0x000b
+	extensionSignatureAlgorithms = 0x000d
+	extensionALPN                = 0x0010
+	extensionSupportedVersions   = 0x002b
+)
+
+// clientHello holds the ClientHello details used for fingerprinting.
+type clientHello struct {
+	version             uint16
+	cipherSuites        []uint16
+	extensions          []uint16
+	supportedGroups     []uint16
+	pointFormats        []uint8
+	signatureAlgorithms []uint16
+	supportedVersions   []uint16
+	alpn                []string
+	hasServerName       bool
+}
+
+// isGREASE reports whether v is a GREASE value defined in RFC 8701.
+func isGREASE(v uint16) bool {
+	return v&0x0f0f == 0x0a0a && v>>8 == v&0xff
+}
+
+func readUint16s(data []byte) []uint16 {
+	values := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		v := uint16(data[i])<<8 | uint16(data[i+1])
+		if !isGREASE(v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseClientHello parses a complete ClientHello handshake message.
+func parseClientHello(data []byte) (*clientHello, error) {
+	if len(data) < 39 || data[0] != 0x01 {
+		return nil, errNotClientHello
+	}
+	hello := &clientHello{version: uint16(data[4])<<8 | uint16(data[5])}
+	sessionIDLen := int(data[38])
+	data = data[39:]
+	if len(data) < sessionIDLen+2 {
+		return nil, errNotClientHello
+	}
+	data = data[sessionIDLen:]
+	cipherSuiteLen := int(data[0])<<8 | int(data[1])
+	if len(data) < 2+cipherSuiteLen+1 {
+		return nil, errNotClientHello
+	}
+	hello.cipherSuites = readUint16s(data[2 : 2+cipherSuiteLen])
+	data = data[2+cipherSuiteLen:]
+	compressionMethodsLen := int(data[0])
+	if len(data) < 1+compressionMethodsLen {
+		return nil, errNotClientHello
+	}
+	data = data[1+compressionMethodsLen:]
+	if len(data) < 2 {
+		// No extensions.
+		return hello, nil
+	}
+	extensionsLen := int(data[0])<<8 | int(data[1])
+	data = data[2:]
+	if len(data) < extensionsLen {
+		return nil, errNotClientHello
+	}
+	data = data[:extensionsLen]
+
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, errNotClientHello
+		}
+		extension := uint16(data[0])<<8 | uint16(data[1])
+		length := int(data[2])<<8 | int(data[3])
+		data = data[4:]
+		if len(data) < length {
+			return nil, errNotClientHello
+		}
+		body := data[:length]
+		data = data[length:]
+		if isGREASE(extension) {
+			continue
+		}
+		hello.extensions = append(hello.extensions, extension)
+
+		switch extension {
+		case extensionServerName:
+			hello.hasServerName = true
+		case extensionSupportedGroups:
+			if len(body) >= 2 {
+				hello.supportedGroups = readUint16s(body[2:])
+			}
+		case extensionECPointFormats:
+			if len(body) >= 1 {
+				hello.pointFormats = append(hello.pointFormats, body[1:]...)
+			}
+		case extensionSignatureAlgorithms:
+			if len(body) >= 2 {
+				hello.signatureAlgorithms = readUint16s(body[2:])
+			}
+		case extensionSupportedVersions:
+			if len(body) >= 1 {
+				hello.supportedVersions = readUint16s(body[1:])
+			}
+		case extensionALPN:
+			if len(body) < 2 {
+				break
+			}
+			for protocols := body[2:]; len(protocols) > 0; {
+				protocolLen := int(protocols[0])
+				if len(protocols) < 1+protocolLen {
+					return nil, errNotClientHello
+				}
+				hello.alpn = append(hello.alpn, string(protocols[1:1+protocolLen]))
+				protocols = protocols[1+protocolLen:]
+			}
+		}
+	}
+	return hello, nil
+}
+
+// versions returns the TLS versions offered by the client, such as "1.3".
+func (h *clientHello) versions() []string {
+	versions := h.supportedVersions
+	if len(versions) == 0 {
+		versions = []uint16{h.version}
+	}
+	names := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if name := versionName(v); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func versionName(v uint16) string {
+	switch v {
+	case 0x0300:
+		return "ssl3.0"
+	case 0x0301:
+		return "1.0"
+	case 0x0302:
+		return "1.1"
+	case 0x0303:
+		return "1.2"
+	case 0x0304:
+		return "1.3"
+	}
+	return ""
+}
+
+func joinUint16s(values []uint16, format, sep string) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf(format, v)
+	}
+	return strings.Join(parts, sep)
+}
+
+// ja3 returns the JA3 fingerprint of the ClientHello, in MD5 hex.
+func (h *clientHello) ja3() string {
+	pointFormats := make([]string, len(h.pointFormats))
+	for i, v := range h.pointFormats {
+		pointFormats[i] = strconv.Itoa(int(v))
+	}
+	s := strings.Join([]string{
+		strconv.Itoa(int(h.version)),
+		joinUint16s(h.cipherSuites, "%d", "-"),
+		joinUint16s(h.extensions, "%d", "-"),
+		joinUint16s(h.supportedGroups, "%d", "-"),
+		strings.Join(pointFormats, "-"),
+	}, ",")
+	sum := md5.Sum([]byte(s)) // nolint: gosec
+	return hex.EncodeToString(sum[:])
+}
+
+func truncatedHash(s string) string {
+	if s == "" {
+		return "000000000000"
+	}
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+func sortedUint16s(values []uint16) []uint16 {
+	sorted := append([]uint16(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// ja4 returns the JA4 fingerprint of the ClientHello sent over TCP.
+func (h *clientHello) ja4() string {
+	version := h.version
+	if len(h.supportedVersions) > 0 {
+		version = 0
+		for _, v := range h.supportedVersions {
+			version = max(version, v)
+		}
+	}
+	versionCode := "00"
+	switch version {
+	case 0x0300:
+		versionCode = "s3"
+	case 0x0301:
+		versionCode = "10"
+	case 0x0302:
+		versionCode = "11"
+	case 0x0303:
+		versionCode = "12"
+	case 0x0304:
+		versionCode = "13"
+	}
+	sni := "i"
+	if h.hasServerName {
+		sni = "d"
+	}
+	alpn := "00"
+	if len(h.alpn) > 0 && len(h.alpn[0]) > 0 {
+		first := h.alpn[0]
+		alpn = string([]byte{first[0], first[len(first)-1]})
+	}
+
+	var extensions []uint16
+	for _, e := range sortedUint16s(h.extensions) {
+		if e != extensionServerName && e != extensionALPN {
+			extensions = append(extensions, e)
+		}
+	}
+	extensionString := joinUint16s(extensions, "%04x", ",")
+	if len(h.signatureAlgorithms) > 0 {
+		extensionString += "_" + joinUint16s(h.signatureAlgorithms, "%04x", ",")
+	}
+
+	return fmt.Sprintf("t%s%s%02d%02d%s_%s_%s",
+		versionCode, sni, min(len(h.cipherSuites), 99), min(len(h.extensions), 99), alpn,
+		truncatedHash(joinUint16s(sortedUint16s(h.cipherSuites), "%04x", ",")),
+		truncatedHash(extensionString),
+	)
+}
@@ -14,6 +14,11 @@ import (
 
 type SniffHeader struct {
 	domain string
+
+	alpn     []string
+	versions []string
+	ja3      string
+	ja4      string
 }
 
 func (h *SniffHeader) Protocol() string {
@@ -24,6 +29,59 @@ func (h *SniffHeader) Domain() string {
 	return h.domain
 }
 
+// ALPN returns the application protocols offered by the client.
+func (h *SniffHeader) ALPN() []string {
+	return h.alpn
+}
+
+// Versions returns the TLS versions supported by the client, such as "1.3".
+func (h *SniffHeader) Versions() []string {
+	return h.versions
+}
+
+// JA3 returns the JA3 fingerprint of the ClientHello in MD5 hex.
+func (h *SniffHeader) JA3() string {
+	return h.ja3
+}
+
+// JA4 returns the JA4 fingerprint of the ClientHello.
+func (h *SniffHeader) JA4() string {
+	return h.ja4
+}
+
+// Attributes returns the ClientHello details as content attributes.
+func (h *SniffHeader) Attributes() map[string]string {
+	if h.ja3 == "" {
+		return nil
+	}
+	return map[string]string{
+		AttributeALPN:     strings.Join(h.alpn, ","),
+		AttributeVersions: strings.Join(h.versions, ","),
+		AttributeJA3:      h.ja3,
+		AttributeJA4:      h.ja4,
+	}
+}
+
+func isCompleteHandshake(data []byte) bool {
+	if len(data) < 4 {
+		return false
+	}
+	return len(data) >= 4+(int(data[1])<<16|int(data[2])<<8|int(data[3]))
+}
+
+// readClientHelloDetails fills in the details of a complete ClientHello.
+// The details are left empty if the ClientHello is truncated.
+func (h *SniffHeader) readClientHelloDetails(data []byte) {
+	hello, err := parseClientHello(data)
+	if err != nil {
+		return
+	}
+	h.alpn = hello.alpn
+	h.versions = hello.versions()
+	h.ja3 = hello.ja3()
+	h.ja4 = hello.ja4()
+}
+
 var (
 	errNotTLS         = errors.New("not TLS header")
 	errNotClientHello = errors.New("not client hello")
@@ -180,9 +238,16 @@ func SniffTLS(b []byte) (*SniffHeader, error) {
 		data.Write(b[5:min(5+headerLen, len(b))])
 		err := ReadClientHello(data.Bytes(), h, nil)
 		if err == nil {
-			return h, nil
-		}
-		if err == errNotTLS || err == errNotClientHello {
+			if isCompleteHandshake(data.Bytes()) {
+				h.readClientHelloDetails(data.Bytes())
+				return h, nil
+			}
+			next := min(5+headerLen, len(b))
+			if len(b) < next+5 || b[next] != 0x16 {
+				// The rest of the ClientHello is not available, return the server name only.
+				return h, nil
+			}
+		} else if err == errNotTLS || err == errNotClientHello {
 			return nil, err
 		}
 		b = b[min(5+headerLen, len(b)):]
@@ -13,6 +13,8 @@ func TestTLSHeaders(t *testing.T) {
 		input  []byte
 		domain string
 		err    bool
+		ja3    string
+		ja4    string
 	}{
 		{
 			input: []byte{
@@ -45,6 +47,8 @@ func TestTLSHeaders(t *testing.T) {
 			},
 			domain: "c.s-microsoft.com",
 			err:    false,
+			ja3:    "b8f81673c0e1d29908346f3bab892b9b",
+			ja4:    "t12d1510h2_f0daf39aad75_e69ac49eb88f",
 		},
 		{
 			input: []byte{
@@ -158,6 +162,9 @@ func TestTLSHeaders(t *testing.T) {
 			if header.Domain() != test.domain {
 				t.Error("expect domain ", test.domain, " but got ", header.Domain())
 			}
+			if test.ja3 != "" && (header.JA3() != test.ja3 || header.JA4() != test.ja4) {
+				t.Error("expect fingerprints ", test.ja3, " ", test.ja4, " but got ", header.JA3(), " ", header.JA4())
+			}
 		}
 	}
 }
@@ -175,4 +182,10 @@ func TestSniffTLSRecordFragmentation(t *testing.T) {
 	if err != nil || hdr.Domain() != "example.com" {
 		t.Error("failed")
 	}
+	if alpn := hdr.ALPN(); len(alpn) != 2 || alpn[0] != "h2" || alpn[1] != "http/1.1" {
+		t.Error("unexpected alpn ", alpn)
+	}
+	if versions := hdr.Versions(); len(versions) != 2 || versions[0] != "1.3" || versions[1] != "1.2" {
+		t.Error("unexpected versions ", versions)
+	}
 }
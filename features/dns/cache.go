@@ -0,0 +1,46 @@
+package dns
+
+import (
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+// CacheEntry is a record cached for a name server.
+type CacheEntry struct {
+	Server string
+	Domain string
+	// Type is the query type of the record, either A or AAAA.
+	Type  uint16
+	IPs   []net.IP
+	RCode int
+	// Expire is the time the record expires.
+	Expire time.Time
+	// Stale is true if the record has expired, and is kept only to be served stale.
+	Stale bool
+	// Hits is the number of lookups of the domain since its records are updated.
+	Hits uint32
+}
+
+// CacheStats is the statistics of a DNS cache.
+type CacheStats struct {
+	// Size is the number of cached domains, and Capacity is the maximum of it.
+	Size      int
+	Capacity  int
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+// CacheManager is a Client that caches the records of its name servers.
+//
+// v2ray:api:beta
+type CacheManager interface {
+	// GetCacheEntries returns the cached records of the name server and domain. Empty server or domain matches all.
+	GetCacheEntries(server, domain string) []*CacheEntry
+	// FlushCache removes the cached records of the name server and domain, and returns the number of removed domains.
+	// Empty server or domain matches all.
+	FlushCache(server, domain string) int
+	// GetCacheStats returns the statistics of the cache.
+	GetCacheStats() CacheStats
+}
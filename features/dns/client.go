@@ -1,6 +1,7 @@
 package dns
 
 import (
+	"context"
 	"time"
 
 	"github.com/v2fly/v2ray-core/v5/common/errors"
@@ -68,6 +69,15 @@ type RawQuery interface {
 	QueryRaw(b []byte) ([]byte, error)
 }
 
+// ContextQuery is an optional feature for querying on behalf of the inbound connection in the context, which is
+// recorded in the DNS log. The context is not used for cancelling the query.
+//
+// v2ray:api:beta
+type ContextQuery interface {
+	LookupIPWithContext(ctx context.Context, domain string, option IPOption) ([]net.IP, time.Time, error)
+	QueryRawWithContext(ctx context.Context, b []byte) ([]byte, error)
+}
+
 // LookupIPWithOption is a helper function for querying DNS information from a dns.Client with dns.IPOption.
 //
 // v2ray:api:beta
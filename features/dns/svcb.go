@@ -0,0 +1,31 @@
+package dns
+
+import (
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+// SVCBRecord is a service binding from an HTTPS or SVCB record (RFC 9460) in ServiceMode.
+type SVCBRecord struct {
+	Priority uint16
+	// Target is the target name of the service, or "." for the owner name of the record.
+	Target string
+	ALPN   []string
+	// NoDefaultALPN indicates that the default ALPN of the scheme is not supported.
+	NoDefaultALPN bool
+	Port          uint16
+	IPv4Hint      []net.IP
+	IPv6Hint      []net.IP
+	ECHConfig     []byte
+}
+
+// SVCBLookup is an optional feature for querying service bindings of a domain.
+//
+// v2ray:api:beta
+type SVCBLookup interface {
+	// LookupHTTPS returns the HTTPS records of the domain in order of priority, with TTL information.
+	LookupHTTPS(domain string) ([]*SVCBRecord, time.Time, error)
+	// LookupSVCB returns the SVCB records of the domain in order of priority, with TTL information.
+	LookupSVCB(domain string) ([]*SVCBRecord, time.Time, error)
+}
@@ -27,6 +27,12 @@ type Stats struct {
 	UserUplink bool
 	// Whether or not to enable stat counter for user downlink traffic.
 	UserDownlink bool
+	// Whether or not to enable stat gauge for the connections of the user.
+	UserOnline bool
+	// Whether or not to enable stat counter for the distinct source IPs of the user.
+	UserSourceIPs bool
+	// Window of the source IPs of the user being counted.
+	SourceIPWindow time.Duration
 }
 
 // Buffer contains settings for internal buffer.
@@ -45,6 +51,10 @@ type SystemStats struct {
 	OutboundUplink bool
 	// Whether or not to enable stat counter for downlink traffic in outbound handlers.
 	OutboundDownlink bool
+	// Whether or not to enable stat gauge for the connections in inbound handlers.
+	InboundConnections bool
+	// Whether or not to account the traffic of the top destinations per outbound handler and user.
+	OutboundDestinations bool
 }
 
 // System contains policy settings at system level.
@@ -126,8 +136,9 @@ func SessionDefault() Session {
 			DownlinkOnly:   time.Second * 1,
 		},
 		Stats: Stats{
-			UserUplink:   false,
-			UserDownlink: false,
+			UserUplink:     false,
+			UserDownlink:   false,
+			SourceIPWindow: time.Minute * 10,
 		},
 		Buffer: defaultBufferPolicy(),
 	}
@@ -2,6 +2,7 @@ package routing
 
 import (
 	"context"
+	"time"
 
 	"github.com/v2fly/v2ray-core/v5/common/net"
 	"github.com/v2fly/v2ray-core/v5/features"
@@ -25,3 +26,24 @@ type Dispatcher interface {
 func DispatcherType() interface{} {
 	return (*Dispatcher)(nil)
 }
+
+// SessionInfo is the snapshot of a session being dispatched.
+type SessionInfo struct {
+	ID          uint32
+	InboundTag  string
+	User        string
+	Source      net.Destination
+	Target      net.Destination
+	OutboundTag string
+	Start       time.Time
+	Uplink      int64
+	Downlink    int64
+}
+
+// SessionRegistry is an optional interface of Dispatcher, which tracks the sessions being dispatched.
+type SessionRegistry interface {
+	// Sessions returns the sessions being dispatched.
+	Sessions() []*SessionInfo
+	// CloseSession interrupts the session of the ID. It returns false if no such session is being dispatched.
+	CloseSession(id uint32) bool
+}
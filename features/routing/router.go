@@ -1,6 +1,10 @@
 package routing
 
 import (
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/features"
 )
@@ -15,6 +19,68 @@ type Router interface {
 	PickRoute(ctx Context) (Route, error)
 }
 
+// RuleManager is a Router whose rules can be modified at runtime. Routes already picked are not affected.
+//
+// v2ray:api:beta
+type RuleManager interface {
+	// AddRule adds the routing rules and balancing rules in config, before or after existing rules.
+	AddRule(config proto.Message, prepend bool) error
+
+	// RemoveRule removes all routing rules with the given rule tag.
+	RemoveRule(tag string) error
+
+	// ReplaceRules atomically replaces all routing rules and balancing rules with those in config.
+	ReplaceRules(config proto.Message) error
+}
+
+// RuleStats is the statistics of a routing rule.
+type RuleStats struct {
+	// Index is the position of the rule in the rule list.
+	Index        int
+	RuleTag      string
+	OutboundTag  string
+	BalancingTag string
+	// Hits is the number of times the rule was matched.
+	Hits int64
+	// LastMatch is the time the rule was last matched, zero if never.
+	LastMatch time.Time
+}
+
+// RuleStatsReader is a Router that keeps statistics of its rules.
+//
+// v2ray:api:beta
+type RuleStatsReader interface {
+	// GetRuleStats returns statistics of rules with the given rule tag in order, or of all rules if tag is empty.
+	// Hit counts of the returned rules are reset if reset is true.
+	GetRuleStats(tag string, reset bool) []*RuleStats
+}
+
+// RuleTrace is the evaluation result of a routing rule.
+type RuleTrace struct {
+	// Index is the position of the rule in the rule list.
+	Index        int
+	RuleTag      string
+	OutboundTag  string
+	BalancingTag string
+	Matched      bool
+	// Reason describes the first condition of the rule that does not match.
+	Reason string
+	// Resolved is true if the rule is evaluated again with IPs resolved from the target domain.
+	Resolved bool
+	// Candidates are the outbounds the balancer of the matched rule picks from.
+	Candidates []string
+}
+
+// RouteExplainer is a Router that can explain its routing decisions.
+//
+// v2ray:api:beta
+type RouteExplainer interface {
+	// ExplainRoute works like PickRoute, and also returns the evaluation of each rule in order. It never changes
+	// the routing state, so if the matched rule has a balancer, the outbound of the route may be left empty, while
+	// the candidates of the balancer are in the trace of the rule.
+	ExplainRoute(ctx Context) (Route, []*RuleTrace, error)
+}
+
 // Route is the routing result of Router feature.
 //
 // v2ray:api:stable
@@ -4,6 +4,7 @@ package stats
 
 import (
 	"context"
+	"time"
 
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/features"
@@ -21,6 +22,54 @@ type Counter interface {
 	Add(int64) int64
 }
 
+// UniqueCounter is a counter whose value is the number of distinct values recorded in a sliding window.
+type UniqueCounter interface {
+	Counter
+	// Record records the value seen just now.
+	Record(value string)
+}
+
+// UniqueCounterManager is an optional interface of Manager, which supports unique counters.
+type UniqueCounterManager interface {
+	// RegisterUniqueCounter registers a new unique counter of the window to the manager. The identifier string
+	// must not be empty, and unique among other counters.
+	RegisterUniqueCounter(name string, window time.Duration) (UniqueCounter, error)
+}
+
+// Gauge is a counter of an amount at the moment, such as the number of connections, which is never reset by
+// the stats queries.
+type Gauge interface {
+	Counter
+	// IsGauge marks the counter as a gauge.
+	IsGauge()
+}
+
+// GaugeManager is an optional interface of Manager, which supports gauges.
+type GaugeManager interface {
+	// RegisterGauge registers a new gauge to the manager. The identifier string must not be empty, and unique
+	// among other counters.
+	RegisterGauge(name string) (Gauge, error)
+}
+
+// DestinationTraffic is the traffic to a destination through an outbound, of a user.
+type DestinationTraffic struct {
+	OutboundTag string
+	User        string
+	Domain      string
+	// Bytes is the estimated traffic, which is overestimated by at most Error bytes.
+	Bytes int64
+	Error int64
+}
+
+// DestinationRecorder is an optional interface of Manager, which keeps the destinations of most traffic in
+// bounded memory.
+type DestinationRecorder interface {
+	// RecordDestination adds the bytes to the traffic of the destination.
+	RecordDestination(outboundTag, user, domain string, bytes int64)
+	// TopDestinations returns the destinations of most traffic, most first.
+	TopDestinations() []*DestinationTraffic
+}
+
 // Channel is the interface for stats channel.
 //
 // v2ray:api:stable
@@ -89,6 +138,62 @@ func GetOrRegisterCounter(m Manager, name string) (Counter, error) {
 	return m.RegisterCounter(name)
 }
 
+// GetOrRegisterUniqueCounter tries to get the unique counter first. If not exist, it then tries to create a new
+// unique counter, if the manager supports it. It is safe to be called concurrently with the same name.
+func GetOrRegisterUniqueCounter(m Manager, name string, window time.Duration) (UniqueCounter, error) {
+	if counter := m.GetCounter(name); counter != nil {
+		return asUniqueCounter(name, counter)
+	}
+	um, ok := m.(UniqueCounterManager)
+	if !ok {
+		return nil, newError("unique counter is not supported")
+	}
+	c, err := um.RegisterUniqueCounter(name, window)
+	if err != nil {
+		// The counter may have been registered by another caller since it was not found.
+		if counter := m.GetCounter(name); counter != nil {
+			return asUniqueCounter(name, counter)
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func asUniqueCounter(name string, counter Counter) (UniqueCounter, error) {
+	if c, ok := counter.(UniqueCounter); ok {
+		return c, nil
+	}
+	return nil, newError("counter ", name, " is not a unique counter")
+}
+
+// GetOrRegisterGauge tries to get the gauge first. If not exist, it then tries to create a new gauge, if the
+// manager supports it. It is safe to be called concurrently with the same name.
+func GetOrRegisterGauge(m Manager, name string) (Gauge, error) {
+	if counter := m.GetCounter(name); counter != nil {
+		return asGauge(name, counter)
+	}
+	gm, ok := m.(GaugeManager)
+	if !ok {
+		return nil, newError("gauge is not supported")
+	}
+	g, err := gm.RegisterGauge(name)
+	if err != nil {
+		// The gauge may have been registered by another caller since it was not found.
+		if counter := m.GetCounter(name); counter != nil {
+			return asGauge(name, counter)
+		}
+		return nil, err
+	}
+	return g, nil
+}
+
+func asGauge(name string, counter Counter) (Gauge, error) {
+	if g, ok := counter.(Gauge); ok {
+		return g, nil
+	}
+	return nil, newError("counter ", name, " is not a gauge")
+}
+
 // GetOrRegisterChannel tries to get the StatChannel first. If not exist, it then tries to create a new channel.
 func GetOrRegisterChannel(m Manager, name string) (Channel, error) {
 	channel := m.GetChannel(name)
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/v2fly/v2ray-core/v5/app/proxyman"
+	protocolsniffer "github.com/v2fly/v2ray-core/v5/common/protocol/sniffer"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon"
 )
 
@@ -33,7 +34,10 @@ func (c *SniffingConfig) Build() (*proxyman.SniffingConfig, error) {
 			case "fakedns+others":
 				p = append(p, "fakedns+others")
 			default:
-				return nil, newError("unknown protocol: ", domainOverride)
+				if !protocolsniffer.IsRegistered(domainOverride) {
+					return nil, newError("unknown protocol: ", domainOverride)
+				}
+				p = append(p, domainOverride)
 			}
 		}
 	}
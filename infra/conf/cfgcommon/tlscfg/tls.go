@@ -7,6 +7,7 @@ import (
 	"github.com/golang/protobuf/proto"
 
 	"github.com/v2fly/v2ray-core/v5/common/platform/filesystem"
+	"github.com/v2fly/v2ray-core/v5/features"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon"
 	"github.com/v2fly/v2ray-core/v5/transport/internet/tls"
 )
@@ -24,6 +25,7 @@ type TLSConfig struct {
 	ECHConfig                            string                `json:"echConfig"`
 	ECHDOHServer                         string                `json:"echDohServer"`
 	ECHQueryDomain                       string                `json:"echQueryDomain"`
+	QueryHTTPSRecord                     bool                  `json:"queryHttpsRecord"`
 	MinVersion                           string                `json:"minVersion"`
 	MaxVersion                           string                `json:"maxVersion"`
 	AllowInsecureIfPinnedPeerCertificate bool                  `json:"allowInsecureIfPinnedPeerCertificate"`
@@ -72,8 +74,12 @@ func (c *TLSConfig) Build() (proto.Message, error) {
 		config.EchConfig = ECHConfig
 	}
 
+	if c.ECHDOHServer != "" {
+		features.PrintDeprecatedFeatureWarning("echDohServer")
+	}
 	config.Ech_DOHserver = c.ECHDOHServer
 	config.EchQueryDomain = c.ECHQueryDomain
+	config.QueryHttpsRecord = c.QueryHTTPSRecord
 
 	switch strings.ToLower(c.MinVersion) {
 	case "tls1_0", "tls1.0":
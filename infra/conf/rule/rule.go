@@ -215,6 +215,19 @@ func parseFieldRule(ctx context.Context, msg json.RawMessage) (*router.RoutingRu
 		InboundTag *cfgcommon.StringList  `json:"inboundTag"`
 		Protocols  *cfgcommon.StringList  `json:"protocol"`
 		Attributes string                 `json:"attrs"`
+		TimeWindow *cfgcommon.StringList  `json:"timeWindow"`
+		TimeZone   string                 `json:"timeZone"`
+
+		Script         string                `json:"script"`
+		ScriptMaxSteps uint64                `json:"scriptMaxSteps"`
+		ScriptGeoIP    *cfgcommon.StringList `json:"scriptGeoip"`
+		ScriptGeoSite  *cfgcommon.StringList `json:"scriptGeosite"`
+
+		TLSALPN        *cfgcommon.StringList `json:"tlsAlpn"`
+		TLSVersion     *cfgcommon.StringList `json:"tlsVersion"`
+		TLSFingerprint *cfgcommon.StringList `json:"tlsFingerprint"`
+		HTTPMethod     *cfgcommon.StringList `json:"httpMethod"`
+		HTTPPath       *cfgcommon.StringList `json:"httpPath"`
 	}
 	rawFieldRule := new(RawFieldRule)
 	err := json.Unmarshal(msg, rawFieldRule)
@@ -240,6 +253,10 @@ func parseFieldRule(ctx context.Context, msg json.RawMessage) (*router.RoutingRu
 		rule.DomainMatcher = rawFieldRule.DomainMatcher
 	}
 
+	if rawFieldRule.RuleTag != "" {
+		rule.RuleTag = rawFieldRule.RuleTag
+	}
+
 	if rawFieldRule.Domain != nil {
 		for _, domain := range *rawFieldRule.Domain {
 			rules, err := parseDomainRule(ctx, domain)
@@ -310,6 +327,67 @@ func parseFieldRule(ctx context.Context, msg json.RawMessage) (*router.RoutingRu
 		rule.Attributes = rawFieldRule.Attributes
 	}
 
+	if rawFieldRule.TLSALPN != nil {
+		rule.TlsAlpn = *rawFieldRule.TLSALPN
+	}
+
+	if rawFieldRule.TLSVersion != nil {
+		rule.TlsVersion = *rawFieldRule.TLSVersion
+	}
+
+	if rawFieldRule.TLSFingerprint != nil {
+		rule.TlsFingerprint = *rawFieldRule.TLSFingerprint
+	}
+
+	if rawFieldRule.HTTPMethod != nil {
+		rule.HttpMethod = *rawFieldRule.HTTPMethod
+	}
+
+	if rawFieldRule.HTTPPath != nil {
+		rule.HttpPath = *rawFieldRule.HTTPPath
+	}
+
+	if rawFieldRule.TimeWindow != nil {
+		for _, s := range *rawFieldRule.TimeWindow {
+			window, err := router.ParseTimeWindow(s)
+			if err != nil {
+				return nil, newError("failed to parse time window: ", s).Base(err)
+			}
+			rule.TimeWindow = append(rule.TimeWindow, window)
+		}
+		rule.TimeZone = rawFieldRule.TimeZone
+	}
+
+	if len(rawFieldRule.Script) > 0 {
+		rule.Script = rawFieldRule.Script
+		rule.ScriptMaxSteps = rawFieldRule.ScriptMaxSteps
+		geoLoader := cfgcommon.GetConfigureLoadingEnvironment(ctx).GetGeoLoader()
+		if rawFieldRule.ScriptGeoIP != nil {
+			for _, code := range *rawFieldRule.ScriptGeoIP {
+				cidrs, err := geoLoader.LoadGeoIP(code)
+				if err != nil {
+					return nil, newError("failed to load geoip: ", code).Base(err)
+				}
+				rule.ScriptGeoip = append(rule.ScriptGeoip, &routercommon.GeoIP{
+					CountryCode: strings.ToUpper(code),
+					Cidr:        cidrs,
+				})
+			}
+		}
+		if rawFieldRule.ScriptGeoSite != nil {
+			for _, code := range *rawFieldRule.ScriptGeoSite {
+				domains, err := geoLoader.LoadGeoSite(code)
+				if err != nil {
+					return nil, newError("failed to load geosite: ", code).Base(err)
+				}
+				rule.ScriptGeosite = append(rule.ScriptGeosite, &routercommon.GeoSite{
+					CountryCode: code,
+					Domain:      domains,
+				})
+			}
+		}
+	}
+
 	return rule, nil
 }
 
@@ -390,6 +468,7 @@ type RouterRule struct {
 	Type        string `json:"type"`
 	OutboundTag string `json:"outboundTag"`
 	BalancerTag string `json:"balancerTag"`
+	RuleTag     string `json:"ruleTag"`
 
 	DomainMatcher string `json:"domainMatcher"`
 }
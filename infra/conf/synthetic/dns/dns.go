@@ -30,6 +30,9 @@ type NameServerConfig struct {
 	Domains          []string
 	ExpectIPs        cfgcommon.StringList
 	FakeDNS          FakeDNSConfigExtend
+	DNSSEC           string
+	TrustAnchor      cfgcommon.StringList
+	ClientSubnet     *ClientSubnetConfig
 
 	cfgctx context.Context
 }
@@ -53,6 +56,9 @@ func (c *NameServerConfig) UnmarshalJSON(data []byte) error {
 		Domains          []string             `json:"domains"`
 		ExpectIPs        cfgcommon.StringList `json:"expectIps"`
 		FakeDNS          FakeDNSConfigExtend  `json:"fakedns"`
+		DNSSEC           string               `json:"dnssec"`
+		TrustAnchor      cfgcommon.StringList `json:"dnssecTrustAnchor"`
+		ClientSubnet     *ClientSubnetConfig  `json:"clientSubnet"`
 	}
 	if err := json.Unmarshal(data, &advanced); err == nil {
 		c.Address = advanced.Address
@@ -66,6 +72,9 @@ func (c *NameServerConfig) UnmarshalJSON(data []byte) error {
 		c.Domains = advanced.Domains
 		c.ExpectIPs = advanced.ExpectIPs
 		c.FakeDNS = advanced.FakeDNS
+		c.DNSSEC = advanced.DNSSEC
+		c.TrustAnchor = advanced.TrustAnchor
+		c.ClientSubnet = advanced.ClientSubnet
 		return nil
 	}
 
@@ -142,6 +151,14 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 		myClientIP = []byte(c.ClientIP.IP())
 	}
 
+	var clientSubnet *dns.ClientSubnet
+	if c.ClientSubnet != nil {
+		clientSubnet, err = c.ClientSubnet.Build(cfgctx)
+		if err != nil {
+			return nil, newError("failed to build client subnet").Base(err)
+		}
+	}
+
 	queryStrategy := new(dns.QueryStrategy)
 	switch strings.ToLower(c.QueryStrategy) {
 	case "useip", "use_ip", "use-ip":
@@ -155,12 +172,9 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 	}
 
 	cacheStrategy := new(dns.CacheStrategy)
-	switch strings.ToLower(c.CacheStrategy) {
-	case "enabled":
-		*cacheStrategy = dns.CacheStrategy_CacheEnabled
-	case "disabled":
-		*cacheStrategy = dns.CacheStrategy_CacheDisabled
-	default:
+	if strategy, ok := parseCacheStrategy(c.CacheStrategy); ok {
+		*cacheStrategy = strategy
+	} else {
 		cacheStrategy = nil
 	}
 
@@ -176,6 +190,16 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 		fallbackStrategy = nil
 	}
 
+	var dnssec dns.DnssecMode
+	switch strings.ToLower(c.DNSSEC) {
+	case "", "disabled", "off":
+		dnssec = dns.DnssecMode_DnssecDisabled
+	case "validate":
+		dnssec = dns.DnssecMode_DnssecValidate
+	default:
+		return nil, newError("unknown dnssec mode: ", c.DNSSEC)
+	}
+
 	return &dns.NameServer{
 		Address: &net.Endpoint{
 			Network: net.Network_UDP,
@@ -183,6 +207,7 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 			Port:    uint32(c.Port),
 		},
 		ClientIp:          myClientIP,
+		ClientSubnet:      clientSubnet,
 		Tag:               c.Tag,
 		SkipFallback:      c.SkipFallback,
 		QueryStrategy:     queryStrategy,
@@ -192,9 +217,27 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 		Geoip:             geoipList,
 		OriginalRules:     originalRules,
 		FakeDns:           fakeDNS,
+		Dnssec:            dnssec,
+		DnssecTrustAnchor: c.TrustAnchor,
 	}, nil
 }
 
+func parseCacheStrategy(s string) (dns.CacheStrategy, bool) {
+	switch strings.ToLower(s) {
+	case "enabled":
+		return dns.CacheStrategy_CacheEnabled, true
+	case "disabled":
+		return dns.CacheStrategy_CacheDisabled, true
+	case "servestale", "serve_stale", "serve-stale":
+		return dns.CacheStrategy_CacheServeStale, true
+	case "prefetch":
+		return dns.CacheStrategy_CachePrefetch, true
+	case "servestaleandprefetch", "serve_stale_and_prefetch", "serve-stale-and-prefetch":
+		return dns.CacheStrategy_CacheServeStaleAndPrefetch, true
+	}
+	return dns.CacheStrategy_CacheEnabled, false
+}
+
 var typeMap = map[routercommon.Domain_Type]dns.DomainMatchingType{
 	routercommon.Domain_Full:       dns.DomainMatchingType_Full,
 	routercommon.Domain_RootDomain: dns.DomainMatchingType_Subdomain,
@@ -216,9 +259,100 @@ type DNSConfig struct { // nolint: revive
 	DisableCache           bool                    `json:"disableCache"`
 	DisableFallback        bool                    `json:"disableFallback"`
 	DisableFallbackIfMatch bool                    `json:"disableFallbackIfMatch"`
+	CacheSize              uint32                  `json:"cacheSize"`
+	CacheMinTTL            uint32                  `json:"cacheMinTTL"`
+	CacheMaxTTL            uint32                  `json:"cacheMaxTTL"`
+	ParallelStrategy       string                  `json:"parallelStrategy"`
+	ParallelCount          uint32                  `json:"parallelCount"`
+	HostsFiles             []*HostsFileConfig      `json:"hostsFiles"`
+	ClientSubnet           *ClientSubnetConfig     `json:"clientSubnet"`
 	cfgctx                 context.Context
 }
 
+// ClientSubnetConfig is a JSON serializable object for dns.ClientSubnet.
+type ClientSubnetConfig struct {
+	FromSource bool                      `json:"fromSource"`
+	IPv4Prefix uint32                    `json:"ipv4Prefix"`
+	IPv6Prefix uint32                    `json:"ipv6Prefix"`
+	Rules      []*ClientSubnetRuleConfig `json:"rules"`
+}
+
+// ClientSubnetRuleConfig is a JSON serializable object for dns.ClientSubnet_Rule.
+type ClientSubnetRuleConfig struct {
+	Domains  []string           `json:"domains"`
+	Users    []string           `json:"users"`
+	ClientIP *cfgcommon.Address `json:"clientIp"`
+}
+
+// Build builds the client subnet, parsing the domain rules in the configure loading context.
+func (c *ClientSubnetConfig) Build(cfgctx context.Context) (*dns.ClientSubnet, error) {
+	if c.IPv4Prefix > 32 {
+		return nil, newError("invalid ipv4Prefix: ", c.IPv4Prefix)
+	}
+	if c.IPv6Prefix > 128 {
+		return nil, newError("invalid ipv6Prefix: ", c.IPv6Prefix)
+	}
+	subnet := &dns.ClientSubnet{
+		FromSource: c.FromSource,
+		Ipv4Prefix: c.IPv4Prefix,
+		Ipv6Prefix: c.IPv6Prefix,
+	}
+	for _, r := range c.Rules {
+		rule := &dns.ClientSubnet_Rule{
+			UserEmail: r.Users,
+		}
+		for _, domain := range r.Domains {
+			parsedDomain, err := rule2.ParseDomainRule(cfgctx, domain)
+			if err != nil {
+				return nil, newError("invalid domain rule: ", domain).Base(err)
+			}
+			for _, pd := range parsedDomain {
+				rule.Domain = append(rule.Domain, &dns.NameServer_PriorityDomain{
+					Type:   toDomainMatchingType(pd.Type),
+					Domain: pd.Value,
+				})
+			}
+		}
+		if r.ClientIP != nil {
+			if !r.ClientIP.Family().IsIP() {
+				return nil, newError("not an IP address:", r.ClientIP.String())
+			}
+			rule.ClientIp = []byte(r.ClientIP.IP())
+		}
+		subnet.Rule = append(subnet.Rule, rule)
+	}
+	return subnet, nil
+}
+
+// HostsFileConfig is a JSON serializable object for dns.HostsFile.
+type HostsFileConfig struct {
+	Format         string `json:"format"`
+	Source         string `json:"source"`
+	ImportUsingTag string `json:"importUsingTag"`
+	ReloadInterval uint32 `json:"reloadInterval"`
+}
+
+// Build implements Buildable.
+func (c *HostsFileConfig) Build() (*dns.HostsFile, error) {
+	if c.Source == "" {
+		return nil, newError("empty source of hosts file")
+	}
+	file := &dns.HostsFile{
+		Source:         c.Source,
+		ImportUsingTag: c.ImportUsingTag,
+		ReloadInterval: c.ReloadInterval,
+	}
+	switch strings.ToLower(c.Format) {
+	case "", "hosts":
+		file.Format = dns.HostsFile_Hosts
+	case "adblock":
+		file.Format = dns.HostsFile_Adblock
+	default:
+		return nil, newError("unknown hosts file format: ", c.Format)
+	}
+	return file, nil
+}
+
 type HostAddress struct {
 	addr  *cfgcommon.Address
 	addrs []*cfgcommon.Address
@@ -295,6 +429,13 @@ func (c *DNSConfig) Build() (*dns.Config, error) {
 		DisableFallback:        c.DisableFallback,
 		DisableFallbackIfMatch: c.DisableFallbackIfMatch,
 		DomainMatcher:          c.DomainMatcher,
+		CacheSize:              c.CacheSize,
+		CacheMinTtl:            c.CacheMinTTL,
+		CacheMaxTtl:            c.CacheMaxTTL,
+		ParallelCount:          c.ParallelCount,
+	}
+	if c.CacheMaxTTL > 0 && c.CacheMinTTL > c.CacheMaxTTL {
+		return nil, newError("cacheMinTTL ", c.CacheMinTTL, " is larger than cacheMaxTTL ", c.CacheMaxTTL)
 	}
 
 	if c.ClientIP != nil {
@@ -304,6 +445,14 @@ func (c *DNSConfig) Build() (*dns.Config, error) {
 		config.ClientIp = []byte(c.ClientIP.IP())
 	}
 
+	if c.ClientSubnet != nil {
+		clientSubnet, err := c.ClientSubnet.Build(c.cfgctx)
+		if err != nil {
+			return nil, newError("failed to build client subnet").Base(err)
+		}
+		config.ClientSubnet = clientSubnet
+	}
+
 	config.QueryStrategy = dns.QueryStrategy_USE_IP
 	switch strings.ToLower(c.QueryStrategy) {
 	case "useip", "use_ip", "use-ip":
@@ -315,11 +464,8 @@ func (c *DNSConfig) Build() (*dns.Config, error) {
 	}
 
 	config.CacheStrategy = dns.CacheStrategy_CacheEnabled
-	switch strings.ToLower(c.CacheStrategy) {
-	case "enabled":
-		config.CacheStrategy = dns.CacheStrategy_CacheEnabled
-	case "disabled":
-		config.CacheStrategy = dns.CacheStrategy_CacheDisabled
+	if strategy, ok := parseCacheStrategy(c.CacheStrategy); ok {
+		config.CacheStrategy = strategy
 	}
 
 	config.FallbackStrategy = dns.FallbackStrategy_Enabled
@@ -332,6 +478,25 @@ func (c *DNSConfig) Build() (*dns.Config, error) {
 		config.FallbackStrategy = dns.FallbackStrategy_DisabledIfAnyMatch
 	}
 
+	switch strings.ToLower(c.ParallelStrategy) {
+	case "", "sequential":
+		config.ParallelStrategy = dns.ParallelStrategy_Sequential
+	case "race":
+		config.ParallelStrategy = dns.ParallelStrategy_Race
+	case "preferexpectedips", "prefer_expected_ips", "prefer-expected-ips":
+		config.ParallelStrategy = dns.ParallelStrategy_PreferExpectedIPs
+	default:
+		return nil, newError("unknown parallel strategy: ", c.ParallelStrategy)
+	}
+
+	for _, hostsFile := range c.HostsFiles {
+		file, err := hostsFile.Build()
+		if err != nil {
+			return nil, newError("failed to build hosts file").Base(err)
+		}
+		config.HostsFile = append(config.HostsFile, file)
+	}
+
 	for _, server := range c.Servers {
 		server.cfgctx = c.cfgctx
 		ns, err := server.Build()
@@ -185,7 +185,23 @@ func TestDNSConfigParsing(t *testing.T) {
 				"clientIp": "10.0.0.1",
 				"queryStrategy": "UseIPv4",
 				"cacheStrategy": "disabled",
-				"fallbackStrategy": "enabled"
+				"fallbackStrategy": "enabled",
+				"cacheSize": 1024,
+				"cacheMinTTL": 60,
+				"cacheMaxTTL": 3600,
+				"parallelStrategy": "preferExpectedIPs",
+				"parallelCount": 2,
+				"hostsFiles": [
+					{ "source": "/etc/hosts" },
+					{ "format": "adblock", "source": "https://example.com/list.txt", "importUsingTag": "direct", "reloadInterval": 86400 }
+				],
+				"clientSubnet": {
+					"fromSource": true,
+					"ipv6Prefix": 48,
+					"rules": [
+						{ "domains": ["domain:example.com"], "users": ["love@v2fly.org"], "clientIp": "1.2.3.4" }
+					]
+				}
 			}`,
 			Parser: parserCreator(),
 			Output: &dns.Config{
@@ -284,6 +300,36 @@ func TestDNSConfigParsing(t *testing.T) {
 				QueryStrategy:    dns.QueryStrategy_USE_IP4,
 				CacheStrategy:    dns.CacheStrategy_CacheDisabled,
 				FallbackStrategy: dns.FallbackStrategy_Enabled,
+				CacheSize:        1024,
+				CacheMinTtl:      60,
+				CacheMaxTtl:      3600,
+				ParallelStrategy: dns.ParallelStrategy_PreferExpectedIPs,
+				ParallelCount:    2,
+				HostsFile: []*dns.HostsFile{
+					{
+						Format: dns.HostsFile_Hosts,
+						Source: "/etc/hosts",
+					},
+					{
+						Format:         dns.HostsFile_Adblock,
+						Source:         "https://example.com/list.txt",
+						ImportUsingTag: "direct",
+						ReloadInterval: 86400,
+					},
+				},
+				ClientSubnet: &dns.ClientSubnet{
+					FromSource: true,
+					Ipv6Prefix: 48,
+					Rule: []*dns.ClientSubnet_Rule{
+						{
+							Domain: []*dns.NameServer_PriorityDomain{
+								{Type: dns.DomainMatchingType_Subdomain, Domain: "example.com"},
+							},
+							UserEmail: []string{"love@v2fly.org"},
+							ClientIp:  []byte{1, 2, 3, 4},
+						},
+					},
+				},
 			},
 		},
 	})
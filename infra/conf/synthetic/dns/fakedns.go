@@ -8,8 +8,19 @@ import (
 )
 
 type FakeDNSPoolElementConfig struct {
-	IPPool  string `json:"ipPool"`
-	LRUSize int64  `json:"poolSize"`
+	IPPool           string `json:"ipPool"`
+	LRUSize          int64  `json:"poolSize"`
+	Persistent       bool   `json:"persistent"`
+	SnapshotInterval int64  `json:"snapshotInterval"`
+}
+
+func (c *FakeDNSPoolElementConfig) build() *fakedns.FakeDnsPool {
+	return &fakedns.FakeDnsPool{
+		IpPool:           c.IPPool,
+		LruSize:          c.LRUSize,
+		Persistent:       c.Persistent,
+		SnapshotInterval: c.SnapshotInterval,
+	}
 }
 
 type FakeDNSConfig struct {
@@ -54,16 +65,13 @@ func (f *FakeDNSConfig) Build() (*fakedns.FakeDnsPoolMulti, error) {
 	fakeDNSPool := fakedns.FakeDnsPoolMulti{}
 
 	if f.pool != nil {
-		fakeDNSPool.Pools = append(fakeDNSPool.Pools, &fakedns.FakeDnsPool{
-			IpPool:  f.pool.IPPool,
-			LruSize: f.pool.LRUSize,
-		})
+		fakeDNSPool.Pools = append(fakeDNSPool.Pools, f.pool.build())
 		return &fakeDNSPool, nil
 	}
 
 	if f.pools != nil {
 		for _, v := range f.pools {
-			fakeDNSPool.Pools = append(fakeDNSPool.Pools, &fakedns.FakeDnsPool{IpPool: v.IPPool, LruSize: v.LRUSize})
+			fakeDNSPool.Pools = append(fakeDNSPool.Pools, v.build())
 		}
 		return &fakeDNSPool, nil
 	}
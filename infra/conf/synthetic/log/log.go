@@ -18,6 +18,7 @@ type LogConfig struct { // nolint: revive
 	AccessLog string `json:"access"`
 	ErrorLog  string `json:"error"`
 	LogLevel  string `json:"loglevel"`
+	DNSLog    string `json:"dns"`
 }
 
 func (v *LogConfig) Build() *log.Config {
@@ -41,6 +42,13 @@ func (v *LogConfig) Build() *log.Config {
 		config.Error.Path = v.ErrorLog
 		config.Error.Type = log.LogType_File
 	}
+	switch v.DNSLog {
+	case "", "none":
+	case "console":
+		config.Dns = &log.LogSpecification{Type: log.LogType_Console}
+	default:
+		config.Dns = &log.LogSpecification{Type: log.LogType_File, Path: v.DNSLog}
+	}
 
 	level := strings.ToLower(v.LogLevel)
 	switch level {
@@ -55,6 +55,10 @@ func (r *BalancingRule) Build() (*router.BalancingRule, error) {
 		strategy = "leastping"
 	case strategyFallback:
 		strategy = "fallback"
+	case strategyConsistentHash:
+		strategy = strategyConsistentHash
+	case strategyWeighted:
+		strategy = strategyWeighted
 	default:
 		return nil, newError("unknown balancing strategy: " + r.Strategy.Type)
 	}
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"strings"
+
 	"github.com/golang/protobuf/proto"
 
 	"github.com/v2fly/v2ray-core/v5/app/observatory/burst"
@@ -10,17 +12,21 @@ import (
 )
 
 const (
-	strategyRandom    string = "random"
-	strategyLeastLoad string = "leastload"
-	strategyLeastPing string = "leastping"
-	strategyFallback  string = "fallback"
+	strategyRandom         string = "random"
+	strategyLeastLoad      string = "leastload"
+	strategyLeastPing      string = "leastping"
+	strategyFallback       string = "fallback"
+	strategyConsistentHash string = "consistenthash"
+	strategyWeighted       string = "weighted"
 )
 
 var strategyConfigLoader = loader.NewJSONConfigLoader(loader.ConfigCreatorCache{
-	strategyRandom:    func() interface{} { return new(strategyRandomConfig) },
-	strategyLeastLoad: func() interface{} { return new(strategyLeastLoadConfig) },
-	strategyLeastPing: func() interface{} { return new(strategyLeastPingConfig) },
-	strategyFallback:  func() interface{} { return new(strategyFallbackConfig) },
+	strategyRandom:         func() interface{} { return new(strategyRandomConfig) },
+	strategyLeastLoad:      func() interface{} { return new(strategyLeastLoadConfig) },
+	strategyLeastPing:      func() interface{} { return new(strategyLeastPingConfig) },
+	strategyFallback:       func() interface{} { return new(strategyFallbackConfig) },
+	strategyConsistentHash: func() interface{} { return new(strategyConsistentHashConfig) },
+	strategyWeighted:       func() interface{} { return new(strategyWeightedConfig) },
 }, "type", "settings")
 
 type strategyEmptyConfig struct{}
@@ -121,3 +127,42 @@ type strategyFallbackConfig struct {
 func (s strategyFallbackConfig) Build() (proto.Message, error) {
 	return &router.StrategyFallbackConfig{ObserverTag: s.ObserverTag}, nil
 }
+
+type strategyConsistentHashConfig struct {
+	Key         string `json:"key,omitempty"`
+	ObserverTag string `json:"observerTag,omitempty"`
+}
+
+func (s strategyConsistentHashConfig) Build() (proto.Message, error) {
+	config := &router.StrategyConsistentHashConfig{ObserverTag: s.ObserverTag}
+	switch strings.ToLower(s.Key) {
+	case "", "sourceip", "source":
+		config.Key = router.StrategyConsistentHashConfig_SourceIp
+	case "user", "email":
+		config.Key = router.StrategyConsistentHashConfig_UserEmail
+	case "domain":
+		config.Key = router.StrategyConsistentHashConfig_TargetDomain
+	default:
+		return nil, newError("unknown consistent hash key: ", s.Key)
+	}
+	return config, nil
+}
+
+type strategyWeightedConfig struct {
+	Weights     []*router.StrategyWeight `json:"weights,omitempty"`
+	AliveOnly   bool                     `json:"aliveOnly,omitempty"`
+	ObserverTag string                   `json:"observerTag,omitempty"`
+}
+
+func (s strategyWeightedConfig) Build() (proto.Message, error) {
+	for _, w := range s.Weights {
+		if w.Value < 0 {
+			return nil, newError("negative weight for ", w.Match)
+		}
+	}
+	return &router.StrategyWeightedConfig{
+		Weights:     s.Weights,
+		AliveOnly:   s.AliveOnly,
+		ObserverTag: s.ObserverTag,
+	}, nil
+}
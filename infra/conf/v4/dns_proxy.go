@@ -36,3 +36,23 @@ func (c *DNSOutboundConfig) Build() (proto.Message, error) {
 	}
 	return config, nil
 }
+
+type DNSServerConfig struct {
+	NetworkList *cfgcommon.NetworkList `json:"network"`
+	UserLevel   uint32                 `json:"userLevel"`
+	DoHPath     string                 `json:"dohPath"`
+	FakeDNS     bool                   `json:"fakeDns"`
+}
+
+func (c *DNSServerConfig) Build() (proto.Message, error) {
+	config := &dns.ServerConfig{
+		UserLevel: c.UserLevel,
+		DohPath:   c.DoHPath,
+		FakeDns:   c.FakeDNS,
+	}
+	// Serve both TCP and UDP by default.
+	if c.NetworkList != nil {
+		config.Networks = c.NetworkList.Build()
+	}
+	return config, nil
+}
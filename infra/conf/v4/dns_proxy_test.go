@@ -33,3 +33,27 @@ func TestDnsProxyConfig(t *testing.T) {
 		},
 	})
 }
+
+func TestDnsServerConfig(t *testing.T) {
+	creator := func() cfgcommon.Buildable {
+		return new(v4.DNSServerConfig)
+	}
+
+	testassist.RunMultiTestCase(t, []testassist.TestCase{
+		{
+			Input: `{
+				"network": "udp",
+				"userLevel": 1,
+				"dohPath": "/query",
+				"fakeDns": true
+			}`,
+			Parser: testassist.LoadJSON(creator),
+			Output: &dns.ServerConfig{
+				Networks:  []net.Network{net.Network_UDP},
+				UserLevel: 1,
+				DohPath:   "/query",
+				FakeDns:   true,
+			},
+		},
+	})
+}
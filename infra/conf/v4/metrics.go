@@ -0,0 +1,31 @@
+package v4
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"github.com/v2fly/v2ray-core/v5/app/metrics"
+)
+
+type MetricsConfig struct {
+	ListenAddr string `json:"listenAddr"`
+	ListenPort int32  `json:"listenPort"`
+	Path       string `json:"path"`
+}
+
+func (c *MetricsConfig) Build() (proto.Message, error) {
+	if c.ListenPort <= 0 || c.ListenPort > 65535 {
+		return nil, newError("invalid metrics listen port: ", c.ListenPort)
+	}
+	if c.Path != "" && c.Path[0] != '/' {
+		return nil, newError("metrics path must start with /: ", c.Path)
+	}
+	listenAddr := c.ListenAddr
+	if listenAddr == "" {
+		listenAddr = "127.0.0.1"
+	}
+	return &metrics.Config{
+		ListenAddr: listenAddr,
+		ListenPort: c.ListenPort,
+		Path:       c.Path,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package v4_test
+
+import (
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/app/metrics"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/testassist"
+	v4 "github.com/v2fly/v2ray-core/v5/infra/conf/v4"
+)
+
+func TestMetricsConfig(t *testing.T) {
+	creator := func() cfgcommon.Buildable {
+		return new(v4.MetricsConfig)
+	}
+
+	testassist.RunMultiTestCase(t, []testassist.TestCase{
+		{
+			Input: `{
+				"listenPort": 9090
+			}`,
+			Parser: testassist.LoadJSON(creator),
+			Output: &metrics.Config{
+				ListenAddr: "127.0.0.1",
+				ListenPort: 9090,
+			},
+		},
+		{
+			Input: `{
+				"listenAddr": "::1",
+				"listenPort": 9090,
+				"path": "/stats"
+			}`,
+			Parser: testassist.LoadJSON(creator),
+			Output: &metrics.Config{
+				ListenAddr: "::1",
+				ListenPort: 9090,
+				Path:       "/stats",
+			},
+		},
+	})
+}
@@ -5,13 +5,16 @@ import (
 )
 
 type Policy struct {
-	Handshake         *uint32 `json:"handshake"`
-	ConnectionIdle    *uint32 `json:"connIdle"`
-	UplinkOnly        *uint32 `json:"uplinkOnly"`
-	DownlinkOnly      *uint32 `json:"downlinkOnly"`
-	StatsUserUplink   bool    `json:"statsUserUplink"`
-	StatsUserDownlink bool    `json:"statsUserDownlink"`
-	BufferSize        *int32  `json:"bufferSize"`
+	Handshake               *uint32 `json:"handshake"`
+	ConnectionIdle          *uint32 `json:"connIdle"`
+	UplinkOnly              *uint32 `json:"uplinkOnly"`
+	DownlinkOnly            *uint32 `json:"downlinkOnly"`
+	StatsUserUplink         bool    `json:"statsUserUplink"`
+	StatsUserDownlink       bool    `json:"statsUserDownlink"`
+	StatsUserOnline         bool    `json:"statsUserOnline"`
+	StatsUserSourceIPs      bool    `json:"statsUserSourceIPs"`
+	StatsUserSourceIPWindow *uint32 `json:"statsUserSourceIPWindow"`
+	BufferSize              *int32  `json:"bufferSize"`
 }
 
 func (t *Policy) Build() (*policy.Policy, error) {
@@ -32,10 +35,15 @@ func (t *Policy) Build() (*policy.Policy, error) {
 	p := &policy.Policy{
 		Timeout: config,
 		Stats: &policy.Policy_Stats{
-			UserUplink:   t.StatsUserUplink,
-			UserDownlink: t.StatsUserDownlink,
+			UserUplink:    t.StatsUserUplink,
+			UserDownlink:  t.StatsUserDownlink,
+			UserOnline:    t.StatsUserOnline,
+			UserSourceIps: t.StatsUserSourceIPs,
 		},
 	}
+	if t.StatsUserSourceIPWindow != nil {
+		p.Stats.SourceIpWindow = &policy.Second{Value: *t.StatsUserSourceIPWindow}
+	}
 
 	if t.BufferSize != nil {
 		bs := int32(-1)
@@ -51,20 +59,24 @@ func (t *Policy) Build() (*policy.Policy, error) {
 }
 
 type SystemPolicy struct {
-	StatsInboundUplink    bool `json:"statsInboundUplink"`
-	StatsInboundDownlink  bool `json:"statsInboundDownlink"`
-	StatsOutboundUplink   bool `json:"statsOutboundUplink"`
-	StatsOutboundDownlink bool `json:"statsOutboundDownlink"`
-	OverrideAccessLogDest bool `json:"overrideAccessLogDest"`
+	StatsInboundUplink        bool `json:"statsInboundUplink"`
+	StatsInboundDownlink      bool `json:"statsInboundDownlink"`
+	StatsOutboundUplink       bool `json:"statsOutboundUplink"`
+	StatsOutboundDownlink     bool `json:"statsOutboundDownlink"`
+	StatsInboundConnections   bool `json:"statsInboundConnections"`
+	StatsOutboundDestinations bool `json:"statsOutboundDestinations"`
+	OverrideAccessLogDest     bool `json:"overrideAccessLogDest"`
 }
 
 func (p *SystemPolicy) Build() (*policy.SystemPolicy, error) {
 	return &policy.SystemPolicy{
 		Stats: &policy.SystemPolicy_Stats{
-			InboundUplink:    p.StatsInboundUplink,
-			InboundDownlink:  p.StatsInboundDownlink,
-			OutboundUplink:   p.StatsOutboundUplink,
-			OutboundDownlink: p.StatsOutboundDownlink,
+			InboundUplink:        p.StatsInboundUplink,
+			InboundDownlink:      p.StatsInboundDownlink,
+			OutboundUplink:       p.StatsOutboundUplink,
+			OutboundDownlink:     p.StatsOutboundDownlink,
+			InboundConnections:   p.StatsInboundConnections,
+			OutboundDestinations: p.StatsOutboundDestinations,
 		},
 		OverrideAccessLogDest: p.OverrideAccessLogDest,
 	}, nil
@@ -29,6 +29,7 @@ import (
 var (
 	inboundConfigLoader = loader.NewJSONConfigLoader(loader.ConfigCreatorCache{
 		"dokodemo-door":          func() interface{} { return new(DokodemoConfig) },
+		"dns":                    func() interface{} { return new(DNSServerConfig) },
 		"http":                   func() interface{} { return new(HTTPServerConfig) },
 		"shadowsocks":            func() interface{} { return new(ShadowsocksServerConfig) },
 		"socks":                  func() interface{} { return new(SocksServerConfig) },
@@ -314,11 +315,41 @@ func (c *OutboundDetourConfig) Build() (*core.OutboundHandlerConfig, error) {
 	}, nil
 }
 
-type StatsConfig struct{}
+type StatsHistoryConfig struct {
+	SecondSamples uint32   `json:"secondSamples"`
+	MinuteSamples uint32   `json:"minuteSamples"`
+	Patterns      []string `json:"patterns"`
+	Regexp        bool     `json:"regexp"`
+}
+
+// Build implements Buildable.
+func (c *StatsHistoryConfig) Build() (*stats.HistoryConfig, error) {
+	return &stats.HistoryConfig{
+		SecondSamples: c.SecondSamples,
+		MinuteSamples: c.MinuteSamples,
+		Patterns:      c.Patterns,
+		Regexp:        c.Regexp,
+	}, nil
+}
+
+type StatsConfig struct {
+	History         *StatsHistoryConfig `json:"history"`
+	TopDestinations uint32              `json:"topDestinations"`
+}
 
 // Build implements Buildable.
 func (c *StatsConfig) Build() (*stats.Config, error) {
-	return &stats.Config{}, nil
+	config := &stats.Config{
+		TopDestinations: c.TopDestinations,
+	}
+	if c.History != nil {
+		history, err := c.History.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.History = history
+	}
+	return config, nil
 }
 
 type Config struct {
@@ -359,6 +390,7 @@ type Config struct {
 	BurstObservatory  *BurstObservatoryConfig  `json:"burstObservatory"`
 	MultiObservatory  *MultiObservatoryConfig  `json:"multiObservatory"`
 	RestfulAPI        *RestfulAPIConfig        `json:"restfulAPI"`
+	Metrics           *MetricsConfig           `json:"metrics"`
 	TUN               *TUNConfig               `json:"tun"`
 	FileSystemStorage *FileSystemStorageConfig `json:"fileSystemStorage"`
 
@@ -528,6 +560,14 @@ func (c *Config) Build() (*core.Config, error) {
 		config.App = append(config.App, serial.ToTypedMessage(r))
 	}
 
+	if c.Metrics != nil {
+		m, err := c.Metrics.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.App = append(config.App, serial.ToTypedMessage(m))
+	}
+
 	if c.TUN != nil {
 		t, err := c.TUN.Build() // nolint:staticcheck
 		if err != nil {         // nolint:staticcheck
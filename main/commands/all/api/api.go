@@ -13,7 +13,11 @@ var CmdAPI = &base.Command{
 	Commands: []*base.Command{
 		cmdLog,
 		cmdStats,
+		cmdStatsHistory,
+		cmdStatsTop,
+		cmdSessions,
 		cmdBalancerInfo,
 		cmdBalancerOverride,
+		cmdRouteExplain,
 	},
 }
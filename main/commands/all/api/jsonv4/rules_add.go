@@ -0,0 +1,94 @@
+package jsonv4
+
+import (
+	"fmt"
+
+	routerService "github.com/v2fly/v2ray-core/v5/app/router/command"
+	"github.com/v2fly/v2ray-core/v5/main/commands/all/api"
+	"github.com/v2fly/v2ray-core/v5/main/commands/base"
+	"github.com/v2fly/v2ray-core/v5/main/commands/helpers"
+)
+
+var cmdAddRules = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api adrules [--server=127.0.0.1:8080] [c1.json] [dir1]...",
+	Short:       "add routing rules",
+	Long: `
+Add routing rules and balancers to V2Ray. Existing connections keep 
+their routes.
+
+> Make sure you have "RoutingService" set in "config.api.services" 
+of server config.
+
+Arguments:
+
+	-format <format>
+		The input format.
+		Available values: "auto", "json", "toml", "yaml"
+		Default: "auto"
+
+	-r
+		Load folders recursively.
+
+	-prepend
+		Insert the rules before existing rules, instead of after them.
+
+	-replace
+		Replace all existing rules and balancers with the input.
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout seconds to call API. Default 3
+
+Example:
+
+    {{.Exec}} {{.LongName}} dir
+    {{.Exec}} {{.LongName}} -prepend c1.json c2.yaml
+    {{.Exec}} {{.LongName}} -replace routing.json
+`,
+	Run: executeAddRules,
+}
+
+func executeAddRules(cmd *base.Command, args []string) {
+	api.SetSharedFlags(cmd)
+	api.SetSharedConfigFlags(cmd)
+	prepend := cmd.Flag.Bool("prepend", false, "")
+	replace := cmd.Flag.Bool("replace", false, "")
+	cmd.Flag.Parse(args)
+	c, err := helpers.LoadConfig(cmd.Flag.Args(), api.APIConfigFormat, api.APIConfigRecursively)
+	if err != nil {
+		base.Fatalf("failed to load: %s", err)
+	}
+	if c.RouterConfig == nil {
+		base.Fatalf("no valid routing config found")
+	}
+	config, err := c.RouterConfig.Build()
+	if err != nil {
+		base.Fatalf("failed to build conf: %s", err)
+	}
+
+	conn, ctx, close := api.DialAPIServer()
+	defer close()
+
+	client := routerService.NewRoutingServiceClient(conn)
+	if *replace {
+		fmt.Println("replacing:", len(config.Rule), "rules,", len(config.BalancingRule), "balancers")
+		_, err = client.ReplaceRules(ctx, &routerService.ReplaceRulesRequest{
+			Config: config,
+		})
+		if err != nil {
+			base.Fatalf("failed to replace rules: %s", err)
+		}
+		return
+	}
+	fmt.Println("adding:", len(config.Rule), "rules,", len(config.BalancingRule), "balancers")
+	_, err = client.AddRule(ctx, &routerService.AddRuleRequest{
+		Config:  config,
+		Prepend: *prepend,
+	})
+	if err != nil {
+		base.Fatalf("failed to add rules: %s", err)
+	}
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	routerService "github.com/v2fly/v2ray-core/v5/app/router/command"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/main/commands/base"
+)
+
+var cmdRouteExplain = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api route-explain [--server=127.0.0.1:8080] [-port 443] <domain or IP>",
+	Short:       "explain routing decision",
+	Long: `
+Test which outbound a connection would be routed to, and show how
+each routing rule is evaluated.
+
+> Make sure you have "RoutingService" set in "config.api.services"
+of server config.
+
+Arguments:
+
+	-port <port>
+		The target port.
+
+	-network <network>
+		The network of the connection, "tcp" or "udp". Default "tcp"
+
+	-source <ip>
+		The source IP of the connection.
+
+	-sport <port>
+		The source port of the connection.
+
+	-inbound <tag>
+		The inbound tag of the connection.
+
+	-protocol <protocol>
+		The sniffed protocol of the connection, e.g. "tls".
+
+	-user <email>
+		The user email of the connection.
+
+	-attrs <key=value,...>
+		The attributes of the connection.
+
+	-json
+		Use json output.
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout seconds to call API. Default 3
+
+Example:
+
+    {{.Exec}} {{.LongName}} -port 443 www.v2fly.org
+    {{.Exec}} {{.LongName}} -network udp -port 53 -inbound dns-in 8.8.8.8
+`,
+	Run: executeRouteExplain,
+}
+
+func executeRouteExplain(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	var (
+		port     uint
+		network  string
+		source   string
+		sport    uint
+		inbound  string
+		protocol string
+		user     string
+		attrs    string
+	)
+	cmd.Flag.UintVar(&port, "port", 0, "")
+	cmd.Flag.StringVar(&network, "network", "tcp", "")
+	cmd.Flag.StringVar(&source, "source", "", "")
+	cmd.Flag.UintVar(&sport, "sport", 0, "")
+	cmd.Flag.StringVar(&inbound, "inbound", "", "")
+	cmd.Flag.StringVar(&protocol, "protocol", "", "")
+	cmd.Flag.StringVar(&user, "user", "", "")
+	cmd.Flag.StringVar(&attrs, "attrs", "", "")
+	cmd.Flag.Parse(args)
+
+	if cmd.Flag.NArg() == 0 {
+		base.Fatalf("target domain or IP not specified")
+	}
+
+	rc := &routerService.RoutingContext{
+		InboundTag: inbound,
+		TargetPort: uint32(port),
+		SourcePort: uint32(sport),
+		Protocol:   protocol,
+		User:       user,
+	}
+	switch strings.ToLower(network) {
+	case "tcp":
+		rc.Network = net.Network_TCP
+	case "udp":
+		rc.Network = net.Network_UDP
+	default:
+		base.Fatalf("unknown network: %s", network)
+	}
+	target := net.ParseAddress(cmd.Flag.Arg(0))
+	if target.Family().IsDomain() {
+		rc.TargetDomain = target.Domain()
+	} else {
+		rc.TargetIPs = [][]byte{target.IP()}
+	}
+	if source != "" {
+		ip := net.ParseAddress(source)
+		if ip.Family().IsDomain() {
+			base.Fatalf("invalid source IP: %s", source)
+		}
+		rc.SourceIPs = [][]byte{ip.IP()}
+	}
+	if attrs != "" {
+		rc.Attributes = make(map[string]string)
+		for _, attr := range strings.Split(attrs, ",") {
+			key, value, _ := strings.Cut(attr, "=")
+			rc.Attributes[key] = value
+		}
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := routerService.NewRoutingServiceClient(conn)
+	resp, err := client.ExplainRoute(ctx, &routerService.ExplainRouteRequest{RoutingContext: rc})
+	if err != nil {
+		base.Fatalf("failed to explain route: %s", err)
+	}
+
+	if apiJSON {
+		showJSONResponse(resp)
+		return
+	}
+
+	showRouteExplain(resp)
+}
+
+func showRouteExplain(resp *routerService.ExplainRouteResponse) {
+	sb := new(strings.Builder)
+	for _, trace := range resp.Trace {
+		target := trace.OutboundTag
+		if trace.BalancingTag != "" {
+			target = "balancer:" + trace.BalancingTag
+		}
+		name := fmt.Sprintf("#%d", trace.Index)
+		if trace.RuleTag != "" {
+			name += " " + trace.RuleTag
+		}
+		if trace.Resolved {
+			name += " (resolved)"
+		}
+		result := "matched"
+		if !trace.Matched {
+			result = trace.Reason
+		}
+		sb.WriteString(fmt.Sprintf("%-24s -> %-16s %s\n", name, target, result))
+	}
+	var candidates []string
+	if n := len(resp.Trace); n > 0 && resp.Trace[n-1].Matched {
+		candidates = resp.Trace[n-1].Candidate
+	}
+	switch {
+	case resp.Result != nil && resp.Result.OutboundTag != "":
+		sb.WriteString("Outbound: " + resp.Result.OutboundTag + "\n")
+	case resp.Result != nil:
+		sb.WriteString("Outbound: picked by the balancer from " + strings.Join(candidates, ", ") + "\n")
+	default:
+		sb.WriteString("No rule matched, the default outbound is used.\n")
+	}
+	os.Stdout.WriteString(sb.String())
+}
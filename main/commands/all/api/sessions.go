@@ -0,0 +1,118 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	sessionService "github.com/v2fly/v2ray-core/v5/app/dispatcher/command"
+	"github.com/v2fly/v2ray-core/v5/common/units"
+	"github.com/v2fly/v2ray-core/v5/main/commands/base"
+)
+
+var cmdSessions = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api sessions [--server=127.0.0.1:8080] [-user email] [-inbound tag] [-close id]",
+	Short:       "list or close active sessions",
+	Long: `
+List the sessions V2Ray is dispatching, or close one of them.
+
+> Make sure you have "SessionService" set in "config.api.services" 
+of server config.
+
+Arguments:
+
+	-user <email>
+		List only the sessions of the user.
+
+	-inbound <tag>
+		List only the sessions of the inbound.
+
+	-close <id>
+		Close the session of the id.
+
+	-json
+		Use json output.
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout seconds to call API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}}
+	{{.Exec}} {{.LongName}} -user love@v2fly.org
+	{{.Exec}} {{.LongName}} -close 42
+`,
+	Run: executeSessions,
+}
+
+func executeSessions(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	var (
+		user    string
+		inbound string
+		closeID uint
+	)
+	cmd.Flag.StringVar(&user, "user", "", "")
+	cmd.Flag.StringVar(&inbound, "inbound", "", "")
+	cmd.Flag.UintVar(&closeID, "close", 0, "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := sessionService.NewSessionServiceClient(conn)
+	if closeID > 0 {
+		if _, err := client.CloseSession(ctx, &sessionService.CloseSessionRequest{Id: uint32(closeID)}); err != nil {
+			base.Fatalf("failed to close session: %s", err)
+		}
+		return
+	}
+
+	resp, err := client.ListSessions(ctx, &sessionService.ListSessionsRequest{
+		User:       user,
+		InboundTag: inbound,
+	})
+	if err != nil {
+		base.Fatalf("failed to list sessions: %s", err)
+	}
+	if apiJSON {
+		showJSONResponse(resp)
+		return
+	}
+	showSessions(resp.Sessions)
+}
+
+func showSessions(sessions []*sessionService.Session) {
+	if len(sessions) == 0 {
+		return
+	}
+	formats := []string{"%-8s", "%-14s", "%-20s", "%-24s", "%-14s", "%-10s", "%-10s", "%-10s", "%s"}
+	sb := new(strings.Builder)
+	writeRow(sb, 0, 0,
+		[]string{"ID", "Inbound", "User", "Source", "Outbound", "Duration", "Up", "Down", "Target"},
+		formats,
+	)
+	now := time.Now()
+	for i, s := range sessions {
+		writeRow(sb, 0, i+1,
+			[]string{
+				fmt.Sprintf("%d", s.Id),
+				s.InboundTag,
+				s.User,
+				s.Source,
+				s.OutboundTag,
+				now.Sub(time.Unix(s.StartTime, 0)).Truncate(time.Second).String(),
+				units.ByteSize(s.Uplink).String(),
+				units.ByteSize(s.Downlink).String(),
+				s.Target,
+			},
+			formats,
+		)
+	}
+	os.Stdout.WriteString(sb.String())
+}
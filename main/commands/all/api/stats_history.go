@@ -0,0 +1,119 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	statsService "github.com/v2fly/v2ray-core/v5/app/stats/command"
+	"github.com/v2fly/v2ray-core/v5/common/units"
+	"github.com/v2fly/v2ray-core/v5/main/commands/base"
+)
+
+var cmdStatsHistory = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api stats-history [--server=127.0.0.1:8080] [-minute] [-limit n] [pattern]...",
+	Short:       "query rates of statistics",
+	Long: `
+Query the recorded history of statistics from V2Ray, and show the 
+rates of the counters.
+
+> Make sure you have "StatsService" set in "config.api.services", 
+and "history" enabled in "config.stats" of server config.
+
+Arguments:
+
+	-regexp
+		The patterns are using regexp.
+
+	-minute
+		Use the per-minute samples instead of the per-second ones.
+
+	-limit <n>
+		Use only the latest n samples of each counter.
+
+	-json
+		Use json output, with all the samples.
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout seconds to call API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}} traffic
+	{{.Exec}} {{.LongName}} -minute -limit 5 -regexp 'user>>>.+>>>downlink'
+`,
+	Run: executeStatsHistory,
+}
+
+func executeStatsHistory(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	var (
+		regexp bool
+		minute bool
+		limit  uint
+	)
+	cmd.Flag.BoolVar(&regexp, "regexp", false, "")
+	cmd.Flag.BoolVar(&minute, "minute", false, "")
+	cmd.Flag.UintVar(&limit, "limit", 0, "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := statsService.NewStatsServiceClient(conn)
+	r := &statsService.QueryStatsHistoryRequest{
+		Patterns: cmd.Flag.Args(),
+		Regexp:   regexp,
+		Limit:    uint32(limit),
+	}
+	if minute {
+		r.Interval = statsService.QueryStatsHistoryRequest_Minute
+	}
+	resp, err := client.QueryStatsHistory(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to query stats history: %s", err)
+	}
+	if apiJSON {
+		showJSONResponse(resp)
+		return
+	}
+	showStatsHistory(resp.History)
+}
+
+func showStatsHistory(history []*statsService.StatsHistory) {
+	if len(history) == 0 {
+		return
+	}
+	formats := []string{"%-12s", "%-12s", "%-12s", "%s"}
+	sb := new(strings.Builder)
+	writeRow(sb, 0, 0,
+		[]string{"Value", "Rate", "Avg Rate", "Name"},
+		formats,
+	)
+	for i, h := range history {
+		samples := h.Sample
+		last := samples[len(samples)-1]
+		var avg float64
+		if len(samples) > 1 {
+			for _, s := range samples[1:] {
+				avg += s.Rate
+			}
+			avg /= float64(len(samples) - 1)
+		}
+		writeRow(
+			sb, 0, i+1,
+			[]string{
+				units.ByteSize(last.Value).String(),
+				fmt.Sprintf("%s/s", units.ByteSize(int64(last.Rate))),
+				fmt.Sprintf("%s/s", units.ByteSize(int64(avg))),
+				h.Name,
+			},
+			formats,
+		)
+	}
+	os.Stdout.WriteString(sb.String())
+}
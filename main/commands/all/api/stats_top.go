@@ -0,0 +1,105 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	statsService "github.com/v2fly/v2ray-core/v5/app/stats/command"
+	"github.com/v2fly/v2ray-core/v5/common/units"
+	"github.com/v2fly/v2ray-core/v5/main/commands/base"
+)
+
+var cmdStatsTop = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api stats-top [--server=127.0.0.1:8080] [-outbound tag] [-user email] [-limit n]",
+	Short:       "query destinations of most traffic",
+	Long: `
+Query the destinations of most traffic per outbound and user from V2Ray.
+
+> Make sure you have "StatsService" set in "config.api.services", 
+and "statsOutboundDestinations" enabled in "config.policy.system" 
+of server config.
+
+Arguments:
+
+	-outbound <tag>
+		Only the destinations through the outbound.
+
+	-user <email>
+		Only the destinations of the user.
+
+	-limit <n>
+		Show at most n destinations. Default 20
+
+	-json
+		Use json output.
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout seconds to call API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}}
+	{{.Exec}} {{.LongName}} -outbound proxy -limit 5
+`,
+	Run: executeStatsTop,
+}
+
+func executeStatsTop(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	var (
+		outbound string
+		user     string
+		limit    uint
+	)
+	cmd.Flag.StringVar(&outbound, "outbound", "", "")
+	cmd.Flag.StringVar(&user, "user", "", "")
+	cmd.Flag.UintVar(&limit, "limit", 20, "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := statsService.NewStatsServiceClient(conn)
+	resp, err := client.GetTopDestinations(ctx, &statsService.GetTopDestinationsRequest{
+		OutboundTag: outbound,
+		User:        user,
+		Limit:       uint32(limit),
+	})
+	if err != nil {
+		base.Fatalf("failed to get top destinations: %s", err)
+	}
+	if apiJSON {
+		showJSONResponse(resp)
+		return
+	}
+	showTopDestinations(resp.Destination)
+}
+
+func showTopDestinations(destinations []*statsService.DestinationStat) {
+	if len(destinations) == 0 {
+		return
+	}
+	formats := []string{"%-12s", "%-12s", "%-20s", "%s"}
+	sb := new(strings.Builder)
+	writeRow(sb, 0, 0,
+		[]string{"Bytes", "Outbound", "User", "Destination"},
+		formats,
+	)
+	for i, d := range destinations {
+		bytes := units.ByteSize(d.Bytes).String()
+		if d.Error > 0 {
+			bytes = fmt.Sprintf("~%s", bytes)
+		}
+		writeRow(
+			sb, 0, i+1,
+			[]string{bytes, d.OutboundTag, d.User, d.Domain},
+			formats,
+		)
+	}
+	os.Stdout.WriteString(sb.String())
+}
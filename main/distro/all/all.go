@@ -10,6 +10,8 @@ import (
 
 	// Default commander and all its services. This is an optional feature.
 	_ "github.com/v2fly/v2ray-core/v5/app/commander"
+	_ "github.com/v2fly/v2ray-core/v5/app/dispatcher/command"
+	_ "github.com/v2fly/v2ray-core/v5/app/dns/command"
 	_ "github.com/v2fly/v2ray-core/v5/app/log/command"
 	_ "github.com/v2fly/v2ray-core/v5/app/proxyman/command"
 	_ "github.com/v2fly/v2ray-core/v5/app/router/command"
@@ -24,6 +26,7 @@ import (
 	_ "github.com/v2fly/v2ray-core/v5/app/dns"
 	_ "github.com/v2fly/v2ray-core/v5/app/dns/fakedns"
 	_ "github.com/v2fly/v2ray-core/v5/app/log"
+	_ "github.com/v2fly/v2ray-core/v5/app/metrics"
 	_ "github.com/v2fly/v2ray-core/v5/app/policy"
 	_ "github.com/v2fly/v2ray-core/v5/app/reverse"
 	_ "github.com/v2fly/v2ray-core/v5/app/router"
@@ -163,11 +163,154 @@ func (x *SimplifiedConfig) GetLookupAsExchange() bool {
 	return false
 }
 
+// ServerConfig is the config of DNS server inbound, which answers queries with
+// the DNS feature. Queries over TCP are served as DNS over TLS, and as DNS over
+// HTTPS if the connection starts with an HTTP request, when TLS is enabled in
+// stream settings. Queries over QUIC transport are served as DNS over QUIC.
+type ServerConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Networks to serve on. Both TCP and UDP are served if empty.
+	Networks  []net.Network `protobuf:"varint,1,rep,packed,name=networks,proto3,enum=v2ray.core.common.net.Network" json:"networks,omitempty"`
+	UserLevel uint32        `protobuf:"varint,2,opt,name=user_level,json=userLevel,proto3" json:"user_level,omitempty"`
+	// Path of DNS over HTTPS requests. "/dns-query" is used if empty.
+	DohPath string `protobuf:"bytes,3,opt,name=doh_path,json=dohPath,proto3" json:"doh_path,omitempty"`
+	// Answer queries with FakeDNS if it is enabled.
+	FakeDns       bool `protobuf:"varint,4,opt,name=fake_dns,json=fakeDns,proto3" json:"fake_dns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerConfig) Reset() {
+	*x = ServerConfig{}
+	mi := &file_proxy_dns_config_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerConfig) ProtoMessage() {}
+
+func (x *ServerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proxy_dns_config_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerConfig.ProtoReflect.Descriptor instead.
+func (*ServerConfig) Descriptor() ([]byte, []int) {
+	return file_proxy_dns_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ServerConfig) GetNetworks() []net.Network {
+	if x != nil {
+		return x.Networks
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetUserLevel() uint32 {
+	if x != nil {
+		return x.UserLevel
+	}
+	return 0
+}
+
+func (x *ServerConfig) GetDohPath() string {
+	if x != nil {
+		return x.DohPath
+	}
+	return ""
+}
+
+func (x *ServerConfig) GetFakeDns() bool {
+	if x != nil {
+		return x.FakeDns
+	}
+	return false
+}
+
+type SimplifiedServerConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Networks      *net.NetworkList       `protobuf:"bytes,1,opt,name=networks,proto3" json:"networks,omitempty"`
+	UserLevel     uint32                 `protobuf:"varint,2,opt,name=user_level,json=userLevel,proto3" json:"user_level,omitempty"`
+	DohPath       string                 `protobuf:"bytes,3,opt,name=doh_path,json=dohPath,proto3" json:"doh_path,omitempty"`
+	FakeDns       bool                   `protobuf:"varint,4,opt,name=fake_dns,json=fakeDns,proto3" json:"fake_dns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimplifiedServerConfig) Reset() {
+	*x = SimplifiedServerConfig{}
+	mi := &file_proxy_dns_config_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimplifiedServerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimplifiedServerConfig) ProtoMessage() {}
+
+func (x *SimplifiedServerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proxy_dns_config_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimplifiedServerConfig.ProtoReflect.Descriptor instead.
+func (*SimplifiedServerConfig) Descriptor() ([]byte, []int) {
+	return file_proxy_dns_config_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SimplifiedServerConfig) GetNetworks() *net.NetworkList {
+	if x != nil {
+		return x.Networks
+	}
+	return nil
+}
+
+func (x *SimplifiedServerConfig) GetUserLevel() uint32 {
+	if x != nil {
+		return x.UserLevel
+	}
+	return 0
+}
+
+func (x *SimplifiedServerConfig) GetDohPath() string {
+	if x != nil {
+		return x.DohPath
+	}
+	return ""
+}
+
+func (x *SimplifiedServerConfig) GetFakeDns() bool {
+	if x != nil {
+		return x.FakeDns
+	}
+	return false
+}
+
 var File_proxy_dns_config_proto protoreflect.FileDescriptor
 
 const file_proxy_dns_config_proto_rawDesc = "" +
 	"\n" +
-	"\x16proxy/dns/config.proto\x12\x14v2ray.core.proxy.dns\x1a\x1ccommon/net/destination.proto\x1a common/protoext/extensions.proto\"\x87\x02\n" +
+	"\x16proxy/dns/config.proto\x12\x14v2ray.core.proxy.dns\x1a\x1ccommon/net/destination.proto\x1a\x18common/net/network.proto\x1a common/protoext/extensions.proto\"\x87\x02\n" +
 	"\x06Config\x127\n" +
 	"\x06server\x18\x01 \x01(\v2\x1f.v2ray.core.common.net.EndpointR\x06server\x12\x1d\n" +
 	"\n" +
@@ -181,7 +324,20 @@ const file_proxy_dns_config_proto_rawDesc = "" +
 	"\x15override_response_ttl\x18\x04 \x01(\bR\x13overrideResponseTtl\x12!\n" +
 	"\fresponse_ttl\x18\x03 \x01(\rR\vresponseTtl\x12,\n" +
 	"\x12lookup_as_exchange\x18c \x01(\bR\x10lookupAsExchange:\x13\x82\xb5\x18\x0f\n" +
-	"\boutbound\x12\x03dnsB]\n" +
+	"\boutbound\x12\x03dns\"\x9f\x01\n" +
+	"\fServerConfig\x12:\n" +
+	"\bnetworks\x18\x01 \x03(\x0e2\x1e.v2ray.core.common.net.NetworkR\bnetworks\x12\x1d\n" +
+	"\n" +
+	"user_level\x18\x02 \x01(\rR\tuserLevel\x12\x19\n" +
+	"\bdoh_path\x18\x03 \x01(\tR\adohPath\x12\x19\n" +
+	"\bfake_dns\x18\x04 \x01(\bR\afakeDns\"\xc1\x01\n" +
+	"\x16SimplifiedServerConfig\x12>\n" +
+	"\bnetworks\x18\x01 \x01(\v2\".v2ray.core.common.net.NetworkListR\bnetworks\x12\x1d\n" +
+	"\n" +
+	"user_level\x18\x02 \x01(\rR\tuserLevel\x12\x19\n" +
+	"\bdoh_path\x18\x03 \x01(\tR\adohPath\x12\x19\n" +
+	"\bfake_dns\x18\x04 \x01(\bR\afakeDns:\x12\x82\xb5\x18\x0e\n" +
+	"\ainbound\x12\x03dnsB]\n" +
 	"\x18com.v2ray.core.proxy.dnsP\x01Z(github.com/v2fly/v2ray-core/v5/proxy/dns\xaa\x02\x14V2Ray.Core.Proxy.Dnsb\x06proto3"
 
 var (
@@ -196,19 +352,25 @@ func file_proxy_dns_config_proto_rawDescGZIP() []byte {
 	return file_proxy_dns_config_proto_rawDescData
 }
 
-var file_proxy_dns_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proxy_dns_config_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_proxy_dns_config_proto_goTypes = []any{
-	(*Config)(nil),           // 0: v2ray.core.proxy.dns.Config
-	(*SimplifiedConfig)(nil), // 1: v2ray.core.proxy.dns.SimplifiedConfig
-	(*net.Endpoint)(nil),     // 2: v2ray.core.common.net.Endpoint
+	(*Config)(nil),                 // 0: v2ray.core.proxy.dns.Config
+	(*SimplifiedConfig)(nil),       // 1: v2ray.core.proxy.dns.SimplifiedConfig
+	(*ServerConfig)(nil),           // 2: v2ray.core.proxy.dns.ServerConfig
+	(*SimplifiedServerConfig)(nil), // 3: v2ray.core.proxy.dns.SimplifiedServerConfig
+	(*net.Endpoint)(nil),           // 4: v2ray.core.common.net.Endpoint
+	(net.Network)(0),               // 5: v2ray.core.common.net.Network
+	(*net.NetworkList)(nil),        // 6: v2ray.core.common.net.NetworkList
 }
 var file_proxy_dns_config_proto_depIdxs = []int32{
-	2, // 0: v2ray.core.proxy.dns.Config.server:type_name -> v2ray.core.common.net.Endpoint
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	4, // 0: v2ray.core.proxy.dns.Config.server:type_name -> v2ray.core.common.net.Endpoint
+	5, // 1: v2ray.core.proxy.dns.ServerConfig.networks:type_name -> v2ray.core.common.net.Network
+	6, // 2: v2ray.core.proxy.dns.SimplifiedServerConfig.networks:type_name -> v2ray.core.common.net.NetworkList
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_proxy_dns_config_proto_init() }
@@ -222,7 +384,7 @@ func file_proxy_dns_config_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proxy_dns_config_proto_rawDesc), len(file_proxy_dns_config_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
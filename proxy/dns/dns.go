@@ -219,12 +219,12 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, d internet.
 				isIPQuery, domain, id, qType := parseIPQuery(b.Bytes())
 				if isIPQuery || h.nonIPQuery != "drop" {
 					if isIPQuery && !h.lookupAsExchange {
-						go h.handleIPQuery(id, qType, domain, writer)
+						go h.handleIPQuery(ctx, id, qType, domain, writer)
 						b.Release()
 						continue
 					} else {
 						go func() {
-							h.handleRawQuery(b.Bytes(), writer)
+							h.handleRawQuery(ctx, b.Bytes(), writer)
 							b.Release()
 						}()
 						continue
@@ -265,22 +265,28 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, d internet.
 	return nil
 }
 
-func (h *Handler) handleRawQuery(b []byte, writer dns_proto.MessageWriter) {
-	if rawQuery, ok := h.client.(feature_dns.RawQuery); ok {
-		resp, err := rawQuery.QueryRaw(b)
-		if err != nil {
-			newError(err).AtError().WriteToLog()
-			return
-		}
-		if err := writer.WriteMessage(buf.FromBytes(resp)); err != nil {
-			newError("write IP answer").Base(err).WriteToLog()
-		}
-	} else {
+func (h *Handler) handleRawQuery(ctx context.Context, b []byte, writer dns_proto.MessageWriter) {
+	var resp []byte
+	var err error
+	switch client := h.client.(type) {
+	case feature_dns.ContextQuery:
+		resp, err = client.QueryRawWithContext(ctx, b)
+	case feature_dns.RawQuery:
+		resp, err = client.QueryRaw(b)
+	default:
 		newError("dns.RawQuery not implemented").AtError().WriteToLog()
+		return
+	}
+	if err != nil {
+		newError(err).AtError().WriteToLog()
+		return
+	}
+	if err := writer.WriteMessage(buf.FromBytes(resp)); err != nil {
+		newError("write IP answer").Base(err).WriteToLog()
 	}
 }
 
-func (h *Handler) handleIPQuery(id uint16, qType uint16, domain string, writer dns_proto.MessageWriter) {
+func (h *Handler) handleIPQuery(ctx context.Context, id uint16, qType uint16, domain string, writer dns_proto.MessageWriter) {
 	var ips []net.IP
 	var err error
 
@@ -291,15 +297,21 @@ func (h *Handler) handleIPQuery(id uint16, qType uint16, domain string, writer d
 	}
 	var expireAt time.Time
 
-	switch qType {
-	case dns.TypeA:
+	contextQuery, hasContextQuery := h.client.(feature_dns.ContextQuery)
+	switch {
+	case hasContextQuery:
+		ips, expireAt, err = contextQuery.LookupIPWithContext(ctx, domain, feature_dns.IPOption{
+			IPv4Enable: qType == dns.TypeA,
+			IPv6Enable: qType == dns.TypeAAAA,
+		})
+	case qType == dns.TypeA:
 		if ipv4Lookup, ok := h.ipv4Lookup.(feature_dns.IPv4LookupWithTTL); ok {
 			ips, expireAt, err = ipv4Lookup.LookupIPv4WithTTL(domain)
 		} else {
 			ips, err = h.ipv4Lookup.LookupIPv4(domain)
 			expireAt = timeNow.Add(time.Duration(ttl) * time.Second)
 		}
-	case dns.TypeAAAA:
+	case qType == dns.TypeAAAA:
 		if ipv6Lookup, ok := h.ipv6Lookup.(feature_dns.IPv6LookupWithTTL); ok {
 			ips, expireAt, err = ipv6Lookup.LookupIPv6WithTTL(domain)
 		} else {
@@ -0,0 +1,350 @@
+package dns
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/miekg/dns"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/buf"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	dns_proto "github.com/v2fly/v2ray-core/v5/common/protocol/dns"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	"github.com/v2fly/v2ray-core/v5/common/signal"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+	"github.com/v2fly/v2ray-core/v5/features/policy"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+	"github.com/v2fly/v2ray-core/v5/transport/internet"
+)
+
+const (
+	defaultDoHPath = "/dns-query"
+	dohMediaType   = "application/dns-message"
+
+	// maxPendingQueries is the maximum number of queries being answered concurrently on a connection.
+	maxPendingQueries = 64
+)
+
+func init() {
+	common.Must(common.RegisterConfig((*ServerConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		s := new(Server)
+		if err := core.RequireFeatures(ctx, func(dnsClient feature_dns.Client, policyManager policy.Manager) error {
+			return s.Init(config.(*ServerConfig), dnsClient, policyManager)
+		}); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}))
+
+	common.Must(common.RegisterConfig((*SimplifiedServerConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		simplifiedServer := config.(*SimplifiedServerConfig)
+		fullConfig := &ServerConfig{
+			Networks:  simplifiedServer.Networks.GetNetwork(),
+			UserLevel: simplifiedServer.UserLevel,
+			DohPath:   simplifiedServer.DohPath,
+			FakeDns:   simplifiedServer.FakeDns,
+		}
+		return common.CreateObject(ctx, fullConfig)
+	}))
+}
+
+// Server is an inbound handler serving DNS queries with the DNS feature.
+type Server struct {
+	config        *ServerConfig
+	client        feature_dns.RawQuery
+	policyManager policy.Manager
+	dohPath       string
+}
+
+// Init initializes the Server with necessary parameters.
+func (s *Server) Init(config *ServerConfig, dnsClient feature_dns.Client, policyManager policy.Manager) error {
+	if config.FakeDns {
+		if clientWithFakeDNS, ok := dnsClient.(feature_dns.ClientWithFakeDNS); ok {
+			dnsClient = clientWithFakeDNS.AsFakeDNSClient()
+		}
+	}
+	client, ok := dnsClient.(feature_dns.RawQuery)
+	if !ok {
+		return newError("dns.Client doesn't implement RawQuery")
+	}
+	s.client = client
+	s.config = config
+	s.policyManager = policyManager
+	s.dohPath = config.DohPath
+	if s.dohPath == "" {
+		s.dohPath = defaultDoHPath
+	}
+	return nil
+}
+
+// Network implements proxy.Inbound.
+func (s *Server) Network() []net.Network {
+	if len(s.config.Networks) > 0 {
+		return s.config.Networks
+	}
+	return []net.Network{net.Network_TCP, net.Network_UDP}
+}
+
+// Process implements proxy.Inbound.
+func (s *Server) Process(ctx context.Context, network net.Network, conn internet.Connection, dispatcher routing.Dispatcher) error {
+	if inbound := session.InboundFromContext(ctx); inbound != nil {
+		inbound.User = &protocol.MemoryUser{
+			Level: s.config.UserLevel,
+		}
+	}
+	plcy := s.policyManager.ForLevel(s.config.UserLevel)
+
+	if network == net.Network_UDP {
+		return s.serveMessages(ctx, &packetReader{reader: buf.NewPacketReader(conn)}, &dns_proto.UDPWriter{Writer: &buf.SequentialWriter{Writer: conn}}, plcy)
+	}
+
+	reader := bufio.NewReader(conn)
+	if isHTTPRequest(reader) {
+		return s.serveHTTP(ctx, &bufferedConn{Connection: conn, reader: reader}, plcy)
+	}
+	return s.serveMessages(ctx, &dns_proto.TCPReader{Reader: reader}, &dns_proto.TCPWriter{Writer: buf.NewWriter(conn)}, plcy)
+}
+
+// isHTTPRequest returns true if the connection starts with an HTTP/1.x request or HTTP/2 preface,
+// instead of the length of a DNS message.
+func isHTTPRequest(reader *bufio.Reader) bool {
+	prefix, err := reader.Peek(4)
+	if err != nil {
+		return false
+	}
+	for _, p := range []string{"GET ", "POST", "PRI "} {
+		if string(prefix) == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) serveMessages(ctx context.Context, reader dns_proto.MessageReader, writer dns_proto.MessageWriter, plcy policy.Session) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	timer := signal.CancelAfterInactivity(ctx, cancel, plcy.Timeouts.ConnectionIdle)
+
+	// Responses are written out of order as they are answered, but never concurrently.
+	var writeAccess sync.Mutex
+	pending := make(chan struct{}, maxPendingQueries)
+
+	done := make(chan error, 1)
+	go func() {
+		for {
+			b, err := reader.ReadMessage()
+			if err != nil {
+				done <- err
+				return
+			}
+			timer.Update()
+			select {
+			case pending <- struct{}{}:
+			case <-ctx.Done():
+				b.Release()
+				return
+			}
+			go func() {
+				defer func() { <-pending }()
+
+				response := s.query(ctx, b.Bytes())
+				b.Release()
+				if response == nil {
+					return
+				}
+				writeAccess.Lock()
+				err := writer.WriteMessage(buf.FromBytes(response))
+				writeAccess.Unlock()
+				if err != nil {
+					newError("failed to write DNS response").Base(err).WriteToLog(session.ExportIDToError(ctx))
+					return
+				}
+				timer.Update()
+			}()
+		}
+	}()
+
+	select {
+	case err := <-done:
+		if err != io.EOF {
+			return newError("connection ends").Base(err)
+		}
+		return nil
+	case <-ctx.Done():
+		common.Interrupt(reader)
+		return nil
+	}
+}
+
+// query answers the DNS request. It returns a SERVFAIL response if the query fails, or nil if
+// the request is malformed.
+func (s *Server) query(ctx context.Context, request []byte) []byte {
+	var response []byte
+	var err error
+	if client, ok := s.client.(feature_dns.ContextQuery); ok {
+		response, err = client.QueryRawWithContext(ctx, request)
+	} else {
+		response, err = s.client.QueryRaw(request)
+	}
+	if err == nil {
+		return response
+	}
+	newError("failed to query").Base(err).AtWarning().WriteToLog()
+
+	message := new(dns.Msg)
+	if err := message.Unpack(request); err != nil {
+		return nil
+	}
+	message.SetRcode(message, dns.RcodeServerFailure)
+	message.RecursionAvailable = true
+	response, err = message.Pack()
+	if err != nil {
+		return nil
+	}
+	return response
+}
+
+func (s *Server) serveHTTP(ctx context.Context, conn net.Conn, plcy policy.Session) error {
+	listener := &singleConnListener{conn: conn, closed: make(chan struct{})}
+	server := &http.Server{
+		Handler:           http.HandlerFunc(s.handleDoH),
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+		ReadHeaderTimeout: plcy.Timeouts.Handshake,
+		IdleTimeout:       plcy.Timeouts.ConnectionIdle,
+		Protocols:         new(http.Protocols),
+		ConnState: func(c net.Conn, state http.ConnState) {
+			if state == http.StateClosed || state == http.StateHijacked {
+				listener.Close()
+			}
+		},
+	}
+	server.Protocols.SetHTTP1(true)
+	// TLS has been handled by the transport.
+	server.Protocols.SetUnencryptedHTTP2(true)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			server.Close()
+		case <-listener.closed:
+		}
+	}()
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed && err != errListenerClosed {
+		return newError("failed to serve DNS over HTTPS").Base(err)
+	}
+	<-listener.closed
+	return nil
+}
+
+func (s *Server) handleDoH(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != s.dohPath {
+		http.NotFound(w, r)
+		return
+	}
+
+	var request []byte
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		request, err = base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+	case http.MethodPost:
+		if r.Header.Get("Content-Type") != dohMediaType {
+			http.Error(w, "unsupported media type", http.StatusUnsupportedMediaType)
+			return
+		}
+		request, err = io.ReadAll(io.LimitReader(r.Body, dns.MaxMsgSize))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil || len(request) == 0 {
+		http.Error(w, "invalid DNS request", http.StatusBadRequest)
+		return
+	}
+
+	response := s.query(r.Context(), request)
+	if response == nil {
+		http.Error(w, "invalid DNS request", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", dohMediaType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(response)
+}
+
+// packetReader reads each packet as a DNS message.
+type packetReader struct {
+	reader  buf.Reader
+	pending buf.MultiBuffer
+}
+
+// ReadMessage implements dns_proto.MessageReader.
+func (r *packetReader) ReadMessage() (*buf.Buffer, error) {
+	for len(r.pending) == 0 {
+		mb, err := r.reader.ReadMultiBuffer()
+		if err != nil {
+			return nil, err
+		}
+		r.pending = mb
+	}
+	b := r.pending[0]
+	r.pending = r.pending[1:]
+	return b, nil
+}
+
+func (r *packetReader) Interrupt() {
+	common.Interrupt(r.reader)
+}
+
+// bufferedConn is a connection reading through the buffered reader.
+type bufferedConn struct {
+	internet.Connection
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+var errListenerClosed = newError("listener closed")
+
+// singleConnListener is a net.Listener accepting the connection only once.
+type singleConnListener struct {
+	access sync.Mutex
+	conn   net.Conn
+	closed chan struct{}
+}
+
+func (l *singleConnListener) Accept() (net.Conn, error) {
+	l.access.Lock()
+	conn := l.conn
+	l.conn = nil
+	l.access.Unlock()
+	if conn != nil {
+		return conn, nil
+	}
+	<-l.closed
+	return nil, errListenerClosed
+}
+
+func (l *singleConnListener) Close() error {
+	l.access.Lock()
+	defer l.access.Unlock()
+	select {
+	case <-l.closed:
+	default:
+		close(l.closed)
+	}
+	return nil
+}
+
+func (l *singleConnListener) Addr() net.Addr {
+	return &net.TCPAddr{}
+}
@@ -0,0 +1,203 @@
+package dns_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	gonet "net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/buf"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	dns_proto "github.com/v2fly/v2ray-core/v5/common/protocol/dns"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+	"github.com/v2fly/v2ray-core/v5/features/policy"
+	dns_proxy "github.com/v2fly/v2ray-core/v5/proxy/dns"
+)
+
+type staticClient struct{}
+
+func (*staticClient) Type() interface{} { return feature_dns.ClientType() }
+func (*staticClient) Start() error      { return nil }
+func (*staticClient) Close() error      { return nil }
+
+func (*staticClient) LookupIP(domain string) ([]net.IP, error) {
+	return nil, feature_dns.ErrEmptyResponse
+}
+
+func (*staticClient) QueryRaw(b []byte) ([]byte, error) {
+	request := new(dns.Msg)
+	if err := request.Unpack(b); err != nil {
+		return nil, err
+	}
+	if request.Question[0].Name != "google.com." {
+		return nil, errors.New("failed")
+	}
+	response := new(dns.Msg)
+	response.SetReply(request)
+	rr, _ := dns.NewRR("google.com. IN A 8.8.8.8")
+	response.Answer = append(response.Answer, rr)
+	return response.Pack()
+}
+
+// contextClient records the inbound connections the queries are made on behalf of.
+type contextClient struct {
+	staticClient
+	inbounds chan *session.Inbound
+}
+
+func (c *contextClient) LookupIPWithContext(ctx context.Context, domain string, option feature_dns.IPOption) ([]net.IP, time.Time, error) {
+	return nil, time.Time{}, feature_dns.ErrEmptyResponse
+}
+
+func (c *contextClient) QueryRawWithContext(ctx context.Context, b []byte) ([]byte, error) {
+	c.inbounds <- session.InboundFromContext(ctx)
+	return c.QueryRaw(b)
+}
+
+func newTestServer(t *testing.T) *dns_proxy.Server {
+	server := new(dns_proxy.Server)
+	common.Must(server.Init(&dns_proxy.ServerConfig{}, &staticClient{}, policy.DefaultManager{}))
+	return server
+}
+
+func serve(t *testing.T, server *dns_proxy.Server) net.Conn {
+	return serveWithContext(t, context.Background(), server)
+}
+
+func serveWithContext(t *testing.T, ctx context.Context, server *dns_proxy.Server) net.Conn {
+	client, conn := gonet.Pipe()
+	go func() {
+		if err := server.Process(ctx, net.Network_TCP, conn, nil); err != nil {
+			t.Log(err)
+		}
+		conn.Close()
+	}()
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func packQuery(t *testing.T, name string) []byte {
+	request := new(dns.Msg)
+	request.SetQuestion(name, dns.TypeA)
+	b, err := request.Pack()
+	common.Must(err)
+	return b
+}
+
+func checkResponse(t *testing.T, b []byte, rcode int, answers int) {
+	response := new(dns.Msg)
+	common.Must(response.Unpack(b))
+	if response.Rcode != rcode {
+		t.Error("expect rcode ", rcode, " but got ", response.Rcode)
+	}
+	if len(response.Answer) != answers {
+		t.Error("expect ", answers, " answers but got ", len(response.Answer))
+	}
+}
+
+func TestServerTCP(t *testing.T) {
+	conn := serve(t, newTestServer(t))
+	writer := &dns_proto.TCPWriter{Writer: buf.NewWriter(conn)}
+	reader := &dns_proto.TCPReader{Reader: bufio.NewReader(conn)}
+
+	common.Must(writer.WriteMessage(buf.FromBytes(packQuery(t, "google.com."))))
+	b, err := reader.ReadMessage()
+	common.Must(err)
+	checkResponse(t, b.Bytes(), dns.RcodeSuccess, 1)
+
+	common.Must(writer.WriteMessage(buf.FromBytes(packQuery(t, "notexist.com."))))
+	b, err = reader.ReadMessage()
+	common.Must(err)
+	checkResponse(t, b.Bytes(), dns.RcodeServerFailure, 0)
+}
+
+func TestServerTCPPipelined(t *testing.T) {
+	conn := serve(t, newTestServer(t))
+	writer := &dns_proto.TCPWriter{Writer: buf.NewWriter(conn)}
+	reader := &dns_proto.TCPReader{Reader: bufio.NewReader(conn)}
+
+	const queries = 200
+	go func() {
+		for i := 0; i < queries; i++ {
+			if err := writer.WriteMessage(buf.FromBytes(packQuery(t, "google.com."))); err != nil {
+				return
+			}
+		}
+	}()
+	for i := 0; i < queries; i++ {
+		b, err := reader.ReadMessage()
+		common.Must(err)
+		checkResponse(t, b.Bytes(), dns.RcodeSuccess, 1)
+		b.Release()
+	}
+}
+
+func TestServerInboundContext(t *testing.T) {
+	client := &contextClient{inbounds: make(chan *session.Inbound, 1)}
+	server := new(dns_proxy.Server)
+	common.Must(server.Init(&dns_proxy.ServerConfig{}, client, policy.DefaultManager{}))
+
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		Tag:    "dns-in",
+		Source: net.TCPDestination(net.ParseAddress("10.0.0.1"), 5353),
+	})
+	conn := serveWithContext(t, ctx, server)
+	writer := &dns_proto.TCPWriter{Writer: buf.NewWriter(conn)}
+	reader := &dns_proto.TCPReader{Reader: bufio.NewReader(conn)}
+
+	common.Must(writer.WriteMessage(buf.FromBytes(packQuery(t, "google.com."))))
+	b, err := reader.ReadMessage()
+	common.Must(err)
+	checkResponse(t, b.Bytes(), dns.RcodeSuccess, 1)
+
+	inbound := <-client.inbounds
+	if inbound == nil || inbound.Tag != "dns-in" || inbound.Source.Address.String() != "10.0.0.1" {
+		t.Error("unexpected inbound of query: ", inbound)
+	}
+}
+
+func TestServerDoH(t *testing.T) {
+	conn := serve(t, newTestServer(t))
+	reader := bufio.NewReader(conn)
+
+	request, err := http.NewRequest(http.MethodGet, "http://dns.example/dns-query?dns="+base64.RawURLEncoding.EncodeToString(packQuery(t, "google.com.")), nil)
+	common.Must(err)
+	common.Must(request.Write(conn))
+	response, err := http.ReadResponse(reader, request)
+	common.Must(err)
+	if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "application/dns-message" {
+		t.Fatal("unexpected response: ", response.Status)
+	}
+	b, err := io.ReadAll(response.Body)
+	common.Must(err)
+	checkResponse(t, b, dns.RcodeSuccess, 1)
+
+	request, err = http.NewRequest(http.MethodPost, "http://dns.example/dns-query", bytes.NewReader(packQuery(t, "google.com.")))
+	common.Must(err)
+	request.Header.Set("Content-Type", "application/dns-message")
+	common.Must(request.Write(conn))
+	response, err = http.ReadResponse(reader, request)
+	common.Must(err)
+	b, err = io.ReadAll(response.Body)
+	common.Must(err)
+	checkResponse(t, b, dns.RcodeSuccess, 1)
+
+	request, err = http.NewRequest(http.MethodGet, "http://dns.example/other", nil)
+	common.Must(err)
+	common.Must(request.Write(conn))
+	response, err = http.ReadResponse(reader, request)
+	common.Must(err)
+	if response.StatusCode != http.StatusNotFound {
+		t.Error("expect 404 but got ", response.Status)
+	}
+}
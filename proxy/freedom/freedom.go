@@ -4,6 +4,7 @@ package freedom
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"time"
 
@@ -72,14 +73,18 @@ func (h *Handler) policy() policy.Session {
 }
 
 func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Address) net.Address {
-	ips, err := dns.LookupIPWithOption(h.dns, domain, dns.IPOption{
+	option := dns.IPOption{
 		IPv4Enable: h.config.DomainStrategy != Config_USE_IP6 || (localAddr != nil && localAddr.Family().IsIPv4()),
 		IPv6Enable: h.config.DomainStrategy != Config_USE_IP4 || (localAddr != nil && localAddr.Family().IsIPv6()),
 		FakeEnable: false,
-	})
+	}
+	ips, err := dns.LookupIPWithOption(h.dns, domain, option)
 	if err != nil {
 		newError("failed to get IP address for domain ", domain).Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
+	if len(ips) == 0 {
+		ips = h.lookupAddressHints(domain, option)
+	}
 	if len(ips) == 0 {
 		return nil
 	}
@@ -95,6 +100,32 @@ func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Ad
 	return net.IPAddress(ips[0])
 }
 
+// lookupAddressHints returns the ipv4hint and ipv6hint of the HTTPS records of the domain, which clients
+// may use when the address records are not available (RFC 9460 Section 7.3).
+func (h *Handler) lookupAddressHints(domain string, option dns.IPOption) []net.IP {
+	lookup, ok := h.dns.(dns.SVCBLookup)
+	if !ok {
+		return nil
+	}
+	records, _, err := lookup.LookupHTTPS(domain)
+	if err != nil {
+		return nil
+	}
+	var ips []net.IP
+	for _, rec := range records {
+		if rec.Target != "." && !strings.EqualFold(strings.TrimSuffix(rec.Target, "."), domain) {
+			continue
+		}
+		if option.IPv4Enable {
+			ips = append(ips, rec.IPv4Hint...)
+		}
+		if option.IPv6Enable {
+			ips = append(ips, rec.IPv6Hint...)
+		}
+	}
+	return ips
+}
+
 func isValidAddress(addr *net.IPOrDomain) bool {
 	if addr == nil {
 		return false
@@ -266,10 +266,9 @@ func (c *Config) GetTLSConfig(opts ...Option) *tls.Config {
 		config.MaxVersion = tls.VersionTLS13
 	}
 
-	if len(c.EchConfig) > 0 || len(c.Ech_DOHserver) > 0 {
-		err := ApplyECH(c, config) //nolint: staticcheck
-		if err != nil {            //nolint: staticcheck
-			newError("unable to set ECH").AtError().Base(err).WriteToLog()
+	if len(c.EchConfig) > 0 || len(c.Ech_DOHserver) > 0 { //nolint: staticcheck
+		if err := ApplyECH(c, config); err != nil {
+			newError("unable to set ECH, the connection will fail").AtError().Base(err).WriteToLog()
 		}
 	}
 
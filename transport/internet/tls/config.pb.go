@@ -230,14 +230,18 @@ type Config struct {
 	// ECH Config in bytes format
 	EchConfig []byte `protobuf:"bytes,16,opt,name=ech_config,json=echConfig,proto3" json:"ech_config,omitempty"`
 	// DOH server to query HTTPS record for ECH
+	// Deprecated. Use query_https_record.
 	Ech_DOHserver string `protobuf:"bytes,17,opt,name=ech_DOHserver,json=echDOHserver,proto3" json:"ech_DOHserver,omitempty"`
 	// domain to query for https record
 	EchQueryDomain string `protobuf:"bytes,18,opt,name=ech_query_domain,json=echQueryDomain,proto3" json:"ech_query_domain,omitempty"`
 	// cipher suites to to be offered or accepted.
 	// This is an developer option.
-	Ciphersuites  []uint32 `protobuf:"varint,19,rep,packed,name=ciphersuites,proto3" json:"ciphersuites,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Ciphersuites []uint32 `protobuf:"varint,19,rep,packed,name=ciphersuites,proto3" json:"ciphersuites,omitempty"`
+	// Query the HTTPS record of the server with the DNS feature, for the ECH
+	// config if ech_config is not set, and for ALPN if next_protocol is not set.
+	QueryHttpsRecord bool `protobuf:"varint,20,opt,name=query_https_record,json=queryHttpsRecord,proto3" json:"query_https_record,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *Config) Reset() {
@@ -368,6 +372,13 @@ func (x *Config) GetCiphersuites() []uint32 {
 	return nil
 }
 
+func (x *Config) GetQueryHttpsRecord() bool {
+	if x != nil {
+		return x.QueryHttpsRecord
+	}
+	return false
+}
+
 var File_transport_internet_tls_config_proto protoreflect.FileDescriptor
 
 const file_transport_internet_tls_config_proto_rawDesc = "" +
@@ -383,7 +394,7 @@ const file_transport_internet_tls_config_proto_rawDesc = "" +
 	"\fENCIPHERMENT\x10\x00\x12\x14\n" +
 	"\x10AUTHORITY_VERIFY\x10\x01\x12\x13\n" +
 	"\x0fAUTHORITY_ISSUE\x10\x02\x12\x1b\n" +
-	"\x17AUTHORITY_VERIFY_CLIENT\x10\x03\"\x8e\a\n" +
+	"\x17AUTHORITY_VERIFY_CLIENT\x10\x03\"\xbc\a\n" +
 	"\x06Config\x12-\n" +
 	"\x0eallow_insecure\x18\x01 \x01(\bB\x06\x82\xb5\x18\x02(\x01R\rallowInsecure\x12P\n" +
 	"\vcertificate\x18\x02 \x03(\v2..v2ray.core.transport.internet.tls.CertificateR\vcertificate\x12\x1f\n" +
@@ -403,7 +414,8 @@ const file_transport_internet_tls_config_proto_rawDesc = "" +
 	"ech_config\x18\x10 \x01(\fR\techConfig\x12#\n" +
 	"\rech_DOHserver\x18\x11 \x01(\tR\fechDOHserver\x12(\n" +
 	"\x10ech_query_domain\x18\x12 \x01(\tR\x0eechQueryDomain\x12\"\n" +
-	"\fciphersuites\x18\x13 \x03(\rR\fciphersuites\"I\n" +
+	"\fciphersuites\x18\x13 \x03(\rR\fciphersuites\x12,\n" +
+	"\x12query_https_record\x18\x14 \x01(\bR\x10queryHttpsRecord\"I\n" +
 	"\n" +
 	"TLSVersion\x12\v\n" +
 	"\aDefault\x10\x00\x12\n" +
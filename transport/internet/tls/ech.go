@@ -1,156 +1,90 @@
 package tls
 
 import (
-	"bytes"
-	"context"
 	"crypto/tls"
-	"io"
-	"net/http"
-	"sync"
-	"time"
-
-	"github.com/miekg/dns"
+	"strings"
 
 	"github.com/v2fly/v2ray-core/v5/common/net"
-	"github.com/v2fly/v2ray-core/v5/transport/internet"
+	dns_feature "github.com/v2fly/v2ray-core/v5/features/dns"
 )
 
+// ApplyECH sets the static ECH config, or the ECH config in the HTTPS record queried from the DoH server of the
+// deprecated ech_DOHserver. If the ECH config cannot be applied, the TLS handshake is made to fail before the
+// client hello is sent, so that the server name is never sent in the clear.
 func ApplyECH(c *Config, config *tls.Config) error {
-	var echConfig []byte
-	var err error
-	var domain string
-
 	if len(c.EchConfig) > 0 {
-		echConfig = c.EchConfig
-	} else { // ECH config > DOH lookup
-		if c.EchQueryDomain == "" {
-			domain = config.ServerName
-		} else {
-			domain = c.EchQueryDomain
-		}
-		addr := net.ParseAddress(domain)
-		if !addr.Family().IsDomain() {
-			return newError("Using DOH for ECH needs SNI")
-		}
-		echConfig, err = QueryRecord(addr.Domain(), c.Ech_DOHserver)
-		if err != nil {
-			return err
-		}
-		if len(echConfig) == 0 {
-			return newError("no ech record found")
-		}
+		config.EncryptedClientHelloConfigList = c.EchConfig
+		return nil
 	}
-
-	config.EncryptedClientHelloConfigList = echConfig
-	return nil
-}
-
-type record struct {
-	record []byte
-	expire time.Time
-}
-
-var (
-	dnsCache = make(map[string]record)
-	mutex    sync.RWMutex
-)
-
-func QueryRecord(domain string, server string) ([]byte, error) {
-	mutex.Lock()
-	rec, found := dnsCache[domain]
-	if found {
-		if rec.expire.After(time.Now()) {
-			mutex.Unlock()
-			return rec.record, nil
-		}
-		delete(dnsCache, domain)
+	if len(c.Ech_DOHserver) == 0 { //nolint: staticcheck
+		return nil
 	}
-	mutex.Unlock()
-
-	newError("Trying to query ECH config for domain: ", domain, " with ECH server: ", server).AtDebug().WriteToLog()
-	record, ttl, err := dohQuery(server, domain)
-	if err != nil {
-		return nil, err
+	err := ApplyHTTPSRecord(c, config, dohLookup{server: c.Ech_DOHserver}, false) //nolint: staticcheck
+	if err == nil && len(config.EncryptedClientHelloConfigList) == 0 {
+		err = newError("no ECH config in HTTPS record")
 	}
-
-	if ttl > 0 {
-		mutex.Lock()
-		defer mutex.Unlock()
-		rec.record = record
-		rec.expire = time.Now().Add(time.Second * time.Duration(ttl))
-		dnsCache[domain] = rec
+	if err != nil {
+		failECH(config)
+		return err
 	}
+	return nil
+}
 
-	return record, nil
+// failECH makes the TLS handshake fail before the client hello is sent, as an ECH config list without any config
+// is rejected by the TLS client.
+func failECH(config *tls.Config) {
+	config.EncryptedClientHelloConfigList = []byte{0, 0}
 }
 
-func dohQuery(server string, domain string) ([]byte, uint32, error) {
-	m := new(dns.Msg)
-	m.SetQuestion(dns.Fqdn(domain), dns.TypeHTTPS)
-	m.Id = 0
-	msg, err := m.Pack()
-	if err != nil {
-		return nil, 0, err
-	}
-	tr := &http.Transport{
-		IdleConnTimeout:   90 * time.Second,
-		ForceAttemptHTTP2: true,
-		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-			dest, err := net.ParseDestination(network + ":" + addr)
-			if err != nil {
-				return nil, err
-			}
-			conn, err := internet.DialSystem(ctx, dest, nil)
-			if err != nil {
-				return nil, err
-			}
-			return conn, nil
-		},
-	}
-	client := &http.Client{
-		Timeout:   5 * time.Second,
-		Transport: tr,
+// ApplyHTTPSRecord sets the ECH config and optionally ALPN from the HTTPS record of the server, queried with
+// the given lookup. The ECH config set by ApplyECH takes precedence.
+func ApplyHTTPSRecord(c *Config, config *tls.Config, lookup dns_feature.SVCBLookup, overrideALPN bool) error {
+	if lookup == nil {
+		return newError("DNS feature does not support HTTPS records")
 	}
-	req, err := http.NewRequest("POST", server, bytes.NewReader(msg))
-	if err != nil {
-		return nil, 0, err
+	domain := c.EchQueryDomain
+	if domain == "" {
+		domain = config.ServerName
 	}
-	req.Header.Set("Content-Type", "application/dns-message")
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, 0, err
+	addr := net.ParseAddress(domain)
+	if !addr.Family().IsDomain() {
+		return newError("querying HTTPS record needs SNI")
 	}
-	defer resp.Body.Close()
-	respBody, err := io.ReadAll(resp.Body)
+	records, _, err := lookup.LookupHTTPS(addr.Domain())
 	if err != nil {
-		return nil, 0, err
+		return newError("failed to query HTTPS record of ", domain).Base(err)
 	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, 0, newError("query failed with response code: ", resp.StatusCode)
+	if len(records) == 0 {
+		return newError("no HTTPS record found for ", domain)
 	}
-	respMsg := new(dns.Msg)
-	err = respMsg.Unpack(respBody)
-	if err != nil {
-		return nil, 0, err
+	rec := records[0]
+	if len(config.EncryptedClientHelloConfigList) == 0 && len(rec.ECHConfig) > 0 {
+		config.EncryptedClientHelloConfigList = rec.ECHConfig
 	}
-	for _, answer := range respMsg.Answer {
-		if answer.Header().Class == dns.ClassINET && answer.Header().Header().Rrtype == dns.TypeHTTPS {
-			if https, ok := answer.(*dns.HTTPS); ok {
-				for _, v := range https.Value {
-					if echConfig, ok := v.(*dns.SVCBECHConfig); ok {
-						newError(context.Background(), "Get ECH config:", echConfig.String(), " TTL:", respMsg.Answer[0].Header().Ttl).AtDebug().WriteToLog()
-						return echConfig.ECH, answer.Header().Ttl, nil
-					}
-				}
-			}
+	if overrideALPN {
+		if alpn := alpnFromHTTPSRecord(rec); len(alpn) > 0 {
+			config.NextProtos = alpn
 		}
 	}
-	if respMsg.Rcode == dns.RcodeSuccess || respMsg.Rcode == dns.RcodeNameError {
-		for _, ns := range respMsg.Ns {
-			if soa, ok := ns.(*dns.SOA); ok {
-				return nil, min(ns.Header().Ttl, soa.Minttl), nil
-			}
+	return nil
+}
+
+// alpnFromHTTPSRecord returns the protocols of the record usable over TCP, with http/1.1 as the default
+// protocol of the https scheme unless it is excluded.
+func alpnFromHTTPSRecord(rec *dns_feature.SVCBRecord) []string {
+	alpn := make([]string, 0, len(rec.ALPN)+1)
+	hasDefault := false
+	for _, p := range rec.ALPN {
+		if strings.HasPrefix(p, "h3") {
+			continue
 		}
+		if p == "http/1.1" {
+			hasDefault = true
+		}
+		alpn = append(alpn, p)
+	}
+	if !rec.NoDefaultALPN && !hasDefault {
+		alpn = append(alpn, "http/1.1")
 	}
-	return nil, 0, newError("no ech record found")
+	return alpn
 }
@@ -0,0 +1,145 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	dns_feature "github.com/v2fly/v2ray-core/v5/features/dns"
+	"github.com/v2fly/v2ray-core/v5/transport/internet"
+)
+
+// dohLookup queries HTTPS records from a DoH server, for the deprecated ech_DOHserver setting. It is used where
+// the DNS feature is unavailable, as the TLS config is built by transports without a context.
+type dohLookup struct {
+	server string
+}
+
+type dohRecord struct {
+	records []*dns_feature.SVCBRecord
+	expire  time.Time
+}
+
+var (
+	dohCacheAccess sync.Mutex
+	dohCache       = make(map[string]dohRecord)
+	dohClient      = &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			IdleConnTimeout:   90 * time.Second,
+			ForceAttemptHTTP2: true,
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dest, err := net.ParseDestination(network + ":" + addr)
+				if err != nil {
+					return nil, err
+				}
+				return internet.DialSystem(ctx, dest, nil)
+			},
+		},
+	}
+)
+
+// LookupHTTPS implements dns_feature.SVCBLookup.
+func (l dohLookup) LookupHTTPS(domain string) ([]*dns_feature.SVCBRecord, time.Time, error) {
+	key := l.server + "|" + domain
+	dohCacheAccess.Lock()
+	rec, found := dohCache[key]
+	if found && rec.expire.Before(time.Now()) {
+		delete(dohCache, key)
+		found = false
+	}
+	dohCacheAccess.Unlock()
+	if found {
+		return rec.records, rec.expire, nil
+	}
+
+	newError("querying HTTPS record of ", domain, " with DoH server ", l.server).AtDebug().WriteToLog()
+	records, ttl, err := l.query(domain)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	rec = dohRecord{records: records, expire: time.Now().Add(time.Duration(ttl) * time.Second)}
+	if ttl > 0 {
+		dohCacheAccess.Lock()
+		dohCache[key] = rec
+		dohCacheAccess.Unlock()
+	}
+	return rec.records, rec.expire, nil
+}
+
+// LookupSVCB implements dns_feature.SVCBLookup.
+func (l dohLookup) LookupSVCB(string) ([]*dns_feature.SVCBRecord, time.Time, error) {
+	return nil, time.Time{}, newError("SVCB records are not supported by ech_DOHserver")
+}
+
+func (l dohLookup) query(domain string) ([]*dns_feature.SVCBRecord, uint32, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(domain), dns.TypeHTTPS)
+	m.Id = 0
+	msg, err := m.Pack()
+	if err != nil {
+		return nil, 0, err
+	}
+	req, err := http.NewRequest("POST", l.server, bytes.NewReader(msg))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	resp, err := dohClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, newError("query failed with response code: ", resp.StatusCode)
+	}
+	respMsg := new(dns.Msg)
+	if err := respMsg.Unpack(respBody); err != nil {
+		return nil, 0, err
+	}
+	if respMsg.Rcode != dns.RcodeSuccess && respMsg.Rcode != dns.RcodeNameError {
+		return nil, 0, dns_feature.RCodeError(respMsg.Rcode)
+	}
+
+	var records []*dns_feature.SVCBRecord
+	var ttl uint32
+	for _, answer := range respMsg.Answer {
+		https, ok := answer.(*dns.HTTPS)
+		if !ok || https.Hdr.Class != dns.ClassINET || https.Priority == 0 {
+			continue
+		}
+		rec := &dns_feature.SVCBRecord{Priority: https.Priority, Target: https.Target}
+		for _, kv := range https.Value {
+			switch v := kv.(type) {
+			case *dns.SVCBAlpn:
+				rec.ALPN = v.Alpn
+			case *dns.SVCBNoDefaultAlpn:
+				rec.NoDefaultALPN = true
+			case *dns.SVCBECHConfig:
+				rec.ECHConfig = v.ECH
+			}
+		}
+		records = append(records, rec)
+		if ttl == 0 || https.Hdr.Ttl < ttl {
+			ttl = https.Hdr.Ttl
+		}
+	}
+	if len(records) == 0 {
+		for _, ns := range respMsg.Ns {
+			if soa, ok := ns.(*dns.SOA); ok {
+				ttl = min(ns.Header().Ttl, soa.Minttl)
+			}
+		}
+	}
+	return records, ttl, nil
+}
@@ -0,0 +1,129 @@
+package tls_test
+
+import (
+	gotls "crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	mdns "github.com/miekg/dns"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/features/dns"
+	. "github.com/v2fly/v2ray-core/v5/transport/internet/tls"
+)
+
+type staticSVCBLookup struct {
+	records []*dns.SVCBRecord
+	domain  string
+}
+
+func (l *staticSVCBLookup) LookupHTTPS(domain string) ([]*dns.SVCBRecord, time.Time, error) {
+	l.domain = domain
+	return l.records, time.Now().Add(time.Minute), nil
+}
+
+func (l *staticSVCBLookup) LookupSVCB(domain string) ([]*dns.SVCBRecord, time.Time, error) {
+	return nil, time.Time{}, dns.ErrEmptyResponse
+}
+
+func TestApplyHTTPSRecord(t *testing.T) {
+	lookup := &staticSVCBLookup{
+		records: []*dns.SVCBRecord{
+			{Priority: 1, Target: ".", ALPN: []string{"h3", "h2"}, ECHConfig: []byte{1, 2, 3}},
+			{Priority: 2, Target: ".", ALPN: []string{"http/1.1"}},
+		},
+	}
+	c := &Config{ServerName: "www.v2fly.org", QueryHttpsRecord: true}
+
+	config := c.GetTLSConfig()
+	if err := ApplyHTTPSRecord(c, config, lookup, true); err != nil {
+		t.Fatal(err)
+	}
+	if lookup.domain != "www.v2fly.org" {
+		t.Error("unexpected query domain: ", lookup.domain)
+	}
+	if r := cmp.Diff(config.EncryptedClientHelloConfigList, []byte{1, 2, 3}); r != "" {
+		t.Error(r)
+	}
+	if r := cmp.Diff(config.NextProtos, []string{"h2", "http/1.1"}); r != "" {
+		t.Error(r)
+	}
+
+	config = &gotls.Config{ServerName: "www.v2fly.org", NextProtos: []string{"h2"}}
+	if err := ApplyHTTPSRecord(c, config, lookup, false); err != nil {
+		t.Fatal(err)
+	}
+	if r := cmp.Diff(config.NextProtos, []string{"h2"}); r != "" {
+		t.Error(r)
+	}
+
+	config = &gotls.Config{ServerName: "127.0.0.1"}
+	if err := ApplyHTTPSRecord(c, config, lookup, true); err == nil {
+		t.Error("expect error for IP server name")
+	}
+}
+
+func newDOHServer(ech []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		common.Must(err)
+		req := new(mdns.Msg)
+		common.Must(req.Unpack(body))
+		resp := new(mdns.Msg)
+		resp.SetReply(req)
+		if len(ech) > 0 {
+			https := &mdns.HTTPS{SVCB: mdns.SVCB{
+				Hdr:      mdns.RR_Header{Name: req.Question[0].Name, Rrtype: mdns.TypeHTTPS, Class: mdns.ClassINET, Ttl: 300},
+				Priority: 1,
+				Target:   ".",
+				Value:    []mdns.SVCBKeyValue{&mdns.SVCBECHConfig{ECH: ech}},
+			}}
+			resp.Answer = append(resp.Answer, https)
+		}
+		msg, err := resp.Pack()
+		common.Must(err)
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(msg)
+	}))
+}
+
+func TestECHDOHServer(t *testing.T) {
+	server := newDOHServer([]byte{1, 2, 3})
+	defer server.Close()
+
+	c := &Config{ServerName: "www.v2fly.org", Ech_DOHserver: server.URL} //nolint: staticcheck
+	config := c.GetTLSConfig()
+	if r := cmp.Diff(config.EncryptedClientHelloConfigList, []byte{1, 2, 3}); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestECHDOHServerFailClosed(t *testing.T) {
+	server := newDOHServer(nil)
+	defer server.Close()
+
+	for _, dohServer := range []string{server.URL, "http://127.0.0.1:1/dns-query"} {
+		c := &Config{ServerName: "www.v2fly.org", Ech_DOHserver: dohServer} //nolint: staticcheck
+		config := c.GetTLSConfig()
+		if err := ApplyECH(c, config); err == nil {
+			t.Error("expect error for ", dohServer)
+		}
+
+		client, peer := net.Pipe()
+		go func() {
+			// Nothing should be sent by the client.
+			if n, err := peer.Read(make([]byte, 1)); err == nil {
+				t.Error("unexpected client hello of ", n, " bytes")
+			}
+		}()
+		if err := gotls.Client(client, config).Handshake(); err == nil {
+			t.Error("expect handshake error for ", dohServer)
+		}
+		client.Close()
+	}
+}
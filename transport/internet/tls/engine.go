@@ -2,26 +2,37 @@ package tls
 
 import (
 	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/features/dns"
 	"github.com/v2fly/v2ray-core/v5/transport/internet/security"
 )
 
 type Engine struct {
 	config *Config
+	// svcb is used for querying HTTPS records when query_https_record is set.
+	svcb dns.SVCBLookup
 }
 
 func (e *Engine) Client(conn net.Conn, opts ...security.Option) (security.Conn, error) {
 	var options []Option
+	hasALPN := len(e.config.NextProtocol) > 0
 	for _, v := range opts {
 		switch s := v.(type) {
 		case security.OptionWithALPN:
 			options = append(options, WithNextProto(s.ALPNs...))
+			hasALPN = true
 		case security.OptionWithDestination:
 			options = append(options, WithDestination(s.Dest))
 		default:
 			return nil, newError("unknown option")
 		}
 	}
-	tlsConn := Client(conn, e.config.GetTLSConfig(options...))
+	config := e.config.GetTLSConfig(options...)
+	if e.config.QueryHttpsRecord {
+		if err := ApplyHTTPSRecord(e.config, config, e.svcb, !hasALPN); err != nil {
+			newError("unable to apply HTTPS record").AtWarning().Base(err).WriteToLog()
+		}
+	}
+	tlsConn := Client(conn, config)
 	return tlsConn, nil
 }
 
@@ -4,9 +4,11 @@ import (
 	"context"
 	"crypto/tls"
 
+	core "github.com/v2fly/v2ray-core/v5"
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/buf"
 	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/features/dns"
 )
 
 //go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
@@ -73,6 +75,15 @@ func Server(c net.Conn, config *tls.Config) net.Conn {
 
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
-		return NewTLSSecurityEngineFromConfig(config.(*Config))
+		engine := &Engine{config: config.(*Config)}
+		if engine.config.QueryHttpsRecord && core.FromContext(ctx) != nil {
+			// The DNS feature may be registered after the engine is created.
+			if err := core.RequireFeatures(ctx, func(client dns.Client) {
+				engine.svcb, _ = client.(dns.SVCBLookup)
+			}); err != nil {
+				return nil, err
+			}
+		}
+		return engine, nil
 	}))
 }